@@ -0,0 +1,85 @@
+package taskman
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// AwaitRun blocks until the next run of the job with the given ID finishes, which may be a run
+// already executing, and returns the run's result. Use it e.g. to wait for a triggered run, or for
+// the first run of a job at startup, rather than polling for its side effects.
+// Returns the context's error if ctx is done first, ErrManagerStopped if the TaskManager is
+// stopped first, and an error if the job is not scheduled or is removed before the run finishes.
+func (tm *TaskManager) AwaitRun(ctx context.Context, jobID string) (JobResult, error) {
+	done := make(chan JobResult, 1)
+	err := func() error {
+		tm.Lock()
+		defer tm.Unlock()
+
+		select {
+		case <-tm.ctx.Done():
+			return ErrManagerStopped
+		default:
+		}
+		if _, ok := tm.deadLetters[jobID]; !ok {
+			if _, err := tm.jobQueue.JobInQueue(jobID); err != nil {
+				return fmt.Errorf("job with ID %s not found", jobID)
+			}
+		}
+		tm.runWaiters[jobID] = append(tm.runWaiters[jobID], done)
+		return nil
+	}()
+	if err != nil {
+		return JobResult{}, err
+	}
+
+	select {
+	case result, ok := <-done:
+		if !ok {
+			return JobResult{}, fmt.Errorf("job with ID %s removed", jobID)
+		}
+		return result, nil
+	case <-ctx.Done():
+		tm.removeRunWaiter(jobID, done)
+		return JobResult{}, ctx.Err()
+	case <-tm.ctx.Done():
+		tm.removeRunWaiter(jobID, done)
+		return JobResult{}, ErrManagerStopped
+	}
+}
+
+// removeRunWaiter removes a caller of AwaitRun no longer waiting for a run of the job.
+func (tm *TaskManager) removeRunWaiter(jobID string, done chan JobResult) {
+	tm.Lock()
+	defer tm.Unlock()
+	waiters := slices.DeleteFunc(tm.runWaiters[jobID], func(waiter chan JobResult) bool {
+		return waiter == done
+	})
+	if len(waiters) == 0 {
+		delete(tm.runWaiters, jobID)
+	} else {
+		tm.runWaiters[jobID] = waiters
+	}
+}
+
+// notifyRunWaiters passes the result of a finished run to the callers of AwaitRun waiting for a
+// run of its job.
+func (tm *TaskManager) notifyRunWaiters(result JobResult) {
+	tm.Lock()
+	defer tm.Unlock()
+	for _, done := range tm.runWaiters[result.JobID] {
+		done <- result
+	}
+	delete(tm.runWaiters, result.JobID)
+}
+
+// releaseRunWaiters releases the callers of AwaitRun waiting for a run of a job which has been
+// removed, and will not run again.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) releaseRunWaiters(jobID string) {
+	for _, done := range tm.runWaiters[jobID] {
+		close(done)
+	}
+	delete(tm.runWaiters, jobID)
+}
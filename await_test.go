@@ -0,0 +1,84 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAwaitRun(t *testing.T) {
+	t.Run("Returns the result of the next run", func(t *testing.T) {
+		manager := New(WithWorkers(1))
+		defer manager.Stop()
+
+		job := getMockedJob(2, "awaited-job", time.Hour, time.Hour)
+		job.Tasks[1] = MockTask{executeFunc: func() error { return errors.New("task failed") }}
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			assert.NoError(t, manager.TriggerJob(job.ID))
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		result, err := manager.AwaitRun(ctx, job.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, job.ID, result.JobID)
+		assert.Len(t, result.TaskResults, 2)
+		assert.ErrorContains(t, result.Err(), "task failed")
+	})
+
+	t.Run("Returns when the context is done", func(t *testing.T) {
+		manager := New(WithWorkers(1))
+		defer manager.Stop()
+
+		job := getMockedJob(1, "awaited-job", time.Hour, time.Hour)
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := manager.AwaitRun(ctx, job.ID)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		manager.RLock()
+		assert.Empty(t, manager.runWaiters, "Expected the waiter to be removed")
+		manager.RUnlock()
+	})
+
+	t.Run("Returns when the job is removed", func(t *testing.T) {
+		manager := New(WithWorkers(1))
+		defer manager.Stop()
+
+		job := getMockedJob(1, "awaited-job", time.Hour, time.Hour)
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			assert.NoError(t, manager.RemoveJob(job.ID))
+		}()
+		_, err := manager.AwaitRun(context.Background(), job.ID)
+		assert.ErrorContains(t, err, "removed")
+
+		_, err = manager.AwaitRun(context.Background(), job.ID)
+		assert.ErrorContains(t, err, "not found", "Expected an error awaiting a job not scheduled")
+	})
+
+	t.Run("Returns when the manager is stopped", func(t *testing.T) {
+		manager := New(WithWorkers(1))
+
+		job := getMockedJob(1, "awaited-job", time.Hour, time.Hour)
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			manager.Stop()
+		}()
+		_, err := manager.AwaitRun(context.Background(), job.ID)
+		assert.ErrorIs(t, err, ErrManagerStopped)
+
+		_, err = manager.AwaitRun(context.Background(), job.ID)
+		assert.ErrorIs(t, err, ErrManagerStopped)
+	})
+}
@@ -0,0 +1,144 @@
+package taskman
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// benchmarkQueueSizes are the numbers of jobs in the queue the queue benchmarks are run at.
+var benchmarkQueueSizes = []int{1_000, 10_000, 100_000}
+
+// benchmarkManager returns a manager with a queue of n jobs, none of which are due during the
+// benchmark.
+func benchmarkManager(b *testing.B, n int) *TaskManager {
+	b.Helper()
+	manager := New(WithWorkers(runtime.NumCPU()), WithScaleInterval(time.Hour))
+	jobs := make([]Job, n)
+	for i := range jobs {
+		jobs[i] = benchmarkJob(fmt.Sprintf("queued-%d", i))
+	}
+	if err := manager.ScheduleJobs(jobs); err != nil {
+		b.Fatalf("Failed to fill the queue: %v", err)
+	}
+	return manager
+}
+
+// benchmarkJob returns a job which is not due during the benchmark.
+func benchmarkJob(jobID string) Job {
+	return Job{ID: jobID, Cadence: time.Hour, NextExec: time.Now().Add(time.Hour), Tasks: []Task{MockTask{}}}
+}
+
+func BenchmarkScheduleJob(b *testing.B) {
+	for _, n := range benchmarkQueueSizes {
+		b.Run(fmt.Sprintf("jobs=%d", n), func(b *testing.B) {
+			manager := benchmarkManager(b, n)
+			defer manager.Stop()
+			jobs := make([]Job, b.N)
+			for i := range jobs {
+				jobs[i] = benchmarkJob(fmt.Sprintf("job-%d", i))
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := range b.N {
+				if err := manager.ScheduleJob(jobs[i]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRemoveJob(b *testing.B) {
+	for _, n := range benchmarkQueueSizes {
+		b.Run(fmt.Sprintf("jobs=%d", n), func(b *testing.B) {
+			manager := benchmarkManager(b, n)
+			defer manager.Stop()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := range b.N {
+				// Remove a queued job, and put it back outside of the measurement
+				jobID := fmt.Sprintf("queued-%d", i%n)
+				if err := manager.RemoveJob(jobID); err != nil {
+					b.Fatal(err)
+				}
+				b.StopTimer()
+				if err := manager.ScheduleJob(benchmarkJob(jobID)); err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+			}
+		})
+	}
+}
+
+func BenchmarkJobLookup(b *testing.B) {
+	for _, n := range benchmarkQueueSizes {
+		b.Run(fmt.Sprintf("jobs=%d", n), func(b *testing.B) {
+			manager := benchmarkManager(b, n)
+			defer manager.Stop()
+
+			b.ResetTimer()
+			for i := range b.N {
+				if _, err := manager.Job(fmt.Sprintf("queued-%d", i%n)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkDispatchThroughput measures scheduling, dispatching and executing one-shot jobs, each
+// due immediately, reporting the executed tasks per second.
+func BenchmarkDispatchThroughput(b *testing.B) {
+	manager := New(WithWorkers(runtime.NumCPU()), WithScaleInterval(time.Hour))
+	defer manager.Stop()
+
+	var wg sync.WaitGroup
+	task := MockTask{executeFunc: func() error {
+		wg.Done()
+		return nil
+	}}
+	jobs := make([]Job, b.N)
+	for i := range jobs {
+		jobs[i] = Job{ID: fmt.Sprintf("job-%d", i), NextExec: time.Now(), Tasks: []Task{task}, once: true}
+	}
+
+	b.ResetTimer()
+	wg.Add(b.N)
+	if err := manager.ScheduleJobs(jobs); err != nil {
+		b.Fatal(err)
+	}
+	wg.Wait()
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "tasks/s")
+}
+
+// BenchmarkWorkerPoolThroughput measures executing tasks sent directly to the worker pool,
+// reporting the executed tasks per second.
+func BenchmarkWorkerPoolThroughput(b *testing.B) {
+	tasks := newTaskQueue(runtime.NumCPU(), 64)
+	pool := newWorkerPool(runtime.NumCPU(), make(chan error, 1), make(chan time.Duration, 1), tasks,
+		make(chan struct{}), zerologLogger{})
+	defer pool.stop()
+
+	var executed atomic.Int64
+	done := make(chan struct{})
+	task := MockTask{executeFunc: func() error {
+		if executed.Add(1) == int64(b.N) {
+			close(done)
+		}
+		return nil
+	}}
+
+	b.ResetTimer()
+	for range b.N {
+		tasks.send(nil, task)
+	}
+	<-done
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "tasks/s")
+}
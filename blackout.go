@@ -0,0 +1,159 @@
+package taskman
+
+import (
+	"container/heap"
+	"errors"
+	"slices"
+	"time"
+)
+
+// BlackoutPolicy determines what happens to a run of a job which is due during a blackout window
+// or while the TaskManager is in maintenance mode.
+type BlackoutPolicy int
+
+const (
+	// BlackoutDefer defers the run until the blackout ends, dispatching it once regardless of how
+	// many executions were due during the blackout.
+	BlackoutDefer BlackoutPolicy = iota
+	// BlackoutSkip skips the run, rescheduling the job to its next execution and emitting an
+	// EventRunSkipped event.
+	BlackoutSkip
+)
+
+// Blackout is a daily window of time during which due runs of jobs are not dispatched, e.g. from
+// 00:00 to 02:00 for nightly maintenance of a database. Windows with an End before their Start
+// span midnight. Times are in the time zone of the TaskManager's clock, the local time zone by
+// default, as for DailyAt.
+type Blackout struct {
+	Start  TimeOfDay      // Time of day the window starts, inclusive
+	End    TimeOfDay      // Time of day the window ends, exclusive
+	Policy BlackoutPolicy // What to do with runs due during the window
+}
+
+// validate returns an error if the window's times or policy are invalid.
+func (b Blackout) validate() error {
+	for _, tod := range []TimeOfDay{b.Start, b.End} {
+		if tod.Hour < 0 || tod.Hour > 23 || tod.Minute < 0 || tod.Minute > 59 {
+			return errors.New("invalid blackout window, times must be within a day")
+		}
+	}
+	if b.Start == b.End {
+		return errors.New("invalid blackout window, start must differ from end")
+	}
+	if b.Policy < BlackoutDefer || b.Policy > BlackoutSkip {
+		return errors.New("invalid blackout policy")
+	}
+	return nil
+}
+
+// end returns the end of the window if t is within it, or false if it is not.
+func (b Blackout) end(t time.Time) (time.Time, bool) {
+	minute := t.Hour()*60 + t.Minute()
+	start, end := b.Start.Hour*60+b.Start.Minute, b.End.Hour*60+b.End.Minute
+	year, month, day := t.Date()
+	switch {
+	case start < end && minute >= start && minute < end:
+		return b.End.on(year, month, day, t.Location()), true
+	case start > end && minute >= start:
+		// The window spans midnight, and ends the following day
+		return b.End.on(year, month, day+1, t.Location()), true
+	case start > end && minute < end:
+		return b.End.on(year, month, day, t.Location()), true
+	}
+	return time.Time{}, false
+}
+
+// SetBlackouts sets the blackout windows applying to the runs of all jobs, in addition to the
+// Blackouts of each job, replacing any windows set before. Runs triggered with TriggerJob or
+// RunJobNow are not subject to blackouts. Call with no windows to remove them.
+func (tm *TaskManager) SetBlackouts(blackouts ...Blackout) error {
+	for _, blackout := range blackouts {
+		if err := blackout.validate(); err != nil {
+			return err
+		}
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	tm.blackouts = slices.Clone(blackouts)
+	return nil
+}
+
+// StartMaintenance puts the TaskManager in maintenance mode, in which the due runs of all jobs are
+// deferred or skipped according to the policy until EndMaintenance is called, e.g. in place of
+// pausing and resuming jobs by hand around a deployment of a dependency. Runs already executing
+// are left to finish. Calling it while in maintenance mode changes the policy.
+func (tm *TaskManager) StartMaintenance(policy BlackoutPolicy) error {
+	if policy < BlackoutDefer || policy > BlackoutSkip {
+		return errors.New("invalid blackout policy")
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	select {
+	case <-tm.ctx.Done():
+		return ErrManagerStopped
+	default:
+	}
+
+	tm.maintenance = true
+	tm.maintenancePolicy = policy
+	tm.logger.Info("Started maintenance mode", "policy", policy)
+	return nil
+}
+
+// EndMaintenance ends maintenance mode, dispatching the runs deferred during it.
+func (tm *TaskManager) EndMaintenance() error {
+	tm.Lock()
+	defer tm.Unlock()
+
+	select {
+	case <-tm.ctx.Done():
+		return ErrManagerStopped
+	default:
+	}
+
+	if !tm.maintenance {
+		return errors.New("not in maintenance mode")
+	}
+	tm.maintenance = false
+	for _, job := range tm.jobQueue.jobs {
+		if !job.deferred {
+			continue
+		}
+		job.deferred = false
+		if job.Dedicated {
+			tm.wakeDedicated(job)
+		}
+	}
+	heap.Init(&tm.jobQueue)
+	tm.wakeRunLoop()
+	tm.logger.Info("Ended maintenance mode")
+	return nil
+}
+
+// InMaintenance returns true while the TaskManager is in maintenance mode.
+func (tm *TaskManager) InMaintenance() bool {
+	tm.RLock()
+	defer tm.RUnlock()
+	return tm.maintenance
+}
+
+// blackout returns the end of the blackout the job's run due at now falls in, and the policy of
+// the blackout, or false if the run is not blacked out. The end is zero in maintenance mode,
+// which lasts until ended.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) blackout(job *Job, now time.Time) (time.Time, BlackoutPolicy, bool) {
+	if tm.maintenance {
+		return time.Time{}, tm.maintenancePolicy, true
+	}
+	for _, blackouts := range [][]Blackout{job.Blackouts, tm.blackouts} {
+		for _, blackout := range blackouts {
+			if end, ok := blackout.end(now); ok {
+				return end, blackout.Policy, true
+			}
+		}
+	}
+	return time.Time{}, 0, false
+}
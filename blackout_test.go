@@ -0,0 +1,139 @@
+package taskman
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlackoutEnd(t *testing.T) {
+	day := func(hour, minute int) time.Time {
+		return time.Date(2025, time.March, 10, hour, minute, 0, 0, time.UTC)
+	}
+	nightly := Blackout{Start: At(0, 0), End: At(2, 0)}
+	end, ok := nightly.end(day(1, 30))
+	assert.True(t, ok)
+	assert.Equal(t, day(2, 0), end)
+	_, ok = nightly.end(day(2, 0))
+	assert.False(t, ok, "Expected the end of the window to be outside it")
+
+	// Windows with an end before their start span midnight
+	overnight := Blackout{Start: At(22, 0), End: At(1, 0)}
+	end, ok = overnight.end(day(23, 0))
+	assert.True(t, ok)
+	assert.Equal(t, day(25, 0), end, "Expected the window to end the following day")
+	end, ok = overnight.end(day(0, 30))
+	assert.True(t, ok)
+	assert.Equal(t, day(1, 0), end)
+	_, ok = overnight.end(day(12, 0))
+	assert.False(t, ok)
+
+	assert.Error(t, Blackout{Start: At(1, 0), End: At(1, 0)}.validate(), "Expected error for an empty window")
+	assert.Error(t, Blackout{Start: At(24, 0), End: At(1, 0)}.validate(), "Expected error for a time beyond a day")
+	assert.Error(t, Blackout{Start: At(0, 0), End: At(1, 0), Policy: BlackoutPolicy(-1)}.validate())
+}
+
+func TestBlackouts(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+
+	// A window from a minute ago until two minutes from now, wrapping around midnight
+	now := time.Now()
+	minuteOfDay := func(offset int) TimeOfDay {
+		minute := ((now.Hour()*60+now.Minute()+offset)%1440 + 1440) % 1440
+		return At(minute/60, minute%60)
+	}
+	window := Blackout{Start: minuteOfDay(-1), End: minuteOfDay(2)}
+	end, _ := window.end(now)
+
+	executed := make(chan string, 2)
+	deferred := getMockedJob(1, "deferred-job", time.Hour, -time.Millisecond)
+	deferred.Blackouts = []Blackout{window}
+	deferred.Tasks[0] = MockTask{executeFunc: func() error {
+		executed <- deferred.ID
+		return nil
+	}}
+	assert.NoError(t, manager.ScheduleJob(deferred))
+
+	// Windows of the manager apply to all jobs
+	window.Policy = BlackoutSkip
+	assert.NoError(t, manager.SetBlackouts(window))
+	skipped := getMockedJob(1, "skipped-job", time.Hour, -time.Millisecond)
+	skipped.Tasks[0] = MockTask{executeFunc: func() error {
+		executed <- skipped.ID
+		return nil
+	}}
+	assert.NoError(t, manager.ScheduleJob(skipped))
+
+	assert.Eventually(t, func() bool {
+		stats, err := manager.JobStats(skipped.ID)
+		return err == nil && stats.SkippedRuns == 1
+	}, time.Second, time.Millisecond, "Expected the run due in the window to be skipped")
+	info, err := manager.Job(skipped.ID)
+	assert.NoError(t, err)
+	assert.True(t, info.NextExec.After(now.Add(50*time.Minute)), "Expected the job to be rescheduled to its next execution")
+
+	assert.Eventually(t, func() bool {
+		info, err := manager.Job(deferred.ID)
+		return err == nil && info.NextExec.Equal(end)
+	}, time.Second, time.Millisecond, "Expected the run to be deferred to the end of the window")
+	select {
+	case jobID := <-executed:
+		t.Fatalf("Expected no runs during the window, %s executed", jobID)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// Runs triggered manually are not subject to blackouts
+	assert.NoError(t, manager.TriggerJob(skipped.ID))
+	select {
+	case <-executed:
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the triggered run to execute")
+	}
+
+	assert.Error(t, manager.SetBlackouts(Blackout{Start: At(1, 0), End: At(1, 0)}), "Expected error for an invalid window")
+	invalid := getMockedJob(1, "invalid-blackout-job", time.Hour, time.Hour)
+	invalid.Blackouts = []Blackout{{Start: At(25, 0), End: At(1, 0)}}
+	assert.Error(t, manager.ScheduleJob(invalid), "Expected error scheduling a job with an invalid window")
+}
+
+func TestMaintenance(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+	assert.Error(t, manager.StartMaintenance(BlackoutPolicy(2)), "Expected error for an invalid policy")
+	assert.Error(t, manager.EndMaintenance(), "Expected error ending maintenance mode when not in it")
+
+	// Runs due in maintenance mode are deferred until it ends
+	assert.NoError(t, manager.StartMaintenance(BlackoutDefer))
+	assert.True(t, manager.InMaintenance())
+	executed := make(chan struct{}, 4)
+	job := getMockedJob(1, "maintenance-job", 20*time.Millisecond, -time.Millisecond)
+	job.Tasks[0] = MockTask{executeFunc: func() error {
+		executed <- struct{}{}
+		return nil
+	}}
+	assert.NoError(t, manager.ScheduleJob(job))
+	select {
+	case <-executed:
+		t.Fatal("Expected no runs in maintenance mode")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Empty(t, manager.QueueSnapshot(0), "Expected the deferred job to be left out of the snapshot")
+
+	assert.NoError(t, manager.EndMaintenance())
+	assert.False(t, manager.InMaintenance())
+	select {
+	case <-executed:
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the deferred run to execute once maintenance mode ended")
+	}
+
+	// Runs due in maintenance mode with BlackoutSkip are skipped
+	assert.NoError(t, manager.StartMaintenance(BlackoutSkip))
+	assert.Eventually(t, func() bool {
+		stats, err := manager.JobStats(job.ID)
+		return err == nil && stats.SkippedRuns > 0
+	}, time.Second, time.Millisecond, "Expected the runs to be skipped")
+	assert.NoError(t, manager.EndMaintenance())
+}
@@ -0,0 +1,99 @@
+// Package boltstore provides a taskman.JobStore persisting jobs in a BoltDB database file.
+package boltstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	taskman "github.com/jkbrsn/go-taskman"
+	bolt "go.etcd.io/bbolt"
+)
+
+// jobsBucket is the bucket holding the job records, keyed by job ID.
+var jobsBucket = []byte("jobs")
+
+// Store is a taskman.JobStore backed by a BoltDB database. Records are stored as JSON.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens, or creates, the database file at path and returns a Store using it. The Store owns
+// the database, which is closed by Close.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open job store: %w", err)
+	}
+	store, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// New returns a Store using an already open database, e.g. one shared with other parts of an
+// application. The jobs are kept in a bucket of their own.
+func New(db *bolt.DB) (*Store, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(jobsBucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create jobs bucket: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save creates or overwrites the record with the record's ID.
+func (s *Store) Save(record taskman.JobRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode job %s: %w", record.ID, err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Put([]byte(record.ID), data)
+	})
+}
+
+// Load returns the record of the job with the given ID, or taskman.ErrJobNotFound.
+func (s *Store) Load(jobID string) (taskman.JobRecord, error) {
+	var record taskman.JobRecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(jobsBucket).Get([]byte(jobID))
+		if data == nil {
+			return taskman.ErrJobNotFound
+		}
+		return json.Unmarshal(data, &record)
+	})
+	return record, err
+}
+
+// Delete deletes the record of the job with the given ID.
+func (s *Store) Delete(jobID string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Delete([]byte(jobID))
+	})
+}
+
+// List returns all records in the store, ordered by job ID.
+func (s *Store) List() ([]taskman.JobRecord, error) {
+	var records []taskman.JobRecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(key, data []byte) error {
+			var record taskman.JobRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return fmt.Errorf("failed to decode job %s: %w", key, err)
+			}
+			records = append(records, record)
+			return nil
+		})
+	})
+	return records, err
+}
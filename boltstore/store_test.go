@@ -0,0 +1,89 @@
+package boltstore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	taskman "github.com/jkbrsn/go-taskman"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	store, err := Open(path)
+	assert.NoError(t, err, "Expected no error opening store")
+
+	record := taskman.JobRecord{
+		ID:            "job-1",
+		Cadence:       10 * time.Second,
+		NextExec:      time.Now().Add(10 * time.Second).Round(0),
+		OverlapPolicy: taskman.OverlapSkip,
+		RetryPolicy:   &taskman.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Second},
+	}
+	assert.NoError(t, store.Save(record), "Expected no error saving record")
+	assert.NoError(t, store.Save(taskman.JobRecord{ID: "job-2", CronExpr: "@daily"}))
+
+	t.Run("Load", func(t *testing.T) {
+		loaded, err := store.Load("job-1")
+		assert.NoError(t, err, "Expected no error loading record")
+		assert.True(t, record.NextExec.Equal(loaded.NextExec), "Expected NextExec to be preserved")
+		loaded.NextExec = record.NextExec
+		assert.Equal(t, record, loaded)
+
+		_, err = store.Load("unknown-job")
+		assert.ErrorIs(t, err, taskman.ErrJobNotFound)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		records, err := store.List()
+		assert.NoError(t, err, "Expected no error listing records")
+		assert.Len(t, records, 2)
+		assert.Equal(t, "@daily", records[1].CronExpr)
+	})
+
+	t.Run("Reopen and Delete", func(t *testing.T) {
+		assert.NoError(t, store.Close())
+		store, err = Open(path)
+		assert.NoError(t, err, "Expected no error reopening store")
+		defer store.Close()
+
+		assert.NoError(t, store.Delete("job-1"), "Expected no error deleting record")
+		assert.NoError(t, store.Delete("job-1"), "Expected no error deleting missing record")
+		records, err := store.List()
+		assert.NoError(t, err)
+		assert.Len(t, records, 1, "Expected records to persist across reopening")
+		assert.Equal(t, "job-2", records[0].ID)
+	})
+}
+
+func TestStoreWithManager(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	store, err := Open(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	nextExec := time.Now().Add(1 * time.Hour)
+	manager := taskman.New()
+	manager.SetJobStore(store)
+	job := taskman.Job{ID: "persisted-job", Cadence: 2 * time.Hour, NextExec: nextExec, Tasks: []taskman.Task{noopTask{}}}
+	assert.NoError(t, manager.ScheduleJob(job))
+	manager.Stop()
+
+	// A new manager restores the job with its schedule
+	manager = taskman.New()
+	defer manager.Stop()
+	manager.SetJobStore(store)
+	err = manager.RestoreJobs(func(taskman.JobRecord) ([]taskman.Task, error) {
+		return []taskman.Task{noopTask{}}, nil
+	})
+	assert.NoError(t, err, "Expected no error restoring jobs")
+
+	info, err := manager.Job("persisted-job")
+	assert.NoError(t, err, "Expected restored job to be scheduled")
+	assert.True(t, nextExec.Equal(info.NextExec), "Expected the stored schedule to be resumed")
+}
+
+type noopTask struct{}
+
+func (noopTask) Execute() error { return nil }
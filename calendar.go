@@ -0,0 +1,55 @@
+package taskman
+
+import "time"
+
+// Calendar excludes dates from the runs of a job, e.g. the public holidays on which a job settling
+// trades must not run, which a cron expression cannot express. Set a job's Calendar to have its
+// runs due on excluded dates skipped. Skipped runs are rescheduled to the job's next execution,
+// emit an EventRunSkipped event, and are recorded in the job's history as skipped.
+// Note: calendars are not part of a JobRecord, so stored jobs are restored without their calendar,
+// unless scheduled again with it.
+type Calendar interface {
+	// Excludes returns true if no run may execute on the date of t, in the location of t.
+	Excludes(t time.Time) bool
+}
+
+// CalendarFunc is an adapter allowing the use of an ordinary function as a Calendar.
+type CalendarFunc func(t time.Time) bool
+
+// Excludes returns the result of calling the function with t.
+func (f CalendarFunc) Excludes(t time.Time) bool {
+	return f(t)
+}
+
+// ExcludeDates returns a calendar excluding each of the given dates. Only the year, month and day
+// of each date are used, in the date's own location, and are compared with the date of a run in
+// the time zone of the TaskManager's clock, e.g. time.Date(2025, time.December, 25, 0, 0, 0, 0,
+// time.UTC) excludes runs on Christmas Day in any time zone.
+func ExcludeDates(dates ...time.Time) Calendar {
+	excluded := make(dateCalendar, len(dates))
+	for _, date := range dates {
+		excluded[dateOf(date)] = struct{}{}
+	}
+	return excluded
+}
+
+// civilDate is a date without a time or location.
+type civilDate struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+// dateOf returns the date of t, in the location of t.
+func dateOf(t time.Time) civilDate {
+	year, month, day := t.Date()
+	return civilDate{year: year, month: month, day: day}
+}
+
+type dateCalendar map[civilDate]struct{}
+
+// Excludes returns true if the date of t is one of the calendar's dates.
+func (c dateCalendar) Excludes(t time.Time) bool {
+	_, ok := c[dateOf(t)]
+	return ok
+}
@@ -0,0 +1,69 @@
+package taskman
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExcludeDates(t *testing.T) {
+	calendar := ExcludeDates(time.Date(2025, time.December, 25, 0, 0, 0, 0, time.UTC))
+	assert.True(t, calendar.Excludes(time.Date(2025, time.December, 25, 18, 30, 0, 0, time.UTC)))
+	assert.False(t, calendar.Excludes(time.Date(2025, time.December, 26, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, calendar.Excludes(time.Date(2024, time.December, 25, 0, 0, 0, 0, time.UTC)), "Expected only the year of the date to be excluded")
+
+	// Dates are compared in the location of the run
+	tokyo := time.FixedZone("UTC+9", 9*60*60)
+	assert.True(t, calendar.Excludes(time.Date(2025, time.December, 25, 1, 0, 0, 0, tokyo)))
+
+	weekends := CalendarFunc(func(t time.Time) bool {
+		return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+	})
+	assert.True(t, weekends.Excludes(time.Date(2025, time.December, 27, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestJobCalendar(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+
+	executed := make(chan string, 2)
+	holiday := getMockedJob(1, "holiday-job", time.Hour, -time.Millisecond)
+	holiday.Calendar = ExcludeDates(time.Now())
+	holiday.Tasks[0] = MockTask{executeFunc: func() error {
+		executed <- holiday.ID
+		return nil
+	}}
+	assert.NoError(t, manager.ScheduleJob(holiday))
+	workday := getMockedJob(1, "workday-job", time.Hour, -time.Millisecond)
+	workday.Calendar = CalendarFunc(func(time.Time) bool { return false })
+	workday.Tasks[0] = MockTask{executeFunc: func() error {
+		executed <- workday.ID
+		return nil
+	}}
+	assert.NoError(t, manager.ScheduleJob(workday))
+
+	select {
+	case jobID := <-executed:
+		assert.Equal(t, workday.ID, jobID, "Expected only the job without an excluded date to execute")
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the job without an excluded date to execute")
+	}
+
+	// The skip is recorded in the history of the job
+	assert.Eventually(t, func() bool {
+		history, err := manager.JobHistory(holiday.ID)
+		return err == nil && len(history) == 1
+	}, time.Second, time.Millisecond, "Expected the skipped run in the job's history")
+	history, err := manager.JobHistory(holiday.ID)
+	assert.NoError(t, err)
+	assert.True(t, history[0].Skipped)
+	assert.False(t, history[0].Succeeded)
+	stats, err := manager.JobStats(holiday.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.SkippedRuns)
+	assert.Equal(t, 0, stats.TotalRuns)
+	info, err := manager.Job(holiday.ID)
+	assert.NoError(t, err)
+	assert.True(t, info.NextExec.After(time.Now()), "Expected the job to be rescheduled to its next execution")
+}
@@ -0,0 +1,95 @@
+package taskman
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// ErrChaosPanic is the value tasks panic with when a panic is injected by the chaos mode, reported
+// wrapped in a *PanicError.
+var ErrChaosPanic = errors.New("panic injected by chaos mode")
+
+// Chaos configures the chaos mode of a TaskManager, a stress-testing aid which randomly delays the
+// dispatch and execution of tasks, and makes tasks panic instead of executing, to shake out
+// ordering bugs in the TaskManager and in the code using it. Not meant for production use.
+type Chaos struct {
+	DelayRate float64       // Probability, from 0 to 1, of delaying each dispatch and execution of a task
+	MaxDelay  time.Duration // Max of the random delays, which are uniformly distributed up to it
+	PanicRate float64       // Probability, from 0 to 1, of a task panicking with ErrChaosPanic
+	Seed      uint64        // Seed of the random decisions, for reproducible runs, 0 for a random seed
+}
+
+// chaos is the state of an enabled chaos mode.
+type chaos struct {
+	config Chaos
+
+	mu  sync.Mutex // Guards rng, which is not safe for concurrent use
+	rng *rand.Rand
+}
+
+// SetChaos enables the chaos mode of the TaskManager with the given configuration, or disables it
+// if the configuration is the zero value. Tasks dispatched before the change keep the configuration
+// they were dispatched with.
+func (tm *TaskManager) SetChaos(config Chaos) error {
+	if config.DelayRate < 0 || config.DelayRate > 1 || config.PanicRate < 0 || config.PanicRate > 1 {
+		return fmt.Errorf("chaos rates must be between 0 and 1, got %v and %v", config.DelayRate, config.PanicRate)
+	}
+	if config.MaxDelay < 0 {
+		return fmt.Errorf("chaos max delay must not be negative, got %v", config.MaxDelay)
+	}
+
+	if config == (Chaos{}) {
+		tm.chaos.Store(nil)
+		return nil
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	tm.chaos.Store(&chaos{config: config, rng: rand.New(rand.NewPCG(seed, seed))})
+	tm.logger.Warn("Chaos mode enabled", "delayRate", config.DelayRate, "maxDelay", config.MaxDelay, "panicRate", config.PanicRate, "seed", seed)
+	return nil
+}
+
+// roll returns true with the given probability, and a random delay up to the max delay.
+func (c *chaos) roll(rate float64) (bool, time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hit := rate > 0 && c.rng.Float64() < rate
+	var delay time.Duration
+	if c.config.MaxDelay > 0 {
+		delay = time.Duration(c.rng.Int64N(int64(c.config.MaxDelay) + 1))
+	}
+	return hit, delay
+}
+
+// delay sleeps for a random delay with the configured probability, or until done is closed.
+// A nil chaos never delays.
+func (c *chaos) delay(done <-chan struct{}) {
+	if c == nil {
+		return
+	}
+	hit, delay := c.roll(c.config.DelayRate)
+	if !hit || delay == 0 {
+		return
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-done:
+	}
+}
+
+// maybePanic panics with ErrChaosPanic with the configured probability. A nil chaos never panics.
+func (c *chaos) maybePanic() {
+	if c == nil {
+		return
+	}
+	if hit, _ := c.roll(c.config.PanicRate); hit {
+		panic(ErrChaosPanic)
+	}
+}
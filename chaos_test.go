@@ -0,0 +1,118 @@
+package taskman
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stressDuration is how long TestChaosStress runs for, e.g. go test -race -run Stress -stress 1m.
+var stressDuration = flag.Duration("stress", 200*time.Millisecond, "duration of the chaos stress test")
+
+func TestSetChaos(t *testing.T) {
+	manager := New(WithWorkers(1), WithChaos(Chaos{DelayRate: 0.5, MaxDelay: time.Millisecond, Seed: 1}))
+	defer manager.Stop()
+	assert.NotNil(t, manager.chaos.Load(), "Expected chaos mode to be enabled")
+
+	assert.Error(t, manager.SetChaos(Chaos{DelayRate: 1.5}), "Expected error for a rate above 1")
+	assert.Error(t, manager.SetChaos(Chaos{PanicRate: -0.1}), "Expected error for a negative rate")
+	assert.Error(t, manager.SetChaos(Chaos{MaxDelay: -time.Second}), "Expected error for a negative max delay")
+
+	assert.NoError(t, manager.SetChaos(Chaos{}))
+	assert.Nil(t, manager.chaos.Load(), "Expected chaos mode to be disabled")
+}
+
+func TestChaosPanic(t *testing.T) {
+	manager := New(WithWorkers(1), WithChaos(Chaos{PanicRate: 1}))
+	defer manager.Stop()
+
+	executed := false
+	job := getMockedJob(1, "chaos-job", time.Hour, time.Hour)
+	job.Tasks[0] = MockTask{executeFunc: func() error {
+		executed = true
+		return nil
+	}}
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	result, err := manager.RunJobNow(ctx, job.ID)
+	assert.NoError(t, err)
+	var panicErr *PanicError
+	assert.ErrorAs(t, result.Err(), &panicErr)
+	assert.ErrorIs(t, result.Err(), ErrChaosPanic)
+	assert.False(t, executed, "Expected the task to panic instead of executing")
+}
+
+// TestChaosStress operates a TaskManager in chaos mode from several goroutines, for shaking out
+// races and deadlocks when run with the race detector. Stopping must not hang however the injected
+// delays and panics interleave with the operations.
+func TestChaosStress(t *testing.T) {
+	manager := New(
+		WithWorkerBounds(2, 8),
+		WithChaos(Chaos{DelayRate: 0.3, MaxDelay: 2 * time.Millisecond, PanicRate: 0.05}),
+	)
+	errs, unsubscribeErrors := manager.SubscribeErrors(16)
+	defer unsubscribeErrors()
+	go func() {
+		for range errs {
+		}
+	}()
+
+	deadline := time.Now().Add(*stressDuration)
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(uint64(g), 0))
+			for i := 0; time.Now().Before(deadline); i++ {
+				jobID := fmt.Sprintf("stress-%d-%d", g, rng.IntN(8))
+				switch rng.IntN(7) {
+				case 0:
+					job := getMockedJob(1+rng.IntN(3), jobID, time.Duration(1+rng.IntN(5))*time.Millisecond, 0)
+					job.ExecutionMode = ExecutionMode(rng.IntN(2))
+					_ = manager.ScheduleJob(job)
+				case 1:
+					_ = manager.RemoveJob(jobID)
+				case 2:
+					_ = manager.PauseJob(jobID)
+				case 3:
+					_ = manager.ResumeJob(jobID)
+				case 4:
+					_ = manager.ReplaceJob(getMockedJob(1+rng.IntN(3), jobID, 2*time.Millisecond, 0))
+				case 5:
+					ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+					_, _ = manager.RunJobNow(ctx, jobID)
+					cancel()
+				case 6:
+					events, unsubscribe := manager.SubscribeEvents(rng.IntN(4))
+					select {
+					case <-events:
+					case <-time.After(time.Millisecond):
+					}
+					unsubscribe()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	stopped := make(chan struct{})
+	go func() {
+		manager.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the TaskManager to stop")
+	}
+	assert.Error(t, manager.ScheduleJob(getMockedJob(1, "late-job", time.Hour, 0)), "Expected scheduling to fail once stopped")
+}
@@ -0,0 +1,28 @@
+package taskman
+
+import "time"
+
+// Clock is the source of time of a TaskManager, used to schedule jobs and to wait for them to
+// become due. A fake implementation allows tests to control the passing of time, see the
+// taskmantest package.
+// Note: execution times, retry delays and the scaling of the worker pool use the real time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time on the returned
+	// channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is a Clock using the time package.
+type realClock struct{}
+
+// Now returns the current local time.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for the duration to elapse, see time.After.
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
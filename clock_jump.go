@@ -0,0 +1,102 @@
+package taskman
+
+import (
+	"container/heap"
+	"fmt"
+	"time"
+)
+
+const (
+	// clockJumpThreshold is the difference between the wall-clock and monotonic time elapsed
+	// between two readings of the clock above which the wall clock is considered to have jumped.
+	clockJumpThreshold = time.Second
+	// clockCheckInterval is the max time the run loop waits before reading the clock again, so
+	// that jumps of the wall clock, and time passed while the host was suspended, are acted upon
+	// without waiting out a wait measured before them.
+	clockCheckInterval = 10 * time.Second
+)
+
+// ClockJumpPolicy determines how the schedules of jobs are adjusted when the wall clock jumps, e.g.
+// when it is stepped by NTP or after the host resumes from a suspension. Jumps are detected by
+// comparing the wall-clock and monotonic time elapsed between readings of the clock, and thus only
+// with clocks providing monotonic readings, like the default clock.
+//
+// Jobs are scheduled by the wall clock, so after the clock jumps forward, runs which became due
+// are dispatched as soon as the jump is detected, with missed executions handled according to
+// each job's MisfirePolicy rather than executed in a burst.
+type ClockJumpPolicy int
+
+const (
+	// ClockJumpShift shifts the next executions of cadence-based jobs back by a backward jump of
+	// the wall clock, so that they keep their cadence rather than wait out the jump. Jobs with a
+	// cron expression or Schedule follow the wall clock. This is the default policy.
+	ClockJumpShift ClockJumpPolicy = iota
+	// ClockJumpIgnore leaves the next executions of all jobs unchanged after a jump, so that jobs
+	// are delayed by backward jumps of the wall clock.
+	ClockJumpIgnore
+)
+
+// SetClockJumpPolicy sets how the schedules of jobs are adjusted when the wall clock jumps.
+// Defaults to ClockJumpShift.
+func (tm *TaskManager) SetClockJumpPolicy(policy ClockJumpPolicy) error {
+	if policy < ClockJumpShift || policy > ClockJumpIgnore {
+		return fmt.Errorf("invalid clock jump policy %d", policy)
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	tm.clockJumpPolicy = policy
+	return nil
+}
+
+// now returns the current time of the TaskManager's clock without its monotonic reading, so that
+// the times jobs are scheduled at compare by the wall clock alone.
+func (tm *TaskManager) now() time.Time {
+	return tm.clock.Now().Round(0)
+}
+
+// observeClock reads the TaskManager's clock, adjusting the schedules of jobs if the wall clock
+// has jumped since the previous reading, and returns the current time without its monotonic
+// reading.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) observeClock() time.Time {
+	now := tm.clock.Now()
+	last := tm.lastClockReading
+	tm.lastClockReading = now
+	if !last.IsZero() {
+		if jump := clockJump(last, now); jump.Abs() >= clockJumpThreshold {
+			tm.clockJumped(jump)
+		}
+	}
+	return now.Round(0)
+}
+
+// clockJump returns how far the wall clock has jumped between two readings of a clock, the
+// difference between the wall-clock and monotonic time elapsed. Without monotonic readings, the
+// elapsed times are the same and no jump is detected.
+func clockJump(last, now time.Time) time.Duration {
+	return now.Round(0).Sub(last.Round(0)) - now.Sub(last)
+}
+
+// clockJumped adjusts the schedules of jobs after the wall clock jumped by the duration, according
+// to the TaskManager's clock jump policy.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) clockJumped(jump time.Duration) {
+	tm.logger.Warn("Wall clock jumped", "jump", jump, "policy", tm.clockJumpPolicy)
+	if jump >= 0 || tm.clockJumpPolicy != ClockJumpShift {
+		return
+	}
+	shifted := 0
+	for _, job := range tm.jobQueue.jobs {
+		if job.Schedule != nil || job.cron != nil || len(job.DependsOn) > 0 {
+			continue
+		}
+		job.NextExec = job.NextExec.Add(jump)
+		job.scheduled = job.scheduled.Add(jump)
+		shifted++
+	}
+	if shifted > 0 {
+		heap.Init(&tm.jobQueue)
+		tm.logger.Debug("Shifted schedules of jobs after clock jump", "jobs", shifted)
+	}
+}
@@ -0,0 +1,56 @@
+package taskman
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClockJump(t *testing.T) {
+	last := time.Now()
+	assert.Less(t, clockJump(last, time.Now()).Abs(), clockJumpThreshold, "Expected no jump between real readings")
+
+	// Readings without monotonic time never jump
+	assert.Zero(t, clockJump(last.Round(0), last.Add(time.Hour).Round(0)))
+}
+
+func TestClockJumped(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+	assert.Error(t, manager.SetClockJumpPolicy(ClockJumpPolicy(-1)), "Expected error setting an invalid policy")
+
+	cadenceJob := getMockedJob(1, "cadence-job", time.Hour, time.Hour)
+	assert.NoError(t, manager.ScheduleJob(cadenceJob))
+	_, err := manager.ScheduleCron(MockTask{ID: "cron"}, "0 * * * *")
+	assert.NoError(t, err)
+	nextExec := func(jobID string) time.Time {
+		info, err := manager.Job(jobID)
+		assert.NoError(t, err)
+		return info.NextExec
+	}
+	cronJobID := manager.Jobs()[0].ID
+	if cronJobID == cadenceJob.ID {
+		cronJobID = manager.Jobs()[1].ID
+	}
+	cadenceNext, cronNext := nextExec(cadenceJob.ID), nextExec(cronJobID)
+
+	// Forward jumps leave the schedules unchanged, as time may really have passed
+	manager.Lock()
+	manager.clockJumped(time.Hour)
+	manager.Unlock()
+	assert.Equal(t, cadenceNext, nextExec(cadenceJob.ID))
+
+	// Cadence jobs keep their cadence after a backward jump, cron jobs follow the wall clock
+	manager.Lock()
+	manager.clockJumped(-30 * time.Minute)
+	manager.Unlock()
+	assert.Equal(t, cadenceNext.Add(-30*time.Minute), nextExec(cadenceJob.ID))
+	assert.Equal(t, cronNext, nextExec(cronJobID))
+
+	assert.NoError(t, manager.SetClockJumpPolicy(ClockJumpIgnore))
+	manager.Lock()
+	manager.clockJumped(-30 * time.Minute)
+	manager.Unlock()
+	assert.Equal(t, cadenceNext.Add(-30*time.Minute), nextExec(cadenceJob.ID), "Expected no shift when ignoring jumps")
+}
@@ -0,0 +1,76 @@
+package taskman
+
+import (
+	"sync"
+	"time"
+)
+
+// fakeClock is a Clock with time only passing when advanced, as taskmantest.Clock, which cannot be
+// imported by the package's own tests.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+// fakeWaiter is a pending channel returned by After.
+type fakeWaiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// newFakeClock creates a fakeClock set to the start time.
+func newFakeClock(start time.Time) *fakeClock {
+	return &fakeClock{now: start}
+}
+
+// Now returns the clock's current time.
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel receiving the clock's time once it has been advanced by the duration.
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{deadline: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward by the duration, firing channels returned by After which have
+// reached their deadline.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if !w.deadline.After(c.now) {
+			w.ch <- c.now
+		} else {
+			pending = append(pending, w)
+		}
+	}
+	c.waiters = pending
+}
+
+// wake fires all pending channels returned by After, regardless of their deadline, for a
+// TaskManager waiting on a deadline set just before the clock was advanced to check the time again.
+func (c *fakeClock) wake() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, w := range c.waiters {
+		w.ch <- c.now
+	}
+	c.waiters = nil
+}
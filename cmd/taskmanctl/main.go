@@ -0,0 +1,308 @@
+// Command taskmanctl controls a TaskManager through the admin endpoints of the httpadmin package,
+// listing jobs, showing the queue depth, triggering, pausing, resuming and removing jobs, resizing
+// the worker pool and watching metrics live.
+//
+// Usage:
+//
+//	taskmanctl [-addr url] <command> [arguments]
+//
+// The address defaults to the TASKMAN_ADDR environment variable, or http://localhost:8080, and
+// includes any prefix the admin handler is mounted under.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jkbrsn/go-taskman/httpadmin"
+)
+
+// usage describes the commands of taskmanctl.
+const usage = `Usage: taskmanctl [-addr url] <command> [arguments]
+
+Commands:
+  jobs                   list all scheduled jobs
+  job <id>               show a job and its execution statistics
+  trigger <id>           execute a job immediately
+  pause <id>             pause a job
+  resume <id>            resume a paused job
+  remove <id>            remove a job
+  queue                  show the queue depth
+  metrics [-watch dur]   show the metrics, refreshing every dur if set
+  workers [count]        show or set the worker count
+
+Flags:
+`
+
+// defaultAddr is the address used if neither -addr nor TASKMAN_ADDR is set.
+const defaultAddr = "http://localhost:8080"
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "taskmanctl:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// run parses the arguments and executes their command, writing its output to stdout.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("taskmanctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+	addr := flags.String("addr", envOr("TASKMAN_ADDR", defaultAddr), "base URL of the admin endpoints")
+	timeout := flags.Duration("timeout", 5*time.Second, "timeout of each request")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("no command given")
+	}
+
+	c := &cli{client: httpadmin.NewClient(*addr, nil), timeout: *timeout, out: stdout}
+	command, args := flags.Arg(0), flags.Args()[1:]
+	switch command {
+	case "jobs":
+		return c.jobs(ctx)
+	case "job":
+		return withJobID(args, func(jobID string) error { return c.job(ctx, jobID) })
+	case "trigger":
+		return withJobID(args, func(jobID string) error { return c.action(ctx, c.client.TriggerJob, jobID, "Triggered") })
+	case "pause":
+		return withJobID(args, func(jobID string) error { return c.action(ctx, c.client.PauseJob, jobID, "Paused") })
+	case "resume":
+		return withJobID(args, func(jobID string) error { return c.action(ctx, c.client.ResumeJob, jobID, "Resumed") })
+	case "remove":
+		return withJobID(args, func(jobID string) error { return c.action(ctx, c.client.RemoveJob, jobID, "Removed") })
+	case "queue":
+		return c.queue(ctx)
+	case "metrics":
+		return c.metrics(ctx, args, stderr)
+	case "workers":
+		return c.workers(ctx, args)
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+// cli executes the commands of taskmanctl against the admin endpoints.
+type cli struct {
+	client  *httpadmin.Client
+	timeout time.Duration
+	out     io.Writer
+}
+
+// jobs lists all scheduled jobs.
+func (c *cli) jobs(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	jobs, err := c.client.Jobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCADENCE\tNEXT EXEC\tTASKS\tRUNNING\tPAUSED")
+	for _, job := range jobs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%t\n", job.ID, job.Cadence, formatTime(job.NextExec),
+			job.TaskCount, job.Running, job.Paused)
+	}
+	return w.Flush()
+}
+
+// job shows a job and its execution statistics.
+func (c *cli) job(ctx context.Context, jobID string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	job, err := c.client.Job(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	stats, err := c.client.Stats(ctx, jobID)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%s\n", job.ID)
+	fmt.Fprintf(w, "Cadence:\t%s\n", job.Cadence)
+	fmt.Fprintf(w, "Next exec:\t%s\n", formatTime(job.NextExec))
+	fmt.Fprintf(w, "Tasks:\t%d\n", job.TaskCount)
+	fmt.Fprintf(w, "Running:\t%d\n", job.Running)
+	fmt.Fprintf(w, "Paused:\t%t\n", job.Paused)
+	fmt.Fprintf(w, "Runs:\t%d (%d failed, %d consecutive, %d skipped)\n", stats.TotalRuns, stats.FailedRuns,
+		stats.ConsecutiveFailures, stats.SkippedRuns)
+	fmt.Fprintf(w, "Last run:\t%s (%s)\n", formatTime(stats.LastRun), stats.LastDuration)
+	fmt.Fprintf(w, "Average duration:\t%s\n", stats.AverageDuration)
+	fmt.Fprintf(w, "Task time:\t%s wall, %s CPU\n", stats.TotalTaskTime, stats.TotalCPUTime)
+	fmt.Fprintf(w, "Overrunning:\t%t\n", stats.Overrunning)
+	fmt.Fprintf(w, "Lateness:\tp50 %s, p95 %s, max %s\n", stats.Lateness.P50, stats.Lateness.P95, stats.Lateness.Max)
+	if stats.LastError != "" {
+		fmt.Fprintf(w, "Last error:\t%s\n", stats.LastError)
+	}
+	for _, taskID := range slices.Sorted(maps.Keys(stats.TaskFailures)) {
+		fmt.Fprintf(w, "Task %s failures:\t%d\n", taskID, stats.TaskFailures[taskID])
+	}
+	return w.Flush()
+}
+
+// action applies an action to a job, reporting it with the past tense verb.
+func (c *cli) action(ctx context.Context, action func(context.Context, string) error, jobID, verb string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	if err := action(ctx, jobID); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "%s job %s\n", verb, jobID)
+	return nil
+}
+
+// queue shows the queue depth.
+func (c *cli) queue(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	metrics, err := c.client.Metrics(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Queued jobs:\t%d\n", metrics.QueuedJobs)
+	fmt.Fprintf(w, "Queued tasks:\t%d\n", metrics.QueuedTasks)
+	fmt.Fprintf(w, "Max job width:\t%d\n", metrics.QueueMaxJobWidth)
+	return w.Flush()
+}
+
+// metrics shows the metrics, refreshing them at the interval of the -watch flag until the context
+// is cancelled.
+func (c *cli) metrics(ctx context.Context, args []string, stderr io.Writer) error {
+	flags := flag.NewFlagSet("metrics", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	watch := flags.Duration("watch", 0, "refresh the metrics at this interval")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *watch < 0 {
+		return errors.New("invalid watch interval, must not be negative")
+	}
+
+	if err := c.printMetrics(ctx); err != nil || *watch == 0 {
+		return err
+	}
+	ticker := time.NewTicker(*watch)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			fmt.Fprintln(c.out)
+			if err := c.printMetrics(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// printMetrics fetches and prints the metrics once.
+func (c *cli) printMetrics(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	metrics, err := c.client.Metrics(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Time:\t%s\n", formatTime(time.Now()))
+	fmt.Fprintf(w, "Queued jobs:\t%d\n", metrics.QueuedJobs)
+	fmt.Fprintf(w, "Queued tasks:\t%d\n", metrics.QueuedTasks)
+	fmt.Fprintf(w, "Overrunning jobs:\t%d\n", metrics.JobsOverrunning)
+	fmt.Fprintf(w, "Dispatch lateness:\tp50 %s, p95 %s, max %s\n", metrics.DispatchLateness.P50,
+		metrics.DispatchLateness.P95, metrics.DispatchLateness.Max)
+	fmt.Fprintf(w, "Saturation:\t%d runs shed, %d tasks spilled\n", metrics.RunsShed, metrics.TasksSpilled)
+	fmt.Fprintf(w, "Coalesced runs:\t%d\n", metrics.RunsCoalesced)
+	fmt.Fprintf(w, "Blocked time:\t%s dispatching tasks, %s delivering results\n", metrics.DispatchBlocked,
+		metrics.ResultsBlocked)
+	fmt.Fprintf(w, "Task executions:\t%d\n", metrics.TasksTotalExecutions)
+	fmt.Fprintf(w, "Tasks per second:\t%.2f\n", metrics.TasksPerSecond)
+	fmt.Fprintf(w, "Average exec time:\t%s\n", metrics.TaskAverageExecTime)
+	fmt.Fprintf(w, "Total exec time:\t%s wall, %s CPU\n", metrics.TaskTotalExecTime, metrics.TaskTotalCPUTime)
+	fmt.Fprintf(w, "Workers:\t%d running, %d active, %d draining, %d target\n", metrics.WorkersRunning,
+		metrics.WorkersActive, metrics.WorkersDraining, metrics.WorkerCountTarget)
+	fmt.Fprintf(w, "Worker utilization:\t%.2f\n", metrics.WorkerUtilization)
+	fmt.Fprintf(w, "Dropped errors:\t%d\n", metrics.DroppedErrors)
+	fmt.Fprintf(w, "Dropped results:\t%d\n", metrics.DroppedResults)
+	fmt.Fprintf(w, "Dropped exec times:\t%d\n", metrics.DroppedExecTimes)
+	return w.Flush()
+}
+
+// workers shows the worker count, or sets it if a count is given.
+func (c *cli) workers(ctx context.Context, args []string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	switch len(args) {
+	case 0:
+		count, err := c.client.WorkerCount(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "Workers: %d\n", count)
+		return nil
+	case 1:
+		count, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid worker count %q", args[0])
+		}
+		if err := c.client.SetWorkerCount(ctx, count); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "Set workers to %d\n", count)
+		return nil
+	default:
+		return errors.New("usage: workers [count]")
+	}
+}
+
+// withJobID calls fn with the job ID of a command taking exactly one argument.
+func withJobID(args []string, fn func(jobID string) error) error {
+	if len(args) != 1 {
+		return errors.New("expected exactly one job ID")
+	}
+	return fn(args[0])
+}
+
+// formatTime formats a time for display, or "-" for the zero time.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
+
+// envOr returns the value of the environment variable, or fallback if it is unset or empty.
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	taskman "github.com/jkbrsn/go-taskman"
+	"github.com/jkbrsn/go-taskman/httpadmin"
+	"github.com/stretchr/testify/assert"
+)
+
+// funcTask is a task executing a function.
+type funcTask func() error
+
+// Execute executes the function.
+func (f funcTask) Execute() error {
+	return f()
+}
+
+// runCommand runs taskmanctl against the server with the arguments, returning its output.
+func runCommand(ctx context.Context, server *httptest.Server, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	err := run(ctx, append([]string{"-addr", server.URL}, args...), &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func TestRun(t *testing.T) {
+	manager := taskman.New(taskman.WithWorkers(1))
+	defer manager.Stop()
+	server := httptest.NewServer(httpadmin.New(manager))
+	defer server.Close()
+	ctx := context.Background()
+
+	job := taskman.Job{ID: "cli-job", Cadence: time.Hour, NextExec: time.Now().Add(time.Hour),
+		Tasks: []taskman.Task{funcTask(func() error { return nil })}}
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	t.Run("Jobs", func(t *testing.T) {
+		out, err := runCommand(ctx, server, "jobs")
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if assert.Len(t, lines, 2, "Expected a header and one job") {
+			assert.Contains(t, lines[0], "NEXT EXEC")
+			assert.Contains(t, lines[1], "cli-job")
+			assert.Contains(t, lines[1], "1h0m0s")
+		}
+
+		out, err = runCommand(ctx, server, "job", "cli-job")
+		assert.NoError(t, err)
+		assert.Contains(t, out, "Runs:")
+
+		_, err = runCommand(ctx, server, "job")
+		assert.Error(t, err, "Expected an error for a missing job ID")
+		_, err = runCommand(ctx, server, "job", "missing-job")
+		assert.ErrorContains(t, err, "missing-job")
+	})
+
+	t.Run("Job actions", func(t *testing.T) {
+		out, err := runCommand(ctx, server, "pause", "cli-job")
+		assert.NoError(t, err)
+		assert.Equal(t, "Paused job cli-job\n", out)
+		info, err := manager.Job("cli-job")
+		assert.NoError(t, err)
+		assert.True(t, info.Paused)
+
+		_, err = runCommand(ctx, server, "resume", "cli-job")
+		assert.NoError(t, err)
+		_, err = runCommand(ctx, server, "trigger", "cli-job")
+		assert.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			stats, err := manager.JobStats("cli-job")
+			return err == nil && stats.TotalRuns == 1
+		}, 50*time.Millisecond, time.Millisecond, "Expected the triggered job to execute")
+	})
+
+	t.Run("Queue and metrics", func(t *testing.T) {
+		out, err := runCommand(ctx, server, "queue")
+		assert.NoError(t, err)
+		assert.Regexp(t, `Queued jobs:\s+1\n`, out)
+
+		watchCtx, cancel := context.WithTimeout(ctx, 35*time.Millisecond)
+		defer cancel()
+		out, err = runCommand(watchCtx, server, "metrics", "-watch", "10ms")
+		assert.NoError(t, err, "Expected watching to end without error when cancelled")
+		assert.GreaterOrEqual(t, strings.Count(out, "Queued jobs:"), 2, "Expected the metrics to be refreshed")
+	})
+
+	t.Run("Workers", func(t *testing.T) {
+		out, err := runCommand(ctx, server, "workers", "2")
+		assert.NoError(t, err)
+		assert.Equal(t, "Set workers to 2\n", out)
+		assert.Eventually(t, func() bool {
+			out, err := runCommand(ctx, server, "workers")
+			return err == nil && out == "Workers: 2\n"
+		}, 50*time.Millisecond, time.Millisecond, "Expected the worker count to be set")
+
+		_, err = runCommand(ctx, server, "workers", "many")
+		assert.Error(t, err, "Expected an error for an invalid worker count")
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		_, err := runCommand(ctx, server, "remove", "cli-job")
+		assert.NoError(t, err)
+		_, err = manager.Job("cli-job")
+		assert.Error(t, err, "Expected the job to be removed")
+	})
+
+	t.Run("Invalid commands", func(t *testing.T) {
+		_, err := runCommand(ctx, server)
+		assert.Error(t, err, "Expected an error without a command")
+		_, err = runCommand(ctx, server, "unknown")
+		assert.ErrorContains(t, err, "unknown command")
+	})
+}
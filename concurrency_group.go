@@ -0,0 +1,105 @@
+package taskman
+
+import (
+	"errors"
+	"sync"
+)
+
+// concurrencyQuota limits the number of concurrently executing tasks of the jobs in a concurrency
+// group. Tasks received by a worker while the group is at its limit are parked, rather than
+// blocking the worker, and sent to their queue again as tasks of the group finish.
+type concurrencyQuota struct {
+	mu      sync.Mutex
+	limit   int       // Max concurrently executing tasks, 0 for no limit
+	running int       // Number of tasks executing, or unparked to execute
+	parked  []jobTask // Tasks waiting for a slot, in the order they were received
+}
+
+// SetConcurrencyLimit sets the max number of tasks of the jobs in the concurrency group, see
+// Job.ConcurrencyGroup, executing at the same time across all workers, e.g. to cap the concurrent
+// connections to a downstream API regardless of the worker count. Tasks received by a worker
+// while the group is at its limit wait, without occupying the worker, until a task of the group
+// finishes. A limit of 0 removes the group's limit. The limit of a group applies to the runs
+// dispatched after it is set.
+func (tm *TaskManager) SetConcurrencyLimit(group string, limit int) error {
+	if group == "" {
+		return errors.New("invalid concurrency group, must not be empty")
+	}
+	if limit < 0 {
+		return errors.New("invalid concurrency limit, must not be negative")
+	}
+
+	tm.Lock()
+	quota, ok := tm.quotas[group]
+	switch {
+	case limit == 0:
+		delete(tm.quotas, group)
+	case !ok:
+		quota = &concurrencyQuota{}
+		tm.quotas[group] = quota
+	}
+	tm.Unlock()
+
+	if quota != nil {
+		tm.sendUnparked(quota.setLimit(limit))
+	}
+	return nil
+}
+
+// acquire takes a slot of the quota for the task, or parks the task if the quota is at its limit
+// and returns false. Tasks unparked with a slot of their own keep it.
+func (q *concurrencyQuota) acquire(task jobTask) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if task.quotaHeld {
+		return true
+	}
+	if q.limit > 0 && q.running >= q.limit {
+		q.parked = append(q.parked, task)
+		return false
+	}
+	q.running++
+	return true
+}
+
+// release frees the slot of a finished task, and returns the parked tasks given its slot.
+func (q *concurrencyQuota) release() []jobTask {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.running--
+	return q.unpark()
+}
+
+// setLimit changes the limit of the quota, and returns the parked tasks given a slot by it.
+func (q *concurrencyQuota) setLimit(limit int) []jobTask {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.limit = limit
+	return q.unpark()
+}
+
+// unpark takes the parked tasks fitting within the quota's limit, giving each a slot.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (q *concurrencyQuota) unpark() []jobTask {
+	var tasks []jobTask
+	for len(q.parked) > 0 && (q.limit == 0 || q.running < q.limit) {
+		task := q.parked[0]
+		q.parked = q.parked[1:]
+		task.quotaHeld = true
+		q.running++
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+// sendUnparked sends tasks unparked from their quota to the queues of their jobs, without blocking
+// the caller, e.g. the worker which released a slot.
+func (tm *TaskManager) sendUnparked(tasks []jobTask) {
+	for _, task := range tasks {
+		tm.dispatches.Add(1)
+		go func() {
+			defer tm.dispatches.Done()
+			task.run.queue.send(tm.ctx.Done(), task)
+		}()
+	}
+}
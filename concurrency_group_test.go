@@ -0,0 +1,86 @@
+package taskman
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	manager := New(WithWorkers(8), WithConcurrencyLimit("api", 2))
+	defer manager.Stop()
+	assert.Error(t, manager.SetConcurrencyLimit("", 1), "Expected error for an empty group")
+	assert.Error(t, manager.SetConcurrencyLimit("api", -1), "Expected error for a negative limit")
+
+	// Two jobs of three parallel tasks each share the group's limit of two executing tasks
+	tracker := &concurrencyTracker{}
+	for i := range 2 {
+		job := Job{
+			ID:               fmt.Sprintf("api-job-%d", i),
+			Cadence:          time.Hour,
+			NextExec:         time.Now().Add(time.Hour),
+			Tasks:            []Task{tracker.task(20 * time.Millisecond), tracker.task(20 * time.Millisecond), tracker.task(20 * time.Millisecond)},
+			ConcurrencyGroup: "api",
+		}
+		assert.NoError(t, manager.ScheduleJob(job))
+	}
+	// Jobs of other groups are not limited
+	other := &concurrencyTracker{}
+	assert.NoError(t, manager.ScheduleJob(Job{
+		ID:       "other-job",
+		Cadence:  time.Hour,
+		NextExec: time.Now().Add(time.Hour),
+		Tasks:    []Task{other.task(20 * time.Millisecond), other.task(20 * time.Millisecond), other.task(20 * time.Millisecond)},
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, jobID := range []string{"api-job-0", "api-job-1", "other-job"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := manager.RunJobNow(ctx, jobID)
+			assert.NoError(t, err)
+			assert.NoError(t, result.Err())
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(6), tracker.executions.Load(), "Expected all tasks of the group to execute")
+	assert.Equal(t, int32(2), tracker.max.Load(), "Expected at most 2 concurrently executing tasks of the group")
+	assert.Equal(t, int32(3), other.max.Load(), "Expected the tasks of other jobs to execute in parallel")
+
+	// Removing the limit releases the parked tasks
+	assert.NoError(t, manager.SetConcurrencyLimit("api", 1))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := manager.RunJobNow(ctx, "api-job-0")
+		assert.NoError(t, err)
+	}()
+	time.Sleep(5 * time.Millisecond)
+	assert.NoError(t, manager.SetConcurrencyLimit("api", 0))
+	<-done
+	assert.Equal(t, int32(9), tracker.executions.Load())
+}
+
+func TestConcurrencyQuota(t *testing.T) {
+	quota := &concurrencyQuota{limit: 1}
+	assert.True(t, quota.acquire(jobTask{index: 0}))
+	assert.False(t, quota.acquire(jobTask{index: 1}), "Expected the task to be parked at the limit")
+	assert.False(t, quota.acquire(jobTask{index: 2}), "Expected the task to be parked at the limit")
+
+	// Releasing a slot unparks the longest waiting task, which keeps its slot
+	unparked := quota.release()
+	assert.Len(t, unparked, 1)
+	assert.Equal(t, 1, unparked[0].index)
+	assert.True(t, quota.acquire(unparked[0]))
+	assert.Equal(t, 1, quota.running)
+
+	assert.Len(t, quota.setLimit(0), 1, "Expected removing the limit to unpark all tasks")
+}
@@ -0,0 +1,51 @@
+package taskman
+
+import (
+	"runtime"
+	"time"
+)
+
+// cpuTimer measures the CPU time of a task executing on the calling goroutine, which is locked to
+// its OS thread while measured so that the thread's CPU time is the task's.
+type cpuTimer struct {
+	start time.Duration // CPU time of the thread when the timer started
+	ok    bool          // True if the CPU time is measured
+}
+
+// startCPUTimer starts measuring the CPU time of the calling goroutine, if supported on the
+// platform. The timer must be stopped on the same goroutine.
+func startCPUTimer() cpuTimer {
+	if !cpuTimeSupported {
+		return cpuTimer{}
+	}
+	runtime.LockOSThread()
+	start, ok := threadCPUTime()
+	if !ok {
+		runtime.UnlockOSThread()
+	}
+	return cpuTimer{start: start, ok: ok}
+}
+
+// stop stops the timer, returning the CPU time used since it started, or 0 if not measured.
+func (t cpuTimer) stop() time.Duration {
+	if !t.ok {
+		return 0
+	}
+	end, ok := threadCPUTime()
+	runtime.UnlockOSThread()
+	if !ok {
+		return 0
+	}
+	return end - t.start
+}
+
+// SetCPUAccounting enables measuring the CPU time of the tasks of jobs, counted in the TotalCPUTime
+// of their JobStats and in the TaskTotalCPUTime metric, e.g. to find the most expensive jobs. CPU
+// time is measured on Linux only, by pinning the worker executing a task to its OS thread, which
+// adds a small overhead to every execution. CPU time used by goroutines a task starts is not
+// counted. Disabled by default.
+func (tm *TaskManager) SetCPUAccounting(enabled bool) {
+	tm.Lock()
+	defer tm.Unlock()
+	tm.measureCPU = enabled
+}
@@ -0,0 +1,18 @@
+package taskman
+
+import (
+	"syscall"
+	"time"
+)
+
+// cpuTimeSupported is true on platforms on which the CPU time of a thread can be measured.
+const cpuTimeSupported = true
+
+// threadCPUTime returns the user and system CPU time used by the calling OS thread.
+func threadCPUTime() (time.Duration, bool) {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_THREAD, &usage); err != nil {
+		return 0, false
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano()), true
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package taskman
+
+import "time"
+
+// cpuTimeSupported is true on platforms on which the CPU time of a thread can be measured.
+const cpuTimeSupported = false
+
+// threadCPUTime is not supported on this platform, and returns false.
+func threadCPUTime() (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,62 @@
+package taskman
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// spin keeps the CPU busy for the duration.
+func spin(d time.Duration) {
+	for start := time.Now(); time.Since(start) < d; {
+	}
+}
+
+func TestCPUTimer(t *testing.T) {
+	timer := startCPUTimer()
+	spin(20 * time.Millisecond)
+	cpu := timer.stop()
+	if !cpuTimeSupported {
+		assert.Zero(t, cpu, "Expected no CPU time where unsupported")
+		return
+	}
+	assert.Positive(t, cpu, "Expected the CPU time of the busy goroutine")
+	assert.Zero(t, cpuTimer{}.stop(), "Expected no CPU time of a timer not started")
+}
+
+func TestCPUAccounting(t *testing.T) {
+	manager := New(WithWorkers(1), WithCPUAccounting())
+	defer manager.Stop()
+
+	job := getMockedJob(1, "busy-job", time.Hour, time.Hour)
+	job.Tasks[0] = MockTask{executeFunc: func() error {
+		spin(20 * time.Millisecond)
+		return nil
+	}}
+	assert.NoError(t, manager.ScheduleJob(job))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := manager.RunJobNow(ctx, job.ID)
+	assert.NoError(t, err)
+
+	stats, err := manager.JobStats(job.ID)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, stats.TotalTaskTime, 20*time.Millisecond, "Expected the wall time of the task")
+	metrics := manager.Metrics()
+	assert.Equal(t, stats.TotalTaskTime, metrics.TaskTotalExecTime)
+	assert.Equal(t, stats.TotalCPUTime, metrics.TaskTotalCPUTime)
+	if cpuTimeSupported {
+		assert.Positive(t, stats.TotalCPUTime, "Expected the CPU time of the task")
+	}
+
+	// Without CPU accounting, only the wall time is recorded
+	manager.SetCPUAccounting(false)
+	_, err = manager.RunJobNow(ctx, job.ID)
+	assert.NoError(t, err)
+	next, err := manager.JobStats(job.ID)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, next.TotalTaskTime, stats.TotalTaskTime+20*time.Millisecond)
+	assert.Equal(t, stats.TotalCPUTime, next.TotalCPUTime, "Expected no CPU time without accounting")
+}
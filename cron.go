@@ -0,0 +1,305 @@
+package taskman
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed cron expression. Each field is stored as a bit mask, where bit n is set
+// if the value n is allowed by the expression.
+type cronSchedule struct {
+	expr string // The expression the schedule was parsed from
+
+	second uint64
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// Set when the day-of-month or day-of-week field was '*' or '?', used to decide how the two
+	// day fields combine. Standard cron matches either field when both are restricted.
+	domStar bool
+	dowStar bool
+}
+
+// cronField describes the valid range and value names of a cron expression field.
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronSecond = cronField{name: "second", min: 0, max: 59}
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day-of-month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Day-of-week accepts both 0 and 7 for Sunday
+	cronDow = cronField{name: "day-of-week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+
+	// Predefined schedules, expanded to their 5-field equivalents
+	cronDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// cronSearchYears limits how far into the future next searches for a matching time, which
+// guarantees termination for expressions that can never match, e.g. "0 0 30 2 *".
+const cronSearchYears = 5
+
+// parseCron parses a standard cron expression. Both the 5-field format (minute, hour,
+// day-of-month, month, day-of-week) and the 6-field format with a leading seconds field are
+// supported, as are the descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight and
+// @hourly. Fields accept '*', '?', single values, ranges (a-b), lists (a,b) and steps (*/n, a-b/n).
+// Month and day-of-week fields also accept three-letter names, e.g. JAN or MON.
+func parseCron(expr string) (*cronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("empty cron expression")
+	}
+	cs := cronSchedule{expr: expr}
+	if strings.HasPrefix(expr, "@") {
+		expanded, ok := cronDescriptors[strings.ToLower(expr)]
+		if !ok {
+			return nil, fmt.Errorf("unknown cron descriptor %q", expr)
+		}
+		expr = expanded
+	}
+
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		// Standard format, run at the start of the minute
+		fields = append([]string{"0"}, fields...)
+	case 6:
+		// Format with seconds
+	default:
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 or 6 fields, got %d", expr, len(fields))
+	}
+
+	var err error
+	if cs.second, _, err = parseCronField(fields[0], cronSecond); err != nil {
+		return nil, err
+	}
+	if cs.minute, _, err = parseCronField(fields[1], cronMinute); err != nil {
+		return nil, err
+	}
+	if cs.hour, _, err = parseCronField(fields[2], cronHour); err != nil {
+		return nil, err
+	}
+	if cs.dom, cs.domStar, err = parseCronField(fields[3], cronDom); err != nil {
+		return nil, err
+	}
+	if cs.month, _, err = parseCronField(fields[4], cronMonth); err != nil {
+		return nil, err
+	}
+	if cs.dow, cs.dowStar, err = parseCronField(fields[5], cronDow); err != nil {
+		return nil, err
+	}
+	// Fold day-of-week 7 into 0, both meaning Sunday
+	if cs.dow&(1<<7) != 0 {
+		cs.dow = cs.dow&^(1<<7) | 1
+	}
+
+	return &cs, nil
+}
+
+// parseCronField parses a single cron field into a bit mask. The returned bool reports whether
+// the field was a plain wildcard.
+func parseCronField(field string, spec cronField) (uint64, bool, error) {
+	if field == "*" || field == "?" {
+		return bitRange(spec.min, spec.max, 1), true, nil
+	}
+
+	var mask uint64
+	for _, part := range strings.Split(field, ",") {
+		bitsForPart, err := parseCronPart(part, spec)
+		if err != nil {
+			return 0, false, err
+		}
+		mask |= bitsForPart
+	}
+	return mask, false, nil
+}
+
+// parseCronPart parses one comma-separated element of a cron field.
+func parseCronPart(part string, spec cronField) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+	step := 1
+	if hasStep {
+		var err error
+		step, err = strconv.Atoi(stepPart)
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step %q in %s field", stepPart, spec.name)
+		}
+	}
+
+	var start, end int
+	switch {
+	case rangePart == "*" || rangePart == "?":
+		start, end = spec.min, spec.max
+	case strings.Contains(rangePart, "-"):
+		lo, hi, _ := strings.Cut(rangePart, "-")
+		var err error
+		if start, err = parseCronValue(lo, spec); err != nil {
+			return 0, err
+		}
+		if end, err = parseCronValue(hi, spec); err != nil {
+			return 0, err
+		}
+		if start > end {
+			return 0, fmt.Errorf("invalid range %q in %s field", rangePart, spec.name)
+		}
+	default:
+		var err error
+		if start, err = parseCronValue(rangePart, spec); err != nil {
+			return 0, err
+		}
+		end = start
+		// A single value with a step, e.g. "5/15", runs from the value to the end of the range
+		if hasStep {
+			end = spec.max
+		}
+	}
+
+	return bitRange(start, end, step), nil
+}
+
+// parseCronValue parses a single numeric or named value of a cron field.
+func parseCronValue(value string, spec cronField) (int, error) {
+	if n, ok := spec.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", value, spec.name)
+	}
+	if n < spec.min || n > spec.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d] in %s field", n, spec.min, spec.max, spec.name)
+	}
+	return n, nil
+}
+
+// bitRange returns a bit mask with every step:th bit set between start and end, inclusive.
+func bitRange(start, end, step int) uint64 {
+	var mask uint64
+	for i := start; i <= end; i += step {
+		mask |= 1 << uint(i)
+	}
+	return mask
+}
+
+// has reports whether the bit for value is set in mask.
+func has(mask uint64, value int) bool {
+	return mask&(1<<uint(value)) != 0
+}
+
+// dayMatches reports whether the day of t matches the day-of-month and day-of-week fields.
+func (cs *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := has(cs.dom, t.Day())
+	dowMatch := has(cs.dow, int(t.Weekday()))
+	if cs.domStar || cs.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// next returns the first time strictly after t that matches the schedule, in t's location. The
+// zero time is returned if no match exists within the search limit.
+func (cs *cronSchedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	// Start at the next whole second
+	t = t.Truncate(time.Second).Add(time.Second)
+	yearLimit := t.Year() + cronSearchYears
+
+	// Whether a field has been advanced, after which all lower fields must start from their minimum
+	advanced := false
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for !has(cs.month, int(t.Month())) {
+		if !advanced {
+			advanced = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 1, 0)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !cs.dayMatches(t) {
+		if !advanced {
+			advanced = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 0, 1)
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for !has(cs.hour, t.Hour()) {
+		if !advanced {
+			advanced = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+		}
+		t = t.Add(time.Hour)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+
+	for !has(cs.minute, t.Minute()) {
+		if !advanced {
+			advanced = true
+			t = t.Truncate(time.Minute)
+		}
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	for !has(cs.second, t.Second()) {
+		t = t.Add(time.Second)
+		if t.Second() == 0 {
+			goto wrap
+		}
+	}
+
+	return t
+}
+
+// interval estimates the time between consecutive executions by looking at the first two
+// executions after t. Used to give cron jobs a cadence for validation and metrics.
+func (cs *cronSchedule) interval(t time.Time) time.Duration {
+	first := cs.next(t)
+	if first.IsZero() {
+		return 0
+	}
+	second := cs.next(first)
+	if second.IsZero() {
+		return 0
+	}
+	return second.Sub(first)
+}
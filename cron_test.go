@@ -0,0 +1,150 @@
+package taskman
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	t.Run("Valid expressions", func(t *testing.T) {
+		validExprs := []string{
+			"* * * * *",
+			"*/5 * * * *",
+			"0 3 * * MON",
+			"0 0 1,15 * *",
+			"30 9-17/2 * * 1-5",
+			"0 0 * JAN-MAR SUN",
+			"*/10 * * * * *",
+			"0 0 12 ? * 7",
+			"@daily",
+			"@HOURLY",
+		}
+		for _, expr := range validExprs {
+			_, err := parseCron(expr)
+			assert.NoError(t, err, "Expected no error parsing %q", expr)
+		}
+	})
+
+	t.Run("Invalid expressions", func(t *testing.T) {
+		invalidExprs := []string{
+			"",
+			"* * * *",
+			"* * * * * * *",
+			"60 * * * *",
+			"* 24 * * *",
+			"* * 0 * *",
+			"* * * 13 *",
+			"* * * * 8",
+			"*/0 * * * *",
+			"5-1 * * * *",
+			"a * * * *",
+			"@fortnightly",
+		}
+		for _, expr := range invalidExprs {
+			_, err := parseCron(expr)
+			assert.Error(t, err, "Expected error parsing %q", expr)
+		}
+	})
+
+	t.Run("Field values", func(t *testing.T) {
+		cs, err := parseCron("*/15 9-11 1,15 * 7")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), cs.second, "Expected 5-field expression to run at second 0")
+		assert.Equal(t, bitRange(0, 45, 15), cs.minute, "Expected minutes 0, 15, 30 and 45")
+		assert.Equal(t, bitRange(9, 11, 1), cs.hour, "Expected hours 9 to 11")
+		assert.Equal(t, uint64(1<<1|1<<15), cs.dom, "Expected days 1 and 15")
+		assert.Equal(t, uint64(1), cs.dow, "Expected day-of-week 7 to be folded into Sunday")
+		assert.False(t, cs.domStar)
+		assert.False(t, cs.dowStar)
+	})
+}
+
+func TestCronNext(t *testing.T) {
+	// Wednesday 2025-01-15 10:20:30 UTC
+	base := time.Date(2025, time.January, 15, 10, 20, 30, 0, time.UTC)
+
+	testCases := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2025, time.January, 15, 10, 21, 0, 0, time.UTC)},
+		{"* * * * * *", time.Date(2025, time.January, 15, 10, 20, 31, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, time.January, 15, 10, 30, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2025, time.January, 16, 3, 0, 0, 0, time.UTC)},
+		{"0 3 * * MON", time.Date(2025, time.January, 20, 3, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted, matching either the 1st of the month or a Friday
+		{"0 0 1 * FRI", time.Date(2025, time.January, 17, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range testCases {
+		cs, err := parseCron(tc.expr)
+		assert.NoError(t, err, "Expected no error parsing %q", tc.expr)
+		assert.Equal(t, tc.expected, cs.next(base), "Unexpected next time for %q", tc.expr)
+	}
+
+	t.Run("Never matching expression", func(t *testing.T) {
+		cs, err := parseCron("0 0 30 2 *")
+		assert.NoError(t, err)
+		assert.True(t, cs.next(base).IsZero(), "Expected zero time for expression that never matches")
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		cs, err := parseCron("*/5 * * * *")
+		assert.NoError(t, err)
+		assert.Equal(t, 5*time.Minute, cs.interval(base))
+	})
+}
+
+func TestScheduleCron(t *testing.T) {
+	manager := NewCustom(1, 1, 1*time.Minute)
+	defer manager.Stop()
+
+	task := MockTask{ID: "cron-task"}
+
+	t.Run("Valid expression", func(t *testing.T) {
+		before := time.Now()
+		jobID, err := manager.ScheduleCron(task, "0 3 * * MON")
+		assert.NoError(t, err, "Expected no error scheduling cron job")
+
+		job := manager.jobQueue.jobs[0]
+		assert.Equal(t, jobID, job.ID)
+		assert.Equal(t, time.Monday, job.NextExec.Weekday(), "Expected next execution on a Monday")
+		assert.Equal(t, 3, job.NextExec.Hour(), "Expected next execution at 03:00")
+		assert.True(t, job.NextExec.After(before), "Expected next execution in the future")
+		// Allow for a DST transition in the local time zone
+		assert.InDelta(t, float64(7*24*time.Hour), float64(job.Cadence), float64(time.Hour), "Expected cadence to be estimated to one week")
+
+		// Rescheduling follows the cron expression rather than the cadence
+		next := job.nextExecAfter(job.NextExec)
+		assert.Equal(t, time.Monday, next.Weekday())
+		assert.Equal(t, 3, next.Hour())
+	})
+
+	t.Run("Invalid expression", func(t *testing.T) {
+		_, err := manager.ScheduleCron(task, "not a cron expression")
+		assert.Error(t, err, "Expected error scheduling invalid cron expression")
+
+		_, err = manager.ScheduleCron(task, "0 0 31 2 *")
+		assert.Error(t, err, "Expected error scheduling cron expression which never matches")
+		assert.Equal(t, 1, manager.jobsInQueue(), "Expected only the valid job in the queue")
+	})
+
+	t.Run("Replaced job", func(t *testing.T) {
+		jobID := manager.jobQueue.jobs[0].ID
+		nextExec := manager.jobQueue.jobs[0].NextExec
+		err := manager.ReplaceJob(Job{ID: jobID, Tasks: []Task{MockTask{ID: "replacing-task"}}})
+		assert.NoError(t, err, "Expected no error replacing cron job")
+
+		// The replacing job keeps executing according to the cron expression
+		job := manager.jobQueue.jobs[0]
+		assert.Equal(t, nextExec, job.NextExec)
+		assert.InDelta(t, float64(7*24*time.Hour), float64(job.Cadence), float64(time.Hour), "Expected cadence to be estimated to one week")
+		next := job.nextExecAfter(job.NextExec)
+		assert.Equal(t, time.Monday, next.Weekday())
+		assert.Equal(t, 3, next.Hour())
+	})
+}
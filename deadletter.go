@@ -0,0 +1,114 @@
+package taskman
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SetDeadLetterThreshold sets the default number of consecutive failed runs after which a job is
+// dead-lettered, applied to jobs without a threshold of their own. A threshold of 0 disables
+// dead-lettering for such jobs.
+func (tm *TaskManager) SetDeadLetterThreshold(threshold int) error {
+	if threshold < 0 {
+		return errors.New("invalid dead letter threshold, must not be negative")
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	tm.deadLetterThreshold = threshold
+	return nil
+}
+
+// DeadLetteredJobs returns a snapshot of all dead-lettered jobs, ordered by ID. Their execution
+// statistics, including the error of the last failed run, are available through JobStats.
+func (tm *TaskManager) DeadLetteredJobs() []JobInfo {
+	tm.RLock()
+	jobs := make([]JobInfo, 0, len(tm.deadLetters))
+	for _, job := range tm.deadLetters {
+		jobs = append(jobs, job.info())
+	}
+	tm.RUnlock()
+
+	slices.SortFunc(jobs, func(a, b JobInfo) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return jobs
+}
+
+// RequeueJob moves a dead-lettered job back into the queue, executing it as soon as possible.
+// The job's consecutive failures are reset, while the rest of its statistics are kept. Requeueing
+// a job in a full queue returns ErrQueueFull, regardless of the overflow policy.
+func (tm *TaskManager) RequeueJob(jobID string) error {
+	tm.Lock()
+	defer tm.Unlock()
+
+	select {
+	case <-tm.ctx.Done():
+		return ErrManagerStopped
+	default:
+	}
+
+	job, ok := tm.deadLetters[jobID]
+	if !ok {
+		return fmt.Errorf("dead-lettered job with ID %s not found", jobID)
+	}
+	if tm.queueFull() {
+		return ErrQueueFull
+	}
+	delete(tm.deadLetters, jobID)
+	job.state.stats.resetFailures()
+
+	now := tm.now()
+	job.scheduled = now
+	if job.cron != nil {
+		job.scheduled = job.cron.next(now)
+	}
+	job.NextExec = job.scheduled
+	job.awaiting = false
+
+	// Update task metrics and scale the worker pool, as when scheduling the job
+	if job.pooled() {
+		taskCount := len(job.Tasks)
+		tm.metrics.updateTaskMetrics(taskCount, job.Cadence)
+		tm.scaleWorkerPool(taskCount)
+	}
+
+	heap.Push(&tm.jobQueue, job)
+	if job.Dedicated {
+		tm.wakeDedicated(job)
+	}
+	tm.logger.Info("Requeued dead-lettered job", "jobID", jobID)
+
+	// Signal the run loop that the job is due
+	tm.wakeRunLoop()
+	return nil
+}
+
+// deadLetterThresholdOf returns the number of consecutive failed runs after which the job is
+// dead-lettered, or 0 if the job is never dead-lettered.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) deadLetterThresholdOf(job *Job) int {
+	if job.DeadLetterThreshold > 0 {
+		return job.DeadLetterThreshold
+	}
+	return tm.deadLetterThreshold
+}
+
+// deadLetterJob moves a job from the queue to the dead-lettered jobs. Runs already executing are
+// left to finish, and the job's context is kept so that it can be requeued. The job's record is
+// kept in the job store, so the job is scheduled again if restored.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) deadLetterJob(job *Job) error {
+	if err := tm.removeJob(job); err != nil {
+		return err
+	}
+	job.delayed = false
+	job.deferred = false
+	tm.deadLetters[job.ID] = job
+	tm.logger.Warn("Dead-lettered job after consecutive failures", "jobID", job.ID,
+		"failures", job.state.stats.snapshot().ConsecutiveFailures)
+	return nil
+}
@@ -0,0 +1,151 @@
+package taskman
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingJob returns a job whose single task fails until succeed is set.
+func failingJob(jobID string, cadence time.Duration, executions *atomic.Int32, succeed *atomic.Bool) Job {
+	return Job{
+		ID:       jobID,
+		Cadence:  cadence,
+		NextExec: time.Now(),
+		Tasks: []Task{MockTask{ID: "failing-task", executeFunc: func() error {
+			executions.Add(1)
+			if succeed.Load() {
+				return nil
+			}
+			return errors.New("task failed")
+		}}},
+	}
+}
+
+func TestDeadLetterJob(t *testing.T) {
+	t.Run("Job threshold", func(t *testing.T) {
+		manager := NewCustom(2, 8, 1*time.Minute)
+		defer manager.Stop()
+
+		var executions atomic.Int32
+		var succeed atomic.Bool
+		job := failingJob("dead-letter-job", 5*time.Millisecond, &executions, &succeed)
+		job.DeadLetterThreshold = 3
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(3), executions.Load(), "Expected the job to stop executing after three failures")
+
+		_, err := manager.Job("dead-letter-job")
+		assert.Error(t, err, "Expected the job to be removed from the queue")
+		deadLettered := manager.DeadLetteredJobs()
+		if assert.Len(t, deadLettered, 1) {
+			assert.Equal(t, "dead-letter-job", deadLettered[0].ID)
+		}
+		stats, err := manager.JobStats("dead-letter-job")
+		assert.NoError(t, err, "Expected dead-lettered jobs to remain inspectable")
+		assert.Equal(t, 3, stats.ConsecutiveFailures)
+		assert.EqualError(t, stats.LastError, "job dead-letter-job, task 0, attempt 1: task failed")
+	})
+
+	t.Run("Manager default", func(t *testing.T) {
+		manager := New(WithWorkers(2), WithDeadLetterThreshold(2))
+		defer manager.Stop()
+
+		var executions atomic.Int32
+		var succeed atomic.Bool
+		assert.NoError(t, manager.ScheduleJob(failingJob("default-job", 5*time.Millisecond, &executions, &succeed)))
+
+		time.Sleep(40 * time.Millisecond)
+		assert.Equal(t, int32(2), executions.Load(), "Expected the default threshold to apply")
+		assert.Len(t, manager.DeadLetteredJobs(), 1)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		manager := NewCustom(2, 8, 1*time.Minute)
+		defer manager.Stop()
+
+		var executions atomic.Int32
+		var succeed atomic.Bool
+		assert.NoError(t, manager.ScheduleJob(failingJob("failing-job", 5*time.Millisecond, &executions, &succeed)))
+
+		time.Sleep(40 * time.Millisecond)
+		assert.Greater(t, executions.Load(), int32(3), "Expected the job to keep executing without a threshold")
+		assert.Empty(t, manager.DeadLetteredJobs())
+	})
+
+	t.Run("Successful runs reset failures", func(t *testing.T) {
+		manager := NewCustom(2, 8, 1*time.Minute)
+		defer manager.Stop()
+
+		var executions atomic.Int32
+		job := Job{ID: "flaky-job", Cadence: 5 * time.Millisecond, NextExec: time.Now(), DeadLetterThreshold: 2,
+			Tasks: []Task{MockTask{executeFunc: func() error {
+				if executions.Add(1)%2 == 0 {
+					return errors.New("task failed")
+				}
+				return nil
+			}}}}
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		time.Sleep(40 * time.Millisecond)
+		assert.Empty(t, manager.DeadLetteredJobs(), "Expected alternating failures to not dead-letter the job")
+	})
+
+	t.Run("Invalid threshold", func(t *testing.T) {
+		manager := NewCustom(1, 1, 1*time.Minute)
+		defer manager.Stop()
+
+		job := getMockedJob(1, "invalid-threshold-job", time.Second, 0)
+		job.DeadLetterThreshold = -1
+		assert.Error(t, manager.ScheduleJob(job), "Expected error for negative dead letter threshold")
+		assert.Error(t, manager.SetDeadLetterThreshold(-1), "Expected error for negative default threshold")
+	})
+}
+
+func TestRequeueJob(t *testing.T) {
+	manager := NewCustom(2, 8, 1*time.Minute)
+	defer manager.Stop()
+
+	var executions atomic.Int32
+	var succeed atomic.Bool
+	job := failingJob("requeue-job", 5*time.Millisecond, &executions, &succeed)
+	job.DeadLetterThreshold = 1
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Len(t, manager.DeadLetteredJobs(), 1)
+	job.NextExec = time.Now().Add(time.Hour)
+	assert.ErrorIs(t, manager.ScheduleJob(job), ErrDuplicateJobID, "Expected error scheduling a job with the ID of a dead-lettered job")
+
+	succeed.Store(true)
+	assert.NoError(t, manager.RequeueJob("requeue-job"))
+	assert.Error(t, manager.RequeueJob("requeue-job"), "Expected error requeueing a job that is not dead-lettered")
+	assert.Empty(t, manager.DeadLetteredJobs())
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Greater(t, executions.Load(), int32(2), "Expected the requeued job to execute again")
+	stats, err := manager.JobStats("requeue-job")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.FailedRuns, "Expected the statistics to be kept")
+	assert.Zero(t, stats.ConsecutiveFailures)
+}
+
+func TestRemoveDeadLetteredJob(t *testing.T) {
+	manager := NewCustom(2, 8, 1*time.Minute)
+	defer manager.Stop()
+
+	var executions atomic.Int32
+	var succeed atomic.Bool
+	job := failingJob("removed-job", 5*time.Millisecond, &executions, &succeed)
+	job.DeadLetterThreshold = 1
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	time.Sleep(20 * time.Millisecond)
+	assert.NoError(t, manager.RemoveJob("removed-job"), "Expected dead-lettered jobs to be removable")
+	assert.Empty(t, manager.DeadLetteredJobs())
+	assert.Error(t, manager.RequeueJob("removed-job"))
+}
@@ -0,0 +1,153 @@
+package taskman
+
+import (
+	"context"
+	"time"
+)
+
+// dedicatedRunner dispatches the runs of a Dedicated job on a goroutine of its own, to a worker
+// pool executing only the job's tasks, so that neither the run loop nor the default worker pool
+// being saturated can delay the job's runs.
+type dedicatedRunner struct {
+	ctx   context.Context // Context of the job, done when the job is removed
+	state *jobState       // Runtime state of the job, identifying it across replacements
+	pool  *workerPool     // Worker pool executing the job's tasks
+	queue *taskQueue      // Queue to send the job's tasks to the pool's workers
+	wake  chan struct{}   // Channel to signal the runner that the job may be due
+	done  chan struct{}   // Channel closed when the runner has exited and its pool has stopped
+}
+
+// startDedicated starts the runner of a Dedicated job, with a worker for each of the job's tasks
+// executed in parallel.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) startDedicated(job *Job) {
+	workers := 1
+	if job.ExecutionMode == ExecutionParallel {
+		workers = len(job.Tasks)
+	}
+
+	// Execution times only inform the scaling of the default pool, so those of the job's workers
+	// are sent to a channel without receivers, and discarded
+	queue := newTaskQueue(1, len(job.Tasks))
+	poolDone := make(chan struct{})
+	runner := &dedicatedRunner{
+		ctx:   job.ctx,
+		state: job.state,
+		pool:  newWorkerPool(workers, tm.errorFan.in, make(chan time.Duration), queue, poolDone, tm.logger),
+		queue: queue,
+		wake:  make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+	if tm.workerLifecycle != nil {
+		runner.pool.lifecycle.Store(tm.workerLifecycle)
+	}
+	tm.dedicated[job.state] = runner
+	go tm.runDedicated(job.ID, runner)
+	tm.logger.Debug("Started dedicated runner of job", "jobID", job.ID, "workers", workers)
+}
+
+// runDedicated dispatches the runs of a Dedicated job as they become due, until the job is removed
+// and its last run has finished, or the TaskManager is stopped.
+func (tm *TaskManager) runDedicated(jobID string, runner *dedicatedRunner) {
+	defer func() {
+		runner.pool.stop()
+		tm.Lock()
+		delete(tm.dedicated, runner.state)
+		tm.Unlock()
+		close(runner.done)
+	}()
+
+	for {
+		tm.Lock()
+		if runner.ctx.Err() != nil {
+			finished := tm.ctx.Err() != nil || runner.state.running == 0
+			tm.Unlock()
+			if finished {
+				return
+			}
+			// Wait for the job's last run to finish
+			select {
+			case <-runner.wake:
+			case <-tm.ctx.Done():
+			}
+			continue
+		}
+
+		// The job is not in the queue while dead-lettered, or once removed while its last run
+		// finishes, and may have been scheduled again under the same ID with a runner of its own
+		var job *Job
+		if index, err := tm.jobQueue.JobInQueue(jobID); err == nil && tm.jobQueue.jobs[index].state == runner.state {
+			job = tm.jobQueue.jobs[index]
+		}
+
+		var due <-chan time.Time
+		if job != nil && !job.suspended() && !tm.drainedJob(job) {
+			now := tm.observeClock()
+			delay := job.NextExec.Sub(now)
+			if delay <= 0 {
+				if !tm.dispatchDedicated(job, now) {
+					return
+				}
+				continue
+			}
+			due = tm.clock.After(min(delay, clockCheckInterval))
+		}
+		tm.Unlock()
+
+		// Wait until the job is due, may have become due, or is removed
+		select {
+		case <-due:
+		case <-runner.wake:
+		case <-runner.ctx.Done():
+		}
+	}
+}
+
+// dispatchDedicated takes the due run of a Dedicated job and dispatches it, releasing the lock
+// before dispatching. Returns false if the TaskManager was stopped before the run was dispatched.
+// Note: must be called while holding the mutex lock, which is released on return.
+func (tm *TaskManager) dispatchDedicated(job *Job, now time.Time) bool {
+	run, drop, _ := tm.takeDueRun(job, now)
+	store := tm.store
+	tm.Unlock()
+
+	var runs []dueRun
+	var dropped []droppedJob
+	if run != nil {
+		runs = append(runs, *run)
+	}
+	if drop != nil {
+		dropped = append(dropped, *drop)
+	}
+	return tm.dispatchDueRuns(store, runs, dropped)
+}
+
+// wakeDedicated signals the runner of a Dedicated job that the job may be due, or that one of its
+// runs has finished.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) wakeDedicated(job *Job) {
+	runner, ok := tm.dedicated[job.state]
+	if !ok {
+		return
+	}
+	select {
+	case runner.wake <- struct{}{}:
+	default:
+		// A wake-up is already pending
+	}
+}
+
+// stopDedicated waits for the runners of all Dedicated jobs to exit, once the TaskManager is
+// stopped.
+func (tm *TaskManager) stopDedicated() {
+	tm.RLock()
+	runners := make([]*dedicatedRunner, 0, len(tm.dedicated))
+	for _, runner := range tm.dedicated {
+		runners = append(runners, runner)
+	}
+	tm.RUnlock()
+
+	for _, runner := range runners {
+		<-runner.done
+	}
+}
@@ -0,0 +1,121 @@
+package taskman
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedicatedJob(t *testing.T) {
+	t.Run("Executes while the worker pool is saturated", func(t *testing.T) {
+		manager := New(WithWorkers(1), WithWorkerBounds(1, 1))
+		defer manager.Stop()
+
+		// Occupy the only worker of the pool
+		release := make(chan struct{})
+		defer close(release)
+		blocked := make(chan struct{})
+		_, err := manager.ScheduleOnce(SimpleContextTask{func(ctx context.Context) error {
+			close(blocked)
+			<-release
+			return nil
+		}}, 0)
+		assert.NoError(t, err)
+		<-blocked
+
+		executed := make(chan struct{}, 8)
+		job := Job{
+			ID:        "heartbeat",
+			Cadence:   10 * time.Millisecond,
+			NextExec:  time.Now().Add(10 * time.Millisecond),
+			Dedicated: true,
+			Tasks: []Task{MockTask{executeFunc: func() error {
+				executed <- struct{}{}
+				return nil
+			}}},
+		}
+		assert.NoError(t, manager.ScheduleJob(job))
+		for range 3 {
+			select {
+			case <-executed:
+			case <-time.After(100 * time.Millisecond):
+				t.Fatal("Expected the dedicated job to execute")
+			}
+		}
+
+		info, err := manager.Job(job.ID)
+		assert.NoError(t, err)
+		assert.True(t, info.NextExec.After(job.NextExec), "Expected the job to be rescheduled")
+		assert.Equal(t, 0, manager.Metrics().QueueMaxJobWidth, "Expected the job to be excluded from the pool's metrics")
+	})
+
+	t.Run("Stops its runner when removed", func(t *testing.T) {
+		manager := New(WithWorkers(1))
+		defer manager.Stop()
+
+		job := getMockedJob(2, "dedicated-job", time.Hour, time.Hour)
+		job.Dedicated = true
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		result, err := manager.RunJobNow(ctx, job.ID)
+		assert.NoError(t, err)
+		assert.Len(t, result.TaskResults, 2)
+
+		assert.NoError(t, manager.RemoveJob(job.ID))
+		assert.Eventually(t, func() bool {
+			manager.RLock()
+			defer manager.RUnlock()
+			return len(manager.dedicated) == 0
+		}, 100*time.Millisecond, time.Millisecond, "Expected the runner to exit")
+	})
+
+	t.Run("Resumes paused job", func(t *testing.T) {
+		manager := New(WithWorkers(1))
+		defer manager.Stop()
+
+		executed := make(chan struct{}, 8)
+		job := Job{
+			ID:        "paused-job",
+			Cadence:   5 * time.Millisecond,
+			NextExec:  time.Now().Add(5 * time.Millisecond),
+			Dedicated: true,
+			Tasks: []Task{MockTask{executeFunc: func() error {
+				executed <- struct{}{}
+				return nil
+			}}},
+		}
+		assert.NoError(t, manager.ScheduleJob(job))
+		assert.NoError(t, manager.PauseJob(job.ID))
+		select {
+		case <-executed:
+			t.Fatal("Expected the paused job not to execute")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		assert.NoError(t, manager.ResumeJob(job.ID))
+		select {
+		case <-executed:
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("Expected the resumed job to execute")
+		}
+	})
+
+	t.Run("Rejects invalid jobs", func(t *testing.T) {
+		manager := New(WithWorkers(1), WithWorkerGroup("io", 1))
+		defer manager.Stop()
+
+		job := getMockedJob(1, "grouped-job", time.Hour, time.Hour)
+		job.Dedicated = true
+		job.Group = "io"
+		assert.ErrorContains(t, manager.ScheduleJob(job), "worker group")
+
+		job = getMockedJob(1, "replaced-job", time.Hour, time.Hour)
+		assert.NoError(t, manager.ScheduleJob(job))
+		job.Dedicated = true
+		assert.Error(t, manager.ReplaceJob(job), "Expected an error making a job dedicated by replacing it")
+	})
+}
@@ -0,0 +1,133 @@
+package taskman
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ScheduleChain schedules the jobs as a pipeline, in which each job after the first depends on the
+// job before it, executing once it has completed successfully. The first job executes according to
+// its own schedule. If a job fails to schedule, the jobs of the chain already scheduled are removed.
+func (tm *TaskManager) ScheduleChain(jobs ...Job) error {
+	if len(jobs) == 0 {
+		return errors.New("chain has no jobs")
+	}
+
+	for i, job := range jobs {
+		if i > 0 {
+			job.DependsOn = []string{jobs[i-1].ID}
+		}
+		if err := tm.ScheduleJob(job); err != nil {
+			for _, scheduled := range jobs[:i] {
+				if err := tm.RemoveJob(scheduled.ID); err != nil {
+					tm.logger.Warn("Failed to remove job of chain", "jobID", scheduled.ID, "error", err)
+				}
+			}
+			return fmt.Errorf("job %s: %w", job.ID, err)
+		}
+	}
+	return nil
+}
+
+// validateDependencies returns an error if the job depends on itself, directly or through the
+// dependencies of the jobs in the queue.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) validateDependencies(job Job) error {
+	if len(job.DependsOn) == 0 {
+		return nil
+	}
+	return dependencyCycle(job, tm.queuedJobs())
+}
+
+// validateBatchDependencies returns an error if any job of a batch depends on itself, directly or
+// through the dependencies of the jobs in the queue and in the batch.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) validateBatchDependencies(batch []Job) error {
+	jobs := tm.queuedJobs()
+	for i := range batch {
+		jobs[batch[i].ID] = &batch[i]
+	}
+	for _, job := range batch {
+		if err := dependencyCycle(job, jobs); err != nil {
+			return fmt.Errorf("job %s: %w", job.ID, err)
+		}
+	}
+	return nil
+}
+
+// queuedJobs returns the jobs in the queue by ID.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) queuedJobs() map[string]*Job {
+	jobs := make(map[string]*Job, tm.jobQueue.Len())
+	for _, j := range tm.jobQueue.jobs {
+		jobs[j.ID] = j
+	}
+	return jobs
+}
+
+// dependencyCycle returns an error if the job depends on itself, directly or through the
+// dependencies of the given jobs.
+func dependencyCycle(job Job, jobs map[string]*Job) error {
+	visited := make(map[string]bool)
+	pending := slices.Clone(job.DependsOn)
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if id == job.ID {
+			return errors.New("invalid dependencies, job depends on itself")
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		if dependency, ok := jobs[id]; ok {
+			pending = append(pending, dependency.DependsOn...)
+		}
+	}
+	return nil
+}
+
+// releaseDependents records a successful run of the job with the given ID, releasing the jobs
+// depending on it whose dependencies have all completed since their last run.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) releaseDependents(jobID string) {
+	released := false
+	for _, job := range tm.jobQueue.jobs {
+		if !slices.Contains(job.DependsOn, jobID) {
+			continue
+		}
+		if job.state.completed == nil {
+			job.state.completed = make(map[string]bool)
+		}
+		job.state.completed[jobID] = true
+		if !job.awaiting || len(job.state.completed) < countUnique(job.DependsOn) {
+			continue
+		}
+
+		tm.logger.Debug("Releasing dependent job", "jobID", job.ID, "dependency", jobID)
+		job.awaiting = false
+		job.scheduled = tm.now()
+		job.NextExec = job.scheduled
+		heap.Fix(&tm.jobQueue, job.index)
+		if job.Dedicated {
+			tm.wakeDedicated(job)
+		}
+		released = true
+	}
+
+	if released {
+		// Signal the run loop that the released jobs are due
+		tm.wakeRunLoop()
+	}
+}
+
+// countUnique returns the number of unique IDs.
+func countUnique(ids []string) int {
+	unique := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+	return len(unique)
+}
@@ -0,0 +1,132 @@
+package taskman
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// executionLog records the order in which jobs execute.
+type executionLog struct {
+	mu   sync.Mutex
+	runs []string
+}
+
+// task returns a task recording an execution of the job, failing with err.
+func (l *executionLog) task(jobID string, err error) Task {
+	return MockTask{ID: jobID, executeFunc: func() error {
+		l.mu.Lock()
+		l.runs = append(l.runs, jobID)
+		l.mu.Unlock()
+		return err
+	}}
+}
+
+// count returns the number of recorded executions of the job.
+func (l *executionLog) count(jobID string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for _, run := range l.runs {
+		if run == jobID {
+			n++
+		}
+	}
+	return n
+}
+
+func TestScheduleChain(t *testing.T) {
+	manager := NewCustom(4, 8, 1*time.Minute)
+	defer manager.Stop()
+
+	log := &executionLog{}
+	err := manager.ScheduleChain(
+		Job{ID: "extract", Cadence: 20 * time.Millisecond, NextExec: time.Now(), Tasks: []Task{log.task("extract", nil)}},
+		Job{ID: "transform", Tasks: []Task{log.task("transform", nil)}},
+		Job{ID: "load", Tasks: []Task{log.task("load", nil)}},
+	)
+	assert.NoError(t, err)
+
+	time.Sleep(50 * time.Millisecond)
+	log.mu.Lock()
+	runs := append([]string(nil), log.runs...)
+	log.mu.Unlock()
+	if assert.GreaterOrEqual(t, len(runs), 6, "Expected at least two runs of the chain") {
+		assert.Equal(t, []string{"extract", "transform", "load", "extract", "transform", "load"}, runs[:6],
+			"Expected each job to execute after the job it depends on")
+	}
+
+	t.Run("Rollback", func(t *testing.T) {
+		err := manager.ScheduleChain(
+			Job{ID: "first", Cadence: time.Minute, NextExec: time.Now().Add(time.Minute), Tasks: []Task{MockTask{}}},
+			Job{ID: "invalid"},
+		)
+		assert.Error(t, err, "Expected error for a job without tasks")
+		_, err = manager.Job("first")
+		assert.Error(t, err, "Expected the scheduled jobs of the chain to be removed")
+	})
+}
+
+func TestDependsOn(t *testing.T) {
+	t.Run("Failed dependency", func(t *testing.T) {
+		manager := NewCustom(2, 8, 1*time.Minute)
+		defer manager.Stop()
+
+		log := &executionLog{}
+		assert.NoError(t, manager.ScheduleJob(Job{ID: "failing", Cadence: 5 * time.Millisecond, NextExec: time.Now(),
+			Tasks: []Task{log.task("failing", errors.New("task failed"))}}))
+		assert.NoError(t, manager.ScheduleJob(Job{ID: "dependent", DependsOn: []string{"failing"},
+			Tasks: []Task{log.task("dependent", nil)}}))
+
+		time.Sleep(30 * time.Millisecond)
+		assert.Greater(t, log.count("failing"), 0)
+		assert.Zero(t, log.count("dependent"), "Expected dependent job to not execute after a failed run")
+	})
+
+	t.Run("Multiple dependencies", func(t *testing.T) {
+		manager := NewCustom(4, 8, 1*time.Minute)
+		defer manager.Stop()
+
+		log := &executionLog{}
+		assert.NoError(t, manager.ScheduleJob(Job{ID: "fast", Cadence: 5 * time.Millisecond, NextExec: time.Now(),
+			Tasks: []Task{log.task("fast", nil)}}))
+		assert.NoError(t, manager.ScheduleJob(Job{ID: "slow", Cadence: 25 * time.Millisecond, NextExec: time.Now().Add(25 * time.Millisecond),
+			Tasks: []Task{log.task("slow", nil)}}))
+		assert.NoError(t, manager.ScheduleJob(Job{ID: "joined", DependsOn: []string{"fast", "slow"},
+			Tasks: []Task{log.task("joined", nil)}}))
+
+		time.Sleep(60 * time.Millisecond)
+		assert.Greater(t, log.count("fast"), log.count("slow"))
+		assert.Equal(t, log.count("slow"), log.count("joined"), "Expected one run per run of the slowest dependency")
+	})
+
+	t.Run("Triggered", func(t *testing.T) {
+		manager := NewCustom(2, 8, 1*time.Minute)
+		defer manager.Stop()
+
+		var executions atomic.Int32
+		assert.NoError(t, manager.ScheduleJob(Job{ID: "dependent", DependsOn: []string{"missing"},
+			Tasks: []Task{MockTask{executeFunc: func() error {
+				executions.Add(1)
+				return nil
+			}}}}))
+		assert.NoError(t, manager.TriggerJob("dependent"))
+		assert.Eventually(t, func() bool { return executions.Load() == 1 }, 50*time.Millisecond, time.Millisecond)
+	})
+
+	t.Run("Cycles", func(t *testing.T) {
+		manager := NewCustom(1, 1, 1*time.Minute)
+		defer manager.Stop()
+
+		assert.Error(t, manager.ScheduleJob(Job{ID: "self", DependsOn: []string{"self"}, Tasks: []Task{MockTask{}}}),
+			"Expected error for a job depending on itself")
+		assert.NoError(t, manager.ScheduleJob(Job{ID: "a", DependsOn: []string{"b"}, Tasks: []Task{MockTask{}}}))
+		assert.NoError(t, manager.ScheduleJob(Job{ID: "b", DependsOn: []string{"c"}, Tasks: []Task{MockTask{}}}))
+		assert.Error(t, manager.ScheduleJob(Job{ID: "c", DependsOn: []string{"a"}, Tasks: []Task{MockTask{}}}),
+			"Expected error for a dependency cycle")
+	})
+}
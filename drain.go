@@ -0,0 +1,80 @@
+package taskman
+
+import "time"
+
+// Drain puts the TaskManager in drain mode, e.g. for handing its jobs over to another instance in
+// a blue/green deployment. Once draining, new jobs and triggered runs are rejected with
+// ErrDraining, and jobs are no longer dispatched after their runs due at the time of the call,
+// while those runs and the tasks already dispatched are left to finish. Jobs are kept scheduled,
+// and in the job store, for the instance taking over. Returns a channel which is closed once the
+// TaskManager is idle, with no runs left to dispatch or executing, or once it is stopped. Unlike
+// Stop, the TaskManager keeps running, so that e.g. its metrics can still be read. Calling it
+// again returns the same channel.
+func (tm *TaskManager) Drain() (<-chan struct{}, error) {
+	tm.Lock()
+	defer tm.Unlock()
+
+	select {
+	case <-tm.ctx.Done():
+		return nil, ErrManagerStopped
+	default:
+	}
+
+	if tm.drained != nil {
+		return tm.drained, nil
+	}
+	tm.drained = make(chan struct{})
+	tm.drainStart = tm.now()
+	tm.logger.Info("Draining task manager", "runs", tm.activeRuns)
+	tm.checkDrained()
+	return tm.drained, nil
+}
+
+// draining returns true if the TaskManager has been put in drain mode.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) draining() bool {
+	return tm.drained != nil
+}
+
+// drainedJob returns true if the job is not dispatched again as the TaskManager is draining, its
+// next run not being due when draining started.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) drainedJob(job *Job) bool {
+	return tm.draining() && job.NextExec.After(tm.drainStart)
+}
+
+// checkDrained closes the drained channel if the TaskManager is draining and idle, with no runs
+// executing and no due runs of jobs left to dispatch.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) checkDrained() {
+	if !tm.draining() || tm.activeRuns > 0 {
+		return
+	}
+	select {
+	case <-tm.drained:
+		return
+	default:
+	}
+	for _, job := range tm.jobQueue.jobs {
+		// Runs delayed by the overlap policy are dispatched once the executing runs finish
+		if !job.suspended() && !tm.drainedJob(job) {
+			return
+		}
+	}
+	tm.logger.Info("Drained task manager", "draining", time.Since(tm.drainStart))
+	close(tm.drained)
+}
+
+// closeDrained closes the drained channel if the TaskManager is draining and has not yet drained,
+// releasing the callers waiting for it once stopped.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) closeDrained() {
+	if !tm.draining() {
+		return
+	}
+	select {
+	case <-tm.drained:
+	default:
+		close(tm.drained)
+	}
+}
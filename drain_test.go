@@ -0,0 +1,92 @@
+package taskman
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrain(t *testing.T) {
+	manager := New(WithWorkers(2))
+	defer manager.Stop()
+
+	release := make(chan struct{})
+	slow := getMockedJob(1, "slow-job", time.Hour, time.Hour)
+	slow.Tasks[0] = MockTask{executeFunc: func() error {
+		<-release
+		return nil
+	}}
+	assert.NoError(t, manager.ScheduleJob(slow))
+	assert.NoError(t, manager.TriggerJob(slow.ID))
+	var ticks atomic.Int32
+	tick := getMockedJob(1, "tick-job", 5*time.Millisecond, 0)
+	tick.Tasks[0] = MockTask{executeFunc: func() error {
+		ticks.Add(1)
+		return nil
+	}}
+	assert.NoError(t, manager.ScheduleJob(tick))
+	assert.Eventually(t, func() bool { return ticks.Load() >= 2 }, time.Second, time.Millisecond)
+
+	// The TaskManager is not idle while the dispatched run executes
+	drained, err := manager.Drain()
+	assert.NoError(t, err)
+	select {
+	case <-drained:
+		t.Fatal("Expected the manager not to be drained while a run executes")
+	case <-time.After(20 * time.Millisecond):
+	}
+	executed := ticks.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, executed, ticks.Load(), "Expected no runs of recurring jobs after draining started")
+
+	// New work is rejected, while scheduled jobs are kept
+	assert.ErrorIs(t, manager.ScheduleJob(getMockedJob(1, "new-job", time.Hour, time.Hour)), ErrDraining)
+	assert.ErrorIs(t, manager.ScheduleJobs([]Job{getMockedJob(1, "new-job", time.Hour, time.Hour)}), ErrDraining)
+	assert.ErrorIs(t, manager.TriggerJob(tick.ID), ErrDraining)
+	assert.Len(t, manager.Jobs(), 2)
+
+	close(release)
+	select {
+	case <-drained:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Expected the manager to be drained once the run finished")
+	}
+	again, err := manager.Drain()
+	assert.NoError(t, err)
+	assert.Equal(t, drained, again, "Expected draining again to return the same channel")
+
+	manager.Stop()
+	_, err = manager.Drain()
+	assert.ErrorIs(t, err, ErrManagerStopped)
+}
+
+func TestDrainStopped(t *testing.T) {
+	manager := New(WithWorkers(1))
+
+	release := make(chan struct{})
+	defer close(release)
+	job := getMockedJob(1, "blocking-job", time.Hour, time.Hour)
+	job.Tasks[0] = MockTask{executeFunc: func() error {
+		<-release
+		return nil
+	}}
+	assert.NoError(t, manager.ScheduleJob(job))
+	assert.NoError(t, manager.TriggerJob(job.ID))
+	drained, err := manager.Drain()
+	assert.NoError(t, err)
+	select {
+	case <-drained:
+		t.Fatal("Expected the manager not to be drained while a run executes")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// Stopping releases the callers waiting for the manager to drain
+	go manager.Stop()
+	select {
+	case <-drained:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Expected the drained channel to be closed once stopped")
+	}
+}
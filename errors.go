@@ -0,0 +1,196 @@
+package taskman
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrInvalidCadence is returned when scheduling a job with a cadence it cannot be executed at.
+	ErrInvalidCadence = errors.New("invalid cadence")
+	// ErrManagerStopped is returned when operating on a TaskManager which has been stopped.
+	ErrManagerStopped = errors.New("task manager is stopped")
+	// ErrDraining is returned when scheduling jobs or triggering runs on a TaskManager which is
+	// draining, see Drain.
+	ErrDraining = errors.New("task manager is draining")
+)
+
+// TaskError is the error reported on the error channel when a task of a scheduled job fails. It
+// identifies the job and task the error originated from, and wraps the error returned by the task.
+type TaskError struct {
+	JobID     string    // ID of the job the task belongs to
+	TaskIndex int       // Index of the task within the job's tasks
+	TaskID    string    // ID of the task, if it is a NamedTask
+	Attempt   int       // Attempt which produced the error, starting at 1
+	Time      time.Time // Time at which the failed attempt started
+	Err       error     // The error returned by the task
+
+	Metadata map[string]string // Metadata of the job, must not be modified
+}
+
+// Error returns the error message, prefixed with the origin of the error.
+func (e *TaskError) Error() string {
+	if e.TaskID != "" {
+		return fmt.Sprintf("job %s, task %d (%s), attempt %d: %v", e.JobID, e.TaskIndex, e.TaskID, e.Attempt, e.Err)
+	}
+	return fmt.Sprintf("job %s, task %d, attempt %d: %v", e.JobID, e.TaskIndex, e.Attempt, e.Err)
+}
+
+// Unwrap returns the error returned by the task.
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError is the error of a task which panicked. For tasks of scheduled jobs it is reported
+// wrapped in a TaskError, so use errors.As to detect panics.
+type PanicError struct {
+	JobID string // ID of the job the panicking task belongs to, empty for tasks outside of jobs
+	Value any    // The value passed to panic
+	Stack []byte // Stack trace of the panicking goroutine
+}
+
+// PanicHandler is called with the ID of the job of a panicking task, the value passed to panic and
+// the stack trace of the panicking goroutine, e.g. for reporting panics to an error tracker.
+type PanicHandler func(jobID string, recovered any, stack []byte)
+
+// Error returns the error message, containing the panic value.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, e.g. a runtime error, otherwise nil.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// unrecoveredPanic is the value a panic is raised again with after being reported, when recovery
+// is disabled, so that workers let it crash the process rather than recovering it once more.
+type unrecoveredPanic struct {
+	value any // The value passed to the original panic
+}
+
+// Error returns the original panic value, printed when the panic crashes the process.
+func (p unrecoveredPanic) Error() string {
+	return fmt.Sprint(p.value)
+}
+
+// SubscribeErrors returns a new channel receiving all errors from task execution, independent of
+// ErrorChannel and of other subscriptions, so that several components can observe errors without
+// taking them from each other. Errors are dropped for a subscriber while its channel's buffer of
+// the given size is full, counted in the DroppedErrors metric. The returned function unsubscribes, closing the channel, which is also
+// closed when the TaskManager stops.
+func (tm *TaskManager) SubscribeErrors(bufferSize int) (<-chan error, func()) {
+	return tm.errorFan.subscribe(max(bufferSize, 0))
+}
+
+// errorFanOut delivers the errors reported by the worker pools to the error channel returned by
+// ErrorChannel, and to every subscription registered with SubscribeErrors.
+type errorFanOut struct {
+	mu      sync.Mutex
+	in      chan error              // Errors reported by the worker pools
+	out     chan error              // Channel returned by ErrorChannel
+	subs    map[chan error]struct{} // Channels of the subscriptions
+	closed  bool                    // True once all channels have been closed
+	dropped atomic.Int64            // Number of deliveries dropped as a channel was full
+	done    chan struct{}           // Closed once all channels have been closed
+}
+
+// newErrorFanOut creates an errorFanOut delivering to out, and starts it.
+func newErrorFanOut(out chan error) *errorFanOut {
+	f := &errorFanOut{
+		in:   make(chan error, cap(out)),
+		out:  out,
+		subs: make(map[chan error]struct{}),
+		done: make(chan struct{}),
+	}
+	go f.run()
+	return f
+}
+
+// run delivers errors until the input channel is closed, after which all channels are closed.
+func (f *errorFanOut) run() {
+	defer close(f.done)
+	for err := range f.in {
+		f.deliver(err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	close(f.out)
+	for ch := range f.subs {
+		close(ch)
+	}
+	f.subs = nil
+	f.closed = true
+}
+
+// deliver sends the error to the error channel and to every subscription, without blocking.
+func (f *errorFanOut) deliver(err error) {
+	select {
+	case f.out <- err:
+	default:
+		// Error channel full, drop the error
+		f.dropped.Add(1)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for ch := range f.subs {
+		select {
+		case ch <- err:
+		default:
+			// Subscription full, drop the error
+			f.dropped.Add(1)
+		}
+	}
+}
+
+// subscribe registers a subscription with the given buffer size, returning its channel and a
+// function which unsubscribes. Subscribing once closed returns a closed channel.
+func (f *errorFanOut) subscribe(bufferSize int) (<-chan error, func()) {
+	ch := make(chan error, bufferSize)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	f.subs[ch] = struct{}{}
+
+	return ch, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if _, ok := f.subs[ch]; ok {
+			delete(f.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// stop closes the input channel and waits for all channels to be closed. Must only be called once
+// all worker pools have stopped.
+func (f *errorFanOut) stop() {
+	close(f.in)
+	<-f.done
+}
+
+// droppedErrors returns the number of errors dropped by the worker pools and by the delivery to
+// the error channel and subscriptions.
+// Note: does not acquire a mutex lock for accessing the worker groups, that is up to the caller.
+func (tm *TaskManager) droppedErrors() int64 {
+	dropped := tm.errorFan.dropped.Load() + tm.workerPool.errorsDropped.Load()
+	for _, group := range tm.groups {
+		dropped += group.pool.errorsDropped.Load()
+	}
+	if reservation := tm.reservation.Load(); reservation != nil {
+		dropped += reservation.group.pool.errorsDropped.Load()
+	}
+	return dropped
+}
@@ -0,0 +1,178 @@
+package taskman
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskError(t *testing.T) {
+	baseErr := errors.New("base error")
+	taskErr := &TaskError{JobID: "job1", TaskIndex: 2, Attempt: 3, Time: time.Now(), Err: baseErr}
+
+	assert.Equal(t, "job job1, task 2, attempt 3: base error", taskErr.Error())
+	assert.ErrorIs(t, taskErr, baseErr, "Expected task error to unwrap to the base error")
+}
+
+func TestPanicError(t *testing.T) {
+	panicErr := &PanicError{Value: "something broke", Stack: []byte("stack")}
+	assert.Equal(t, "panic: something broke", panicErr.Error())
+	assert.Nil(t, panicErr.Unwrap(), "Expected no wrapped error for a non-error panic value")
+
+	runtimeErr := errors.New("runtime error")
+	panicErr = &PanicError{Value: runtimeErr}
+	assert.ErrorIs(t, panicErr, runtimeErr, "Expected panic error to unwrap to an error panic value")
+}
+
+func TestTaskErrorAttribution(t *testing.T) {
+	manager := NewCustom(2, 4, 1*time.Minute)
+	defer manager.Stop()
+
+	before := time.Now()
+	job := getMockedJob(2, "attributed-job", 1*time.Second, 0)
+	job.Tasks[1] = MockTask{ID: "failing-task", executeFunc: func() error {
+		return errors.New("task failed")
+	}}
+	err := manager.ScheduleJob(job)
+	assert.NoError(t, err, "Expected no error scheduling job")
+
+	select {
+	case err := <-manager.ErrorChannel():
+		var taskErr *TaskError
+		assert.ErrorAs(t, err, &taskErr, "Expected error to be a task error")
+		assert.Equal(t, "attributed-job", taskErr.JobID)
+		assert.Equal(t, 1, taskErr.TaskIndex)
+		assert.Equal(t, 1, taskErr.Attempt)
+		assert.False(t, taskErr.Time.Before(before), "Expected the attempt time to be set")
+		assert.EqualError(t, taskErr.Err, "task failed")
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected an error to be reported")
+	}
+}
+
+func TestPanicErrorReporting(t *testing.T) {
+	manager := NewCustom(1, 4, 1*time.Minute)
+	defer manager.Stop()
+
+	jobID, err := manager.ScheduleOnce(MockTask{ID: "panicking-task", executeFunc: func() error {
+		panic("task panicked")
+	}}, 0)
+	assert.NoError(t, err)
+
+	select {
+	case err := <-manager.ErrorChannel():
+		var taskErr *TaskError
+		assert.ErrorAs(t, err, &taskErr, "Expected error to be a task error")
+		var panicErr *PanicError
+		assert.ErrorAs(t, err, &panicErr, "Expected error to wrap a panic error")
+		assert.Equal(t, "task panicked", panicErr.Value)
+		assert.Equal(t, jobID, panicErr.JobID, "Expected the panic error to identify the job")
+		assert.Contains(t, string(panicErr.Stack), "goroutine", "Expected a stack trace")
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected an error to be reported")
+	}
+}
+
+func TestPanicHandler(t *testing.T) {
+	type panicked struct {
+		jobID     string
+		recovered any
+		stack     []byte
+	}
+	handled := make(chan panicked, 1)
+	manager := New(WithWorkers(1), WithPanicHandler(func(jobID string, recovered any, stack []byte) {
+		handled <- panicked{jobID: jobID, recovered: recovered, stack: stack}
+	}))
+	defer manager.Stop()
+
+	jobID, err := manager.ScheduleOnce(MockTask{executeFunc: func() error {
+		panic("task panicked")
+	}}, 0)
+	assert.NoError(t, err)
+
+	select {
+	case p := <-handled:
+		assert.Equal(t, jobID, p.jobID, "Expected the handler to be called with the job ID")
+		assert.Equal(t, "task panicked", p.recovered)
+		assert.Contains(t, string(p.stack), "goroutine", "Expected a stack trace")
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the panic handler to be called")
+	}
+	select {
+	case err := <-manager.ErrorChannel():
+		var panicErr *PanicError
+		assert.ErrorAs(t, err, &panicErr, "Expected the panic to be reported as an error as well")
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected an error to be reported")
+	}
+
+	// Without a handler, panics are only reported as errors
+	manager.SetPanicHandler(nil)
+	_, err = manager.ScheduleOnce(MockTask{executeFunc: func() error {
+		panic("task panicked")
+	}}, 0)
+	assert.NoError(t, err)
+	select {
+	case <-manager.ErrorChannel():
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected an error to be reported")
+	}
+	assert.Empty(t, handled, "Expected the removed handler not to be called")
+}
+
+func TestSubscribeErrors(t *testing.T) {
+	manager := NewCustom(1, 4, 1*time.Minute)
+
+	first, unsubscribeFirst := manager.SubscribeErrors(4)
+	second, unsubscribeSecond := manager.SubscribeErrors(4)
+	defer unsubscribeSecond()
+
+	_, err := manager.ScheduleOnce(MockTask{executeFunc: func() error {
+		return errors.New("task failed")
+	}}, 0)
+	assert.NoError(t, err)
+
+	// Every subscriber, and the error channel, receives the error
+	for name, ch := range map[string]<-chan error{"first": first, "second": second, "error channel": manager.ErrorChannel()} {
+		select {
+		case err := <-ch:
+			assert.ErrorContains(t, err, "task failed", "Expected the task's error for %s", name)
+		case <-time.After(50 * time.Millisecond):
+			t.Fatalf("Expected an error to be delivered to %s", name)
+		}
+	}
+
+	unsubscribeFirst()
+	_, ok := <-first
+	assert.False(t, ok, "Expected the channel to be closed when unsubscribing")
+	assert.NotPanics(t, unsubscribeFirst, "Expected unsubscribing twice to be a no-op")
+
+	manager.Stop()
+	_, ok = <-second
+	assert.False(t, ok, "Expected the channel to be closed when the manager stops")
+	late, _ := manager.SubscribeErrors(1)
+	_, ok = <-late
+	assert.False(t, ok, "Expected subscribing to a stopped manager to return a closed channel")
+}
+
+func TestDroppedErrors(t *testing.T) {
+	manager := NewCustom(1, 1, 1*time.Minute)
+	defer manager.Stop()
+
+	// The error channel is never read, so all errors beyond its buffer are dropped
+	var executions atomic.Int32
+	job := Job{ID: "failing-job", Cadence: 5 * time.Millisecond, NextExec: time.Now(), Tasks: []Task{MockTask{executeFunc: func() error {
+		executions.Add(1)
+		return errors.New("task failed")
+	}}}}
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	assert.Eventually(t, func() bool { return executions.Load() >= 5 }, 100*time.Millisecond, time.Millisecond,
+		"Expected the workers to not be blocked by the unread error channel")
+	assert.Eventually(t, func() bool {
+		return manager.Metrics().DroppedErrors >= int(executions.Load())-1
+	}, 20*time.Millisecond, time.Millisecond, "Expected the errors beyond the buffer to be counted as dropped")
+}
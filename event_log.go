@@ -0,0 +1,84 @@
+package taskman
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// eventJSON is the JSON representation of an Event.
+type eventJSON struct {
+	Time      time.Time         `json:"time"`
+	Type      string            `json:"type"`
+	JobID     string            `json:"job_id,omitempty"`
+	TaskIndex *int              `json:"task_index,omitempty"`
+	TaskID    string            `json:"task_id,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Workers   *int              `json:"workers,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// MarshalJSON encodes the event as a JSON object, with its type as a string, its error as its
+// message, and the task index and worker count only for the events they apply to.
+func (e Event) MarshalJSON() ([]byte, error) {
+	encoded := eventJSON{
+		Time:     e.Time,
+		Type:     e.Type.String(),
+		JobID:    e.JobID,
+		TaskID:   e.TaskID,
+		Metadata: e.Metadata,
+	}
+	switch e.Type {
+	case EventTaskStarted, EventTaskCompleted:
+		encoded.TaskIndex = &e.TaskIndex
+	case EventWorkerScaled:
+		encoded.Workers = &e.Workers
+	}
+	if e.Err != nil {
+		encoded.Error = e.Err.Error()
+	}
+	return json.Marshal(encoded)
+}
+
+// eventLog writes events as JSON lines to a writer.
+type eventLog struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// newEventLog returns an event log writing to the writer.
+func newEventLog(w io.Writer) *eventLog {
+	return &eventLog{enc: json.NewEncoder(w)}
+}
+
+// write writes the event as a JSON line, returning the error of the write.
+func (l *eventLog) write(event Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.enc.Encode(event)
+}
+
+// SetEventLog sets a writer to which every event of the TaskManager is written as a line of JSON,
+// e.g. a file keeping an audit trail of the TaskManager's executions separate from its logs. Unlike
+// the subscriptions of SubscribeEvents, no event is dropped: events are written as they are
+// emitted, so the writer should be fast, e.g. a buffered file, as a slow writer delays the
+// TaskManager. Write errors are logged. The writer is owned by the caller, nil stops the log.
+func (tm *TaskManager) SetEventLog(w io.Writer) {
+	if w == nil {
+		tm.eventLog.Store(nil)
+		return
+	}
+	tm.eventLog.Store(newEventLog(w))
+}
+
+// logEvent writes the event to the event log, if one is set.
+func (tm *TaskManager) logEvent(event Event) {
+	log := tm.eventLog.Load()
+	if log == nil {
+		return
+	}
+	if err := log.write(event); err != nil {
+		tm.logger.Warn("Failed to write event to the event log", "event", event.Type.String(), "error", err)
+	}
+}
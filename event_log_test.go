@@ -0,0 +1,66 @@
+package taskman
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// eventLines returns the lines written to the buffer, decoded as JSON.
+func eventLines(t *testing.T, buf *syncBuffer) []map[string]any {
+	var lines []map[string]any
+	scanner := bufio.NewScanner(strings.NewReader(buf.String()))
+	for scanner.Scan() {
+		var line map[string]any
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line), "Expected every line to be JSON")
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestEventLog(t *testing.T) {
+	var buf syncBuffer
+	manager := New(WithWorkers(1), WithEventLog(&buf))
+	defer manager.Stop()
+
+	job := getMockedJob(1, "logged-job", time.Hour, time.Hour)
+	job.Tasks[0] = MockTask{executeFunc: func() error { return errors.New("failure") }}
+	job.Metadata = map[string]string{"tenant": "acme"}
+	assert.NoError(t, manager.ScheduleJob(job))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := manager.RunJobNow(ctx, job.ID)
+	assert.NoError(t, err)
+
+	types := map[string]map[string]any{}
+	for _, line := range eventLines(t, &buf) {
+		types[line["type"].(string)] = line
+	}
+	for _, eventType := range []string{"JobScheduled", "JobDispatched", "TaskStarted", "TaskCompleted"} {
+		assert.Contains(t, types, eventType)
+	}
+	completed := types["TaskCompleted"]
+	assert.Equal(t, "logged-job", completed["job_id"])
+	assert.Equal(t, float64(0), completed["task_index"], "Expected the task index of task events")
+	assert.Contains(t, completed["error"], "failure")
+	assert.Equal(t, map[string]any{"tenant": "acme"}, completed["metadata"])
+	assert.NotContains(t, types["JobScheduled"], "task_index", "Expected no task index for job events")
+
+	// Events are no longer written once the log is unset
+	manager.SetEventLog(nil)
+	count := len(eventLines(t, &buf))
+	assert.NoError(t, manager.ScheduleJob(getMockedJob(1, "unlogged-job", time.Hour, time.Hour)))
+	assert.Len(t, eventLines(t, &buf), count)
+}
+
+func TestEventMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(Event{Type: EventWorkerScaled, Time: time.Unix(0, 0).UTC(), Workers: 0})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"time": "1970-01-01T00:00:00Z", "type": "WorkerScaled", "workers": 0}`, string(data))
+}
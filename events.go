@@ -0,0 +1,201 @@
+package taskman
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventType identifies the kind of an Event.
+type EventType int
+
+const (
+	// EventJobScheduled is emitted when a job is added to the queue.
+	EventJobScheduled EventType = iota
+	// EventJobDispatched is emitted when a run of a job is dispatched to the worker pool.
+	EventJobDispatched
+	// EventTaskStarted is emitted when a worker starts executing a task of a job.
+	EventTaskStarted
+	// EventTaskCompleted is emitted when a task of a job has finished executing, after any retries.
+	EventTaskCompleted
+	// EventWorkerScaled is emitted when the target worker count of the worker pool changes.
+	EventWorkerScaled
+	// EventQueueEmpty is emitted when the last job has been removed from the queue.
+	EventQueueEmpty
+	// EventJobOverrun is emitted when a job is detected as overrunning, see SetOverrunDetection.
+	EventJobOverrun
+	// EventRunSkipped is emitted when a run of a job is skipped, as it would be dispatched later than
+	// the job's MaxDelay, or is shed as the worker pool is saturated, see SaturationShed.
+	EventRunSkipped
+	// EventJobExpired is emitted when a job is removed as it was not touched for longer than its
+	// idle TTL, see SetJobTTL.
+	EventJobExpired
+)
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case EventJobScheduled:
+		return "JobScheduled"
+	case EventJobDispatched:
+		return "JobDispatched"
+	case EventTaskStarted:
+		return "TaskStarted"
+	case EventTaskCompleted:
+		return "TaskCompleted"
+	case EventWorkerScaled:
+		return "WorkerScaled"
+	case EventQueueEmpty:
+		return "QueueEmpty"
+	case EventJobOverrun:
+		return "JobOverrun"
+	case EventRunSkipped:
+		return "RunSkipped"
+	case EventJobExpired:
+		return "JobExpired"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event is an event in the operation of a TaskManager, as delivered to SubscribeEvents.
+type Event struct {
+	Type      EventType // Kind of the event
+	Time      time.Time // Time of the event, according to the TaskManager's clock
+	JobID     string    // ID of the job of job and task events
+	TaskIndex int       // Index of the task within the job's tasks, for task events
+	TaskID    string    // ID of the task, if it is a NamedTask, for task events
+	Err       error     // Error of the task for EventTaskCompleted, the *OverrunError for EventJobOverrun
+	Workers   int       // Target worker count, for EventWorkerScaled
+
+	Metadata map[string]string // Metadata of the job of job and task events, must not be modified
+}
+
+// SubscribeEvents returns a new channel receiving the events of the TaskManager, for building
+// tooling such as dashboards. Like SubscribeErrors, every subscription receives all events, which
+// are dropped for a subscriber while its channel's buffer of the given size is full. The returned
+// function unsubscribes, closing the channel, which is also closed when the TaskManager stops.
+func (tm *TaskManager) SubscribeEvents(bufferSize int) (<-chan Event, func()) {
+	return tm.events.subscribe(max(bufferSize, 0))
+}
+
+// stream delivers values, e.g. events, to the subscriptions registered for them.
+type stream[T any] struct {
+	mu     sync.RWMutex
+	subs   map[chan T]*subscription[T] // Subscriptions, by their channels
+	closed bool                        // True once all channels have been closed
+
+	policy  atomic.Int32    // ResultOverflowPolicy applied when a subscription is full, dropping new values by default
+	dropped atomic.Int64    // Number of values dropped as a subscription was full
+	blocked atomic.Int64    // Nanoseconds emits spent waiting for full subscriptions, with ResultBlock
+	done    <-chan struct{} // Channel closed when the owner stops, releasing blocked emits, if set
+}
+
+// subscription is a subscription of a stream.
+type subscription[T any] struct {
+	filter func(T) bool  // Filter of the values received, all values if nil
+	done   chan struct{} // Channel closed when unsubscribing, releasing blocked emits
+}
+
+// emit sends the value to every subscription accepting it. When a subscription is full, the value
+// is handled according to the stream's overflow policy.
+func (s *stream[T]) emit(value T) {
+	policy := ResultOverflowPolicy(s.policy.Load())
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for ch, sub := range s.subs {
+		if sub.filter != nil && !sub.filter(value) {
+			continue
+		}
+		select {
+		case ch <- value:
+			continue
+		default:
+		}
+
+		// Subscription full
+		switch policy {
+		case ResultDropOldest:
+			select {
+			case <-ch:
+				s.dropped.Add(1)
+			default:
+			}
+			select {
+			case ch <- value:
+			default:
+				s.dropped.Add(1)
+			}
+		case ResultBlock:
+			blockedSince := time.Now()
+			select {
+			case ch <- value:
+			case <-sub.done:
+			case <-s.done:
+			}
+			s.blocked.Add(int64(time.Since(blockedSince)))
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// subscribe registers a subscription with the given buffer size, returning its channel and a
+// function which unsubscribes. Subscribing once closed returns a closed channel.
+func (s *stream[T]) subscribe(bufferSize int) (<-chan T, func()) {
+	return s.subscribeFiltered(bufferSize, nil)
+}
+
+// subscribeFiltered registers a subscription like subscribe, receiving only the values for which
+// the filter returns true, or all values if the filter is nil.
+func (s *stream[T]) subscribeFiltered(bufferSize int, filter func(T) bool) (<-chan T, func()) {
+	ch := make(chan T, bufferSize)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if s.subs == nil {
+		s.subs = make(map[chan T]*subscription[T])
+	}
+	sub := &subscription[T]{filter: filter, done: make(chan struct{})}
+	s.subs[ch] = sub
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			// Release an emit blocked on the subscription, which holds the read lock
+			close(sub.done)
+
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if _, ok := s.subs[ch]; ok {
+				delete(s.subs, ch)
+				close(ch)
+			}
+		})
+	}
+}
+
+// close closes the channels of all subscriptions, after which values are no longer delivered.
+// Emits blocked on a full subscription must have been released by closing the stream's done
+// channel.
+func (s *stream[T]) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subs {
+		close(ch)
+	}
+	s.subs = nil
+	s.closed = true
+}
+
+// emitEvent emits an event of the given type, at the current time of the TaskManager's clock.
+func (tm *TaskManager) emitEvent(event Event) {
+	event.Time = tm.clock.Now()
+	tm.events.emit(event)
+	tm.logEvent(event)
+}
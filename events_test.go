@@ -0,0 +1,144 @@
+package taskman
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventTypeString(t *testing.T) {
+	assert.Equal(t, "JobScheduled", EventJobScheduled.String())
+	assert.Equal(t, "QueueEmpty", EventQueueEmpty.String())
+	assert.Equal(t, "Unknown", EventType(42).String())
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	manager := NewCustom(1, 4, 1*time.Minute)
+	events, unsubscribe := manager.SubscribeEvents(16)
+
+	_, err := manager.ScheduleOnce(MockTask{executeFunc: func() error { return errors.New("task failed") }}, 0)
+	assert.NoError(t, err)
+
+	// A one-shot job is scheduled, dispatched and executed, after which the queue is empty
+	var received []Event
+	timeout := time.After(100 * time.Millisecond)
+	for len(received) < 5 {
+		select {
+		case event := <-events:
+			if event.Type != EventWorkerScaled {
+				received = append(received, event)
+			}
+		case <-timeout:
+			t.Fatalf("Expected 5 events, received %d", len(received))
+		}
+	}
+	types := make([]EventType, 0, len(received))
+	for _, event := range received {
+		types = append(types, event.Type)
+		assert.False(t, event.Time.IsZero(), "Expected the event time to be set")
+	}
+	assert.ElementsMatch(t, []EventType{EventJobScheduled, EventJobDispatched, EventTaskStarted, EventTaskCompleted, EventQueueEmpty}, types)
+	assert.Equal(t, EventJobScheduled, types[0], "Expected the job to be scheduled first")
+	for _, event := range received {
+		if event.Type == EventTaskCompleted {
+			assert.ErrorContains(t, event.Err, "task failed", "Expected the task's error")
+			assert.Equal(t, received[0].JobID, event.JobID)
+		}
+	}
+
+	unsubscribe()
+	_, ok := <-events
+	assert.False(t, ok, "Expected the channel to be closed when unsubscribing")
+
+	other, _ := manager.SubscribeEvents(1)
+	assert.NoError(t, manager.SetWorkerCount(3))
+	select {
+	case event := <-other:
+		assert.Equal(t, EventWorkerScaled, event.Type)
+		assert.Equal(t, 3, event.Workers)
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected a worker scaling event")
+	}
+
+	manager.Stop()
+	_, ok = <-other
+	assert.False(t, ok, "Expected the channel to be closed when the manager stops")
+}
+
+func TestStreamOverflow(t *testing.T) {
+	received := func(ch <-chan int) []int {
+		var values []int
+		for len(ch) > 0 {
+			values = append(values, <-ch)
+		}
+		return values
+	}
+
+	t.Run("DropNewest", func(t *testing.T) {
+		s := &stream[int]{}
+		ch, unsubscribe := s.subscribe(2)
+		defer unsubscribe()
+		for i := range 4 {
+			s.emit(i)
+		}
+		assert.Equal(t, []int{0, 1}, received(ch))
+		assert.Equal(t, int64(2), s.dropped.Load())
+	})
+
+	t.Run("DropOldest", func(t *testing.T) {
+		s := &stream[int]{}
+		s.policy.Store(int32(ResultDropOldest))
+		ch, unsubscribe := s.subscribe(2)
+		defer unsubscribe()
+		for i := range 4 {
+			s.emit(i)
+		}
+		assert.Equal(t, []int{2, 3}, received(ch))
+		assert.Equal(t, int64(2), s.dropped.Load())
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		done := make(chan struct{})
+		s := &stream[int]{done: done}
+		s.policy.Store(int32(ResultBlock))
+		ch, unsubscribe := s.subscribe(1)
+		s.emit(0)
+
+		// Emitting to the full subscription blocks until the value is received
+		emitted := make(chan struct{})
+		go func() {
+			s.emit(1)
+			close(emitted)
+		}()
+		select {
+		case <-emitted:
+			t.Fatal("Expected the emit to block")
+		case <-time.After(20 * time.Millisecond):
+		}
+		assert.Equal(t, 0, <-ch)
+		<-emitted
+		assert.Equal(t, []int{1}, received(ch))
+		assert.Zero(t, s.dropped.Load(), "Expected no values to be dropped")
+		assert.GreaterOrEqual(t, time.Duration(s.blocked.Load()), 20*time.Millisecond, "Expected the time blocked to be counted")
+
+		// Unsubscribing, or the owner stopping, releases a blocked emit
+		s.emit(2)
+		go func() {
+			s.emit(3)
+		}()
+		time.Sleep(10 * time.Millisecond)
+		unsubscribe()
+		_, unsubscribeOther := s.subscribe(0)
+		defer unsubscribeOther()
+		stopped := make(chan struct{})
+		go func() {
+			s.emit(4)
+			close(stopped)
+		}()
+		close(done)
+		<-stopped
+		s.close()
+	})
+}
@@ -0,0 +1,548 @@
+package taskman
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+)
+
+// OverlapPolicy determines what happens when a job becomes due while previous runs of the same
+// job are still executing.
+type OverlapPolicy int
+
+const (
+	// OverlapAllow dispatches due runs regardless of any runs still executing.
+	OverlapAllow OverlapPolicy = iota
+	// OverlapSkip skips a due run if the job's maximum number of concurrent runs are executing,
+	// rescheduling the job to its following execution.
+	OverlapSkip
+	// OverlapDelay delays a due run until one of the job's executing runs has completed, after
+	// which it is dispatched immediately. Runs becoming due while delayed are coalesced into one.
+	OverlapDelay
+)
+
+// MisfirePolicy determines what happens when a job's run is dispatched so late that one or more of
+// its following executions were missed as well, e.g. after the process was suspended.
+type MisfirePolicy int
+
+const (
+	// MisfireRunOnceNow executes the late run once, in place of all missed executions, and resumes
+	// the job at its first execution after the late run.
+	MisfireRunOnceNow MisfirePolicy = iota
+	// MisfireRunAllMissed executes every missed execution, back to back, until the job has caught
+	// up with its schedule. Executions missed while the TaskManager was not running, and restored
+	// from a job store, are executed once.
+	MisfireRunAllMissed
+	// MisfireSkipToNext skips the late run and all missed executions, resuming the job at its first
+	// execution after now.
+	MisfireSkipToNext
+)
+
+// PanicPolicy determines what happens to a job when one of its tasks panics, after the panic has
+// been recovered and reported, and after any retries of the task.
+type PanicPolicy int
+
+const (
+	// PanicKeepRunning keeps the job scheduled, treating the panic like any other failure.
+	PanicKeepRunning PanicPolicy = iota
+	// PanicRemoveJob removes the job from the TaskManager.
+	PanicRemoveJob
+	// PanicQuarantine dead-letters the job once its tasks have panicked PanicThreshold times, from
+	// where it can be inspected and requeued with RequeueJob.
+	PanicQuarantine
+)
+
+// ExecutionMode determines how the tasks of a job's run are executed.
+type ExecutionMode int
+
+const (
+	// ExecutionParallel dispatches all tasks of a run to the worker pool at once.
+	ExecutionParallel ExecutionMode = iota
+	// ExecutionSequential executes the tasks of a run one at a time, in order, dispatching each task
+	// once the previous task has finished.
+	ExecutionSequential
+	// ExecutionStopOnError executes the tasks of a run in order like ExecutionSequential, skipping
+	// the remaining tasks once a task has failed.
+	ExecutionStopOnError
+)
+
+// jobState holds the runtime state of a job, which is kept when the job is replaced.
+type jobState struct {
+	stats     jobStats        // Execution statistics
+	running   int             // Number of runs currently executing, guarded by the TaskManager's lock
+	limiter   *rate.Limiter   // Limiter of the job's dispatch rate, if set
+	completed map[string]bool // Dependencies completed since the job's last run, guarded by the TaskManager's lock
+	panics    int             // Number of runs with a panicking task, guarded by the TaskManager's lock
+	touched   time.Time       // Last time the job was touched, for its idle TTL, guarded by the TaskManager's lock
+}
+
+// jobRun tracks a single execution of a job, from the dispatch of its tasks until all of them
+// have finished executing.
+type jobRun struct {
+	tm        *TaskManager
+	job       *Job
+	start     time.Time    // Time the run was dispatched, according to the TaskManager's clock
+	began     time.Time    // Real time the run was dispatched, for measuring its duration
+	remaining atomic.Int32 // Number of tasks yet to finish
+
+	mu      sync.Mutex
+	errs    []error      // Errors of the run's failed tasks
+	results []TaskResult // Results of the run's tasks, in the order of the job's tasks
+	pending []jobTask    // Tasks of a sequential run yet to be dispatched
+	queue   *taskQueue   // Queue through which the pending tasks are dispatched
+
+	span trace.Span     // Span of the run, if tracing is enabled
+	done chan JobResult // Channel receiving the run's result once finished, if the run is awaited
+
+	ctx     context.Context // Context of the run's tasks, passed on to the job's Fallback
+	final   bool            // True if the job was removed as this is its final run, cancelling its context once finished
+	repanic bool            // Whether panics of the run's tasks crash the process, see SetRecoverDisabled
+}
+
+// newJobRun creates a run for the job's current tasks, starting at the given time.
+// Note: should be called while holding the TaskManager's lock, as it reads the job.
+func newJobRun(tm *TaskManager, job *Job, start time.Time) *jobRun {
+	run := &jobRun{tm: tm, job: job, start: start, began: time.Now(), results: make([]TaskResult, len(job.Tasks))}
+	if tm != nil {
+		run.repanic = tm.recoverDisabled
+	}
+	for i, task := range job.Tasks {
+		run.results[i].TaskIndex = i
+		run.results[i].TaskID = taskIDOf(task)
+	}
+	run.remaining.Store(int32(len(job.Tasks)))
+	return run
+}
+
+// taskExecuted records the result of one of the run's tasks.
+func (r *jobRun) taskExecuted(index int, duration time.Duration, data map[string]any, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if index >= 0 && index < len(r.results) {
+		r.results[index].Duration = duration
+		r.results[index].Data = data
+		r.results[index].Err = err
+	}
+}
+
+// taskTimed records the wall time and CPU time, if measured, of a task execution of the run in the
+// statistics of its job and in the metrics of the TaskManager.
+func (r *jobRun) taskTimed(wall, cpu time.Duration) {
+	if r.job.state != nil {
+		r.job.state.stats.recordTaskTime(wall, cpu)
+	}
+	if r.tm != nil {
+		r.tm.taskTime.Add(int64(wall))
+		r.tm.taskCPUTime.Add(int64(cpu))
+	}
+}
+
+// taskSkipped records one of the run's tasks as skipped without executing.
+func (r *jobRun) taskSkipped(index int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if index >= 0 && index < len(r.results) {
+		r.results[index].Skipped = true
+	}
+}
+
+// taskFailed records the error of one of the run's tasks, counting the failure of a task with an ID
+// in the job's statistics.
+func (r *jobRun) taskFailed(taskID string, err error) {
+	r.mu.Lock()
+	r.errs = append(r.errs, err)
+	r.mu.Unlock()
+
+	if taskID != "" && r.job.state != nil {
+		r.job.state.stats.recordTaskFailure(taskID)
+	}
+	if r.tm != nil {
+		r.tm.hooks.taskFailed(r.job.ID, err)
+	}
+}
+
+// dispatchNext dispatches the next pending task of a sequential run, once the previous task has
+// finished with the error err. If the run stops on errors, the pending tasks are skipped instead.
+// The task is sent from a separate goroutine, so that the finishing worker is never blocked.
+func (r *jobRun) dispatchNext(err error) {
+	r.mu.Lock()
+	if len(r.pending) == 0 {
+		r.mu.Unlock()
+		return
+	}
+	if err != nil && r.job.ExecutionMode == ExecutionStopOnError {
+		skipped := len(r.pending)
+		for _, task := range r.pending {
+			r.results[task.index].Skipped = true
+		}
+		r.pending = nil
+		r.mu.Unlock()
+		r.remaining.Add(-int32(skipped))
+		return
+	}
+	next := r.pending[0]
+	r.pending = r.pending[1:]
+	r.mu.Unlock()
+
+	r.tm.dispatches.Add(1)
+	go func() {
+		defer r.tm.dispatches.Done()
+		if reserved := r.tm.reservedQueue(r.job); reserved != nil && reserved.trySend(next.unpreemptible()) {
+			return
+		}
+		r.tm.preemptFor(r.job, r.queue)
+		r.queue.send(r.tm.ctx.Done(), next)
+	}()
+}
+
+// taskFinished marks one of the run's tasks as finished, finishing the run after the last task.
+func (r *jobRun) taskFinished() {
+	if r.remaining.Add(-1) > 0 {
+		return
+	}
+	// Jobs are removed from the queue when their final run is dispatched, release their context here
+	if r.final && r.job.cancel != nil {
+		r.job.cancel()
+	}
+
+	r.mu.Lock()
+	errs := r.errs
+	err := errors.Join(errs...)
+	results := r.results
+	r.mu.Unlock()
+	duration := time.Since(r.began)
+
+	// Execute the fallback before the run is finished, so that the job's overlap policy applies
+	var fallbackErr error
+	if err != nil && r.job.Fallback != nil {
+		fallbackErr = r.executeFallback(err)
+	}
+
+	if r.job.state != nil {
+		r.job.state.stats.recordRun(r.start, duration, err)
+		if r.tm != nil {
+			r.job.state.stats.recordHistory(newRunRecord(r.start, duration, errs), int(r.tm.historySize.Load()))
+			r.tm.checkOverrun(r.job)
+		}
+	}
+	if r.span != nil {
+		endSpan(r.span, err)
+	}
+	if r.tm != nil {
+		result := JobResult{
+			JobID:       r.job.ID,
+			Started:     r.start,
+			Finished:    r.start.Add(duration),
+			TaskResults: results,
+			FallbackErr: fallbackErr,
+		}
+		removed := r.tm.runFinished(r.job, err)
+		r.tm.hooks.jobCompleted(r.job.ID, duration, err)
+		r.tm.hooks.jobResulted(result)
+		r.tm.notifyRunWaiters(result)
+		if r.done != nil {
+			r.done <- result
+		}
+		if removed {
+			r.tm.RLock()
+			store := r.tm.store
+			r.tm.RUnlock()
+			if store != nil {
+				r.tm.unpersistJob(store, r.job.ID)
+			}
+			r.tm.hooks.jobWasRemoved(r.job.ID)
+		} else {
+			r.tm.saveHistory(r.job)
+		}
+	}
+}
+
+// runFinished updates the state of a job after one of its runs has completed with the error err,
+// releasing the jobs depending on a successful run. The job itself is handled according to its
+// panic policy if a task panicked, it is dead-lettered if it has failed too many times in a row,
+// its next execution is backed off if it failed, and a run delayed by its overlap policy is
+// released. Returns true if the job was removed.
+func (tm *TaskManager) runFinished(job *Job, err error) bool {
+	tm.Lock()
+	defer tm.Unlock()
+	defer tm.checkDrained()
+
+	job.state.running--
+	tm.activeRuns--
+	if key := job.IdempotencyKey; key != "" {
+		if tm.inflightKeys[key]--; tm.inflightKeys[key] <= 0 {
+			delete(tm.inflightKeys, key)
+		}
+	}
+	if job.Dedicated {
+		tm.wakeDedicated(job)
+	}
+	if err == nil {
+		tm.releaseDependents(job.ID)
+	}
+	var panicErr *PanicError
+	panicked := errors.As(err, &panicErr)
+	failed := err != nil
+
+	// The job may have been replaced since the run was dispatched, look up its current version
+	jobIndex, err := tm.jobQueue.JobInQueue(job.ID)
+	if err != nil {
+		return false
+	}
+	current := tm.jobQueue.jobs[jobIndex]
+	if current.state != job.state {
+		return false
+	}
+
+	// Apply the job's panic policy
+	if panicked {
+		current.state.panics++
+		switch current.PanicPolicy {
+		case PanicRemoveJob:
+			if err := tm.removeJob(current); err != nil {
+				tm.logger.Error("Failed to remove panicking job", "jobID", current.ID, "error", err)
+				return false
+			}
+			current.cancel()
+			tm.logger.Warn("Removed job after a task panicked", "jobID", current.ID)
+			return true
+		case PanicQuarantine:
+			if current.state.panics >= max(current.PanicThreshold, 1) {
+				if err := tm.deadLetterJob(current); err != nil {
+					tm.logger.Error("Failed to quarantine job", "jobID", current.ID, "error", err)
+				}
+				return false
+			}
+		}
+	}
+
+	// Stop executing a job which keeps failing, until it is requeued
+	if threshold := tm.deadLetterThresholdOf(current); threshold > 0 &&
+		current.state.stats.snapshot().ConsecutiveFailures >= threshold {
+		if err := tm.deadLetterJob(current); err != nil {
+			tm.logger.Error("Failed to dead-letter job", "jobID", current.ID, "error", err)
+		}
+		return false
+	}
+
+	// Back off the schedule of a job which keeps failing
+	if failed {
+		tm.backOff(current, tm.now())
+	}
+
+	if !current.delayed {
+		return false
+	}
+	current.delayed = false
+	heap.Fix(&tm.jobQueue, current.index)
+
+	// Signal the run loop that the delayed job is due
+	tm.wakeRunLoop()
+	return false
+}
+
+// jobTask wraps a task dispatched to the worker pool, carrying the context of the job the task
+// belongs to and the run it is part of.
+type jobTask struct {
+	task        Task
+	index       int // Index of the task within the job's tasks
+	ctx         context.Context
+	retryPolicy *RetryPolicy
+	run         *jobRun
+	executor    TaskExecutor // Middleware chain executing the task, if any
+	logger      Logger       // Logger of the TaskManager, the package logger if nil
+
+	panicHandler PanicHandler // Handler of the TaskManager called on panics, if set
+	discard      bool         // Whether to discard the task if its context is cancelled before it starts
+	measureCPU   bool         // Whether to measure the CPU time of the task, see SetCPUAccounting
+	resource     any          // Resource of the worker executing the task, if any
+	chaos        *chaos       // Chaos mode of the TaskManager when the task was dispatched, if enabled
+
+	quota     *concurrencyQuota // Quota of the job's concurrency group, if it has a limit
+	quotaHeld bool              // True if the task was unparked with a slot of its quota
+
+	priority    int          // Priority of the task's job
+	preemptions *preemptions // Preemption signals the task registers with while executing, nil if it may not be preempted
+}
+
+// unpreemptible returns the task without a preemption signal, for tasks executed outside of the
+// default worker pool.
+func (jt jobTask) unpreemptible() jobTask {
+	jt.preemptions = nil
+	return jt
+}
+
+// jobID returns the ID of the job the task belongs to.
+func (jt jobTask) jobID() string {
+	if jt.run == nil {
+		return ""
+	}
+	return jt.run.job.ID
+}
+
+// taskID returns the ID of the task, if it is a NamedTask.
+func (jt jobTask) taskID() string {
+	return taskIDOf(jt.task)
+}
+
+// metadata returns the metadata of the job the task belongs to.
+func (jt jobTask) metadata() map[string]string {
+	if jt.run == nil {
+		return nil
+	}
+	return jt.run.job.Metadata
+}
+
+// Execute executes the wrapped task, passing on the job's context to context-aware tasks. Failed
+// executions are retried according to the retry policy, if any, and the error of the last
+// attempt is returned as a *TaskError. A panicking attempt fails with a *PanicError.
+func (jt jobTask) Execute() (err error) {
+	logger := jt.logger
+	if logger == nil {
+		logger = zerologLogger{}
+	}
+
+	// Park the task while its concurrency group is at its limit, freeing the worker
+	if jt.quota != nil {
+		if !jt.quota.acquire(jt) {
+			logger.Debug("Parked task at the limit of its concurrency group", "jobID", jt.jobID(), "taskIndex", jt.index)
+			return nil
+		}
+		defer func() {
+			jt.run.tm.sendUnparked(jt.quota.release())
+		}()
+	}
+
+	// Discard the task if its job was removed while it waited for a worker
+	if jt.discard && jt.ctx != nil && jt.ctx.Err() != nil {
+		logger.Debug("Discarding task of removed job", "jobID", jt.jobID(), "taskIndex", jt.index)
+		if jt.run != nil {
+			jt.run.taskSkipped(jt.index)
+			jt.run.dispatchNext(nil)
+			jt.run.taskFinished()
+		}
+		return nil
+	}
+
+	var data map[string]any // Output of the last attempt of a ResultTask
+	if jt.run != nil {
+		start := time.Now()
+		var cpu cpuTimer
+		if jt.measureCPU {
+			cpu = startCPUTimer()
+		}
+		if jt.run.tm != nil {
+			jt.run.tm.emitEvent(Event{
+				Type:      EventTaskStarted,
+				JobID:     jt.jobID(),
+				TaskIndex: jt.index,
+				TaskID:    jt.taskID(),
+				Metadata:  jt.metadata(),
+			})
+		}
+		defer func() {
+			jt.run.taskTimed(time.Since(start), cpu.stop())
+			if jt.run.tm != nil {
+				jt.run.tm.emitEvent(Event{
+					Type:      EventTaskCompleted,
+					JobID:     jt.jobID(),
+					TaskIndex: jt.index,
+					TaskID:    jt.taskID(),
+					Err:       err,
+					Metadata:  jt.metadata(),
+				})
+				if _, ok := jt.task.(ResultTask); ok {
+					jt.run.tm.emitResult(jt.task, Result{
+						JobID:     jt.jobID(),
+						TaskIndex: jt.index,
+						TaskID:    jt.taskID(),
+						Duration:  time.Since(start),
+						Data:      data,
+						Err:       err,
+					})
+				}
+			}
+			jt.run.taskExecuted(jt.index, time.Since(start), data, err)
+			if err != nil {
+				jt.run.taskFailed(jt.taskID(), err)
+			}
+			jt.run.dispatchNext(err)
+			jt.run.taskFinished()
+		}()
+	}
+
+	ctx := jt.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = withWorkerResource(ctx, jt.resource)
+	if jt.preemptions != nil {
+		preemption := jt.preemptions.add(jt.priority)
+		defer jt.preemptions.remove(preemption)
+		ctx = context.WithValue(ctx, preemptedKey{}, preemption.preempted)
+	}
+	return executeWithRetry(ctx, jt.retryPolicy, logger, func(attempt int) error {
+		start := time.Now()
+		err := jt.executeAttempt(ctx, logger, attempt, &data)
+		if err != nil {
+			return &TaskError{
+				JobID:     jt.jobID(),
+				TaskIndex: jt.index,
+				TaskID:    jt.taskID(),
+				Attempt:   attempt,
+				Time:      start,
+				Err:       err,
+				Metadata:  jt.metadata(),
+			}
+		}
+		return nil
+	})
+}
+
+// executeWithResource executes the wrapped task, passing on the resource of the worker executing
+// it in the context.
+func (jt jobTask) executeWithResource(resource any) error {
+	jt.resource = resource
+	return jt.Execute()
+}
+
+// executeAttempt executes the wrapped task once through the middleware chain, if any, recovering
+// a panic into a *PanicError unless recovery is disabled. The output of a ResultTask is stored in
+// data.
+func (jt jobTask) executeAttempt(ctx context.Context, logger Logger, attempt int, data *map[string]any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack := debug.Stack()
+			logger.Error("Task recovered from panic", "jobID", jt.jobID(), "taskIndex", jt.index, "panic", r, "stack", string(stack))
+			if jt.panicHandler != nil {
+				jt.panicHandler(jt.jobID(), r, stack)
+			}
+			if jt.run != nil && jt.run.repanic {
+				panic(unrecoveredPanic{value: r})
+			}
+			err = &PanicError{JobID: jt.jobID(), Value: r, Stack: stack}
+		}
+	}()
+	jt.chaos.delay(ctx.Done())
+	jt.chaos.maybePanic()
+
+	exec := TaskExecution{
+		JobID:     jt.jobID(),
+		TaskIndex: jt.index,
+		TaskID:    jt.taskID(),
+		Attempt:   attempt,
+		Task:      jt.task,
+		data:      data,
+	}
+	if jt.executor != nil {
+		return jt.executor(ctx, exec)
+	}
+	return executeTask(ctx, exec)
+}
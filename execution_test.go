@@ -0,0 +1,423 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobRunTaskFinished(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &Job{ID: "once-job", Tasks: []Task{MockTask{}, MockTask{}}, once: true, ctx: ctx, cancel: cancel}
+	run := newJobRun(nil, job, time.Now())
+	run.final = true
+	assert.Equal(t, int32(2), run.remaining.Load(), "Expected one remaining task per job task")
+
+	run.taskFinished()
+	assert.NoError(t, ctx.Err(), "Expected context to remain until the last task has finished")
+
+	run.taskFinished()
+	assert.Error(t, ctx.Err(), "Expected the job context to be cancelled after its final run finished")
+}
+
+func TestJobTaskExecute(t *testing.T) {
+	t.Run("Context task", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), struct{}{}, "value")
+		var received context.Context
+		task := SimpleContextTask{function: func(ctx context.Context) error {
+			received = ctx
+			return nil
+		}}
+
+		err := jobTask{task: task, ctx: ctx}.Execute()
+		assert.NoError(t, err)
+		assert.Equal(t, ctx, received, "Expected the job context to be passed to the task")
+	})
+
+	t.Run("Panicking task finishes run", func(t *testing.T) {
+		job := &Job{ID: "panic-job", Tasks: []Task{MockTask{}}}
+		run := newJobRun(nil, job, time.Now())
+		task := MockTask{executeFunc: func() error { panic("test panic") }}
+
+		var err error
+		assert.NotPanics(t, func() { err = jobTask{task: task, run: run}.Execute() })
+		assert.Equal(t, int32(0), run.remaining.Load(), "Expected panicking task to be marked as finished")
+
+		var taskErr *TaskError
+		assert.ErrorAs(t, err, &taskErr, "Expected a task error")
+		assert.Equal(t, "panic-job", taskErr.JobID)
+		var panicErr *PanicError
+		assert.ErrorAs(t, err, &panicErr, "Expected the task error to wrap a panic error")
+		assert.Equal(t, "test panic", panicErr.Value)
+		assert.NotEmpty(t, panicErr.Stack, "Expected the stack trace of the panic")
+	})
+
+	t.Run("Panicking task with recovery disabled", func(t *testing.T) {
+		job := &Job{ID: "panic-job", Tasks: []Task{MockTask{}}}
+		run := newJobRun(nil, job, time.Now())
+		run.repanic = true
+		task := MockTask{executeFunc: func() error { panic("test panic") }}
+
+		var handled any
+		handler := func(_ string, recovered any, _ []byte) { handled = recovered }
+		assert.PanicsWithValue(t, unrecoveredPanic{value: "test panic"}, func() {
+			_ = jobTask{task: task, run: run, panicHandler: handler}.Execute()
+		}, "Expected the panic to be raised again")
+		assert.Equal(t, "test panic", handled, "Expected the panic handler to be called before panicking again")
+	})
+
+	t.Run("Panicking task is retried", func(t *testing.T) {
+		var attempts int
+		task := MockTask{executeFunc: func() error {
+			attempts++
+			if attempts == 1 {
+				var m map[string]int
+				m["nil map"] = 1
+			}
+			return nil
+		}}
+
+		err := jobTask{task: task, retryPolicy: &RetryPolicy{MaxAttempts: 2}}.Execute()
+		assert.NoError(t, err, "Expected the retry to succeed")
+		assert.Equal(t, 2, attempts)
+	})
+}
+
+// concurrencyTracker records how many executions of a task run concurrently.
+type concurrencyTracker struct {
+	current    atomic.Int32
+	max        atomic.Int32
+	executions atomic.Int32
+}
+
+// task returns a task taking the duration to execute, tracking its concurrency.
+func (ct *concurrencyTracker) task(duration time.Duration) Task {
+	return MockTask{ID: "tracked-task", executeFunc: func() error {
+		ct.executions.Add(1)
+		current := ct.current.Add(1)
+		for {
+			maxSeen := ct.max.Load()
+			if current <= maxSeen || ct.max.CompareAndSwap(maxSeen, current) {
+				break
+			}
+		}
+		time.Sleep(duration)
+		ct.current.Add(-1)
+		return nil
+	}}
+}
+
+func TestOverlapPolicy(t *testing.T) {
+	// Each job has a cadence of 10ms and a task taking 35ms, so runs overlap unless prevented
+	cadence := 10 * time.Millisecond
+	duration := 35 * time.Millisecond
+
+	t.Run("Allow", func(t *testing.T) {
+		manager := NewCustom(8, 8, 1*time.Minute)
+		defer manager.Stop()
+
+		tracker := &concurrencyTracker{}
+		job := Job{ID: "allow-job", Cadence: cadence, NextExec: time.Now(), Tasks: []Task{tracker.task(duration)}}
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		time.Sleep(60 * time.Millisecond)
+		assert.Greater(t, tracker.max.Load(), int32(1), "Expected overlapping runs")
+	})
+
+	t.Run("Skip", func(t *testing.T) {
+		manager := NewCustom(8, 8, 1*time.Minute)
+		defer manager.Stop()
+
+		tracker := &concurrencyTracker{}
+		job := Job{
+			ID:            "skip-job",
+			Cadence:       cadence,
+			NextExec:      time.Now(),
+			Tasks:         []Task{tracker.task(duration)},
+			OverlapPolicy: OverlapSkip,
+		}
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, int32(1), tracker.max.Load(), "Expected no overlapping runs")
+		// Runs at 0ms and ~40ms, all runs in between are skipped
+		assert.LessOrEqual(t, tracker.executions.Load(), int32(2), "Expected overlapping runs to be skipped")
+	})
+
+	t.Run("Skip with max concurrent", func(t *testing.T) {
+		manager := NewCustom(8, 8, 1*time.Minute)
+		defer manager.Stop()
+
+		tracker := &concurrencyTracker{}
+		job := Job{
+			ID:            "skip-concurrent-job",
+			Cadence:       cadence,
+			NextExec:      time.Now(),
+			Tasks:         []Task{tracker.task(duration)},
+			OverlapPolicy: OverlapSkip,
+			MaxConcurrent: 2,
+		}
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, int32(2), tracker.max.Load(), "Expected at most 2 overlapping runs")
+	})
+
+	t.Run("Delay", func(t *testing.T) {
+		manager := NewCustom(8, 8, 1*time.Minute)
+		defer manager.Stop()
+
+		// Another job in the queue must keep running while the delayed job waits
+		otherExecutions := atomic.Int32{}
+		other := Job{ID: "other-job", Cadence: cadence, NextExec: time.Now(), Tasks: []Task{MockTask{executeFunc: func() error {
+			otherExecutions.Add(1)
+			return nil
+		}}}}
+		assert.NoError(t, manager.ScheduleJob(other))
+
+		tracker := &concurrencyTracker{}
+		job := Job{
+			ID:            "delay-job",
+			Cadence:       cadence,
+			NextExec:      time.Now(),
+			Tasks:         []Task{tracker.task(duration)},
+			OverlapPolicy: OverlapDelay,
+		}
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		time.Sleep(90 * time.Millisecond)
+		assert.Equal(t, int32(1), tracker.max.Load(), "Expected no overlapping runs")
+		// Runs back to back at 0ms, ~35ms and ~70ms
+		assert.GreaterOrEqual(t, tracker.executions.Load(), int32(3), "Expected delayed runs to execute once the previous run completed")
+		assert.GreaterOrEqual(t, otherExecutions.Load(), int32(5), "Expected other jobs to not be blocked by the delayed job")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		manager := NewCustom(1, 1, 1*time.Minute)
+		defer manager.Stop()
+
+		job := getMockedJob(1, "invalid-overlap-job", cadence, cadence)
+		job.OverlapPolicy = OverlapPolicy(42)
+		assert.Error(t, manager.ScheduleJob(job), "Expected error for unknown overlap policy")
+
+		job.OverlapPolicy = OverlapSkip
+		job.MaxConcurrent = -1
+		assert.Error(t, manager.ScheduleJob(job), "Expected error for negative max concurrent runs")
+	})
+}
+
+func TestExecutionMode(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		manager := NewCustom(4, 8, 1*time.Minute)
+		defer manager.Stop()
+
+		tracker := &concurrencyTracker{}
+		log := &executionLog{}
+		job := Job{ID: "sequential-job", Cadence: time.Minute, NextExec: time.Now(), ExecutionMode: ExecutionSequential}
+		for _, step := range []string{"step-1", "step-2", "step-3"} {
+			tracked := tracker.task(5 * time.Millisecond)
+			job.Tasks = append(job.Tasks, MockTask{executeFunc: func() error {
+				log.task(step, nil).Execute()
+				return tracked.Execute()
+			}})
+		}
+		completed := make(chan error, 1)
+		manager.OnJobComplete(func(jobID string, duration time.Duration, err error) { completed <- err })
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		select {
+		case err := <-completed:
+			assert.NoError(t, err)
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("Expected the sequential run to complete")
+		}
+		assert.Equal(t, int32(1), tracker.max.Load(), "Expected the tasks to execute one at a time")
+		assert.Equal(t, []string{"step-1", "step-2", "step-3"}, log.runs, "Expected the tasks to execute in order")
+	})
+
+	t.Run("Stop on error", func(t *testing.T) {
+		manager := NewCustom(4, 8, 1*time.Minute)
+		defer manager.Stop()
+
+		log := &executionLog{}
+		job := Job{ID: "stop-job", Cadence: time.Minute, NextExec: time.Now(), ExecutionMode: ExecutionStopOnError,
+			Tasks: []Task{log.task("step-1", nil), log.task("step-2", errors.New("step failed")), log.task("step-3", nil)}}
+		completed := make(chan error, 1)
+		manager.OnJobComplete(func(jobID string, duration time.Duration, err error) { completed <- err })
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		select {
+		case err := <-completed:
+			var taskErr *TaskError
+			assert.ErrorAs(t, err, &taskErr, "Expected the run to fail with the task's error")
+			assert.Equal(t, 1, taskErr.TaskIndex)
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("Expected the run to complete after the failed task")
+		}
+		assert.Equal(t, []string{"step-1", "step-2"}, log.runs, "Expected the remaining tasks to be skipped")
+	})
+
+	t.Run("Sequential continues after error", func(t *testing.T) {
+		manager := NewCustom(4, 8, 1*time.Minute)
+		defer manager.Stop()
+
+		log := &executionLog{}
+		job := Job{ID: "continue-job", Cadence: time.Minute, NextExec: time.Now(), ExecutionMode: ExecutionSequential,
+			Tasks: []Task{log.task("step-1", errors.New("step failed")), log.task("step-2", nil)}}
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		assert.Eventually(t, func() bool { return log.count("step-2") == 1 }, 50*time.Millisecond, time.Millisecond,
+			"Expected the remaining tasks to execute")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		manager := NewCustom(1, 1, 1*time.Minute)
+		defer manager.Stop()
+
+		job := getMockedJob(1, "invalid-mode-job", time.Second, 0)
+		job.ExecutionMode = ExecutionMode(42)
+		assert.Error(t, manager.ScheduleJob(job), "Expected error for unknown execution mode")
+	})
+}
+
+func TestMisfirePolicy(t *testing.T) {
+	now := time.Now()
+	scheduled := now.Add(-35 * time.Second)
+
+	t.Run("Run once now", func(t *testing.T) {
+		job := &Job{Cadence: 10 * time.Second, scheduled: scheduled}
+		assert.True(t, job.misfired(now), "Expected a run late by more than a cadence to have misfired")
+		assert.Equal(t, now.Add(5*time.Second), job.nextExecAfter(now), "Expected missed executions to be skipped")
+	})
+
+	t.Run("Run all missed", func(t *testing.T) {
+		job := &Job{Cadence: 10 * time.Second, scheduled: scheduled, MisfirePolicy: MisfireRunAllMissed}
+		assert.Equal(t, scheduled.Add(10*time.Second), job.nextExecAfter(now), "Expected the following execution to catch up")
+	})
+
+	t.Run("Late run", func(t *testing.T) {
+		job := &Job{Cadence: 10 * time.Second, scheduled: now.Add(-5 * time.Second)}
+		assert.False(t, job.misfired(now), "Expected a run late by less than a cadence to not have misfired")
+		assert.Equal(t, now.Add(5*time.Second), job.nextExecAfter(now), "Expected the schedule to be kept")
+	})
+
+	t.Run("Cron", func(t *testing.T) {
+		schedule, err := parseCron("@hourly")
+		assert.NoError(t, err)
+		cronScheduled := schedule.next(now.Add(-3 * time.Hour))
+		job := &Job{cron: schedule, scheduled: cronScheduled}
+		assert.True(t, job.misfired(now))
+		assert.Equal(t, schedule.next(now), job.nextExecAfter(now), "Expected missed executions to be skipped")
+
+		job.MisfirePolicy = MisfireRunAllMissed
+		assert.Equal(t, cronScheduled.Add(time.Hour), job.nextExecAfter(now), "Expected the following execution to catch up")
+	})
+
+	t.Run("Restored", func(t *testing.T) {
+		store := NewMemoryJobStore()
+		stored := time.Now().Add(-1 * time.Hour).Add(-30 * time.Second)
+		assert.NoError(t, store.Save(JobRecord{ID: "once-now-job", Cadence: time.Minute, NextExec: stored}))
+		assert.NoError(t, store.Save(JobRecord{ID: "skip-job", Cadence: time.Minute, NextExec: stored, MisfirePolicy: MisfireSkipToNext}))
+
+		manager := NewCustom(1, 1, 1*time.Minute)
+		defer manager.Stop()
+		manager.SetJobStore(store)
+		assert.NoError(t, manager.RestoreJobs(func(record JobRecord) ([]Task, error) {
+			return []Task{MockTask{ID: record.ID}}, nil
+		}))
+
+		info, err := manager.Job("once-now-job")
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), info.NextExec, time.Second, "Expected the missed executions to execute once now")
+
+		info, err = manager.Job("skip-job")
+		assert.NoError(t, err)
+		assert.True(t, info.NextExec.After(time.Now()), "Expected the missed executions to be skipped")
+		assert.Equal(t, time.Duration(0), info.NextExec.Sub(stored)%time.Minute, "Expected the job to keep its schedule")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		manager := NewCustom(1, 1, 1*time.Minute)
+		defer manager.Stop()
+
+		job := getMockedJob(1, "invalid-misfire-job", time.Second, 0)
+		job.MisfirePolicy = MisfirePolicy(42)
+		assert.Error(t, manager.ScheduleJob(job), "Expected error for unknown misfire policy")
+	})
+}
+
+func TestPanicPolicy(t *testing.T) {
+	// panickingJob returns a job with a task panicking on every execution, counting its executions.
+	panickingJob := func(id string, executions *atomic.Int32) Job {
+		return Job{ID: id, Cadence: 5 * time.Millisecond, NextExec: time.Now(), Tasks: []Task{MockTask{executeFunc: func() error {
+			executions.Add(1)
+			panic("task panicked")
+		}}}}
+	}
+
+	t.Run("Keep running", func(t *testing.T) {
+		manager := NewCustom(2, 4, 1*time.Minute)
+		defer manager.Stop()
+
+		var executions atomic.Int32
+		assert.NoError(t, manager.ScheduleJob(panickingJob("keep-job", &executions)))
+
+		assert.Eventually(t, func() bool { return executions.Load() >= 3 }, 50*time.Millisecond, time.Millisecond,
+			"Expected the job to keep executing")
+	})
+
+	t.Run("Remove job", func(t *testing.T) {
+		manager := NewCustom(2, 4, 1*time.Minute)
+		defer manager.Stop()
+
+		removed := make(chan string, 1)
+		manager.OnJobRemoved(func(jobID string) { removed <- jobID })
+		var executions atomic.Int32
+		job := panickingJob("remove-job", &executions)
+		job.PanicPolicy = PanicRemoveJob
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		select {
+		case id := <-removed:
+			assert.Equal(t, job.ID, id)
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("Expected the job to be removed after its task panicked")
+		}
+		_, err := manager.Job(job.ID)
+		assert.Error(t, err, "Expected the job to be removed")
+		assert.Equal(t, int32(1), executions.Load(), "Expected a single execution")
+	})
+
+	t.Run("Quarantine", func(t *testing.T) {
+		manager := NewCustom(2, 4, 1*time.Minute)
+		defer manager.Stop()
+
+		var executions atomic.Int32
+		job := panickingJob("quarantine-job", &executions)
+		job.PanicPolicy = PanicQuarantine
+		job.PanicThreshold = 2
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		assert.Eventually(t, func() bool { return len(manager.DeadLetteredJobs()) == 1 }, 50*time.Millisecond, time.Millisecond,
+			"Expected the job to be quarantined")
+		assert.Equal(t, job.ID, manager.DeadLetteredJobs()[0].ID)
+		assert.Equal(t, int32(2), executions.Load(), "Expected the job to execute until reaching its panic threshold")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		manager := NewCustom(1, 1, 1*time.Minute)
+		defer manager.Stop()
+
+		job := getMockedJob(1, "invalid-panic-job", time.Second, 0)
+		job.PanicPolicy = PanicPolicy(42)
+		assert.Error(t, manager.ScheduleJob(job), "Expected error for unknown panic policy")
+
+		job.PanicPolicy = PanicQuarantine
+		job.PanicThreshold = -1
+		assert.Error(t, manager.ScheduleJob(job), "Expected error for negative panic threshold")
+	})
+}
@@ -0,0 +1,38 @@
+package taskman
+
+import (
+	"container/heap"
+	"time"
+)
+
+// failureBackoff returns the delay of the job's next execution after the given number of
+// consecutive failed runs, its cadence doubled for every failure, up to its FailureBackoff.
+func (j *Job) failureBackoff(failures int) time.Duration {
+	delay := j.Cadence
+	for range failures {
+		if delay >= j.FailureBackoff/2 {
+			return j.FailureBackoff
+		}
+		delay *= 2
+	}
+	return min(delay, j.FailureBackoff)
+}
+
+// backOff delays the next execution of a job whose run has failed, to its failure backoff after
+// now, unless it is already due later. Jobs without a FailureBackoff or a cadence, and dependent
+// jobs, are left as scheduled.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) backOff(job *Job, now time.Time) {
+	if job.FailureBackoff <= 0 || job.Cadence <= 0 || job.once || len(job.DependsOn) > 0 {
+		return
+	}
+	failures := job.state.stats.snapshot().ConsecutiveFailures
+	next := now.Add(job.failureBackoff(failures))
+	if !next.After(job.scheduled) {
+		return
+	}
+	job.scheduled = next
+	job.NextExec = job.withJitter(next)
+	heap.Fix(&tm.jobQueue, job.index)
+	tm.logger.Debug("Backed off job after failed run", "jobID", job.ID, "failures", failures, "nextExec", job.NextExec)
+}
@@ -0,0 +1,58 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailureBackoffDelay(t *testing.T) {
+	job := Job{Cadence: time.Second, FailureBackoff: 10 * time.Second}
+	for failures, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second} {
+		assert.Equal(t, expected, job.failureBackoff(failures), "Unexpected backoff after %d failures", failures)
+	}
+	assert.Equal(t, 10*time.Second, job.failureBackoff(100), "Expected the backoff to be capped")
+}
+
+func TestJobFailureBackoff(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+
+	failing := atomic.Bool{}
+	failing.Store(true)
+	job := getMockedJob(1, "backoff-job", time.Hour, time.Hour)
+	job.Tasks[0] = MockTask{executeFunc: func() error {
+		if failing.Load() {
+			return errors.New("dependency down")
+		}
+		return nil
+	}}
+	job.FailureBackoff = 3 * time.Hour
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	run := func() time.Duration {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err := manager.RunJobNow(ctx, job.ID)
+		assert.NoError(t, err)
+		info, err := manager.Job(job.ID)
+		assert.NoError(t, err)
+		return time.Until(info.NextExec)
+	}
+
+	// The next execution backs off exponentially from the cadence, up to the cap
+	assert.InDelta(t, 2*time.Hour, run(), float64(time.Minute), "Expected the cadence doubled after one failure")
+	assert.InDelta(t, 3*time.Hour, run(), float64(time.Minute), "Expected the backoff capped after two failures")
+
+	// A successful run leaves the schedule as is, later runs follow the cadence again
+	failing.Store(false)
+	assert.InDelta(t, 3*time.Hour, run(), float64(time.Minute))
+
+	invalid := getMockedJob(1, "invalid-backoff-job", time.Hour, time.Hour)
+	invalid.FailureBackoff = -time.Second
+	assert.Error(t, manager.ScheduleJob(invalid), "Expected error for a negative failure backoff")
+}
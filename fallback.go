@@ -0,0 +1,46 @@
+package taskman
+
+import (
+	"context"
+	"runtime/debug"
+)
+
+// runErrorKey is the context key of the error of the failed run a Fallback is executed after.
+type runErrorKey struct{}
+
+// RunError returns the error of the failed run which a job's Fallback is executed after, from the
+// context passed to the Fallback, or nil for the context of any other task.
+func RunError(ctx context.Context) error {
+	err, _ := ctx.Value(runErrorKey{}).(error)
+	return err
+}
+
+// executeFallback executes the job's Fallback once after the run failed with err, passing err in
+// the context. The Fallback is not retried, and a panic is recovered into a *PanicError. Returns
+// the Fallback's error.
+func (r *jobRun) executeFallback(err error) (fallbackErr error) {
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = context.WithValue(ctx, runErrorKey{}, err)
+
+	logger := Logger(zerologLogger{})
+	if r.tm != nil {
+		logger = r.tm.logger
+	}
+	defer func() {
+		if rec := recover(); rec != nil {
+			stack := debug.Stack()
+			logger.Error("Fallback recovered from panic", "jobID", r.job.ID, "panic", rec, "stack", string(stack))
+			if r.repanic {
+				panic(unrecoveredPanic{value: rec})
+			}
+			fallbackErr = &PanicError{JobID: r.job.ID, Value: rec, Stack: stack}
+		}
+		if fallbackErr != nil {
+			logger.Warn("Fallback of failed run failed", "jobID", r.job.ID, "error", fallbackErr)
+		}
+	}()
+	return executeTask(ctx, TaskExecution{JobID: r.job.ID, Attempt: 1, Task: r.job.Fallback})
+}
@@ -0,0 +1,68 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobFallback(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+
+	fallbackErrs := make(chan error, 4)
+	failing := errors.New("failure")
+	job := getMockedJob(2, "fallback-job", time.Hour, time.Hour)
+	job.Tasks[1] = MockTask{executeFunc: func() error { return failing }}
+	job.RetryPolicy = &RetryPolicy{MaxAttempts: 2}
+	job.Fallback = SimpleContextTask{func(ctx context.Context) error {
+		fallbackErrs <- RunError(ctx)
+		return errors.New("alert not sent")
+	}}
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	result, err := manager.RunJobNow(ctx, job.ID)
+	assert.NoError(t, err)
+	assert.ErrorContains(t, result.FallbackErr, "alert not sent")
+
+	// The fallback is executed once, after the retries, with the run's error
+	select {
+	case err := <-fallbackErrs:
+		assert.ErrorIs(t, err, failing)
+	default:
+		t.Fatal("Expected the fallback to be executed")
+	}
+	assert.Empty(t, fallbackErrs, "Expected the fallback to be executed once")
+	assert.Nil(t, RunError(context.Background()), "Expected no run error outside of a fallback")
+
+	t.Run("Not executed after successful runs", func(t *testing.T) {
+		job := getMockedJob(1, "successful-job", time.Hour, time.Hour)
+		job.Fallback = MockTask{executeFunc: func() error {
+			fallbackErrs <- nil
+			return nil
+		}}
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		result, err := manager.RunJobNow(ctx, job.ID)
+		assert.NoError(t, err)
+		assert.NoError(t, result.FallbackErr)
+		assert.Empty(t, fallbackErrs, "Expected the fallback not to be executed")
+	})
+
+	t.Run("Recovers panics", func(t *testing.T) {
+		job := getMockedJob(1, "panicking-fallback-job", time.Hour, time.Hour)
+		job.Tasks[0] = MockTask{executeFunc: func() error { return failing }}
+		job.Fallback = MockTask{executeFunc: func() error { panic("fallback panic") }}
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		result, err := manager.RunJobNow(ctx, job.ID)
+		assert.NoError(t, err)
+		var panicErr *PanicError
+		assert.ErrorAs(t, result.FallbackErr, &panicErr)
+	})
+}
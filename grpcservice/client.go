@@ -0,0 +1,64 @@
+package grpcservice
+
+import (
+	"context"
+
+	taskman "github.com/jkbrsn/go-taskman"
+	"google.golang.org/grpc"
+)
+
+// Client is a client of the service registered with Register, e.g. for scheduling jobs in a
+// TaskManager running in a central scheduling daemon.
+type Client struct {
+	conn grpc.ClientConnInterface
+}
+
+// NewClient returns a Client calling the service over the connection, e.g. one created with
+// grpc.NewClient. The connection is owned by the caller.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{conn: conn}
+}
+
+// Schedule schedules the job of the entry, returning its ID. Errors are gRPC status errors, e.g.
+// with codes.AlreadyExists if a job with the entry's name is already scheduled.
+func (c *Client) Schedule(ctx context.Context, entry taskman.ScheduleEntry) (string, error) {
+	var response ScheduleResponse
+	if err := c.invoke(ctx, "Schedule", &ScheduleRequest{Job: entry}, &response); err != nil {
+		return "", err
+	}
+	return response.ID, nil
+}
+
+// Remove removes a job.
+func (c *Client) Remove(ctx context.Context, jobID string) error {
+	return c.invoke(ctx, "Remove", &JobRequest{ID: jobID}, &Empty{})
+}
+
+// Pause pauses a job.
+func (c *Client) Pause(ctx context.Context, jobID string) error {
+	return c.invoke(ctx, "Pause", &JobRequest{ID: jobID}, &Empty{})
+}
+
+// Resume resumes a paused job.
+func (c *Client) Resume(ctx context.Context, jobID string) error {
+	return c.invoke(ctx, "Resume", &JobRequest{ID: jobID}, &Empty{})
+}
+
+// Trigger executes a job immediately.
+func (c *Client) Trigger(ctx context.Context, jobID string) error {
+	return c.invoke(ctx, "Trigger", &JobRequest{ID: jobID}, &Empty{})
+}
+
+// List returns the scheduled jobs, or those with the tag if it is not empty.
+func (c *Client) List(ctx context.Context, tag string) ([]Job, error) {
+	var response ListResponse
+	if err := c.invoke(ctx, "List", &ListRequest{Tag: tag}, &response); err != nil {
+		return nil, err
+	}
+	return response.Jobs, nil
+}
+
+// invoke calls the RPC of the service, encoding its messages as JSON.
+func (c *Client) invoke(ctx context.Context, method string, req, resp any) error {
+	return c.conn.Invoke(ctx, "/"+serviceName+"/"+method, req, resp, grpc.CallContentSubtype(codecName))
+}
@@ -0,0 +1,69 @@
+package grpcservice
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	taskman "github.com/jkbrsn/go-taskman"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestClient(t *testing.T) {
+	manager := newManager(t)
+	defer manager.Stop()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	Register(server, manager)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+	client := NewClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	jobID, err := client.Schedule(ctx, taskman.ScheduleEntry{Name: "remote-job", Task: "ping",
+		Params: map[string]any{"name": "remote"}, Cron: "@daily", Retry: &taskman.ScheduleRetry{MaxAttempts: 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, "remote-job", jobID)
+	_, err = client.Schedule(ctx, taskman.ScheduleEntry{Name: "remote-job", Task: "ping", Cadence: "1m"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	jobs, err := client.List(ctx, "")
+	assert.NoError(t, err)
+	if assert.Len(t, jobs, 1) {
+		assert.Equal(t, "remote-job", jobs[0].ID)
+	}
+
+	assert.NoError(t, client.Trigger(ctx, jobID))
+	select {
+	case name := <-executed:
+		assert.Equal(t, "remote", name)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Expected the triggered job to execute")
+	}
+
+	assert.NoError(t, client.Pause(ctx, jobID))
+	info, err := manager.Job(jobID)
+	assert.NoError(t, err)
+	assert.True(t, info.Paused)
+	assert.NoError(t, client.Resume(ctx, jobID))
+
+	assert.NoError(t, client.Remove(ctx, jobID))
+	assert.Equal(t, codes.NotFound, status.Code(client.Remove(ctx, jobID)))
+	jobs, err = client.List(ctx, "")
+	assert.NoError(t, err)
+	assert.Empty(t, jobs)
+}
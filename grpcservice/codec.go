@@ -0,0 +1,34 @@
+package grpcservice
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// codecName is the content subtype of the service's messages, encoded as JSON.
+const codecName = "json"
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
+
+// codec encodes the service's messages as JSON, so that they are plain Go structs rather than
+// generated protobuf messages. Clients in other languages use a JSON marshaller for the
+// "application/grpc+json" content type.
+type codec struct{}
+
+// Marshal encodes the message as JSON.
+func (codec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes the message from JSON.
+func (codec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// Name returns the content subtype of the codec.
+func (codec) Name() string {
+	return codecName
+}
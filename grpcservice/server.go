@@ -0,0 +1,177 @@
+// Package grpcservice provides a gRPC service for scheduling and controlling the jobs of a
+// TaskManager remotely, e.g. from a central scheduling daemon, and a Go client of the service.
+// Jobs are scheduled as taskman.ScheduleEntry values, their tasks created with the task types
+// registered with TaskManager.RegisterTaskType.
+//
+// Messages are encoded as JSON rather than protobuf, with the "application/grpc+json" content
+// type. The service performs no authentication or authorization, configure the grpc.Server with
+// credentials and interceptors doing so before exposing it beyond trusted networks, e.g.
+//
+//	server := grpc.NewServer(grpc.Creds(creds))
+//	grpcservice.Register(server, manager)
+package grpcservice
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	taskman "github.com/jkbrsn/go-taskman"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serviceName is the full name of the gRPC service.
+const serviceName = "taskman.Scheduler"
+
+// Job is a scheduled job, as listed by the List RPC.
+type Job struct {
+	ID        string            `json:"id"`
+	Cadence   string            `json:"cadence"`
+	NextExec  time.Time         `json:"next_exec"`
+	TaskCount int               `json:"task_count"`
+	Running   int               `json:"running"`
+	Paused    bool              `json:"paused"`
+	Tags      []string          `json:"tags,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// ScheduleRequest is the request of the Schedule RPC.
+type ScheduleRequest struct {
+	Job taskman.ScheduleEntry `json:"job"`
+}
+
+// ScheduleResponse is the response of the Schedule RPC.
+type ScheduleResponse struct {
+	ID string `json:"id"`
+}
+
+// JobRequest is the request of the RPCs applying an action to a job, e.g. Trigger.
+type JobRequest struct {
+	ID string `json:"id"`
+}
+
+// ListRequest is the request of the List RPC.
+type ListRequest struct {
+	Tag string `json:"tag,omitempty"` // Tag of the jobs to list, all jobs if empty
+}
+
+// ListResponse is the response of the List RPC.
+type ListResponse struct {
+	Jobs []Job `json:"jobs"`
+}
+
+// Empty is the response of the RPCs without a result.
+type Empty struct{}
+
+// server serves the RPCs of the service for a TaskManager.
+type server struct {
+	manager *taskman.TaskManager
+}
+
+// Register registers the service with the gRPC server, serving the following RPCs of the
+// taskman.Scheduler service for the TaskManager:
+//
+//	Schedule  schedule a job from a ScheduleRequest
+//	Remove    remove a job
+//	Pause     pause a job
+//	Resume    resume a paused job
+//	Trigger   execute a job immediately
+//	List      list the scheduled jobs, optionally by tag
+func Register(registrar grpc.ServiceRegistrar, manager *taskman.TaskManager) {
+	registrar.RegisterService(&grpc.ServiceDesc{
+		ServiceName: serviceName,
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{
+			method("Schedule", (*server).schedule),
+			method("Remove", jobAction(manager.RemoveJob)),
+			method("Pause", jobAction(manager.PauseJob)),
+			method("Resume", jobAction(manager.ResumeJob)),
+			method("Trigger", jobAction(manager.TriggerJob)),
+			method("List", (*server).list),
+		},
+	}, &server{manager: manager})
+}
+
+// method returns the description of a unary RPC handled by call.
+func method[Req, Resp any](name string, call func(s *server, ctx context.Context, req *Req) (*Resp, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			req := new(Req)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			s := srv.(*server)
+			if interceptor == nil {
+				return call(s, ctx, req)
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/" + name}
+			return interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+				return call(s, ctx, req.(*Req))
+			})
+		},
+	}
+}
+
+// schedule schedules the job of the request.
+func (s *server) schedule(ctx context.Context, req *ScheduleRequest) (*ScheduleResponse, error) {
+	err := s.manager.LoadSchedule(taskman.ScheduleFile{Jobs: []taskman.ScheduleEntry{req.Job}})
+	switch {
+	case err == nil:
+		return &ScheduleResponse{ID: req.Job.Name}, nil
+	case errors.Is(err, taskman.ErrDuplicateJobID):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, taskman.ErrQueueFull):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, taskman.ErrManagerStopped):
+		return nil, status.Error(codes.Unavailable, err.Error())
+	default:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+}
+
+// jobAction returns a handler applying the action to the job of the request's ID.
+func jobAction(action func(jobID string) error) func(s *server, ctx context.Context, req *JobRequest) (*Empty, error) {
+	return func(s *server, ctx context.Context, req *JobRequest) (*Empty, error) {
+		err := action(req.ID)
+		switch {
+		case err == nil:
+			return &Empty{}, nil
+		case errors.Is(err, taskman.ErrJobNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		default:
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+}
+
+// list lists the scheduled jobs, or those with the request's tag.
+func (s *server) list(ctx context.Context, req *ListRequest) (*ListResponse, error) {
+	var infos []taskman.JobInfo
+	if req.Tag != "" {
+		infos = s.manager.JobsByTag(req.Tag)
+	} else {
+		infos = s.manager.Jobs()
+	}
+	jobs := make([]Job, 0, len(infos))
+	for _, info := range infos {
+		jobs = append(jobs, jobOf(info))
+	}
+	return &ListResponse{Jobs: jobs}, nil
+}
+
+// jobOf returns the representation of a job in the service's messages.
+func jobOf(info taskman.JobInfo) Job {
+	return Job{
+		ID:        info.ID,
+		Cadence:   info.Cadence.String(),
+		NextExec:  info.NextExec,
+		TaskCount: info.TaskCount,
+		Running:   info.Running,
+		Paused:    info.Paused,
+		Tags:      info.Tags,
+		Metadata:  info.Metadata,
+	}
+}
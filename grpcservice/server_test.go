@@ -0,0 +1,106 @@
+package grpcservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	taskman "github.com/jkbrsn/go-taskman"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// executed receives the names of executed pingTasks.
+var executed = make(chan string, 8)
+
+// pingTask is a serializable task for testing.
+type pingTask struct {
+	Name string `json:"name"`
+}
+
+func (pt pingTask) Execute() error {
+	executed <- pt.Name
+	return nil
+}
+
+func (pt pingTask) TaskType() string { return "ping" }
+
+func (pt pingTask) MarshalTask() ([]byte, error) { return json.Marshal(pt) }
+
+// newManager returns a TaskManager with the pingTask type registered.
+func newManager(t *testing.T) *taskman.TaskManager {
+	manager := taskman.New(taskman.WithWorkers(1))
+	assert.NoError(t, manager.RegisterTaskType("ping", taskman.JSONTaskFactory[pingTask]()))
+	return manager
+}
+
+func TestServer(t *testing.T) {
+	manager := newManager(t)
+	defer manager.Stop()
+	s := &server{manager: manager}
+	ctx := context.Background()
+
+	entry := taskman.ScheduleEntry{Name: "ping-job", Task: "ping", Params: map[string]any{"name": "ping"},
+		Cadence: "1h", Tags: []string{"pings"}}
+	response, err := s.schedule(ctx, &ScheduleRequest{Job: entry})
+	assert.NoError(t, err)
+	assert.Equal(t, "ping-job", response.ID)
+
+	t.Run("Schedule errors", func(t *testing.T) {
+		_, err := s.schedule(ctx, &ScheduleRequest{Job: entry})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+		invalid := entry
+		invalid.Name = "invalid-job"
+		invalid.Task = "unknown"
+		_, err = s.schedule(ctx, &ScheduleRequest{Job: invalid})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("List", func(t *testing.T) {
+		list, err := s.list(ctx, &ListRequest{})
+		assert.NoError(t, err)
+		if assert.Len(t, list.Jobs, 1) {
+			assert.Equal(t, "1h0m0s", list.Jobs[0].Cadence)
+		}
+		list, err = s.list(ctx, &ListRequest{Tag: "other"})
+		assert.NoError(t, err)
+		assert.Empty(t, list.Jobs)
+	})
+
+	t.Run("Job actions", func(t *testing.T) {
+		trigger := jobAction(manager.TriggerJob)
+		_, err := trigger(s, ctx, &JobRequest{ID: "ping-job"})
+		assert.NoError(t, err)
+		select {
+		case name := <-executed:
+			assert.Equal(t, "ping", name)
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("Expected the triggered job to execute")
+		}
+
+		_, err = trigger(s, ctx, &JobRequest{ID: "missing-job"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Remove dead-lettered job", func(t *testing.T) {
+		job := taskman.Job{ID: "failing-job", Cadence: time.Hour, NextExec: time.Now().Add(time.Hour), DeadLetterThreshold: 1,
+			Tasks: []taskman.Task{failingTask{}}}
+		assert.NoError(t, manager.ScheduleJob(job))
+		assert.NoError(t, manager.TriggerJob(job.ID))
+		assert.Eventually(t, func() bool { return len(manager.DeadLetteredJobs()) == 1 }, time.Second, time.Millisecond)
+
+		remove := jobAction(manager.RemoveJob)
+		_, err := remove(s, ctx, &JobRequest{ID: job.ID})
+		assert.NoError(t, err, "Expected the dead-lettered job to be removed")
+		assert.Empty(t, manager.DeadLetteredJobs())
+	})
+}
+
+// failingTask is a task which always fails.
+type failingTask struct{}
+
+func (failingTask) Execute() error { return errors.New("task failed") }
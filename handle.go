@@ -0,0 +1,98 @@
+package taskman
+
+import (
+	"context"
+	"fmt"
+)
+
+// JobHandle refers to a job scheduled in a TaskManager, as an alternative to managing the job by
+// its ID.
+type JobHandle struct {
+	tm   *TaskManager
+	id   string
+	done <-chan struct{}
+}
+
+// ScheduleJobHandle schedules a job like ScheduleJob, and returns a handle to the scheduled job.
+// The job is removed from the TaskManager when ctx is cancelled.
+func (tm *TaskManager) ScheduleJobHandle(ctx context.Context, job Job) (*JobHandle, error) {
+	if err := tm.ScheduleJob(job); err != nil {
+		return nil, err
+	}
+	handle, err := tm.Handle(job.ID)
+	if err != nil {
+		// The job was removed right after being scheduled, e.g. a one-shot job already executed
+		done := make(chan struct{})
+		close(done)
+		return &JobHandle{tm: tm, id: job.ID, done: done}, nil
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			if err := tm.RemoveJob(job.ID); err != nil {
+				tm.logger.Debug("Job of cancelled context already removed", "jobID", job.ID)
+			}
+		case <-handle.done:
+		}
+	}()
+	return handle, nil
+}
+
+// Handle returns a handle to the scheduled or dead-lettered job with the given ID.
+func (tm *TaskManager) Handle(jobID string) (*JobHandle, error) {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	job, ok := tm.deadLetters[jobID]
+	if !ok {
+		jobIndex, err := tm.jobQueue.JobInQueue(jobID)
+		if err != nil {
+			return nil, fmt.Errorf("job with ID %s not found", jobID)
+		}
+		job = tm.jobQueue.jobs[jobIndex]
+	}
+	return &JobHandle{tm: tm, id: jobID, done: job.ctx.Done()}, nil
+}
+
+// ID returns the ID of the job.
+func (h *JobHandle) ID() string {
+	return h.id
+}
+
+// Cancel removes the job from the TaskManager, as with RemoveJob.
+func (h *JobHandle) Cancel() error {
+	return h.tm.RemoveJob(h.id)
+}
+
+// Pause pauses the job, as with PauseJob.
+func (h *JobHandle) Pause() error {
+	return h.tm.PauseJob(h.id)
+}
+
+// Resume resumes the paused job, as with ResumeJob.
+func (h *JobHandle) Resume() error {
+	return h.tm.ResumeJob(h.id)
+}
+
+// Trigger executes the job immediately, as with TriggerJob.
+func (h *JobHandle) Trigger() error {
+	return h.tm.TriggerJob(h.id)
+}
+
+// Info returns a snapshot of the job, as with Job.
+func (h *JobHandle) Info() (JobInfo, error) {
+	return h.tm.Job(h.id)
+}
+
+// Stats returns the execution statistics of the job, as with JobStats.
+func (h *JobHandle) Stats() (JobStats, error) {
+	return h.tm.JobStats(h.id)
+}
+
+// Done returns a channel which is closed once the job is done: when it has been removed from the
+// TaskManager, or the TaskManager has been stopped. For a one-shot job, the channel is closed once
+// its execution has finished.
+func (h *JobHandle) Done() <-chan struct{} {
+	return h.done
+}
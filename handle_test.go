@@ -0,0 +1,86 @@
+package taskman
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobHandle(t *testing.T) {
+	manager := NewCustom(2, 4, 1*time.Minute)
+	defer manager.Stop()
+
+	t.Run("Methods", func(t *testing.T) {
+		executions := make(chan struct{}, 4)
+		job := getMockedJob(1, "handle-job", time.Minute, time.Minute)
+		job.Tasks = []Task{MockTask{executeFunc: func() error {
+			executions <- struct{}{}
+			return nil
+		}}}
+		handle, err := manager.ScheduleJobHandle(context.Background(), job)
+		assert.NoError(t, err)
+		assert.Equal(t, "handle-job", handle.ID())
+
+		assert.NoError(t, handle.Trigger())
+		select {
+		case <-executions:
+		case <-time.After(20 * time.Millisecond):
+			t.Fatal("Expected the triggered job to execute")
+		}
+		assert.Eventually(t, func() bool {
+			stats, err := handle.Stats()
+			return err == nil && stats.TotalRuns == 1
+		}, 20*time.Millisecond, time.Millisecond, "Expected the stats of the job")
+
+		assert.NoError(t, handle.Pause())
+		info, err := handle.Info()
+		assert.NoError(t, err)
+		assert.True(t, info.Paused)
+		assert.NoError(t, handle.Resume())
+
+		assert.NoError(t, handle.Cancel())
+		select {
+		case <-handle.Done():
+		case <-time.After(20 * time.Millisecond):
+			t.Fatal("Expected the handle to be done once the job was removed")
+		}
+		assert.Error(t, handle.Cancel(), "Expected error cancelling a removed job")
+	})
+
+	t.Run("Context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		handle, err := manager.ScheduleJobHandle(ctx, getMockedJob(1, "context-job", time.Minute, time.Minute))
+		assert.NoError(t, err)
+
+		cancel()
+		select {
+		case <-handle.Done():
+		case <-time.After(20 * time.Millisecond):
+			t.Fatal("Expected the job to be removed when its context was cancelled")
+		}
+		_, err = manager.Job("context-job")
+		assert.Error(t, err)
+	})
+
+	t.Run("One-shot job", func(t *testing.T) {
+		jobID, err := manager.ScheduleOnce(MockTask{}, 10*time.Millisecond)
+		assert.NoError(t, err)
+		handle, err := manager.Handle(jobID)
+		assert.NoError(t, err)
+
+		select {
+		case <-handle.Done():
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("Expected the handle to be done once the one-shot job executed")
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := manager.Handle("missing-job")
+		assert.Error(t, err)
+		_, err = manager.ScheduleJobHandle(context.Background(), Job{ID: "invalid-job"})
+		assert.Error(t, err, "Expected error scheduling an invalid job")
+	})
+}
@@ -0,0 +1,140 @@
+package taskman
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	// defaultJobHistory is the default number of recent runs recorded in the history of each job.
+	defaultJobHistory = 10
+	// maxHistoryErrorLen is the max length of the error summary of a run in a job's history.
+	maxHistoryErrorLen = 256
+)
+
+// RunRecord is the record of a completed run of a job, as kept in the job's history. Runs skipped
+// on a date excluded by the job's Calendar are recorded as Skipped, neither succeeded nor failed.
+type RunRecord struct {
+	Started     time.Time     // Time the run was dispatched, according to the TaskManager's clock
+	Finished    time.Time     // Time the run's last task finished
+	Duration    time.Duration // Duration of the run
+	Succeeded   bool          // True if none of the run's tasks failed
+	Skipped     bool          // True if the run was skipped rather than executed, see Job.Calendar
+	FailedTasks int           // Number of the run's tasks which failed, after retries
+	Error       string        // Summary of the run's error, empty if the run succeeded
+}
+
+// runHistory holds the records of a fixed number of recent runs of a job. The zero value is an
+// empty history.
+// Note: not safe for concurrent use, guarded by the lock of its owner.
+type runHistory struct {
+	records []RunRecord // Records of the recent runs, used as a ring buffer once full
+	next    int         // Index of the record to overwrite next, once full
+}
+
+// record records a completed run, replacing the oldest one once the history holds size runs.
+func (h *runHistory) record(record RunRecord, size int) {
+	if size <= 0 {
+		return
+	}
+	if len(h.records) < size {
+		h.records = append(h.records, record)
+		return
+	}
+	h.records[h.next] = record
+	h.next = (h.next + 1) % len(h.records)
+}
+
+// list returns the records of the runs in the history, oldest first.
+func (h *runHistory) list() []RunRecord {
+	if len(h.records) == 0 {
+		return nil
+	}
+	return slices.Concat(h.records[h.next:], h.records[:h.next])
+}
+
+// newRunRecord returns the record of a run finished with the errors of its failed tasks.
+func newRunRecord(start time.Time, duration time.Duration, errs []error) RunRecord {
+	record := RunRecord{
+		Started:     start,
+		Finished:    start.Add(duration),
+		Duration:    duration,
+		Succeeded:   len(errs) == 0,
+		FailedTasks: len(errs),
+	}
+	if len(errs) > 0 {
+		record.Error = errs[0].Error()
+		if len(errs) > 1 {
+			record.Error = fmt.Sprintf("%s (and %d more errors)", record.Error, len(errs)-1)
+		}
+		if len(record.Error) > maxHistoryErrorLen {
+			record.Error = record.Error[:maxHistoryErrorLen-3] + "..."
+		}
+	}
+	return record
+}
+
+// SetJobHistory sets the number of recent runs recorded in the history of each job, see
+// JobHistory. A size of 0 disables the history. If persist is set, and the TaskManager has a job
+// store, each job's record is saved after every run so that the stored history is complete,
+// otherwise the history is stored as of the job's last save. Defaults to 10 runs, not persisted.
+func (tm *TaskManager) SetJobHistory(size int, persist bool) error {
+	if size < 0 {
+		return errors.New("invalid job history size, must not be negative")
+	}
+	tm.historySize.Store(int32(size))
+	tm.persistHistory.Store(persist)
+	return nil
+}
+
+// JobHistory returns the records of the recent completed runs of the job with the given ID, oldest
+// first, e.g. for finding out whether a job ran last night and how the run went. Runs skipped on
+// dates excluded by the job's Calendar are included. The number of runs kept is set by
+// SetJobHistory.
+func (tm *TaskManager) JobHistory(jobID string) ([]RunRecord, error) {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	if job, ok := tm.deadLetters[jobID]; ok {
+		return job.state.stats.historyList(), nil
+	}
+	jobIndex, err := tm.jobQueue.JobInQueue(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("job with ID %s not found", jobID)
+	}
+	return tm.jobQueue.jobs[jobIndex].state.stats.historyList(), nil
+}
+
+// runHistory returns the history of the job, or the history restored from the job store if the
+// job is not yet scheduled.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (j *Job) runHistory() []RunRecord {
+	if j.state == nil {
+		return j.history
+	}
+	return j.state.stats.historyList()
+}
+
+// saveHistory saves the record of the job of a finished run in the job store, if the history is
+// persisted and the job is still scheduled.
+// Note: must not be called while holding the mutex lock, as store I/O may block.
+func (tm *TaskManager) saveHistory(job *Job) {
+	if !tm.persistHistory.Load() {
+		return
+	}
+	tm.RLock()
+	store := tm.store
+	index, err := tm.jobQueue.JobInQueue(job.ID)
+	if store == nil || err != nil {
+		tm.RUnlock()
+		return
+	}
+	record, err := tm.jobQueue.jobs[index].record()
+	tm.RUnlock()
+	if err != nil {
+		tm.logger.Warn("Failed to serialize tasks of job", "jobID", job.ID, "error", err)
+	}
+	tm.persistJob(store, record)
+}
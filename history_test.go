@@ -0,0 +1,98 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunHistory(t *testing.T) {
+	var history runHistory
+	assert.Nil(t, history.list(), "Expected an empty history")
+	for i := range 5 {
+		history.record(RunRecord{FailedTasks: i}, 3)
+	}
+	records := history.list()
+	assert.Len(t, records, 3)
+	for i, record := range records {
+		assert.Equal(t, i+2, record.FailedTasks, "Expected the most recent runs, oldest first")
+	}
+
+	history.record(RunRecord{}, 0)
+	assert.Len(t, history.list(), 3, "Expected nothing recorded with a size of 0")
+
+	record := newRunRecord(time.Now(), time.Second, []error{errors.New(strings.Repeat("x", 300)), errors.New("other")})
+	assert.False(t, record.Succeeded)
+	assert.Equal(t, 2, record.FailedTasks)
+	assert.Len(t, record.Error, maxHistoryErrorLen)
+	assert.True(t, newRunRecord(time.Now(), time.Second, nil).Succeeded)
+}
+
+func TestJobHistory(t *testing.T) {
+	manager := New(WithWorkers(1), WithJobHistory(2, false))
+	defer manager.Stop()
+
+	failing := errors.New("failure")
+	fail := false
+	job := getMockedJob(1, "history-job", time.Hour, time.Hour)
+	job.Tasks[0] = MockTask{executeFunc: func() error {
+		if fail {
+			return failing
+		}
+		return nil
+	}}
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for _, f := range []bool{false, false, true} {
+		fail = f
+		_, err := manager.RunJobNow(ctx, job.ID)
+		assert.NoError(t, err)
+	}
+
+	history, err := manager.JobHistory(job.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 2) {
+		assert.True(t, history[0].Succeeded)
+		assert.False(t, history[1].Succeeded)
+		assert.Equal(t, 1, history[1].FailedTasks)
+		assert.Contains(t, history[1].Error, "failure")
+		assert.False(t, history[1].Finished.Before(history[1].Started))
+	}
+
+	_, err = manager.JobHistory("missing")
+	assert.Error(t, err)
+	assert.Error(t, manager.SetJobHistory(-1, false), "Expected error setting a negative size")
+}
+
+func TestJobHistoryPersistence(t *testing.T) {
+	store := NewMemoryJobStore()
+	manager := New(WithWorkers(1), WithJobStore(store), WithJobHistory(10, true))
+	job := getMockedJob(1, "persisted-history", time.Hour, time.Hour)
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := manager.RunJobNow(ctx, job.ID)
+	assert.NoError(t, err)
+
+	// The record is saved after the run, with the run in its history
+	assert.Eventually(t, func() bool {
+		record, err := store.Load(job.ID)
+		return err == nil && len(record.History) == 1
+	}, time.Second, 5*time.Millisecond)
+	manager.Stop()
+
+	// The history is restored along with the job
+	manager = New(WithWorkers(1), WithJobStore(store))
+	defer manager.Stop()
+	assert.NoError(t, manager.ScheduleJob(job))
+	history, err := manager.JobHistory(job.ID)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+}
@@ -0,0 +1,138 @@
+package taskman
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// hooks holds the lifecycle callbacks registered with a TaskManager.
+type hooks struct {
+	mu sync.RWMutex
+
+	jobStart    []func(jobID string)
+	jobComplete []func(jobID string, duration time.Duration, err error)
+	taskError   []func(jobID string, err error)
+	jobRemoved  []func(jobID string)
+	jobResult   []func(result JobResult)
+}
+
+// TaskResult is the outcome of one of the tasks of a job run.
+type TaskResult struct {
+	TaskIndex int            // Index of the task within the job's tasks
+	TaskID    string         // ID of the task, if it is a NamedTask
+	Duration  time.Duration  // Time the task took to execute, including retries
+	Err       error          // Error of the task's last attempt, nil if it succeeded
+	Data      map[string]any // Output of the task's last attempt, if it is a ResultTask
+	Skipped   bool           // True if the task was skipped, after a failed task of an ExecutionStopOnError job or its removal
+}
+
+// JobResult is the outcome of a job run, collecting the results of all of the run's tasks.
+type JobResult struct {
+	JobID       string       // ID of the job
+	Started     time.Time    // Time the run was dispatched
+	Finished    time.Time    // Time the run's last task finished
+	TaskResults []TaskResult // Results of the run's tasks, in the order of the job's tasks
+	FallbackErr error        // Error of the job's Fallback, if it was executed after the run failed
+}
+
+// Err returns the errors of the run's failed tasks joined, or nil if no task failed.
+func (r JobResult) Err() error {
+	var errs []error
+	for _, result := range r.TaskResults {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OnJobStart registers a callback which is called every time a job is dispatched for execution,
+// before its tasks are sent to the worker pool.
+// Note: callbacks are called synchronously from the TaskManager's run loop, and should return
+// quickly to not delay the dispatch of other jobs.
+func (tm *TaskManager) OnJobStart(fn func(jobID string)) {
+	tm.hooks.mu.Lock()
+	defer tm.hooks.mu.Unlock()
+	tm.hooks.jobStart = append(tm.hooks.jobStart, fn)
+}
+
+// OnJobComplete registers a callback which is called every time all tasks of a dispatched job
+// have finished executing. The duration is measured from dispatch until the last task finished,
+// and err joins the errors of all failed tasks, or is nil if all tasks succeeded.
+// Note: callbacks are called synchronously from the worker executing the job's last task.
+func (tm *TaskManager) OnJobComplete(fn func(jobID string, duration time.Duration, err error)) {
+	tm.hooks.mu.Lock()
+	defer tm.hooks.mu.Unlock()
+	tm.hooks.jobComplete = append(tm.hooks.jobComplete, fn)
+}
+
+// OnTaskError registers a callback which is called every time a task fails, after any retries.
+// Note: callbacks are called synchronously from the worker executing the task.
+func (tm *TaskManager) OnTaskError(fn func(jobID string, err error)) {
+	tm.hooks.mu.Lock()
+	defer tm.hooks.mu.Unlock()
+	tm.hooks.taskError = append(tm.hooks.taskError, fn)
+}
+
+// OnJobRemoved registers a callback which is called every time a job is removed from the
+// TaskManager, either through RemoveJob or automatically, e.g. a one-shot job after execution.
+func (tm *TaskManager) OnJobRemoved(fn func(jobID string)) {
+	tm.hooks.mu.Lock()
+	defer tm.hooks.mu.Unlock()
+	tm.hooks.jobRemoved = append(tm.hooks.jobRemoved, fn)
+}
+
+// OnJobResult registers a callback which is called with the result of every job run, once all of
+// the run's tasks have finished executing, for correlating the outcomes of the tasks of a run.
+// Note: callbacks are called synchronously from the worker executing the job's last task.
+func (tm *TaskManager) OnJobResult(fn func(result JobResult)) {
+	tm.hooks.mu.Lock()
+	defer tm.hooks.mu.Unlock()
+	tm.hooks.jobResult = append(tm.hooks.jobResult, fn)
+}
+
+// jobStarted calls the registered job start callbacks.
+func (h *hooks) jobStarted(jobID string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, fn := range h.jobStart {
+		fn(jobID)
+	}
+}
+
+// jobCompleted calls the registered job completion callbacks.
+func (h *hooks) jobCompleted(jobID string, duration time.Duration, err error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, fn := range h.jobComplete {
+		fn(jobID, duration, err)
+	}
+}
+
+// taskFailed calls the registered task error callbacks.
+func (h *hooks) taskFailed(jobID string, err error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, fn := range h.taskError {
+		fn(jobID, err)
+	}
+}
+
+// jobWasRemoved calls the registered job removal callbacks.
+func (h *hooks) jobWasRemoved(jobID string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, fn := range h.jobRemoved {
+		fn(jobID)
+	}
+}
+
+// jobResulted calls the registered job result callbacks.
+func (h *hooks) jobResulted(result JobResult) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, fn := range h.jobResult {
+		fn(result)
+	}
+}
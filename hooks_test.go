@@ -0,0 +1,145 @@
+package taskman
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycleHooks(t *testing.T) {
+	manager := NewCustom(2, 4, 1*time.Minute)
+	defer manager.Stop()
+
+	var mu sync.Mutex
+	var started, removed []string
+	var taskErrs []error
+	completed := make(chan error, 1)
+
+	manager.OnJobStart(func(jobID string) {
+		mu.Lock()
+		started = append(started, jobID)
+		mu.Unlock()
+	})
+	manager.OnTaskError(func(jobID string, err error) {
+		mu.Lock()
+		taskErrs = append(taskErrs, err)
+		mu.Unlock()
+	})
+	manager.OnJobComplete(func(jobID string, duration time.Duration, err error) {
+		assert.Equal(t, "hooked-job", jobID)
+		assert.GreaterOrEqual(t, duration, 5*time.Millisecond, "Expected duration to cover the slowest task")
+		completed <- err
+	})
+	manager.OnJobRemoved(func(jobID string) {
+		mu.Lock()
+		removed = append(removed, jobID)
+		mu.Unlock()
+		// Removal hooks are called without holding the lock, so using the manager is safe
+		_ = manager.Metrics()
+	})
+
+	job := Job{
+		ID:       "hooked-job",
+		Cadence:  1 * time.Second,
+		NextExec: time.Now(),
+		Tasks: []Task{
+			MockTask{ID: "slow-task", executeFunc: func() error {
+				time.Sleep(5 * time.Millisecond)
+				return nil
+			}},
+			MockTask{ID: "failing-task", executeFunc: func() error {
+				return errors.New("task failed")
+			}},
+		},
+	}
+	err := manager.ScheduleJob(job)
+	assert.NoError(t, err, "Expected no error scheduling job")
+
+	select {
+	case err := <-completed:
+		var taskErr *TaskError
+		assert.ErrorAs(t, err, &taskErr, "Expected job completion to carry the task error")
+		assert.Equal(t, 1, taskErr.TaskIndex, "Expected the error to originate from the second task")
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected job completion hook to be called")
+	}
+
+	err = manager.RemoveJob(job.ID)
+	assert.NoError(t, err, "Expected no error removing job")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"hooked-job"}, started, "Expected one job start")
+	assert.Len(t, taskErrs, 1, "Expected one task error")
+	assert.Equal(t, []string{"hooked-job"}, removed, "Expected one job removal")
+}
+
+func TestLifecycleHooksOneShot(t *testing.T) {
+	manager := NewCustom(1, 2, 1*time.Minute)
+	defer manager.Stop()
+
+	removed := make(chan string, 1)
+	manager.OnJobRemoved(func(jobID string) {
+		removed <- jobID
+	})
+
+	jobID, err := manager.ScheduleOnce(MockTask{ID: "once-task"}, 0)
+	assert.NoError(t, err)
+
+	select {
+	case removedID := <-removed:
+		assert.Equal(t, jobID, removedID, "Expected automatic removal of the one-shot job to call the hook")
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected job removal hook to be called")
+	}
+}
+
+func TestJobResultHook(t *testing.T) {
+	manager := NewCustom(2, 4, 1*time.Minute)
+	defer manager.Stop()
+
+	results := make(chan JobResult, 1)
+	manager.OnJobResult(func(result JobResult) { results <- result })
+
+	before := time.Now()
+	job := Job{
+		ID:            "result-job",
+		Cadence:       1 * time.Minute,
+		NextExec:      time.Now(),
+		ExecutionMode: ExecutionStopOnError,
+		Tasks: []Task{
+			MockTask{ID: "slow-task", executeFunc: func() error {
+				time.Sleep(5 * time.Millisecond)
+				return nil
+			}},
+			MockTask{ID: "failing-task", executeFunc: func() error {
+				return errors.New("task failed")
+			}},
+			MockTask{ID: "skipped-task"},
+		},
+	}
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	select {
+	case result := <-results:
+		assert.Equal(t, "result-job", result.JobID)
+		assert.False(t, result.Started.Before(before), "Expected the start of the run")
+		assert.False(t, result.Finished.Before(result.Started.Add(5*time.Millisecond)), "Expected the run to end after the slowest task")
+		if assert.Len(t, result.TaskResults, 3) {
+			assert.Equal(t, 0, result.TaskResults[0].TaskIndex)
+			assert.NoError(t, result.TaskResults[0].Err)
+			assert.GreaterOrEqual(t, result.TaskResults[0].Duration, 5*time.Millisecond)
+			assert.Equal(t, 1, result.TaskResults[1].TaskIndex)
+			assert.ErrorContains(t, result.TaskResults[1].Err, "task failed")
+			assert.True(t, result.TaskResults[2].Skipped, "Expected the task after the failure to be skipped")
+		}
+		var taskErr *TaskError
+		assert.ErrorAs(t, result.Err(), &taskErr)
+		assert.Equal(t, 1, taskErr.TaskIndex)
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected job result hook to be called")
+	}
+}
@@ -0,0 +1,132 @@
+package taskman
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPTaskType is the name HTTPTask is registered with in every TaskManager, e.g. for the task of
+// schedule file entries.
+const HTTPTaskType = "http"
+
+// HTTPTask is a task sending an HTTP request, e.g. for pinging an endpoint or calling a webhook at
+// a cadence. The task fails if the response does not have the expected status. As a ResultTask,
+// its output has the response's status code under "status_code" and the request's latency, as a
+// time.Duration, under "latency".
+//
+// The task is serialized as JSON, with Timeout as a duration string, e.g. in a schedule file:
+//
+//	task: http
+//	params: {url: "https://example.com/health", timeout: 5s}
+type HTTPTask struct {
+	Method         string            `json:"method,omitempty"`          // Method of the request, GET if empty
+	URL            string            `json:"url"`                       // URL of the request
+	Headers        map[string]string `json:"headers,omitempty"`         // Headers of the request, if any
+	Body           string            `json:"body,omitempty"`            // Body of the request, if any
+	ExpectedStatus int               `json:"expected_status,omitempty"` // Expected status of the response, any 2xx status if 0
+	Timeout        time.Duration     `json:"-"`                         // Timeout of the request, no timeout but the context's if 0
+}
+
+// Execute sends the request with a background context.
+func (ht HTTPTask) Execute() error {
+	_, err := ht.ExecuteResult(context.Background())
+	return err
+}
+
+// ExecuteResult sends the request, returning the response's status code and the request's
+// latency. The output is returned along with the error of an unexpected status.
+func (ht HTTPTask) ExecuteResult(ctx context.Context) (map[string]any, error) {
+	if ht.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ht.Timeout)
+		defer cancel()
+	}
+	method := ht.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	var body io.Reader
+	if ht.Body != "" {
+		body = strings.NewReader(ht.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, ht.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, value := range ht.Headers {
+		req.Header.Set(key, value)
+	}
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	// Drain the body, so that the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	data := map[string]any{"status_code": resp.StatusCode, "latency": time.Since(start)}
+
+	if !ht.expected(resp.StatusCode) {
+		return data, fmt.Errorf("unexpected status %d from %s %s", resp.StatusCode, method, ht.URL)
+	}
+	return data, nil
+}
+
+// expected returns true if the status is the expected status of the response.
+func (ht HTTPTask) expected(status int) bool {
+	if ht.ExpectedStatus == 0 {
+		return status >= 200 && status < 300
+	}
+	return status == ht.ExpectedStatus
+}
+
+// TaskType returns the name HTTPTask is registered with.
+func (ht HTTPTask) TaskType() string { return HTTPTaskType }
+
+// MarshalTask serializes the task as JSON.
+func (ht HTTPTask) MarshalTask() ([]byte, error) { return json.Marshal(ht) }
+
+// httpTaskJSON is the JSON representation of an HTTPTask, with the timeout as a duration string.
+type httpTaskJSON struct {
+	httpTask
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// httpTask is an HTTPTask without its methods, for encoding its fields as JSON.
+type httpTask HTTPTask
+
+// MarshalJSON encodes the task as JSON, with the timeout as a duration string.
+func (ht HTTPTask) MarshalJSON() ([]byte, error) {
+	encoded := httpTaskJSON{httpTask: httpTask(ht)}
+	if ht.Timeout > 0 {
+		encoded.Timeout = ht.Timeout.String()
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes the task from JSON, with the timeout as a duration string.
+func (ht *HTTPTask) UnmarshalJSON(data []byte) error {
+	var decoded httpTaskJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*ht = HTTPTask(decoded.httpTask)
+	if decoded.Timeout != "" {
+		timeout, err := time.ParseDuration(decoded.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout: %w", err)
+		}
+		ht.Timeout = timeout
+	}
+	if ht.URL == "" {
+		return errors.New("http task has no URL")
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package taskman
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPTask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/slow":
+			time.Sleep(50 * time.Millisecond)
+		case r.Method == http.MethodPost && r.Header.Get("X-Token") == "secret" && string(body) == "ping":
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	data, err := HTTPTask{URL: server.URL}.ExecuteResult(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, data["status_code"])
+	assert.IsType(t, time.Duration(0), data["latency"])
+
+	task := HTTPTask{
+		Method:         http.MethodPost,
+		URL:            server.URL,
+		Headers:        map[string]string{"X-Token": "secret"},
+		Body:           "ping",
+		ExpectedStatus: http.StatusAccepted,
+	}
+	assert.NoError(t, task.Execute())
+
+	task.Headers = nil
+	data, err = task.ExecuteResult(context.Background())
+	assert.ErrorContains(t, err, "unexpected status 400")
+	assert.Equal(t, http.StatusBadRequest, data["status_code"], "Expected the output along with the error")
+
+	assert.Error(t, HTTPTask{URL: server.URL + "/slow", Timeout: 10 * time.Millisecond}.Execute(), "Expected error on timeout")
+}
+
+func TestHTTPTaskSerialization(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+
+	task := HTTPTask{URL: "https://example.com", Headers: map[string]string{"Accept": "text/plain"}, Timeout: 5 * time.Second}
+	records, err := marshalTasks([]Task{task})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"url": "https://example.com", "headers": {"Accept": "text/plain"}, "timeout": "5s"}`, string(records[0].Data))
+
+	// HTTPTask is registered in every TaskManager
+	tasks, err := manager.unmarshalTasks(records)
+	assert.NoError(t, err)
+	assert.Equal(t, []Task{task}, tasks)
+
+	_, err = manager.unmarshalTasks([]TaskRecord{{Type: HTTPTaskType, Data: []byte(`{"url": "https://example.com", "timeout": "soon"}`)}})
+	assert.Error(t, err, "Expected error deserializing an invalid timeout")
+	_, err = manager.unmarshalTasks([]TaskRecord{{Type: HTTPTaskType, Data: []byte(`{}`)}})
+	assert.Error(t, err, "Expected error deserializing a task without URL")
+
+	file, err := ParseScheduleFile([]byte("jobs:\n  - name: ping\n    task: http\n    params: {url: \"https://example.com\", timeout: 5s}\n    cadence: 1h\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, manager.LoadSchedule(file))
+	manager.RLock()
+	index, err := manager.jobQueue.JobInQueue("ping")
+	assert.NoError(t, err)
+	assert.Equal(t, HTTPTask{URL: "https://example.com", Timeout: 5 * time.Second}, manager.jobQueue.jobs[index].Tasks[0])
+	manager.RUnlock()
+}
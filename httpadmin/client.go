@@ -0,0 +1,131 @@
+package httpadmin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client is a client of the admin endpoints served by a handler returned by New, e.g. for
+// controlling a TaskManager running in another process.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient returns a Client for the admin endpoints served at baseURL, including any prefix the
+// handler is mounted under, e.g. "http://localhost:8080/taskman". If httpClient is nil,
+// http.DefaultClient is used.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: httpClient}
+}
+
+// Jobs returns all scheduled jobs.
+func (c *Client) Jobs(ctx context.Context) ([]Job, error) {
+	var jobs []Job
+	err := c.do(ctx, http.MethodGet, "/jobs", nil, &jobs)
+	return jobs, err
+}
+
+// Job returns the job with the given ID.
+func (c *Client) Job(ctx context.Context, jobID string) (Job, error) {
+	var job Job
+	err := c.do(ctx, http.MethodGet, "/jobs/"+url.PathEscape(jobID), nil, &job)
+	return job, err
+}
+
+// Stats returns the execution statistics of the job with the given ID.
+func (c *Client) Stats(ctx context.Context, jobID string) (Stats, error) {
+	var stats Stats
+	err := c.do(ctx, http.MethodGet, "/jobs/"+url.PathEscape(jobID)+"/stats", nil, &stats)
+	return stats, err
+}
+
+// TriggerJob executes the job with the given ID immediately.
+func (c *Client) TriggerJob(ctx context.Context, jobID string) error {
+	return c.do(ctx, http.MethodPost, "/jobs/"+url.PathEscape(jobID)+"/trigger", nil, nil)
+}
+
+// PauseJob pauses the job with the given ID.
+func (c *Client) PauseJob(ctx context.Context, jobID string) error {
+	return c.do(ctx, http.MethodPost, "/jobs/"+url.PathEscape(jobID)+"/pause", nil, nil)
+}
+
+// ResumeJob resumes the paused job with the given ID.
+func (c *Client) ResumeJob(ctx context.Context, jobID string) error {
+	return c.do(ctx, http.MethodPost, "/jobs/"+url.PathEscape(jobID)+"/resume", nil, nil)
+}
+
+// RemoveJob removes the job with the given ID.
+func (c *Client) RemoveJob(ctx context.Context, jobID string) error {
+	return c.do(ctx, http.MethodDelete, "/jobs/"+url.PathEscape(jobID), nil, nil)
+}
+
+// Metrics returns the metrics of the TaskManager.
+func (c *Client) Metrics(ctx context.Context) (Metrics, error) {
+	var metrics Metrics
+	err := c.do(ctx, http.MethodGet, "/metrics", nil, &metrics)
+	return metrics, err
+}
+
+// WorkerCount returns the worker count of the worker pool.
+func (c *Client) WorkerCount(ctx context.Context) (int, error) {
+	var workers Workers
+	err := c.do(ctx, http.MethodGet, "/workers", nil, &workers)
+	return workers.Workers, err
+}
+
+// SetWorkerCount sets the worker count of the worker pool.
+func (c *Client) SetWorkerCount(ctx context.Context, count int) error {
+	return c.do(ctx, http.MethodPut, "/workers", Workers{Workers: count}, nil)
+}
+
+// do sends a request with the JSON encoded body, if any, and decodes the JSON body of a
+// successful response into v, if not nil. Failed requests return the error of the response body.
+func (c *Client) do(ctx context.Context, method, path string, body, v any) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var failure errorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&failure); err != nil || failure.Error == "" {
+			return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+		}
+		return errors.New(failure.Error)
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package httpadmin
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	taskman "github.com/jkbrsn/go-taskman"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	manager := taskman.New(taskman.WithWorkers(1))
+	defer manager.Stop()
+	server := httptest.NewServer(New(manager))
+	defer server.Close()
+	client := NewClient(server.URL+"/", nil)
+	ctx := context.Background()
+
+	executed := make(chan struct{}, 1)
+	job := taskman.Job{ID: "client-job", Cadence: time.Hour, NextExec: time.Now().Add(time.Hour),
+		Tasks: []taskman.Task{funcTask(func() error {
+			executed <- struct{}{}
+			return nil
+		})}}
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	t.Run("Jobs", func(t *testing.T) {
+		jobs, err := client.Jobs(ctx)
+		assert.NoError(t, err)
+		if assert.Len(t, jobs, 1) {
+			assert.Equal(t, "client-job", jobs[0].ID)
+		}
+
+		got, err := client.Job(ctx, "client-job")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, got.TaskCount)
+
+		_, err = client.Job(ctx, "missing-job")
+		assert.ErrorContains(t, err, "missing-job", "Expected the error of the response body")
+	})
+
+	t.Run("Job actions", func(t *testing.T) {
+		assert.NoError(t, client.TriggerJob(ctx, "client-job"))
+		select {
+		case <-executed:
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("Expected the triggered job to execute")
+		}
+		assert.Eventually(t, func() bool {
+			stats, err := client.Stats(ctx, "client-job")
+			return err == nil && stats.TotalRuns == 1
+		}, 50*time.Millisecond, time.Millisecond, "Expected the stats to count the triggered run")
+
+		assert.NoError(t, client.PauseJob(ctx, "client-job"))
+		got, err := client.Job(ctx, "client-job")
+		assert.NoError(t, err)
+		assert.True(t, got.Paused)
+		assert.NoError(t, client.ResumeJob(ctx, "client-job"))
+	})
+
+	t.Run("Metrics and workers", func(t *testing.T) {
+		metrics, err := client.Metrics(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, metrics.QueuedJobs)
+
+		assert.NoError(t, client.SetWorkerCount(ctx, 2))
+		assert.Eventually(t, func() bool {
+			count, err := client.WorkerCount(ctx)
+			return err == nil && count == 2
+		}, 50*time.Millisecond, time.Millisecond, "Expected the worker pool to be resized")
+		assert.Error(t, client.SetWorkerCount(ctx, 0), "Expected an error for an invalid worker count")
+	})
+
+	t.Run("Remove job", func(t *testing.T) {
+		assert.NoError(t, client.RemoveJob(ctx, "client-job"))
+		assert.Error(t, client.RemoveJob(ctx, "client-job"), "Expected an error removing a missing job")
+	})
+}
@@ -0,0 +1,277 @@
+// Package httpadmin provides an http.Handler exposing the jobs and metrics of a TaskManager as
+// JSON endpoints, and allowing operators to trigger, pause, resume and remove jobs and to resize
+// the worker pool at runtime.
+//
+// The handler performs no authentication or authorization, wrap it in middleware doing so before
+// exposing it beyond trusted networks. Mount it under a prefix with http.StripPrefix, e.g.
+//
+//	mux.Handle("/taskman/", http.StripPrefix("/taskman", httpadmin.New(manager)))
+package httpadmin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	taskman "github.com/jkbrsn/go-taskman"
+)
+
+// Job is the JSON representation of a scheduled job.
+type Job struct {
+	ID        string            `json:"id"`
+	Cadence   string            `json:"cadence"`
+	NextExec  time.Time         `json:"next_exec"`
+	TaskCount int               `json:"task_count"`
+	Running   int               `json:"running"`
+	Paused    bool              `json:"paused"`
+	Tags      []string          `json:"tags,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// Stats is the JSON representation of the execution statistics of a job.
+type Stats struct {
+	TotalRuns           int       `json:"total_runs"`
+	FailedRuns          int       `json:"failed_runs"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	SkippedRuns         int       `json:"skipped_runs"`
+	LastRun             time.Time `json:"last_run"`
+	LastDuration        string    `json:"last_duration"`
+	LastError           string    `json:"last_error,omitempty"`
+	AverageDuration     string    `json:"average_duration"`
+	Overrunning         bool      `json:"overrunning"`
+	Lateness            Lateness  `json:"lateness"`
+	TotalTaskTime       string    `json:"total_task_time"`
+	TotalCPUTime        string    `json:"total_cpu_time"`
+
+	TaskFailures map[string]int `json:"task_failures,omitempty"`
+}
+
+// Lateness is the JSON representation of the lateness of recent dispatches.
+type Lateness struct {
+	P50 string `json:"p50"`
+	P95 string `json:"p95"`
+	Max string `json:"max"`
+}
+
+// Metrics is the JSON representation of the metrics of a TaskManager.
+type Metrics struct {
+	QueuedJobs           int      `json:"queued_jobs"`
+	QueuedTasks          int      `json:"queued_tasks"`
+	QueueMaxJobWidth     int      `json:"queue_max_job_width"`
+	JobsOverrunning      int      `json:"jobs_overrunning"`
+	DispatchLateness     Lateness `json:"dispatch_lateness"`
+	RunsShed             int      `json:"runs_shed"`
+	RunsCoalesced        int      `json:"runs_coalesced"`
+	TasksSpilled         int      `json:"tasks_spilled"`
+	DispatchBlocked      string   `json:"dispatch_blocked"`
+	TaskAverageExecTime  string   `json:"task_average_exec_time"`
+	TasksTotalExecutions int      `json:"tasks_total_executions"`
+	TasksPerSecond       float32  `json:"tasks_per_second"`
+	TaskTotalExecTime    string   `json:"task_total_exec_time"`
+	TaskTotalCPUTime     string   `json:"task_total_cpu_time"`
+	DroppedErrors        int      `json:"dropped_errors"`
+	DroppedResults       int      `json:"dropped_results"`
+	DroppedExecTimes     int      `json:"dropped_exec_times"`
+	ResultsBlocked       string   `json:"results_blocked"`
+	WorkerCountTarget    int      `json:"worker_count_target"`
+	WorkerScalingEvents  int      `json:"worker_scaling_events"`
+	WorkerUtilization    float32  `json:"worker_utilization"`
+	WorkersActive        int      `json:"workers_active"`
+	WorkersDraining      int      `json:"workers_draining"`
+	WorkersRunning       int      `json:"workers_running"`
+}
+
+// Workers is the JSON representation of the worker count of the worker pool, as read and set
+// through the /workers endpoint.
+type Workers struct {
+	Workers int `json:"workers"`
+}
+
+// errorResponse is the JSON body of failed requests.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// handler serves the admin endpoints of a TaskManager.
+type handler struct {
+	manager *taskman.TaskManager
+	mux     *http.ServeMux
+}
+
+// New returns an http.Handler serving the following endpoints for the TaskManager:
+//
+//	GET    /jobs               list all scheduled jobs
+//	GET    /jobs/{id}          get a job
+//	DELETE /jobs/{id}          remove a job
+//	GET    /jobs/{id}/stats    get the execution statistics of a job
+//	POST   /jobs/{id}/trigger  execute a job immediately
+//	POST   /jobs/{id}/pause    pause a job
+//	POST   /jobs/{id}/resume   resume a paused job
+//	GET    /metrics            get the TaskManager's metrics
+//	GET    /workers            get the worker count of the worker pool
+//	PUT    /workers            set the worker count, with a body like {"workers": 8}
+func New(manager *taskman.TaskManager) http.Handler {
+	h := &handler{manager: manager, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /jobs", h.listJobs)
+	h.mux.HandleFunc("GET /jobs/{id}", h.getJob)
+	h.mux.HandleFunc("DELETE /jobs/{id}", h.jobAction(manager.RemoveJob))
+	h.mux.HandleFunc("GET /jobs/{id}/stats", h.getStats)
+	h.mux.HandleFunc("POST /jobs/{id}/trigger", h.jobAction(manager.TriggerJob))
+	h.mux.HandleFunc("POST /jobs/{id}/pause", h.jobAction(manager.PauseJob))
+	h.mux.HandleFunc("POST /jobs/{id}/resume", h.jobAction(manager.ResumeJob))
+	h.mux.HandleFunc("GET /metrics", h.getMetrics)
+	h.mux.HandleFunc("GET /workers", h.getWorkers)
+	h.mux.HandleFunc("PUT /workers", h.setWorkers)
+	return h
+}
+
+// ServeHTTP dispatches the request to the handler of its endpoint.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// listJobs responds with all scheduled jobs.
+func (h *handler) listJobs(w http.ResponseWriter, r *http.Request) {
+	infos := h.manager.Jobs()
+	jobs := make([]Job, 0, len(infos))
+	for _, info := range infos {
+		jobs = append(jobs, jobOf(info))
+	}
+	writeJSON(w, http.StatusOK, jobs)
+}
+
+// getJob responds with the job of the request's ID.
+func (h *handler) getJob(w http.ResponseWriter, r *http.Request) {
+	info, err := h.manager.Job(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, jobOf(info))
+}
+
+// getStats responds with the execution statistics of the job of the request's ID.
+func (h *handler) getStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.manager.JobStats(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	response := Stats{
+		TotalRuns:           stats.TotalRuns,
+		FailedRuns:          stats.FailedRuns,
+		ConsecutiveFailures: stats.ConsecutiveFailures,
+		SkippedRuns:         stats.SkippedRuns,
+		LastRun:             stats.LastRun,
+		LastDuration:        stats.LastDuration.String(),
+		AverageDuration:     stats.AverageDuration.String(),
+		Overrunning:         stats.Overrunning,
+		Lateness:            newLateness(stats.Lateness),
+		TotalTaskTime:       stats.TotalTaskTime.String(),
+		TotalCPUTime:        stats.TotalCPUTime.String(),
+		TaskFailures:        stats.TaskFailures,
+	}
+	if stats.LastError != nil {
+		response.LastError = stats.LastError.Error()
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// jobAction returns a handler applying the action to the job of the request's ID, responding
+// with 204 No Content on success.
+func (h *handler) jobAction(action func(jobID string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobID := r.PathValue("id")
+		if _, err := h.manager.Job(jobID); err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		if err := action(jobID); err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// getMetrics responds with the TaskManager's metrics.
+func (h *handler) getMetrics(w http.ResponseWriter, r *http.Request) {
+	metrics := h.manager.Metrics()
+	writeJSON(w, http.StatusOK, Metrics{
+		QueuedJobs:           metrics.QueuedJobs,
+		QueuedTasks:          metrics.QueuedTasks,
+		QueueMaxJobWidth:     metrics.QueueMaxJobWidth,
+		JobsOverrunning:      metrics.JobsOverrunning,
+		DispatchLateness:     newLateness(metrics.DispatchLateness),
+		RunsShed:             metrics.RunsShed,
+		RunsCoalesced:        metrics.RunsCoalesced,
+		TasksSpilled:         metrics.TasksSpilled,
+		DispatchBlocked:      metrics.DispatchBlocked.String(),
+		TaskAverageExecTime:  metrics.TaskAverageExecTime.String(),
+		TasksTotalExecutions: metrics.TasksTotalExecutions,
+		TasksPerSecond:       metrics.TasksPerSecond,
+		TaskTotalExecTime:    metrics.TaskTotalExecTime.String(),
+		TaskTotalCPUTime:     metrics.TaskTotalCPUTime.String(),
+		DroppedErrors:        metrics.DroppedErrors,
+		DroppedResults:       metrics.DroppedResults,
+		DroppedExecTimes:     metrics.DroppedExecTimes,
+		ResultsBlocked:       metrics.ResultsBlocked.String(),
+		WorkerCountTarget:    metrics.WorkerCountTarget,
+		WorkerScalingEvents:  metrics.WorkerScalingEvents,
+		WorkerUtilization:    metrics.WorkerUtilization,
+		WorkersActive:        metrics.WorkersActive,
+		WorkersDraining:      metrics.WorkersDraining,
+		WorkersRunning:       metrics.WorkersRunning,
+	})
+}
+
+// newLateness returns the JSON representation of a lateness summary.
+func newLateness(lateness taskman.Lateness) Lateness {
+	return Lateness{P50: lateness.P50.String(), P95: lateness.P95.String(), Max: lateness.Max.String()}
+}
+
+// getWorkers responds with the worker count of the worker pool.
+func (h *handler) getWorkers(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, Workers{Workers: h.manager.WorkerCount()})
+}
+
+// setWorkers sets the worker count of the worker pool to the one of the request's body.
+func (h *handler) setWorkers(w http.ResponseWriter, r *http.Request) {
+	var body Workers
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if err := h.manager.SetWorkerCount(body.Workers); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// jobOf returns the JSON representation of a job.
+func jobOf(info taskman.JobInfo) Job {
+	return Job{
+		ID:        info.ID,
+		Cadence:   info.Cadence.String(),
+		NextExec:  info.NextExec,
+		TaskCount: info.TaskCount,
+		Running:   info.Running,
+		Paused:    info.Paused,
+		Tags:      info.Tags,
+		Metadata:  info.Metadata,
+	}
+}
+
+// writeJSON writes the value as the JSON body of a response with the status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the error as the JSON body of a response with the status code.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
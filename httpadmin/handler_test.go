@@ -0,0 +1,129 @@
+package httpadmin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	taskman "github.com/jkbrsn/go-taskman"
+	"github.com/stretchr/testify/assert"
+)
+
+// funcTask is a task executing a function.
+type funcTask func() error
+
+// Execute executes the function.
+func (f funcTask) Execute() error {
+	return f()
+}
+
+// request serves a request to the handler, returning the response.
+func request(handler http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return recorder
+}
+
+// decode decodes the JSON body of the response into v.
+func decode(t *testing.T, response *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(v))
+}
+
+func TestHandler(t *testing.T) {
+	manager := taskman.New(taskman.WithWorkers(1))
+	defer manager.Stop()
+	handler := New(manager)
+
+	executed := make(chan struct{}, 1)
+	job := taskman.Job{ID: "admin-job", Cadence: time.Hour, NextExec: time.Now().Add(time.Hour),
+		Tasks: []taskman.Task{funcTask(func() error {
+			executed <- struct{}{}
+			return nil
+		})}}
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	t.Run("List jobs", func(t *testing.T) {
+		response := request(handler, http.MethodGet, "/jobs", "")
+		assert.Equal(t, http.StatusOK, response.Code)
+		var jobs []Job
+		decode(t, response, &jobs)
+		if assert.Len(t, jobs, 1) {
+			assert.Equal(t, "admin-job", jobs[0].ID)
+			assert.Equal(t, "1h0m0s", jobs[0].Cadence)
+			assert.Equal(t, 1, jobs[0].TaskCount)
+		}
+	})
+
+	t.Run("Get job", func(t *testing.T) {
+		response := request(handler, http.MethodGet, "/jobs/admin-job", "")
+		assert.Equal(t, http.StatusOK, response.Code)
+		var got Job
+		decode(t, response, &got)
+		assert.Equal(t, "admin-job", got.ID)
+
+		response = request(handler, http.MethodGet, "/jobs/missing-job", "")
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		var body errorResponse
+		decode(t, response, &body)
+		assert.NotEmpty(t, body.Error, "Expected an error message")
+	})
+
+	t.Run("Trigger job", func(t *testing.T) {
+		response := request(handler, http.MethodPost, "/jobs/admin-job/trigger", "")
+		assert.Equal(t, http.StatusNoContent, response.Code)
+		select {
+		case <-executed:
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("Expected the triggered job to execute")
+		}
+
+		assert.Eventually(t, func() bool {
+			var stats Stats
+			decode(t, request(handler, http.MethodGet, "/jobs/admin-job/stats", ""), &stats)
+			return stats.TotalRuns == 1
+		}, 50*time.Millisecond, time.Millisecond, "Expected the stats to count the triggered run")
+	})
+
+	t.Run("Pause and resume job", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, request(handler, http.MethodPost, "/jobs/admin-job/pause", "").Code)
+		var got Job
+		decode(t, request(handler, http.MethodGet, "/jobs/admin-job", ""), &got)
+		assert.True(t, got.Paused)
+
+		assert.Equal(t, http.StatusNoContent, request(handler, http.MethodPost, "/jobs/admin-job/resume", "").Code)
+		decode(t, request(handler, http.MethodGet, "/jobs/admin-job", ""), &got)
+		assert.False(t, got.Paused)
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		response := request(handler, http.MethodGet, "/metrics", "")
+		assert.Equal(t, http.StatusOK, response.Code)
+		var metrics Metrics
+		decode(t, response, &metrics)
+		assert.Equal(t, 1, metrics.QueuedJobs)
+	})
+
+	t.Run("Workers", func(t *testing.T) {
+		response := request(handler, http.MethodPut, "/workers", `{"workers": 3}`)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Eventually(t, func() bool {
+			var workers Workers
+			decode(t, request(handler, http.MethodGet, "/workers", ""), &workers)
+			return workers.Workers == 3
+		}, 50*time.Millisecond, time.Millisecond, "Expected the worker pool to be resized")
+
+		assert.Equal(t, http.StatusBadRequest, request(handler, http.MethodPut, "/workers", `{"workers": 0}`).Code)
+		assert.Equal(t, http.StatusBadRequest, request(handler, http.MethodPut, "/workers", `not json`).Code)
+	})
+
+	t.Run("Remove job", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, request(handler, http.MethodDelete, "/jobs/admin-job", "").Code)
+		assert.Equal(t, http.StatusNotFound, request(handler, http.MethodDelete, "/jobs/admin-job", "").Code)
+		assert.Equal(t, http.StatusMethodNotAllowed, request(handler, http.MethodPost, "/jobs", "").Code)
+	})
+}
@@ -0,0 +1,63 @@
+package taskman
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/rs/xid"
+)
+
+// ErrDuplicateJobID is returned when scheduling a job with the ID of a job already scheduled in the
+// TaskManager, or dead-lettered by it.
+var ErrDuplicateJobID = errors.New("duplicate job ID")
+
+// maxJobIDLength is the maximum length of a job ID, in bytes.
+const maxJobIDLength = 256
+
+// IDGenerator generates the IDs of jobs scheduled without an ID of their own, e.g. with
+// ScheduleFunc. Generated IDs must be unique, and are validated like other job IDs.
+type IDGenerator func() string
+
+// defaultIDGenerator generates globally unique xid IDs, e.g. "9m4e2mr0ui3e8a215n4g".
+func defaultIDGenerator() string {
+	return xid.New().String()
+}
+
+// SetIDGenerator sets the generator of the IDs of jobs scheduled without an ID of their own, e.g.
+// with ScheduleFunc or ScheduleOnce. A nil generator restores the default, generating xid IDs.
+func (tm *TaskManager) SetIDGenerator(generator IDGenerator) {
+	tm.Lock()
+	defer tm.Unlock()
+	if generator == nil {
+		generator = defaultIDGenerator
+	}
+	tm.idGenerator = generator
+}
+
+// newJobID returns a new job ID from the TaskManager's ID generator.
+func (tm *TaskManager) newJobID() string {
+	tm.RLock()
+	generator := tm.idGenerator
+	tm.RUnlock()
+	return generator()
+}
+
+// validateJobID returns an error if the job ID is not valid. Valid IDs are non-empty, at most 256
+// bytes long, and contain no whitespace or control characters, so that they can be used e.g. in
+// URLs and log lines without escaping.
+func validateJobID(jobID string) error {
+	if jobID == "" {
+		return errors.New("invalid job ID, must not be empty")
+	}
+	if len(jobID) > maxJobIDLength {
+		return fmt.Errorf("invalid job ID, must be at most %d bytes", maxJobIDLength)
+	}
+	if strings.ContainsFunc(jobID, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r) || r == unicode.ReplacementChar
+	}) {
+		return errors.New("invalid job ID, must not contain whitespace or control characters")
+	}
+	return nil
+}
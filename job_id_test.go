@@ -0,0 +1,53 @@
+package taskman
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIDGenerator(t *testing.T) {
+	var n atomic.Int32
+	manager := New(WithWorkers(1), WithIDGenerator(func() string {
+		return fmt.Sprintf("job-%d", n.Add(1))
+	}))
+	defer manager.Stop()
+
+	jobID, err := manager.ScheduleFunc(func() error { return nil }, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, "job-1", jobID, "Expected the ID of the generator")
+	jobID, err = manager.ScheduleOnce(MockTask{}, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, "job-2", jobID)
+	jobID, err = manager.ScheduleCron(MockTask{}, "@hourly")
+	assert.NoError(t, err)
+	assert.Equal(t, "job-3", jobID)
+
+	// Generated IDs are validated like other IDs
+	manager.SetIDGenerator(func() string { return "" })
+	_, err = manager.ScheduleTask(MockTask{}, time.Hour)
+	assert.Error(t, err, "Expected an error for an invalid generated ID")
+
+	manager.SetIDGenerator(nil)
+	jobID, err = manager.ScheduleTask(MockTask{}, time.Hour)
+	assert.NoError(t, err)
+	assert.Len(t, jobID, 20, "Expected an xid once the default generator is restored")
+}
+
+func TestValidateJobID(t *testing.T) {
+	for _, jobID := range []string{"job-1", "tenant/a:report", "レポート", strings.Repeat("a", maxJobIDLength)} {
+		assert.NoError(t, validateJobID(jobID), "Expected %q to be valid", jobID)
+	}
+	for _, jobID := range []string{"", "job 1", "job\t1", "job\n", "job\x00", "\xff", strings.Repeat("a", maxJobIDLength+1)} {
+		assert.Error(t, validateJobID(jobID), "Expected %q to be invalid", jobID)
+	}
+
+	manager := NewCustom(1, 4, 1*time.Minute)
+	defer manager.Stop()
+	assert.Error(t, manager.ScheduleJob(getMockedJob(1, "", time.Hour, time.Hour)), "Expected an error for an empty ID")
+	assert.Error(t, manager.ScheduleJob(getMockedJob(1, "my job", time.Hour, time.Hour)), "Expected an error for an ID with a space")
+}
@@ -0,0 +1,106 @@
+package taskman
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// AddTaskToJob appends a task to a scheduled job's tasks, keeping the job's schedule, state and
+// statistics. Runs already executing are unaffected, the task is executed from the job's next run.
+func (tm *TaskManager) AddTaskToJob(jobID string, task Task) error {
+	if task == nil {
+		return errors.New("task cannot be nil")
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	job, err := tm.queuedJob(jobID)
+	if err != nil {
+		return err
+	}
+	tasks := append(slices.Clone(job.Tasks), task)
+	if err := validateTaskIDs(tasks); err != nil {
+		return err
+	}
+	if job.Remote {
+		if records, err := marshalTasks([]Task{task}); err != nil || records == nil {
+			return errors.New("tasks of remote jobs must be serializable")
+		}
+	}
+	tm.setJobTasks(job, tasks)
+	return nil
+}
+
+// RemoveTaskFromJob removes the task at the index of a scheduled job's tasks, keeping the job's
+// schedule, state and statistics. Runs already executing are unaffected. The last task of a job
+// cannot be removed, remove the job with RemoveJob instead.
+func (tm *TaskManager) RemoveTaskFromJob(jobID string, index int) error {
+	tm.Lock()
+	defer tm.Unlock()
+
+	job, err := tm.queuedJob(jobID)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(job.Tasks) {
+		return fmt.Errorf("task index %d out of range for job with %d tasks", index, len(job.Tasks))
+	}
+	if len(job.Tasks) == 1 {
+		return errors.New("cannot remove the last task of a job")
+	}
+	tm.setJobTasks(job, slices.Delete(slices.Clone(job.Tasks), index, index+1))
+	return nil
+}
+
+// queuedJob returns the scheduled job with the ID.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) queuedJob(jobID string) (*Job, error) {
+	index, err := tm.jobQueue.JobInQueue(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("job with ID %s not found", jobID)
+	}
+	return tm.jobQueue.jobs[index], nil
+}
+
+// setJobTasks replaces the tasks of a scheduled job, updating the task metrics and the workers
+// executing the job's tasks accordingly. The job's tasks are replaced rather than modified in
+// place, as started runs hold on to the tasks they were started with.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) setJobTasks(job *Job, tasks []Task) {
+	tm.touchJob(job)
+	previous := len(job.Tasks)
+	job.Tasks = tasks
+	tm.logger.Debug("Updated tasks of job", "jobID", job.ID, "tasks", len(tasks))
+
+	switch {
+	case job.pooled():
+		tm.metrics.updateTaskMetrics(len(tasks)-previous, job.Cadence)
+		if len(tasks) >= previous {
+			tm.metrics.maxJobWidth.Store(max(tm.metrics.maxJobWidth.Load(), int32(len(tasks))))
+		} else if previous == int(tm.metrics.maxJobWidth.Load()) {
+			tm.metrics.maxJobWidth.Store(int32(tm.widestPooledJob()))
+		}
+		tm.scaleWorkerPool(0)
+	case job.Dedicated && job.ExecutionMode == ExecutionParallel:
+		// Dedicated jobs have a worker for each of their tasks executed in parallel
+		if runner, ok := tm.dedicated[job.state]; ok {
+			runner.pool.enqueueWorkerScaling(int32(len(tasks)))
+		}
+	}
+	tm.saveJob(job)
+}
+
+// widestPooledJob returns the number of tasks of the widest job executed by the default worker
+// pool.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) widestPooledJob() int {
+	widest := 0
+	for _, job := range tm.jobQueue.jobs {
+		if job.pooled() {
+			widest = max(widest, len(job.Tasks))
+		}
+	}
+	return widest
+}
@@ -0,0 +1,67 @@
+package taskman
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTaskToJob(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+
+	job := getMockedJob(1, "aggregate-job", time.Hour, time.Hour)
+	assert.NoError(t, manager.ScheduleJob(job))
+	assert.NoError(t, manager.AddTaskToJob(job.ID, MockTask{ID: "endpoint-b"}))
+	assert.Error(t, manager.AddTaskToJob(job.ID, nil), "Expected error adding a nil task")
+	assert.Error(t, manager.AddTaskToJob("unknown", MockTask{}), "Expected error adding to an unknown job")
+
+	info, err := manager.Job(job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, info.TaskCount)
+	assert.Equal(t, job.NextExec, info.NextExec, "Expected the job's schedule to be kept")
+	assert.Equal(t, 2, manager.Metrics().QueueMaxJobWidth)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	result, err := manager.RunJobNow(ctx, job.ID)
+	assert.NoError(t, err)
+	assert.Len(t, result.TaskResults, 2, "Expected the added task to be executed")
+}
+
+func TestRemoveTaskFromJob(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+
+	executed := make(chan string, 4)
+	job := getMockedJob(3, "aggregate-job", time.Hour, time.Hour)
+	for i, task := range job.Tasks {
+		mock := task.(MockTask)
+		mock.executeFunc = func() error {
+			executed <- mock.ID
+			return nil
+		}
+		job.Tasks[i] = mock
+	}
+	assert.NoError(t, manager.ScheduleJob(job))
+	assert.NoError(t, manager.RemoveTaskFromJob(job.ID, 1))
+	assert.Error(t, manager.RemoveTaskFromJob(job.ID, 2), "Expected error removing a task out of range")
+	assert.Error(t, manager.RemoveTaskFromJob("unknown", 0), "Expected error removing from an unknown job")
+	assert.Equal(t, 2, manager.Metrics().QueueMaxJobWidth)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := manager.RunJobNow(ctx, job.ID)
+	assert.NoError(t, err)
+	close(executed)
+	var ids []string
+	for id := range executed {
+		ids = append(ids, id)
+	}
+	assert.ElementsMatch(t, []string{"task-0", "task-2"}, ids)
+
+	assert.NoError(t, manager.RemoveTaskFromJob(job.ID, 0))
+	assert.Error(t, manager.RemoveTaskFromJob(job.ID, 0), "Expected error removing the last task")
+}
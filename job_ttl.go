@@ -0,0 +1,133 @@
+package taskman
+
+import (
+	"errors"
+	"time"
+)
+
+// SetJobTTL sets the default idle TTL of jobs without an IdleTTL of their own. A job which is not
+// touched for longer than its TTL is removed, emitting an EventJobExpired event, e.g. so that
+// per-session jobs a caller forgot to remove do not leak. Jobs are touched when scheduled,
+// triggered, replaced, when their tasks are changed, and with TouchJob, but not by their scheduled
+// runs. Dead-lettered jobs do not expire. A TTL of 0, the default, disables the expiry.
+func (tm *TaskManager) SetJobTTL(ttl time.Duration) error {
+	if ttl < 0 {
+		return errors.New("invalid job TTL, must not be negative")
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	tm.jobTTL = ttl
+	tm.wakeExpiry()
+	return nil
+}
+
+// TouchJob touches the job with the given ID, resetting the time until it expires by its idle
+// TTL, e.g. on every request of the session the job belongs to.
+func (tm *TaskManager) TouchJob(jobID string) error {
+	tm.Lock()
+	defer tm.Unlock()
+
+	job, err := tm.queuedJob(jobID)
+	if err != nil {
+		return err
+	}
+	job.state.touched = tm.now()
+	return nil
+}
+
+// idleTTL returns the idle TTL of the job, or 0 if the job does not expire.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) idleTTL(job *Job) time.Duration {
+	if job.IdleTTL > 0 {
+		return job.IdleTTL
+	}
+	return tm.jobTTL
+}
+
+// touchJob touches the job, and signals the expiry loop if the job expires.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) touchJob(job *Job) {
+	job.state.touched = tm.now()
+	if tm.idleTTL(job) > 0 {
+		tm.wakeExpiry()
+	}
+}
+
+// wakeExpiry signals the expiry loop that the next expiry may have changed.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) wakeExpiry() {
+	select {
+	case tm.expiryWake <- struct{}{}:
+	default:
+		// A wake-up is already pending
+	}
+}
+
+// expireIdleJobs removes the jobs idle for longer than their TTL as they expire, until the
+// TaskManager is stopped.
+func (tm *TaskManager) expireIdleJobs() {
+	defer tm.dispatches.Done()
+
+	for {
+		tm.Lock()
+		now := tm.now()
+		expired, next := tm.takeExpiredJobs(now)
+		store := tm.store
+		tm.Unlock()
+
+		for _, jobID := range expired {
+			if store != nil {
+				tm.unpersistJob(store, jobID)
+			}
+			tm.hooks.jobWasRemoved(jobID)
+		}
+
+		// Wait until the next job expires, reading the clock again at intervals to act on jumps of
+		// the wall clock
+		var due <-chan time.Time
+		if !next.IsZero() {
+			due = tm.clock.After(min(next.Sub(now), clockCheckInterval))
+		}
+		select {
+		case <-due:
+		case <-tm.expiryWake:
+		case <-tm.ctx.Done():
+			return
+		}
+	}
+}
+
+// takeExpiredJobs removes the jobs idle for longer than their TTL at now from the queue, returning
+// their IDs and the time the next job expires, or the zero time if no job expires.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) takeExpiredJobs(now time.Time) ([]string, time.Time) {
+	var expired []*Job
+	var next time.Time
+	for _, job := range tm.jobQueue.jobs {
+		ttl := tm.idleTTL(job)
+		if ttl <= 0 {
+			continue
+		}
+		expiry := job.state.touched.Add(ttl)
+		if !expiry.After(now) {
+			expired = append(expired, job)
+		} else if next.IsZero() || expiry.Before(next) {
+			next = expiry
+		}
+	}
+
+	jobIDs := make([]string, 0, len(expired))
+	for _, job := range expired {
+		tm.logger.Debug("Removing job, idle TTL expired", "jobID", job.ID, "touched", job.state.touched)
+		if err := tm.removeJob(job); err != nil {
+			tm.logger.Warn("Failed to remove expired job", "jobID", job.ID, "error", err)
+			continue
+		}
+		tm.releaseRunWaiters(job.ID)
+		job.cancel()
+		tm.emitEvent(Event{Type: EventJobExpired, JobID: job.ID, Metadata: job.Metadata})
+		jobIDs = append(jobIDs, job.ID)
+	}
+	return jobIDs, next
+}
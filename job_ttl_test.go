@@ -0,0 +1,67 @@
+package taskman
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobTTL(t *testing.T) {
+	manager := New(WithWorkers(1), WithJobTTL(30*time.Millisecond))
+	defer manager.Stop()
+	assert.Error(t, manager.SetJobTTL(-time.Second), "Expected error for a negative TTL")
+	events, unsubscribe := manager.SubscribeEvents(16)
+	defer unsubscribe()
+
+	// Jobs not touched for longer than the default TTL are removed
+	idle := getMockedJob(1, "idle-job", time.Hour, time.Hour)
+	assert.NoError(t, manager.ScheduleJob(idle))
+	// Touched jobs and jobs with a TTL of their own are kept
+	touched := getMockedJob(1, "touched-job", time.Hour, time.Hour)
+	assert.NoError(t, manager.ScheduleJob(touched))
+	long := getMockedJob(1, "long-job", time.Hour, time.Hour)
+	long.IdleTTL = time.Hour
+	assert.NoError(t, manager.ScheduleJob(long))
+
+	deadline := time.After(60 * time.Millisecond)
+	for touching := true; touching; {
+		select {
+		case <-deadline:
+			touching = false
+		case <-time.After(5 * time.Millisecond):
+			assert.NoError(t, manager.TouchJob(touched.ID))
+		}
+	}
+
+	_, err := manager.Job(idle.ID)
+	assert.Error(t, err, "Expected the idle job to be removed")
+	_, err = manager.Job(touched.ID)
+	assert.NoError(t, err, "Expected the touched job to be kept")
+	_, err = manager.Job(long.ID)
+	assert.NoError(t, err, "Expected the job with a longer TTL to be kept")
+	assert.Error(t, manager.TouchJob(idle.ID), "Expected error touching a removed job")
+
+	// Without a TTL, untouched jobs are kept
+	assert.NoError(t, manager.SetJobTTL(0))
+	time.Sleep(40 * time.Millisecond)
+	_, err = manager.Job(touched.ID)
+	assert.NoError(t, err)
+
+	expired := false
+	for !expired {
+		select {
+		case event := <-events:
+			if event.Type == EventJobExpired {
+				assert.Equal(t, idle.ID, event.JobID)
+				expired = true
+			}
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("Expected a job expired event")
+		}
+	}
+
+	invalid := getMockedJob(1, "invalid-ttl-job", time.Hour, time.Hour)
+	invalid.IdleTTL = -time.Second
+	assert.Error(t, manager.ScheduleJob(invalid), "Expected error scheduling a job with a negative TTL")
+}
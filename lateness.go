@@ -0,0 +1,68 @@
+package taskman
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// jobLatenessSamples is the number of recent dispatches of a job its lateness is measured over.
+	jobLatenessSamples = 128
+	// managerLatenessSamples is the number of recent dispatches of all jobs of a TaskManager the
+	// dispatch lateness metrics are measured over.
+	managerLatenessSamples = 1024
+)
+
+// Lateness summarizes how late recent runs were dispatched, measured from the time they were due
+// until they were dispatched to the worker pool. Sustained lateness signals that the TaskManager
+// cannot keep up with its jobs, e.g. that its worker pool is undersized.
+type Lateness struct {
+	P50 time.Duration // Median lateness
+	P95 time.Duration // 95th percentile lateness
+	Max time.Duration // Highest lateness
+}
+
+// latenessWindow holds the lateness of a fixed number of recent dispatches. The zero value is an
+// empty window.
+// Note: not safe for concurrent use, guarded by the lock of its owner.
+type latenessWindow struct {
+	samples []time.Duration // Lateness of the recent dispatches, used as a ring buffer once full
+	next    int             // Index of the sample to overwrite next, once full
+}
+
+// record records the lateness of a dispatch, replacing the oldest one once the window holds size
+// dispatches.
+func (w *latenessWindow) record(lateness time.Duration, size int) {
+	lateness = max(lateness, 0)
+	if len(w.samples) < size {
+		w.samples = append(w.samples, lateness)
+		return
+	}
+	w.samples[w.next] = lateness
+	w.next = (w.next + 1) % len(w.samples)
+}
+
+// summary returns the percentiles of the lateness of the dispatches in the window.
+func (w *latenessWindow) summary() Lateness {
+	if len(w.samples) == 0 {
+		return Lateness{}
+	}
+	samples := slices.Clone(w.samples)
+	slices.Sort(samples)
+	return Lateness{
+		P50: percentile(samples, 50),
+		P95: percentile(samples, 95),
+		Max: samples[len(samples)-1],
+	}
+}
+
+// percentile returns the pth percentile of the sorted samples, by the nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// maxLateness returns the highest of each percentile of the given summaries.
+func maxLateness(a, b Lateness) Lateness {
+	return Lateness{P50: max(a.P50, b.P50), P95: max(a.P95, b.P95), Max: max(a.Max, b.Max)}
+}
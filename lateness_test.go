@@ -0,0 +1,58 @@
+package taskman
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatenessWindow(t *testing.T) {
+	var w latenessWindow
+	assert.Equal(t, Lateness{}, w.summary(), "Expected an empty window to have no lateness")
+
+	for i := 1; i <= 20; i++ {
+		w.record(time.Duration(i)*time.Millisecond, 20)
+	}
+	assert.Equal(t, Lateness{P50: 10 * time.Millisecond, P95: 19 * time.Millisecond, Max: 20 * time.Millisecond},
+		w.summary())
+
+	// Once full, the oldest dispatches are replaced
+	for range 20 {
+		w.record(time.Millisecond, 20)
+	}
+	assert.Len(t, w.samples, 20)
+	assert.Equal(t, Lateness{P50: time.Millisecond, P95: time.Millisecond, Max: time.Millisecond}, w.summary())
+
+	var early latenessWindow
+	early.record(-time.Second, 20)
+	assert.Equal(t, Lateness{}, early.summary(), "Expected early dispatches to count as not late")
+}
+
+func TestDispatchLateness(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+
+	executed := make(chan struct{}, 1)
+	job := Job{
+		ID:       "late-job",
+		Cadence:  time.Hour,
+		NextExec: time.Now().Add(-20 * time.Millisecond),
+		Tasks: []Task{MockTask{executeFunc: func() error {
+			executed <- struct{}{}
+			return nil
+		}}},
+	}
+	assert.NoError(t, manager.ScheduleJob(job))
+	select {
+	case <-executed:
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the late job to execute")
+	}
+
+	stats, err := manager.JobStats(job.ID)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, stats.Lateness.Max, 20*time.Millisecond)
+	assert.Equal(t, stats.Lateness.Max, stats.Lateness.P50, "Expected a single dispatch")
+	assert.GreaterOrEqual(t, manager.Metrics().DispatchLateness.Max, 20*time.Millisecond)
+}
@@ -0,0 +1,152 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/xid"
+)
+
+// DistributedLock is a lock shared by several instances of an application, used to elect the
+// single TaskManager which dispatches jobs when every instance schedules the same jobs. The lock is
+// a lease which expires unless renewed, so that another instance takes over if the holder dies.
+// Implementations, e.g. backed by etcd or Redis, identify the instance holding the lock, and must
+// be safe for concurrent use.
+type DistributedLock interface {
+	// TryLock acquires the lock, or renews it if already held, for the duration of the ttl. Reports
+	// whether the lock is held by this instance.
+	TryLock(ctx context.Context, ttl time.Duration) (bool, error)
+	// Unlock releases the lock if held by this instance.
+	Unlock(ctx context.Context) error
+}
+
+// MemoryLock is a lock shared by TaskManagers within one process, e.g. for tests. Each
+// TaskManager acquires the lock through a holder of its own.
+type MemoryLock struct {
+	mu      sync.Mutex
+	owner   string
+	expires time.Time
+}
+
+// NewMemoryLock creates an unlocked MemoryLock.
+func NewMemoryLock() *MemoryLock {
+	return &MemoryLock{}
+}
+
+// Holder returns a DistributedLock acquiring the MemoryLock on behalf of a new, unique holder.
+func (l *MemoryLock) Holder() DistributedLock {
+	return &memoryLockHolder{lock: l, id: xid.New().String()}
+}
+
+// memoryLockHolder is a holder of a MemoryLock.
+type memoryLockHolder struct {
+	lock *MemoryLock
+	id   string
+}
+
+// TryLock acquires or renews the lock, unless held by another holder whose lease has not expired.
+func (h *memoryLockHolder) TryLock(_ context.Context, ttl time.Duration) (bool, error) {
+	h.lock.mu.Lock()
+	defer h.lock.mu.Unlock()
+
+	now := time.Now()
+	if h.lock.owner != "" && h.lock.owner != h.id && now.Before(h.lock.expires) {
+		return false, nil
+	}
+	h.lock.owner = h.id
+	h.lock.expires = now.Add(ttl)
+	return true, nil
+}
+
+// Unlock releases the lock if held by the holder.
+func (h *memoryLockHolder) Unlock(context.Context) error {
+	h.lock.mu.Lock()
+	defer h.lock.mu.Unlock()
+
+	if h.lock.owner == h.id {
+		h.lock.owner = ""
+	}
+	return nil
+}
+
+// SetDistributedLock makes the TaskManager compete for the lock with other instances, and only
+// dispatch jobs while holding it. Instances not holding the lock keep their jobs scheduled as
+// usual, but skip their executions, ready to take over if the lock holder stops renewing its lease.
+// The lease lasts for the ttl, and is renewed at a third of it. The lock is released when the
+// TaskManager is stopped.
+// Note: jobs triggered with TriggerJob are executed regardless of holding the lock.
+func (tm *TaskManager) SetDistributedLock(lock DistributedLock, ttl time.Duration) error {
+	if lock == nil {
+		return errors.New("lock cannot be nil")
+	}
+	if ttl <= 0 {
+		return errors.New("invalid lock ttl, must be greater than 0")
+	}
+
+	tm.Lock()
+	if tm.lock != nil {
+		tm.Unlock()
+		return errors.New("distributed lock already set")
+	}
+	tm.lock = lock
+	tm.lockDone = make(chan struct{})
+	tm.Unlock()
+
+	// Compete for the lock right away, so that the leader is known once this returns
+	tm.renewLock(ttl)
+	go tm.maintainLock(ttl)
+
+	return nil
+}
+
+// IsLeader reports whether the TaskManager dispatches jobs, which is always the case unless a
+// distributed lock is set and held by another instance.
+func (tm *TaskManager) IsLeader() bool {
+	tm.RLock()
+	defer tm.RUnlock()
+	return tm.lock == nil || tm.leader.Load()
+}
+
+// maintainLock renews the distributed lock at regular intervals, and releases it when the
+// TaskManager is stopped.
+func (tm *TaskManager) maintainLock(ttl time.Duration) {
+	defer close(tm.lockDone)
+
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			tm.renewLock(ttl)
+		case <-tm.ctx.Done():
+			if tm.leader.Load() {
+				ctx, cancel := context.WithTimeout(context.Background(), ttl)
+				if err := tm.lock.Unlock(ctx); err != nil {
+					tm.logger.Warn("Failed to release distributed lock", "error", err)
+				}
+				cancel()
+				tm.leader.Store(false)
+			}
+			return
+		}
+	}
+}
+
+// renewLock acquires or renews the distributed lock. Failing to reach the lock is treated as not
+// holding it, as another instance may have acquired it.
+func (tm *TaskManager) renewLock(ttl time.Duration) {
+	ctx, cancel := context.WithTimeout(tm.ctx, ttl/3)
+	defer cancel()
+
+	held, err := tm.lock.TryLock(ctx, ttl)
+	if err != nil {
+		tm.logger.Warn("Failed to acquire distributed lock", "error", err)
+		held = false
+	}
+	if wasLeader := tm.leader.Swap(held); wasLeader != held {
+		tm.logger.Info("Distributed lock changed holder", "held", held)
+	}
+}
@@ -0,0 +1,93 @@
+package taskman
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryLock(t *testing.T) {
+	ctx := context.Background()
+	lock := NewMemoryLock()
+	holderA, holderB := lock.Holder(), lock.Holder()
+
+	held, err := holderA.TryLock(ctx, 20*time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, held, "Expected first holder to acquire the lock")
+
+	held, _ = holderB.TryLock(ctx, 20*time.Millisecond)
+	assert.False(t, held, "Expected lock held by another holder to not be acquired")
+
+	held, _ = holderA.TryLock(ctx, 20*time.Millisecond)
+	assert.True(t, held, "Expected holder to renew its lock")
+
+	t.Run("Unlock", func(t *testing.T) {
+		assert.NoError(t, holderB.Unlock(ctx), "Expected no error unlocking lock held by another holder")
+		held, _ = holderB.TryLock(ctx, 20*time.Millisecond)
+		assert.False(t, held, "Expected lock to remain held after unlock by another holder")
+
+		assert.NoError(t, holderA.Unlock(ctx))
+		held, _ = holderB.TryLock(ctx, 20*time.Millisecond)
+		assert.True(t, held, "Expected released lock to be acquired")
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		time.Sleep(30 * time.Millisecond)
+		held, _ = holderA.TryLock(ctx, 20*time.Millisecond)
+		assert.True(t, held, "Expected expired lock to be acquired")
+	})
+}
+
+func TestDistributedLock(t *testing.T) {
+	lock := NewMemoryLock()
+	ttl := 30 * time.Millisecond
+
+	// Two managers scheduling the same job
+	var executionsA, executionsB atomic.Int32
+	newManager := func(executions *atomic.Int32) *TaskManager {
+		manager := NewCustom(1, 1, 1*time.Minute)
+		assert.NoError(t, manager.SetDistributedLock(lock.Holder(), ttl))
+		job := getMockedJob(1, "shared-job", 5*time.Millisecond, 0)
+		job.Tasks = []Task{MockTask{ID: "shared-task", executeFunc: func() error {
+			executions.Add(1)
+			return nil
+		}}}
+		assert.NoError(t, manager.ScheduleJob(job))
+		return manager
+	}
+	managerA := newManager(&executionsA)
+	managerB := newManager(&executionsB)
+	defer managerB.Stop()
+
+	assert.True(t, managerA.IsLeader(), "Expected first manager to be the leader")
+	assert.False(t, managerB.IsLeader(), "Expected second manager to not be the leader")
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Greater(t, executionsA.Load(), int32(0), "Expected the leader to execute the job")
+	assert.Equal(t, int32(0), executionsB.Load(), "Expected the follower to not execute the job")
+	assert.Len(t, managerB.Jobs(), 1, "Expected the follower to keep the job scheduled")
+
+	t.Run("Failover", func(t *testing.T) {
+		managerA.Stop()
+		// The released lock is acquired at the follower's next renewal
+		time.Sleep(ttl)
+		assert.True(t, managerB.IsLeader(), "Expected the follower to take over")
+
+		executions := executionsB.Load()
+		time.Sleep(20 * time.Millisecond)
+		assert.Greater(t, executionsB.Load(), executions, "Expected the new leader to execute the job")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		assert.Error(t, managerB.SetDistributedLock(lock.Holder(), ttl), "Expected error setting a second lock")
+
+		manager := NewCustom(1, 1, 1*time.Minute)
+		defer manager.Stop()
+		assert.True(t, manager.IsLeader(), "Expected manager without a lock to be the leader")
+		assert.Error(t, manager.SetDistributedLock(nil, ttl), "Expected error setting a nil lock")
+		assert.Error(t, manager.SetDistributedLock(lock.Holder(), 0), "Expected error setting a zero ttl")
+	})
+}
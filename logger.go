@@ -0,0 +1,72 @@
+package taskman
+
+import (
+	"os"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	// Package-level logger that defaults to a no-op logger
+	logger = zerolog.New(zerolog.NewTestWriter(nil)).Level(zerolog.Disabled)
+)
+
+// Logger is the logging interface of a TaskManager. Messages are accompanied by alternating keys
+// and values, as with the standard library's log/slog, and a *slog.Logger can be used directly.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// SetLogger allows users to inject their own logger for the entire package. It is used by all
+// TaskManagers created without a Logger of their own, see WithLogger.
+func SetLogger(l zerolog.Logger) {
+	logger = l
+}
+
+// InitDefaultLogger initializes the package logger with default settings
+func InitDefaultLogger() {
+	logger = zerolog.New(os.Stdout).With().Timestamp().Logger().Level(zerolog.InfoLevel)
+}
+
+// NewZerologLogger returns a Logger writing to the zerolog logger, with the key-value pairs of
+// each message as fields.
+func NewZerologLogger(l zerolog.Logger) Logger {
+	return zerologLogger{logger: &l}
+}
+
+// zerologLogger adapts a zerolog logger to the Logger interface. A nil logger uses the package
+// logger, as set by SetLogger at the time of logging.
+type zerologLogger struct {
+	logger *zerolog.Logger
+}
+
+// Debug logs a message at debug level.
+func (zl zerologLogger) Debug(msg string, args ...any) {
+	zl.get().Debug().Fields(args).Msg(msg)
+}
+
+// Info logs a message at info level.
+func (zl zerologLogger) Info(msg string, args ...any) {
+	zl.get().Info().Fields(args).Msg(msg)
+}
+
+// Warn logs a message at warn level.
+func (zl zerologLogger) Warn(msg string, args ...any) {
+	zl.get().Warn().Fields(args).Msg(msg)
+}
+
+// Error logs a message at error level.
+func (zl zerologLogger) Error(msg string, args ...any) {
+	zl.get().Error().Fields(args).Msg(msg)
+}
+
+// get returns the zerolog logger to write to.
+func (zl zerologLogger) get() *zerolog.Logger {
+	if zl.logger == nil {
+		return &logger
+	}
+	return zl.logger
+}
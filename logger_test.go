@@ -0,0 +1,64 @@
+package taskman
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (sb *syncBuffer) Write(p []byte) (int, error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.Write(p)
+}
+
+func (sb *syncBuffer) String() string {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.String()
+}
+
+func TestZerologLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewZerologLogger(zerolog.New(&buf).Level(zerolog.InfoLevel))
+
+	l.Debug("debug message")
+	assert.Empty(t, buf.String(), "Expected messages below the level to be dropped")
+
+	l.Warn("warn message", "jobID", "job-1", "count", 3)
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "warn", entry["level"])
+	assert.Equal(t, "warn message", entry["message"])
+	assert.Equal(t, "job-1", entry["jobID"], "Expected key-value pairs as fields")
+	assert.Equal(t, float64(3), entry["count"])
+}
+
+func TestWithLogger(t *testing.T) {
+	var buf syncBuffer
+	log := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	manager := New(WithWorkers(1), WithLogger(log))
+	defer manager.Stop()
+
+	job := getMockedJob(1, "logged-job", 1*time.Minute, 1*time.Minute)
+	assert.NoError(t, manager.ScheduleJob(job))
+	assert.NoError(t, manager.TriggerJob(job.ID))
+	time.Sleep(5 * time.Millisecond)
+
+	output := buf.String()
+	assert.True(t, strings.Contains(output, `msg="Scheduling job" jobID=logged-job`), "Expected the manager to log to the slog logger")
+	assert.True(t, strings.Contains(output, `msg="Worker executing task"`), "Expected the worker pool to log to the slog logger")
+}
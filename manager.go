@@ -1,18 +1,21 @@
 package taskman
 
 import (
+	"cmp"
 	"container/heap"
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
-	"os"
-	"runtime"
+	"math/rand/v2"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/rs/xid"
-	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -21,21 +24,6 @@ const (
 	defaultBufferedSize  = 64
 )
 
-var (
-	// Package-level logger that defaults to a no-op logger
-	logger = zerolog.New(zerolog.NewTestWriter(nil)).Level(zerolog.Disabled)
-)
-
-// SetLogger allows users to inject their own logger for the entire package
-func SetLogger(l zerolog.Logger) {
-	logger = l
-}
-
-// InitDefaultLogger initializes the package logger with default settings
-func InitDefaultLogger() {
-	logger = zerolog.New(os.Stdout).With().Timestamp().Logger().Level(zerolog.InfoLevel)
-}
-
 // TaskManager manages task scheduling and execution. Tasks are scheduled within Jobs, and the
 // manager dispatches scheduled jobs to a worker pool for execution.
 type TaskManager struct {
@@ -44,21 +32,124 @@ type TaskManager struct {
 	// Queue
 	jobQueue   priorityQueue // A priority queue to hold the scheduled jobs
 	newJobChan chan bool     // Channel to signal that new tasks have entered the queue
+	queueSpace *sync.Cond    // Signals callers blocked on a full queue that jobs have been removed
+	jobSeq     uint64        // Sequence number of the last scheduled job
+
+	maxJobs        int            // Maximum number of jobs in the queue, 0 for no limit
+	overflowPolicy OverflowPolicy // What to do when a job is scheduled in a full queue
+
+	saturationPolicy SaturationPolicy // What to do with due runs whose tasks do not fit in the full task queue
+	shedBelow        int              // Priority of jobs below which runs are shed, with SaturationShed
+	batchTasks       int              // Tasks of the runs taken in the run loop's current batch
+	overflow         *overflowQueue   // Tasks spilled with SaturationSpill, waiting for space in the task queue
+	runsShed         atomic.Int64     // Number of runs shed with SaturationShed
 
 	// Context and operations
-	ctx      context.Context    // Context for the task manager
-	cancel   context.CancelFunc // Cancel function for the task manager
-	metrics  *managerMetrics    // Metrics for the task manager
-	runDone  chan struct{}      // Channel to signal run has stopped
-	stopOnce sync.Once          // Ensures Stop is only called once
+	ctx        context.Context    // Context for the task manager
+	cancel     context.CancelFunc // Cancel function for the task manager
+	logger     Logger             // Logger for the task manager and its worker pool
+	clock      Clock              // Source of time for scheduling jobs
+	metrics    *managerMetrics    // Metrics for the task manager
+	runDone    chan struct{}      // Channel to signal run has stopped
+	dispatches sync.WaitGroup     // Tasks of sequential runs being dispatched
+	stopOnce   sync.Once          // Ensures Stop is only called once
+
+	clockJumpPolicy  ClockJumpPolicy // How the schedules of jobs are adjusted when the wall clock jumps
+	lastClockReading time.Time       // Previous reading of the clock, for detecting jumps
 
 	// Worker pool
 	workerPool     *workerPool
-	workerPoolDone chan struct{} // Channel to receive signal that the worker pool has stopped
-	errorChan      chan error    // Channel to receive errors from the worker pool
-	taskChan       chan Task     // Channel to send tasks to the worker pool
-	minWorkerCount int           // Minimum number of workers in the pool
-	scaleInterval  time.Duration // Interval for automatic scaling of the worker pool
+	workerPoolDone chan struct{}           // Channel to receive signal that the worker pool has stopped
+	errorChan      chan error              // Channel to receive errors from the worker pool, see ErrorChannel
+	errorFan       *errorFanOut            // Delivers errors from the worker pools to the error channel and subscribers
+	taskQueue      *taskQueue              // Queue to send tasks to the worker pool
+	minWorkerCount atomic.Int32            // Minimum number of workers in the pool
+	maxWorkers     atomic.Int32            // Maximum number of workers in the pool
+	idleScaleDown  atomic.Int64            // Idle time after which the pool is scaled to its minimum, 0 to disable
+	headroom       atomic.Int32            // Spare idle workers kept beyond the busy workers of the pool
+	lastDispatch   atomic.Int64            // Unix time in nanoseconds of the last dispatch of a task
+	scaleInterval  time.Duration           // Interval for automatic scaling of the worker pool
+	groups         map[string]*workerGroup // Named worker pools, executing the jobs assigned to them
+
+	dedicated map[*jobState]*dedicatedRunner // Runners of Dedicated jobs, by the state of their job
+
+	// Execution
+	hooks       *hooks           // Lifecycle callbacks
+	events      *stream[Event]   // Subscriptions to the TaskManager's events
+	results     *stream[Result]  // Subscriptions to the results of ResultTasks
+	retryPolicy *RetryPolicy     // Default retry policy for jobs without a policy of their own
+	middleware  []TaskMiddleware // Middleware wrapping every task execution
+	executor    TaskExecutor     // Chain of the middleware, nil without middleware
+	tracer      trace.Tracer     // Tracer emitting spans of runs and tasks, if set
+
+	eventLog atomic.Pointer[eventLog] // Log the events are written to as JSON lines, if set
+
+	chaos atomic.Pointer[chaos] // Chaos mode injecting delays and panics, if enabled
+
+	quotas map[string]*concurrencyQuota // Quotas of the concurrency groups with a limit, by group
+
+	reservation atomic.Pointer[workerReservation] // Workers reserved for jobs of a min priority, if set
+
+	preemptions preemptions // Preemption signals of the executing tasks of the default worker pool
+
+	inflightKeys  map[string]int // Number of runs queued or executing, by the idempotency keys of their jobs
+	runsCoalesced atomic.Int64   // Number of runs coalesced with a run of the same idempotency key
+
+	blackouts         []Blackout     // Blackout windows applying to the runs of all jobs
+	maintenance       bool           // True while in maintenance mode, see StartMaintenance
+	maintenancePolicy BlackoutPolicy // What to do with runs due in maintenance mode
+
+	jobTTL     time.Duration // Default idle TTL of jobs, 0 for jobs not to expire
+	expiryWake chan struct{} // Channel to signal the expiry loop that the next expiry may have changed
+
+	activeRuns int           // Number of runs dispatched and not yet finished
+	drained    chan struct{} // Channel closed once drained, nil unless draining, see Drain
+	drainStart time.Time     // Time draining started, runs due later are not dispatched
+
+	resourceBudget  ResourceBudget       // Budget bounding the automatic scaling of the worker pool
+	memoryBudget    uint64               // Bytes of memory of the resource budget, 0 for no limit
+	sampleResources func() resourceUsage // Source of the resources used by the process
+
+	panicHandler PanicHandler // Handler of panicking tasks, if set
+	idGenerator  IDGenerator  // Generator of the IDs of jobs scheduled without an ID
+
+	runWaiters map[string][]chan JobResult // Callers of AwaitRun, by the ID of the job awaited
+
+	workerLifecycle *WorkerLifecycle // Callbacks called as workers start and stop, if set
+
+	recoverDisabled bool // Whether panics of tasks crash the process rather than being recovered
+
+	measureCPU  bool         // Whether the CPU time of tasks is measured, see SetCPUAccounting
+	taskTime    atomic.Int64 // Nanoseconds of wall time of all task executions
+	taskCPUTime atomic.Int64 // Nanoseconds of CPU time of all task executions, if measured
+
+	discardRemoved bool         // Whether tasks of removed jobs are discarded if they have not started
+	overrunRuns    atomic.Int32 // Runs after which jobs are checked for overrunning, 0 to disable
+
+	historySize    atomic.Int32 // Recent runs recorded in the history of each job, 0 to disable
+	persistHistory atomic.Bool  // Whether jobs are saved after every run, persisting their history
+
+	// Rate limiting
+	dispatchLimiter *rate.Limiter // Limiter of the dispatch rate of all jobs, if set
+
+	// Dead-lettering
+	deadLetterThreshold int             // Default consecutive failures after which jobs are dead-lettered, 0 to disable
+	deadLetters         map[string]*Job // Dead-lettered jobs, removed from the queue until requeued
+
+	// Persistence
+	store     JobStore               // Store persisting the scheduled jobs, if set
+	taskTypes map[string]TaskFactory // Factories of registered task types, for restoring tasks
+
+	jobTemplates map[string]JobTemplate // Registered job templates, see RegisterJobTemplate
+
+	// Leader election
+	lock     DistributedLock // Lock which must be held to dispatch jobs, if set
+	leader   atomic.Bool     // True while the lock is held
+	lockDone chan struct{}   // Channel to signal the lock has been released
+
+	// Remote execution
+	dispatcher Dispatcher    // Dispatcher publishing the runs of Remote jobs, if set
+	ackTimeout time.Duration // Time after which unacknowledged remote runs fail, 0 for no limit
 }
 
 // Task is an interface for tasks that can be executed.
@@ -77,22 +168,351 @@ func (st SimpleTask) Execute() error {
 	return err
 }
 
+// ContextTask is a Task which accepts a context. The context is cancelled when the TaskManager is
+// stopped or the task's job is removed, allowing long-running tasks to return early. The
+// TaskManager calls ExecuteContext rather than Execute for tasks implementing this interface.
+type ContextTask interface {
+	Task
+	ExecuteContext(ctx context.Context) error
+}
+
+// SimpleContextTask is a context-aware task that executes a function.
+type SimpleContextTask struct {
+	function func(ctx context.Context) error
+}
+
+// Execute executes the function with a background context and returns the error.
+func (st SimpleContextTask) Execute() error {
+	return st.function(context.Background())
+}
+
+// ExecuteContext executes the function with the provided context and returns the error.
+func (st SimpleContextTask) ExecuteContext(ctx context.Context) error {
+	return st.function(ctx)
+}
+
 // Job is a container for a group of tasks, with a unique ID and a cadence for scheduling.
 type Job struct {
 	Cadence time.Duration // Time between executions of the job
 	Tasks   []Task        // Tasks in the job
+	Group   string        // Worker group executing the job's tasks, the default worker pool if empty
+
+	ConcurrencyGroup string // Group of jobs sharing a limit of concurrently executing tasks, see TaskManager.SetConcurrencyLimit
+
+	Dedicated bool // If true, the job's runs are dispatched and executed by goroutines of its own, bypassing the run loop and worker pool
+
+	Remote bool // If true, the job's runs are published with the TaskManager's Dispatcher for remote workers to execute, see SetDispatcher
+
+	BaseContext context.Context // Context whose values, e.g. a trace, are passed to the job's tasks, its cancellation is not
+
+	Schedule Schedule // Calendar schedule determining the job's executions instead of Cadence, if set
+	Calendar Calendar // Calendar of dates on which the job's runs are skipped, e.g. holidays, if set
+
+	ExecutionMode ExecutionMode // How the tasks of each run are executed, in parallel by default
+
+	DependsOn []string // IDs of jobs which must complete successfully before each run, replacing the cadence
+
+	Fallback Task // Task executed once after a run in which any task failed, after retries, e.g. to send an alert, see RunError
+
+	RetryPolicy *RetryPolicy // Retry policy for failed tasks, overrides the TaskManager default if set
+
+	DeadLetterThreshold int // Consecutive failed runs after which the job is dead-lettered, overrides the TaskManager default if set
+
+	FailureBackoff time.Duration // Cap of the delay of the next execution after failed runs, growing as Cadence × 2^failures, 0 to disable
+
+	OverlapPolicy OverlapPolicy // What to do when the job is due while previous runs are executing
+	MaxConcurrent int           // Max concurrently executing runs, unless OverlapAllow, defaults to 1
+	MisfirePolicy MisfirePolicy // What to do when a run is dispatched after its following executions were missed
+	MaxDelay      time.Duration // Max time after a run is due that it may be dispatched, later runs are skipped, 0 for no limit
+
+	Blackouts []Blackout // Daily windows during which due runs are deferred or skipped, in addition to those of the TaskManager
+
+	IdempotencyKey string // Key of the job's logical work, due runs are skipped while a run of a job with the key is queued or executing
+
+	PanicPolicy    PanicPolicy // What to do with the job when one of its tasks panics
+	PanicThreshold int         // Panicking runs after which a PanicQuarantine job is dead-lettered, defaults to 1
+
+	MaxDispatchRate rate.Limit // Max runs dispatched per second, bursts of a single run, 0 for no limit
+
+	Priority int // Priority of the job's runs when the worker pool is saturated, higher first, see TaskManager.SetSaturationPolicy
+
+	Jitter float64 // Fraction between 0 and 0.5 of the cadence by which each execution is randomized, e.g. 0.1 for ±10%
+	Align  bool    // If true, executions are aligned to multiples of the cadence, e.g. :00, :05 and :10 for 5m
+
+	MaxRuns int       // Number of runs after which the job is removed, 0 for no limit
+	Until   time.Time // Time after which the job is removed instead of executed, zero for no deadline
+
+	IdleTTL time.Duration // Time without a touch after which the job is removed, overrides the TaskManager default if set, see TaskManager.SetJobTTL
+
+	Tags     []string          // Tags of the job, e.g. a tenant, for bulk operations such as TaskManager.PauseJobsByTag
+	Metadata map[string]string // Metadata of the job, e.g. a tenant ID, passed to its tasks and included in its events and errors
 
 	ID       string    // Unique ID for the job
 	NextExec time.Time // The next time the job should be executed
 
-	index int // Index within the heap
+	cron      *cronSchedule      // Cron schedule, if set it determines NextExec instead of Cadence
+	scheduled time.Time          // NextExec before jitter is applied, keeping jittered jobs from drifting
+	once      bool               // If true, the job is removed after its first execution
+	ctx       context.Context    // Context passed to the job's tasks, cancelled when the job is removed
+	cancel    context.CancelFunc // Cancel function for the job's context
+	state     *jobState          // Runtime state of the job
+	delayed   bool               // True if a due run is delayed by the job's overlap policy
+	awaiting  bool               // True while a dependent job waits for its dependencies to complete
+	paused    bool               // True while the job is paused, see TaskManager.PauseJob
+	deferred  bool               // True while a due run is deferred by maintenance mode, see TaskManager.StartMaintenance
+	runs      int                // Number of runs dispatched, counting towards MaxRuns
+	restored  bool               // True if the job's schedule was resumed from its record in the job store
+	template  *templateRef       // Template the job was scheduled from, if any
+	history   []RunRecord        // History restored from the job store, until the job is inserted
+	seq       uint64             // Sequence number, ordering jobs by when they were scheduled
+	index     int                // Index within the heap
+}
+
+// maxConcurrentRuns returns the maximum number of concurrently executing runs of the job, or 0 if
+// the number is unlimited.
+func (j *Job) maxConcurrentRuns() int {
+	if j.OverlapPolicy == OverlapAllow {
+		return 0
+	}
+	return max(j.MaxConcurrent, 1)
+}
+
+// JobInfo is a snapshot of a scheduled job, as returned by TaskManager.Jobs and TaskManager.Job.
+type JobInfo struct {
+	ID        string            // Unique ID of the job
+	Cadence   time.Duration     // Time between executions, an estimate for cron jobs
+	NextExec  time.Time         // The next time the job is executed
+	TaskCount int               // Number of tasks in the job
+	Running   int               // Number of runs currently executing
+	Paused    bool              // True if the job is paused
+	Tags      []string          // Tags of the job
+	Metadata  map[string]string // Metadata of the job
+}
+
+// QueuedRun is the next run of a scheduled job, as returned by QueueSnapshot.
+type QueuedRun struct {
+	JobID    string    // ID of the job
+	NextExec time.Time // Time the run is due
+}
+
+// sortQueuedRuns orders the runs by when they are due, returning the first limit of them, or all
+// of them if limit is 0 or less.
+func sortQueuedRuns(runs []QueuedRun, limit int) []QueuedRun {
+	slices.SortFunc(runs, func(a, b QueuedRun) int {
+		return a.NextExec.Compare(b.NextExec)
+	})
+	if limit > 0 && len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return runs
+}
+
+// info returns a snapshot of the job.
+// Note: should be called while holding the TaskManager's lock, as it reads the job's state.
+func (j *Job) info() JobInfo {
+	return JobInfo{
+		ID:        j.ID,
+		Cadence:   j.Cadence,
+		NextExec:  j.NextExec,
+		TaskCount: len(j.Tasks),
+		Running:   j.state.running,
+		Paused:    j.paused,
+		Tags:      slices.Clone(j.Tags),
+		Metadata:  maps.Clone(j.Metadata),
+	}
+}
+
+// nextExecAfter returns the job's next execution time without jitter, following an execution
+// dispatched at now. Executions missed before now are skipped, unless the job's misfire policy is
+// MisfireRunAllMissed.
+func (j *Job) nextExecAfter(now time.Time) time.Time {
+	next := j.following(j.scheduled)
+	if j.MisfirePolicy != MisfireRunAllMissed && !next.IsZero() && next.Before(now) {
+		next = j.firstAfter(now)
+	}
+	return next
+}
+
+// following returns the execution of the job following the one at t, without jitter, or the zero
+// time if the job's schedule has no further executions.
+func (j *Job) following(t time.Time) time.Time {
+	switch {
+	case j.Schedule != nil:
+		return j.Schedule.Next(t)
+	case j.cron != nil:
+		return j.cron.next(t)
+	default:
+		return t.Add(j.Cadence)
+	}
+}
+
+// firstAfter returns the first execution of the job after now, without jitter, keeping cadence
+// jobs aligned with their previous executions.
+func (j *Job) firstAfter(now time.Time) time.Time {
+	switch {
+	case j.Schedule != nil:
+		return j.Schedule.Next(now)
+	case j.cron != nil:
+		return j.cron.next(now)
+	case j.Cadence <= 0 || j.scheduled.After(now):
+		return j.following(j.scheduled)
+	default:
+		missed := now.Sub(j.scheduled) / j.Cadence
+		return j.scheduled.Add((missed + 1) * j.Cadence)
+	}
+}
+
+// misfired returns true if the job's due run is dispatched at now after its following execution
+// has passed as well. One-shot and dependent jobs have no following executions to miss.
+func (j *Job) misfired(now time.Time) bool {
+	if j.once || len(j.DependsOn) > 0 {
+		return false
+	}
+	next := j.following(j.scheduled)
+	return !next.IsZero() && !next.After(now)
+}
+
+// finalRun returns true if the run dispatched at now, already counted in the job's runs, is the
+// job's last, as it reaches MaxRuns, or as the job's next execution is after Until or beyond the
+// end of its schedule.
+func (j *Job) finalRun(now time.Time) bool {
+	if j.MaxRuns > 0 && j.runs >= j.MaxRuns {
+		return true
+	}
+	if len(j.DependsOn) > 0 {
+		return false
+	}
+	next := j.nextExecAfter(now)
+	if j.Schedule != nil && next.IsZero() {
+		return true
+	}
+	return !j.Until.IsZero() && next.After(j.Until)
+}
+
+// expired returns true if the job's Until has passed at now, or if its next execution is after it.
+func (j *Job) expired(now time.Time) bool {
+	return !j.Until.IsZero() && (now.After(j.Until) || j.scheduled.After(j.Until))
+}
+
+// alignTo returns the first multiple of the cadence at or after t. Multiples are counted from the
+// zero time, so cadences evenly dividing a day are aligned to midnight UTC.
+func alignTo(t time.Time, cadence time.Duration) time.Time {
+	aligned := t.Truncate(cadence)
+	if aligned.Before(t) {
+		aligned = aligned.Add(cadence)
+	}
+	return aligned
+}
+
+// blocked returns true if the job is not dispatched by the run loop, as it is suspended or its
+// runs are dispatched by its dedicated runner.
+func (j *Job) blocked() bool {
+	return j.suspended() || j.Dedicated
+}
+
+// suspended returns true if the job cannot be dispatched until a run of it, or of one of its
+// dependencies, completes, or until it is resumed.
+func (j *Job) suspended() bool {
+	return j.delayed || j.awaiting || j.paused || j.deferred
+}
+
+// pooled returns true if the job's tasks are executed by the default worker pool, rather than by
+// a worker group, workers of its own or remote workers.
+func (j *Job) pooled() bool {
+	return j.Group == "" && !j.Dedicated && !j.Remote
+}
+
+// reschedule sets the job's next execution time, following an execution dispatched at now.
+// Dependent jobs instead wait for their dependencies to complete again.
+func (j *Job) reschedule(now time.Time) {
+	if len(j.DependsOn) > 0 {
+		j.awaiting = true
+		j.state.completed = nil
+		return
+	}
+	j.scheduled = j.nextExecAfter(now)
+	j.NextExec = j.withJitter(j.scheduled)
+}
+
+// resume unpauses the job, skipping the executions missed before now.
+func (j *Job) resume(now time.Time) {
+	j.paused = false
+	if !j.scheduled.Before(now) || len(j.DependsOn) > 0 {
+		return
+	}
+	if j.once || (j.Schedule == nil && j.cron == nil && j.Cadence <= 0) {
+		j.scheduled = now
+	} else {
+		j.scheduled = j.firstAfter(now)
+	}
+	j.NextExec = j.withJitter(j.scheduled)
+	if j.expired(now) {
+		// No executions are left before Until, the job is due to be removed
+		j.NextExec = now
+	}
 }
 
-// ErrorChannel returns a read-only channel for reading errors from task execution.
+// withJitter returns t randomly offset by up to ±Jitter of the job's cadence.
+func (j *Job) withJitter(t time.Time) time.Time {
+	if j.Jitter <= 0 || j.Cadence <= 0 {
+		return t
+	}
+	maxOffset := j.Jitter * float64(j.Cadence)
+	return t.Add(time.Duration(maxOffset * (rand.Float64()*2 - 1)))
+}
+
+// ErrorChannel returns a read-only channel for reading errors from task execution. Errors of tasks
+// in scheduled jobs are of type *TaskError, identifying the job and task which produced them. The
+// channel is shared by all of its readers, use SubscribeErrors for a channel of one's own.
 func (tm *TaskManager) ErrorChannel() <-chan error {
 	return tm.errorChan
 }
 
+// Job returns a snapshot of the job with the given ID.
+func (tm *TaskManager) Job(jobID string) (JobInfo, error) {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	jobIndex, err := tm.jobQueue.JobInQueue(jobID)
+	if err != nil {
+		return JobInfo{}, fmt.Errorf("job with ID %s not found", jobID)
+	}
+	return tm.jobQueue.jobs[jobIndex].info(), nil
+}
+
+// Jobs returns a snapshot of all scheduled jobs, ordered by their next execution.
+func (tm *TaskManager) Jobs() []JobInfo {
+	tm.RLock()
+	jobs := make([]JobInfo, 0, tm.jobQueue.Len())
+	for _, job := range tm.jobQueue.jobs {
+		jobs = append(jobs, job.info())
+	}
+	tm.RUnlock()
+
+	slices.SortFunc(jobs, func(a, b JobInfo) int {
+		return a.NextExec.Compare(b.NextExec)
+	})
+	return jobs
+}
+
+// QueueSnapshot returns the next runs of up to limit scheduled jobs, ordered by when they are due,
+// e.g. for showing which jobs run in the next minute. Jobs which cannot be dispatched when due, as
+// they are paused, delayed by their overlap policy or waiting for their dependencies, are left
+// out. A limit of 0 or less returns the next runs of all such jobs.
+func (tm *TaskManager) QueueSnapshot(limit int) []QueuedRun {
+	tm.RLock()
+	runs := make([]QueuedRun, 0, tm.jobQueue.Len())
+	for _, job := range tm.jobQueue.jobs {
+		if job.suspended() {
+			continue
+		}
+		runs = append(runs, QueuedRun{JobID: job.ID, NextExec: job.NextExec})
+	}
+	tm.RUnlock()
+
+	return sortQueuedRuns(runs, limit)
+}
+
 // Metrics returns a snapshot of the task manager's metrics.
 func (tm *TaskManager) Metrics() TaskManagerMetrics {
 	tm.RLock()
@@ -104,30 +524,277 @@ func (tm *TaskManager) Metrics() TaskManagerMetrics {
 		QueuedJobs:           jobsInQueue,
 		QueuedTasks:          int(tm.metrics.tasksInQueue.Load()),
 		QueueMaxJobWidth:     int(tm.metrics.maxJobWidth.Load()),
+		JobsOverrunning:      tm.overrunningJobs(),
+		DispatchLateness:     tm.metrics.lateness.summary(),
+		RunsShed:             int(tm.runsShed.Load()),
+		RunsCoalesced:        int(tm.runsCoalesced.Load()),
+		TasksSpilled:         tm.overflow.len(),
+		DispatchBlocked:      tm.dispatchBlocked(),
 		TaskAverageExecTime:  tm.metrics.averageExecTime.Load(),
 		TasksTotalExecutions: int(tm.metrics.totalTaskExecutions.Load()),
 		TasksPerSecond:       tm.metrics.tasksPerSecond.Load(),
+		TaskTotalExecTime:    time.Duration(tm.taskTime.Load()),
+		TaskTotalCPUTime:     time.Duration(tm.taskCPUTime.Load()),
+		DroppedErrors:        int(tm.droppedErrors()),
+		DroppedResults:       int(tm.results.dropped.Load()),
+		DroppedExecTimes:     int(tm.workerPool.execTimesDropped.Load()),
+		ResultsBlocked:       time.Duration(tm.results.blocked.Load()),
 		WorkerCountTarget:    int(tm.workerPool.workerCountTarget.Load()),
 		WorkerScalingEvents:  int(tm.workerPool.workerScalingEvents.Load()),
 		WorkerUtilization:    float32(tm.workerPool.utilization()),
 		WorkersActive:        int(tm.workerPool.workersActive.Load()),
+		WorkersDraining:      int(tm.workerPool.drainingWorkers()),
 		WorkersRunning:       int(tm.workerPool.workersRunning.Load()),
 	}
 
 	return metrics
 }
 
+// SetWorkerCount sets the number of workers in the worker pool, which must be between 1 and 4096.
+// The count is the minimum kept by the automatic scaling of the pool, which adds workers beyond it
+// when the jobs in the queue require more. Scaling down is applied gradually, as the pool does not
+// remove workers while highly utilized or shortly after a previous downscaling.
+func (tm *TaskManager) SetWorkerCount(n int) error {
+	if n <= 0 || n > maxWorkerCount {
+		return fmt.Errorf("invalid worker count %d, must be between 1 and %d", n, maxWorkerCount)
+	}
+	if maxWorkers := int(tm.maxWorkers.Load()); n > maxWorkers {
+		return fmt.Errorf("invalid worker count %d, must not exceed the maximum of %d", n, maxWorkers)
+	}
+	tm.minWorkerCount.Store(int32(n))
+
+	tm.Lock()
+	defer tm.Unlock()
+	tm.scaleWorkerPool(0)
+	return nil
+}
+
+// SetWorkerBounds sets the minimum and maximum number of workers the automatic scaling of the
+// worker pool keeps it between, with 1 <= minWorkers <= maxWorkers <= 4096. The minimum is the
+// worker count set by SetWorkerCount, and the maximum defaults to 4096.
+func (tm *TaskManager) SetWorkerBounds(minWorkers, maxWorkers int) error {
+	if minWorkers <= 0 || minWorkers > maxWorkers || maxWorkers > maxWorkerCount {
+		return fmt.Errorf("invalid worker bounds %d and %d, must be 1 <= min <= max <= %d",
+			minWorkers, maxWorkers, maxWorkerCount)
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	tm.minWorkerCount.Store(int32(minWorkers))
+	tm.maxWorkers.Store(int32(maxWorkers))
+	tm.scaleWorkerPool(0)
+	return nil
+}
+
+// SetIdleScaleDown sets the time after which the worker pool is scaled down to its minimum worker
+// count once no tasks have been dispatched and no workers have been busy, rather than the automatic
+// scaling keeping the workers the jobs in the queue would otherwise require. The pool is checked at
+// the scale interval, and scaled up again as soon as tasks are dispatched. A duration of 0, the
+// default, disables scaling down on idle.
+func (tm *TaskManager) SetIdleScaleDown(d time.Duration) error {
+	if d < 0 {
+		return errors.New("invalid idle scale down duration, must not be negative")
+	}
+	tm.idleScaleDown.Store(int64(d))
+	return nil
+}
+
+// SetWorkerHeadroom sets the number of spare idle workers the automatic scaling keeps in the worker
+// pool beyond its busy workers, so that a sudden burst of due runs finds idle workers rather than
+// waiting for the pool to scale up. The headroom is part of the worker count the pool is scaled
+// to, within its worker bounds and resource budget, and follows the busy workers at the scale
+// interval. The pool is still scaled down to its minimum once idle, see SetIdleScaleDown. A
+// headroom of 0, the default, keeps no spare workers.
+func (tm *TaskManager) SetWorkerHeadroom(workers int) error {
+	if workers < 0 {
+		return errors.New("invalid worker headroom, must not be negative")
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	tm.headroom.Store(int32(workers))
+	tm.scaleWorkerPool(0)
+	return nil
+}
+
+// SetScaleDownPolicy sets when the automatic scaling removes workers from the worker pool: only
+// while the pool's utilization is below threshold, between 0 and 1, and at most once per interval.
+// Defaults to a threshold of 0.4 and an interval of 30 seconds.
+func (tm *TaskManager) SetScaleDownPolicy(threshold float64, interval time.Duration) error {
+	if threshold <= 0 || threshold > 1 {
+		return fmt.Errorf("invalid scale down threshold %v, must be greater than 0 and at most 1", threshold)
+	}
+	if interval < 0 {
+		return errors.New("invalid scale down interval, must not be negative")
+	}
+	tm.workerPool.downScaleThreshold.Store(threshold)
+	tm.workerPool.downScaleInterval.Store(interval)
+	return nil
+}
+
+// idle returns true if the worker pool has been idle for the idle scale down duration, if set.
+func (tm *TaskManager) idle() bool {
+	d := time.Duration(tm.idleScaleDown.Load())
+	if d <= 0 || tm.workerPool.activeWorkers() > 0 {
+		return false
+	}
+	return tm.now().Sub(time.Unix(0, tm.lastDispatch.Load())) >= d
+}
+
+// WorkerCount returns the number of workers the worker pool is currently scaled to, which the
+// number of running workers approaches as workers are started and stopped.
+func (tm *TaskManager) WorkerCount() int {
+	return int(tm.workerPool.targetWorkerCount())
+}
+
+// DrainWorkers removes n workers from the worker pool at once, regardless of its utilization,
+// lowering the worker count by n. Idle workers stop immediately, while busy workers finish their
+// current task and stop without receiving another. The minimum worker count set by SetWorkerCount
+// is lowered to the new worker count, if above it, for the automatic scaling not to replace the
+// drained workers. Returns a channel which is closed once all drained workers have stopped.
+func (tm *TaskManager) DrainWorkers(n int) (<-chan struct{}, error) {
+	tm.Lock()
+	defer tm.Unlock()
+
+	drained, err := tm.workerPool.drainWorkers(n)
+	if err != nil {
+		return nil, err
+	}
+	workers := tm.workerPool.targetWorkerCount()
+	if tm.minWorkerCount.Load() > workers {
+		tm.minWorkerCount.Store(workers)
+	}
+	tm.emitEvent(Event{Type: EventWorkerScaled, Workers: int(workers)})
+	return drained, nil
+}
+
+// SetDiscardRemovedTasks sets whether the tasks of a removed job which were dispatched, but have not
+// started executing, are discarded instead of executed. Tasks already executing have their context
+// cancelled either way. Since the context of a job's tasks is also cancelled when the TaskManager
+// is stopped, tasks still waiting for a worker when stopping are discarded too. Discarded tasks are
+// marked as skipped in the result of their run.
+func (tm *TaskManager) SetDiscardRemovedTasks(discard bool) {
+	tm.Lock()
+	defer tm.Unlock()
+	tm.discardRemoved = discard
+}
+
+// SetPanicHandler sets the handler called when a task of a job panics, before the panic is reported
+// as a *PanicError like other task errors. The handler is called from the panicking task's
+// worker, and must not block it for long. A nil handler removes the handler.
+func (tm *TaskManager) SetPanicHandler(handler PanicHandler) {
+	tm.Lock()
+	defer tm.Unlock()
+	tm.panicHandler = handler
+}
+
+// SetRecoverDisabled disables the recovery of panicking tasks and fallbacks, for debugging. With
+// recovery disabled, panics are still logged and passed to the panic handler, but then crash the
+// process with the stack trace of the panicking task, rather than being reported as a *PanicError.
+// It is meant for development, as a single panicking task takes the whole process down.
+func (tm *TaskManager) SetRecoverDisabled(disabled bool) {
+	tm.Lock()
+	defer tm.Unlock()
+	tm.recoverDisabled = disabled
+}
+
+// SetRetryPolicy sets the default retry policy, applied to tasks of jobs without a retry policy of
+// their own. A nil policy disables retries for such jobs.
+func (tm *TaskManager) SetRetryPolicy(policy *RetryPolicy) error {
+	if policy != nil {
+		if err := policy.validate(); err != nil {
+			return err
+		}
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	tm.retryPolicy = policy
+	return nil
+}
+
 // ScheduleFunc takes a function and adds it to the TaskManager in a Job. Creates and returns a
 // randomized ID, used to identify the Job within the task manager.
 func (tm *TaskManager) ScheduleFunc(function func() error, cadence time.Duration) (string, error) {
 	task := SimpleTask{function}
-	jobID := xid.New().String()
+	jobID := tm.newJobID()
 
 	job := Job{
 		Tasks:    []Task{task},
 		Cadence:  cadence,
 		ID:       jobID,
-		NextExec: time.Now().Add(cadence),
+		NextExec: tm.now().Add(cadence),
+	}
+
+	return jobID, tm.ScheduleJob(job)
+}
+
+// ScheduleFuncContext takes a context-aware function and adds it to the TaskManager in a Job. The
+// context passed to the function is cancelled when the TaskManager is stopped or the job is
+// removed. Creates and returns a randomized ID, used to identify the Job within the task manager.
+func (tm *TaskManager) ScheduleFuncContext(function func(ctx context.Context) error, cadence time.Duration) (string, error) {
+	task := SimpleContextTask{function}
+	jobID := tm.newJobID()
+
+	job := Job{
+		Tasks:    []Task{task},
+		Cadence:  cadence,
+		ID:       jobID,
+		NextExec: tm.now().Add(cadence),
+	}
+
+	return jobID, tm.ScheduleJob(job)
+}
+
+// ScheduleCron takes a Task and adds it to the TaskManager in a Job, executed according to the
+// cron expression. Both standard 5-field expressions and 6-field expressions with a leading
+// seconds field are accepted, as well as descriptors such as @daily and @hourly. Times are
+// evaluated in the local time zone. Creates and returns a randomized ID, used to identify the Job
+// within the task manager.
+func (tm *TaskManager) ScheduleCron(task Task, cronExpr string) (string, error) {
+	job, err := cronJob(tm.newJobID(), task, cronExpr, tm.now())
+	if err != nil {
+		return "", err
+	}
+	return job.ID, tm.ScheduleJob(job)
+}
+
+// cronJob returns a job of the given ID, executing the task according to the cron expression.
+func cronJob(jobID string, task Task, cronExpr string, now time.Time) (Job, error) {
+	schedule, err := parseCron(cronExpr)
+	if err != nil {
+		return Job{}, err
+	}
+
+	nextExec := schedule.next(now)
+	if nextExec.IsZero() {
+		return Job{}, fmt.Errorf("cron expression %q never matches", cronExpr)
+	}
+
+	// The cadence is an estimate of the time between executions, used for validation and metrics
+	return Job{
+		Tasks:    []Task{task},
+		Cadence:  schedule.interval(now),
+		ID:       jobID,
+		NextExec: nextExec,
+		cron:     schedule,
+	}, nil
+}
+
+// ScheduleCalendar takes a Task and adds it to the TaskManager in a Job, executed according to the
+// schedule, e.g. DailyAt or Weekly. Creates and returns a randomized ID, used to identify the Job
+// within the task manager.
+func (tm *TaskManager) ScheduleCalendar(task Task, schedule Schedule) (string, error) {
+	if schedule == nil {
+		return "", errors.New("schedule cannot be nil")
+	}
+	jobID := tm.newJobID()
+
+	// NextExec and Cadence are derived from the schedule when the job is scheduled
+	job := Job{
+		Tasks:    []Task{task},
+		ID:       jobID,
+		Schedule: schedule,
 	}
 
 	return jobID, tm.ScheduleJob(job)
@@ -142,63 +809,298 @@ func (tm *TaskManager) ScheduleFunc(function func() error, cadence time.Duration
 // - Job must have at least one task
 // - NextExec must not be more than one cadence old, set to time.Now() for instant execution
 // - Job must have an ID, unique within the TaskManager
+// Jobs with a Schedule may leave Cadence and NextExec unset, in which case they are derived from
+// the schedule.
+// Jobs with Align set start at the first multiple of their cadence at or after NextExec, or now if
+// NextExec is unset, and keep executing at multiples of their cadence.
+// Jobs with MaxRuns or Until set are removed after their final run, or once Until has passed.
+// If the queue has reached its maximum number of jobs, the overflow policy set with SetMaxJobs
+// applies.
+// A job due before the jobs already queued is dispatched when due, as the run loop is woken from
+// waiting for the earliest of them.
 func (tm *TaskManager) ScheduleJob(job Job) error {
+	dropped, err := tm.scheduleJob(job)
+	if err != nil {
+		return err
+	}
+
+	// Call the removal hooks without holding the lock, allowing them to use the TaskManager
+	if dropped != nil {
+		tm.hooks.jobWasRemoved(dropped.ID)
+	}
+	return nil
+}
+
+// ValidateJob validates a job as ScheduleJob does, without scheduling it, e.g. for checking the
+// jobs of a configuration in a CI pipeline before deploying it. The job is validated against the
+// TaskManager's current state, so a job with the ID of a scheduled job is invalid, as are jobs
+// assigned to a worker group which does not exist.
+func (tm *TaskManager) ValidateJob(job Job) error {
+	tm.RLock()
+	defer tm.RUnlock()
+	tm.setJobDefaults(&job)
+	return tm.validateJob(job)
+}
+
+// scheduleJob adds a job to the queue, returning the job dropped to make room for it, if any.
+func (tm *TaskManager) scheduleJob(job Job) (*Job, error) {
 	tm.Lock()
 	defer tm.Unlock()
 
-	// Validate the job
-	err := tm.validateJob(job)
-	if err != nil {
-		return err
+	if err := tm.prepareJob(&job); err != nil {
+		return nil, err
 	}
-	logger.Debug().Msgf("Scheduling job with %d tasks with ID '%s' and cadence %v", len(job.Tasks), job.ID, job.Cadence)
+	tm.logger.Debug("Scheduling job", "jobID", job.ID, "tasks", len(job.Tasks), "cadence", job.Cadence)
 
 	// Check if the task manager is stopped
 	select {
 	case <-tm.ctx.Done():
 		// If the manager is stopped, do not continue adding the job
-		return errors.New("task manager is stopped")
+		return nil, ErrManagerStopped
 	default:
 		// Do nothing if the manager isn't stopped
 	}
+	if tm.draining() {
+		return nil, ErrDraining
+	}
 
-	// Update task metrics
-	taskCount := len(job.Tasks)
-	tm.metrics.updateTaskMetrics(taskCount, job.Cadence)
+	// Make room for the job if the queue is full
+	dropped, err := tm.makeRoom(job)
+	if err != nil {
+		return nil, err
+	}
 
-	// Scale worker pool if needed
-	tm.scaleWorkerPool(taskCount)
+	// Persist the job
+	if tm.store != nil {
+		if err := tm.storeJob(&job); err != nil {
+			return nil, err
+		}
+	}
+
+	tm.insertJob(&job)
+	if job.pooled() {
+		tm.scaleWorkerPool(len(job.Tasks))
+	}
+
+	// Signal the run loop, which may be waiting for a job due later than this one, unless it has
+	// been stopped since the job was inserted
+	tm.wakeRunLoop()
+
+	return dropped, nil
+}
+
+// prepareJob derives the defaults of a job about to be scheduled, resumes its stored schedule, if
+// any, and validates it.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) prepareJob(job *Job) error {
+	tm.setJobDefaults(job)
+
+	// Copy the metadata, which is read by the job's tasks and events without holding the lock
+	job.Metadata = maps.Clone(job.Metadata)
+
+	// Resume the job's stored schedule, if any
+	if tm.store != nil {
+		if err := tm.restoreJob(job); err != nil {
+			return err
+		}
+	}
+
+	return tm.validateJob(*job)
+}
+
+// setJobDefaults sets the NextExec and Cadence of a job left unset to their defaults.
+func (tm *TaskManager) setJobDefaults(job *Job) {
+	// Cron jobs default to the estimated interval of their expression as their cadence
+	if job.cron != nil && job.Cadence == 0 {
+		job.Cadence = job.cron.interval(tm.now())
+	}
+
+	// Jobs with a schedule default to its first execution, and to its interval as their cadence
+	if job.Schedule != nil {
+		now := tm.now()
+		if job.NextExec.IsZero() {
+			job.NextExec = job.Schedule.Next(now)
+		}
+		if job.Cadence == 0 {
+			job.Cadence = scheduleInterval(job.Schedule, now)
+		}
+	}
+
+	// Aligned jobs default to executing now, moved to the first multiple of their cadence
+	if job.Align && job.Cadence > 0 {
+		if job.NextExec.IsZero() {
+			job.NextExec = tm.now()
+		}
+		job.NextExec = alignTo(job.NextExec, job.Cadence)
+	}
+}
+
+// storeJob saves the record of a job about to be scheduled in the job store.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) storeJob(job *Job) error {
+	job.scheduled = job.NextExec
+	record, err := job.record()
+	if err != nil {
+		return err
+	}
+	if err := tm.store.Save(record); err != nil {
+		return fmt.Errorf("failed to save job %s to the job store: %w", job.ID, err)
+	}
+	return nil
+}
+
+// insertJob initializes the runtime state of a prepared job and pushes it to the queue. Scaling
+// the worker pool for the job's tasks is up to the caller.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) insertJob(job *Job) {
+	// Update task metrics, unless executed by a worker group or workers of its own
+	if job.pooled() {
+		tm.metrics.updateTaskMetrics(len(job.Tasks), job.Cadence)
+	}
+
+	// Derive the job's context from the manager's, so that stopping the manager cancels it
+	job.ctx, job.cancel = tm.jobContext(job.BaseContext)
+	// Adjust the schedules of the queued jobs to any clock jump, before the job is scheduled after it
+	tm.observeClock()
+	tm.jobSeq++
+	job.seq = tm.jobSeq
+	job.state = &jobState{}
+	job.state.stats.restoreHistory(job.history, int(tm.historySize.Load()))
+	job.history = nil
+	job.awaiting = len(job.DependsOn) > 0
+	job.state.setDispatchRate(job.MaxDispatchRate, tm.now())
+	tm.touchJob(job)
+
+	// Randomize the first execution, jitter is applied relative to the unjittered schedule
+	job.scheduled = job.NextExec
+	job.NextExec = job.withJitter(job.NextExec)
 
 	// Push the job to the queue
-	heap.Push(&tm.jobQueue, &job)
+	heap.Push(&tm.jobQueue, job)
+	if job.Dedicated {
+		tm.startDedicated(job)
+	}
+	tm.emitEvent(Event{Type: EventJobScheduled, JobID: job.ID, Metadata: job.Metadata})
+}
+
+// ScheduleJobs schedules a batch of jobs atomically, holding the TaskManager's lock once for the
+// whole batch. All jobs are validated as with ScheduleJob before any is scheduled, and if any job
+// is invalid, or fails to be saved to the job store, none of the jobs are scheduled. Jobs in the
+// batch may depend on each other. If the batch does not fit in the queue, ErrQueueFull is returned
+// regardless of the overflow policy.
+func (tm *TaskManager) ScheduleJobs(jobs []Job) error {
+	tm.Lock()
+	defer tm.Unlock()
 
-	// Signal the task manager to check for new tasks
 	select {
 	case <-tm.ctx.Done():
-		// Do nothing if the manager is stopped
-		return errors.New("task manager is stopped")
+		return ErrManagerStopped
 	default:
-		select {
-		case tm.newJobChan <- true:
-			logger.Trace().Msg("Signaled new job added")
-		default:
-			// Do nothing if no one is listening
+	}
+	if tm.draining() {
+		return ErrDraining
+	}
+
+	// Prepare and validate all jobs before scheduling any of them
+	batch := make([]Job, len(jobs))
+	ids := make(map[string]bool, len(jobs))
+	for i, job := range jobs {
+		if err := tm.prepareJob(&job); err != nil {
+			return fmt.Errorf("job %s: %w", job.ID, err)
 		}
+		if ids[job.ID] {
+			return fmt.Errorf("job %s: %w in batch", job.ID, ErrDuplicateJobID)
+		}
+		ids[job.ID] = true
+		batch[i] = job
+	}
+	if err := tm.validateBatchDependencies(batch); err != nil {
+		return err
+	}
+	if tm.maxJobs > 0 && tm.jobQueue.Len()+len(batch) > tm.maxJobs {
+		return ErrQueueFull
 	}
 
+	// Persist all jobs, deleting the records saved so far if any fails
+	if tm.store != nil {
+		for i := range batch {
+			if err := tm.storeJob(&batch[i]); err != nil {
+				for _, saved := range batch[:i] {
+					tm.unpersistJob(tm.store, saved.ID)
+				}
+				return err
+			}
+		}
+	}
+
+	// Schedule the jobs, scaling the worker pool once for the widest of them
+	widest := 0
+	for i := range batch {
+		tm.insertJob(&batch[i])
+		if batch[i].pooled() {
+			widest = max(widest, len(batch[i].Tasks))
+		}
+	}
+	tm.scaleWorkerPool(widest)
+	tm.logger.Debug("Scheduled batch of jobs", "jobs", len(batch))
+
+	// Signal the run loop, which may be waiting for a job due later than these
+	tm.wakeRunLoop()
 	return nil
 }
 
+// jobContext returns the context of a job, passed to its tasks. It is derived from the
+// TaskManager's context, so that stopping the TaskManager cancels it, and carries the values of
+// the job's base context, if set.
+func (tm *TaskManager) jobContext(base context.Context) (context.Context, context.CancelFunc) {
+	if base == nil {
+		return context.WithCancel(tm.ctx)
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(base))
+	stop := context.AfterFunc(tm.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// ScheduleOnce takes a Task and adds it to the TaskManager in a Job, which executes exactly once
+// after the delay and is then removed from the TaskManager. A delay of 0 executes the task as soon
+// as possible. Creates and returns a randomized ID, used to identify the Job within the task
+// manager, e.g. to remove it before it has executed.
+func (tm *TaskManager) ScheduleOnce(task Task, delay time.Duration) (string, error) {
+	job, err := onceJob(tm.newJobID(), task, delay, tm.now())
+	if err != nil {
+		return "", err
+	}
+	return job.ID, tm.ScheduleJob(job)
+}
+
+// onceJob returns a job of the given ID, executing the task once after the delay.
+func onceJob(jobID string, task Task, delay time.Duration, now time.Time) (Job, error) {
+	if delay < 0 {
+		return Job{}, errors.New("invalid delay, must not be negative")
+	}
+
+	// One-shot jobs have no cadence, as they are never rescheduled
+	return Job{
+		Tasks:    []Task{task},
+		ID:       jobID,
+		NextExec: now.Add(delay),
+		once:     true,
+	}, nil
+}
+
 // ScheduleTask takes a Task and adds it to the TaskManager in a Job. Creates and returns a
 // randomized ID, used to identify the Job within the task manager.
 func (tm *TaskManager) ScheduleTask(task Task, cadence time.Duration) (string, error) {
-	jobID := xid.New().String()
+	jobID := tm.newJobID()
 
 	job := Job{
 		Tasks:    append([]Task(nil), []Task{task}...),
 		Cadence:  cadence,
 		ID:       jobID,
-		NextExec: time.Now().Add(cadence),
+		NextExec: tm.now().Add(cadence),
 	}
 
 	return jobID, tm.ScheduleJob(job)
@@ -207,45 +1109,352 @@ func (tm *TaskManager) ScheduleTask(task Task, cadence time.Duration) (string, e
 // ScheduleTasks takes a slice of Task and adds them to the TaskManager in a Job. Creates and returns a
 // randomized ID, used to identify the Job within the task manager.
 func (tm *TaskManager) ScheduleTasks(tasks []Task, cadence time.Duration) (string, error) {
-	jobID := xid.New().String()
+	jobID := tm.newJobID()
 
 	// Takes a copy of the tasks, avoiding unintended consequences if the slice is modified
 	job := Job{
 		Tasks:    append([]Task(nil), tasks...),
 		Cadence:  cadence,
 		ID:       jobID,
-		NextExec: time.Now().Add(cadence),
+		NextExec: tm.now().Add(cadence),
 	}
 
 	return jobID, tm.ScheduleJob(job)
 }
 
-// RemoveJob removes a job from the TaskManager.
+// RemoveJob removes a job from the TaskManager, whether scheduled or dead-lettered.
 func (tm *TaskManager) RemoveJob(jobID string) error {
+	err := func() error {
+		tm.Lock()
+		defer tm.Unlock()
+
+		// Get the job from the queue, or from the dead-lettered jobs
+		job, ok := tm.deadLetters[jobID]
+		if !ok {
+			jobIndex, err := tm.jobQueue.JobInQueue(jobID)
+			if err != nil {
+				return fmt.Errorf("job with ID %s: %w", jobID, ErrJobNotFound)
+			}
+			job = tm.jobQueue.jobs[jobIndex]
+		}
+		return tm.discardJob(job)
+	}()
+	if err != nil {
+		return err
+	}
+
+	// Call the removal hooks without holding the lock, allowing them to use the TaskManager
+	tm.hooks.jobWasRemoved(jobID)
+
+	return nil
+}
+
+// discardJob removes a scheduled or dead-lettered job from the TaskManager and its job store, and
+// cancels the context of any of the job's tasks still executing.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) discardJob(job *Job) error {
+	if _, ok := tm.deadLetters[job.ID]; ok {
+		delete(tm.deadLetters, job.ID)
+	} else if err := tm.removeJob(job); err != nil {
+		return err
+	}
+	if tm.store != nil {
+		tm.unpersistJob(tm.store, job.ID)
+	}
+	tm.releaseRunWaiters(job.ID)
+	job.cancel()
+	return nil
+}
+
+// ReplaceJob replaces a job in the TaskManager's queue with a new job, if their ID:s match. The
+// new job's NextExec will be overwritten by the old job's, to preserve the TaskManager's schedule,
+// and the job's execution statistics are kept. A job scheduled with ScheduleOnce stays a one-shot
+// job, and a job scheduled with ScheduleCron keeps executing according to its cron expression.
+// Returns an error if the new job is invalid, see ValidateJob.
+// Use this function to update a job's tasks without changing its schedule.
+func (tm *TaskManager) ReplaceJob(newJob Job) error {
 	tm.Lock()
 	defer tm.Unlock()
 
-	// Get the job from the queue
-	jobIndex, err := tm.jobQueue.JobInQueue(jobID)
-	if err != nil {
-		return fmt.Errorf("job with ID %s not found", jobID)
-	}
-	job := tm.jobQueue[jobIndex]
+	// Get the job's index in the queue
+	jobIndex, err := tm.jobQueue.JobInQueue(newJob.ID)
+	if err != nil {
+		return errors.New("job not found")
+	}
+
+	// Replace the job in the queue
+	oldJob := tm.jobQueue.jobs[jobIndex]
+	if newJob.Dedicated != oldJob.Dedicated {
+		return errors.New("cannot change whether a job is dedicated by replacing it")
+	}
+	newJob.cron = oldJob.cron
+	tm.setJobDefaults(&newJob)
+	newJob.Metadata = maps.Clone(newJob.Metadata)
+	newJob.NextExec = oldJob.NextExec
+	newJob.once = oldJob.once
+
+	// Validate the new job as it will be scheduled, its NextExec being the TaskManager's own, which
+	// may be overdue while the job is paused or delayed
+	validated := newJob
+	if now := tm.now(); validated.NextExec.Before(now) {
+		validated.NextExec = now
+	}
+	if err := tm.validateJobConfig(validated); err != nil {
+		return err
+	}
+
+	newJob.scheduled = oldJob.scheduled
+	newJob.ctx, newJob.cancel = oldJob.ctx, oldJob.cancel
+	newJob.state = oldJob.state
+	newJob.state.setDispatchRate(newJob.MaxDispatchRate, tm.now())
+	tm.touchJob(&newJob)
+	newJob.delayed = oldJob.delayed
+	newJob.awaiting = oldJob.awaiting
+	newJob.paused = oldJob.paused
+	newJob.deferred = oldJob.deferred
+	newJob.runs = oldJob.runs
+	newJob.template = oldJob.template
+	newJob.seq = oldJob.seq
+	newJob.index = oldJob.index
+	if tm.store != nil {
+		record, err := newJob.record()
+		if err != nil {
+			return err
+		}
+		tm.persistJob(tm.store, record)
+	}
+	tm.jobQueue.Replace(jobIndex, &newJob)
+	return nil
+}
+
+// PauseJob pauses a job, which is kept in the TaskManager but not executed until resumed with
+// ResumeJob. Runs already executing are left to finish.
+func (tm *TaskManager) PauseJob(jobID string) error {
+	tm.Lock()
+	defer tm.Unlock()
+
+	jobIndex, err := tm.jobQueue.JobInQueue(jobID)
+	if err != nil {
+		return fmt.Errorf("job with ID %s: %w", jobID, ErrJobNotFound)
+	}
+	tm.pauseJob(tm.jobQueue.jobs[jobIndex])
+	return nil
+}
+
+// pauseJob pauses a scheduled job.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) pauseJob(job *Job) {
+	job.paused = true
+	heap.Fix(&tm.jobQueue, job.index)
+	tm.logger.Debug("Paused job", "jobID", job.ID)
+	tm.saveJob(job)
+}
+
+// ResumeJob resumes a job paused with PauseJob. Executions missed while paused are skipped, and
+// the job resumes at its next execution following its schedule. A one-shot job which became due
+// while paused is executed immediately, and a job with no executions left before its Until is
+// removed.
+func (tm *TaskManager) ResumeJob(jobID string) error {
+	tm.Lock()
+	defer tm.Unlock()
+
+	jobIndex, err := tm.jobQueue.JobInQueue(jobID)
+	if err != nil {
+		return fmt.Errorf("job with ID %s: %w", jobID, ErrJobNotFound)
+	}
+	if tm.resumeJob(tm.jobQueue.jobs[jobIndex]) {
+		// Signal the run loop that the job may be due
+		tm.wakeRunLoop()
+	}
+	return nil
+}
+
+// resumeJob resumes a scheduled job, returning false if the job was not paused.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) resumeJob(job *Job) bool {
+	if !job.paused {
+		return false
+	}
+	job.resume(tm.now())
+	heap.Fix(&tm.jobQueue, job.index)
+	if job.Dedicated {
+		tm.wakeDedicated(job)
+	}
+	tm.logger.Debug("Resumed job", "jobID", job.ID, "nextExec", job.NextExec)
+	tm.saveJob(job)
+	return true
+}
+
+// TriggerJob executes a job immediately, outside of its regular schedule, which is left unchanged.
+// The job's overlap policy applies, and an error is returned if the job has reached its limit of
+// concurrently executing runs. Triggering a job scheduled with ScheduleOnce executes it ahead of
+// time and removes it from the TaskManager. Triggered runs count towards the job's MaxRuns.
+// Note: blocks until all of the job's tasks have been dispatched to the worker pool.
+func (tm *TaskManager) TriggerJob(jobID string) error {
+	return tm.triggerJob(jobID, nil)
+}
+
+// RunJobNow executes a job immediately, as TriggerJob does, and blocks until the triggered run has
+// finished, returning its result, e.g. for running a job on demand and showing its output. Returns
+// the context's error if ctx is done before the run finishes, leaving the run executing, and
+// ErrManagerStopped if the TaskManager is stopped before it finishes.
+func (tm *TaskManager) RunJobNow(ctx context.Context, jobID string) (JobResult, error) {
+	done := make(chan JobResult, 1)
+	if err := tm.triggerJob(jobID, done); err != nil {
+		return JobResult{}, err
+	}
+
+	select {
+	case result := <-done:
+		return result, nil
+	case <-ctx.Done():
+		return JobResult{}, ctx.Err()
+	case <-tm.ctx.Done():
+		// Prefer the result, if the run finished before the TaskManager stopped
+		select {
+		case result := <-done:
+			return result, nil
+		default:
+			return JobResult{}, ErrManagerStopped
+		}
+	}
+}
+
+// triggerJob executes a job immediately, passing the result of the run to done once finished, if
+// not nil.
+func (tm *TaskManager) triggerJob(jobID string, done chan JobResult) error {
+	tm.Lock()
+
+	// Check if the task manager is stopped
+	select {
+	case <-tm.ctx.Done():
+		tm.Unlock()
+		return ErrManagerStopped
+	default:
+	}
+	if tm.draining() {
+		tm.Unlock()
+		return ErrDraining
+	}
+
+	jobIndex, err := tm.jobQueue.JobInQueue(jobID)
+	if err != nil {
+		tm.Unlock()
+		return fmt.Errorf("job with ID %s: %w", jobID, ErrJobNotFound)
+	}
+	job := tm.jobQueue.jobs[jobIndex]
+	if limit := job.maxConcurrentRuns(); limit > 0 && job.state.running >= limit {
+		tm.Unlock()
+		return fmt.Errorf("job with ID %s has %d runs executing", jobID, job.state.running)
+	}
+
+	tm.logger.Debug("Triggering job", "jobID", jobID)
+	tm.touchJob(job)
+	tasks := tm.startRun(job, tm.now())
+	tasks[0].run.done = done
+	queue := tm.taskQueueOf(job)
+	// A one-shot job's only execution is the triggered one, as is the final run of other jobs
+	job.runs++
+	removed := false
+	if job.once || (job.MaxRuns > 0 && job.runs >= job.MaxRuns) {
+		if err := tm.removeJob(job); err != nil {
+			tm.logger.Warn("Failed to remove one-shot job", "jobID", jobID, "error", err)
+		} else {
+			removed = true
+			tasks[0].run.final = true
+		}
+	}
+	store := tm.store
+	tm.Unlock()
+
+	dispatched := tm.dispatchRun(job, queue, tasks, false)
+	if removed {
+		if store != nil {
+			tm.unpersistJob(store, jobID)
+		}
+		tm.hooks.jobWasRemoved(jobID)
+	}
+	if !dispatched {
+		return ErrManagerStopped
+	}
+	return nil
+}
+
+// Stop signals the TaskManager to stop processing tasks and exit. The context passed to executing
+// context-aware tasks is cancelled, giving them a chance to return early.
+// Note: blocks until the TaskManager, including all workers, has completely stopped.
+func (tm *TaskManager) Stop() {
+	tm.stopOnce.Do(func() {
+		// Signal the manager to stop
+		tm.cancel()
+
+		// Wake any callers blocked on a full queue, holding the lock so that none are about to wait,
+		// and any waiting for the manager to drain
+		tm.Lock()
+		tm.queueSpace.Broadcast()
+		tm.closeDrained()
+		tm.Unlock()
+
+		// Stop the worker pool and worker groups
+		tm.workerPool.stop()
+		tm.stopWorkerGroups()
+		tm.stopWorkerReservation()
+		tm.stopDedicated()
+
+		// Wait for the run loop to exit, the worker pool to stop, and sequential dispatches to abort
+		<-tm.runDone
+		<-tm.workerPoolDone
+		tm.dispatches.Wait()
+
+		// Wait for the distributed lock to be released, if set
+		tm.RLock()
+		lockDone := tm.lockDone
+		tm.RUnlock()
+		if lockDone != nil {
+			<-lockDone
+		}
+
+		// Close the remaining channels
+		close(tm.newJobChan)
+		tm.errorFan.stop()
+		tm.events.close()
+		tm.results.close()
+		tm.taskQueue.close()
+
+		tm.logger.Debug("TaskManager stopped")
+	})
+}
 
+// removeJob removes a job from the queue, and updates the task metrics and worker pool accordingly.
+// Note: does not acquire a mutex lock for accessing the jobQueue, that is up to the caller.
+func (tm *TaskManager) removeJob(job *Job) error {
 	// Remove the job from the queue
-	err = tm.jobQueue.RemoveByID(jobID)
+	err := tm.jobQueue.RemoveByID(job.ID)
 	if err != nil {
 		return err
 	}
+	tm.queueSpace.Broadcast()
+	if tm.jobQueue.Len() == 0 {
+		tm.emitEvent(Event{Type: EventQueueEmpty})
+	}
+
+	// Jobs executed by worker groups or workers of their own are not part of the default worker
+	// pool's metrics
+	if !job.pooled() {
+		return nil
+	}
 
 	// Update task metrics
 	newWidestJob := 0
 	taskCount := len(job.Tasks)
 	if taskCount == int(tm.metrics.maxJobWidth.Load()) {
 		// If the removed job is widest, find the second widest job in the queue
-		for _, j := range tm.jobQueue {
+		for _, j := range tm.jobQueue.jobs {
+			if !j.pooled() {
+				continue
+			}
 			// If another job has the same number of tasks, keep the widest job at the same value
-			if len(j.Tasks) == taskCount && j.ID != jobID {
+			if len(j.Tasks) == taskCount && j.ID != job.ID {
 				newWidestJob = taskCount
 				break
 			}
@@ -265,50 +1474,6 @@ func (tm *TaskManager) RemoveJob(jobID string) error {
 	return nil
 }
 
-// ReplaceJob replaces a job in the TaskManager's queue with a new job, if their ID:s match. The
-// new job's NextExec will be overwritten by the old job's, to preserve the TaskManager's schedule.
-// Use this function to update a job's tasks without changing its schedule.
-func (tm *TaskManager) ReplaceJob(newJob Job) error {
-	tm.Lock()
-	defer tm.Unlock()
-
-	// Get the job's index in the queue
-	jobIndex, err := tm.jobQueue.JobInQueue(newJob.ID)
-	if err != nil {
-		return errors.New("job not found")
-	}
-
-	// Replace the job in the queue
-	oldJob := tm.jobQueue[jobIndex]
-	newJob.NextExec = oldJob.NextExec
-	newJob.index = oldJob.index
-	tm.jobQueue[jobIndex] = &newJob
-	return nil
-}
-
-// Stop signals the TaskManager to stop processing tasks and exit.
-// Note: blocks until the TaskManager, including all workers, has completely stopped.
-func (tm *TaskManager) Stop() {
-	tm.stopOnce.Do(func() {
-		// Signal the manager to stop
-		tm.cancel()
-
-		// Stop the worker pool
-		tm.workerPool.stop()
-
-		// Wait for the run loop to exit, and the worker pool to stop
-		<-tm.runDone
-		<-tm.workerPoolDone
-
-		// Close the remaining channels
-		close(tm.newJobChan)
-		close(tm.errorChan)
-		close(tm.taskChan)
-
-		logger.Debug().Msg("TaskManager stopped")
-	})
-}
-
 // jobsInQueue returns the length of the jobQueue slice.
 func (tm *TaskManager) jobsInQueue() int {
 	tm.Lock()
@@ -334,38 +1499,40 @@ func (tm *TaskManager) run() {
 				// TaskManager received stop signal, exiting run loop
 				return
 			}
+		} else if tm.jobQueue.jobs[0].blocked() || tm.drainedJob(tm.jobQueue.jobs[0]) {
+			// Blocked jobs are ordered last, so all jobs are waiting for executing runs to complete,
+			// while draining no job due after draining started is dispatched
+			tm.checkDrained()
+			tm.Unlock()
+			select {
+			case <-tm.newJobChan:
+				// New job added or delayed job released, checking for next job
+				continue
+			case <-tm.ctx.Done():
+				// TaskManager received stop signal, exiting run loop
+				return
+			}
 		} else {
-			nextJob := tm.jobQueue[0]
-			now := time.Now()
-			delay := nextJob.NextExec.Sub(now)
+			now := tm.observeClock()
+			delay := tm.jobQueue.jobs[0].NextExec.Sub(now)
 			if delay <= 0 {
-				logger.Trace().Msgf("Dispatching job %s", nextJob.ID)
-				tasks := nextJob.Tasks
+				// Take the due runs in one critical section, and dispatch them without the lock
+				runs, dropped := tm.takeDueRuns(now)
+				store := tm.store
 				tm.Unlock()
 
-				// Dispatch all tasks in the job to the worker pool for execution
-				for _, task := range tasks {
-					select {
-					case <-tm.ctx.Done():
-						// TaskManager received stop signal during task dispatch, exiting run loop
-						return
-					case tm.taskChan <- task:
-						// Successfully sent the task
-					}
+				if !tm.dispatchDueRuns(store, runs, dropped) {
+					// TaskManager received stop signal during task dispatch, exiting run loop
+					return
 				}
-
-				// Reschedule the job
-				tm.Lock()
-				nextJob.NextExec = nextJob.NextExec.Add(nextJob.Cadence)
-				heap.Fix(&tm.jobQueue, nextJob.index)
-				tm.Unlock()
 				continue
 			}
 			tm.Unlock()
 
-			// Wait until the next job is due or until stopped.
+			// Wait until the next job is due or until stopped, reading the clock again at
+			// intervals to act on jumps of the wall clock
 			select {
-			case <-time.After(delay):
+			case <-tm.clock.After(min(delay, clockCheckInterval)):
 				// Time to execute the next job
 				continue
 			case <-tm.newJobChan:
@@ -379,6 +1546,381 @@ func (tm *TaskManager) run() {
 	}
 }
 
+// wakeRunLoop signals the run loop to check the head of the queue again, e.g. as a job has been
+// scheduled ahead of the job it waits for. The signal is buffered, so a run loop which is not yet
+// waiting returns from its next wait immediately.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) wakeRunLoop() {
+	if tm.ctx.Err() != nil {
+		// The channel is closed once the run loop has exited
+		return
+	}
+	select {
+	case tm.newJobChan <- true:
+		tm.logger.Debug("Signaled run loop")
+	default:
+		// A signal is already pending
+	}
+}
+
+// dispatchDueRuns dispatches the runs taken from the queue, and handles the jobs removed from it
+// without being executed. Returns false if the TaskManager was stopped before all runs were
+// dispatched.
+// Note: must not be called while holding the mutex lock, as dispatching and store I/O may block.
+func (tm *TaskManager) dispatchDueRuns(store JobStore, runs []dueRun, dropped []droppedJob) bool {
+	for _, drop := range dropped {
+		if store != nil && drop.unpersist {
+			tm.unpersistJob(store, drop.jobID)
+		}
+		tm.hooks.jobWasRemoved(drop.jobID)
+	}
+	for _, run := range runs {
+		if !tm.dispatchRun(run.job, run.queue, run.tasks, run.spill) {
+			return false
+		}
+
+		// Update the job store without holding the lock, as it may perform I/O
+		if store != nil && run.removed {
+			tm.unpersistJob(store, run.job.ID)
+		} else if store != nil && run.record != nil {
+			tm.persistJob(store, *run.record)
+		}
+		if run.removed {
+			tm.hooks.jobWasRemoved(run.job.ID)
+		}
+	}
+	return true
+}
+
+// dispatchBatchSize is the maximum number of due jobs taken from the queue in one critical section
+// of the run loop, bounding how long the lock is held during bursts of due jobs.
+const dispatchBatchSize = 64
+
+// dueRun is a run of a due job, taken from the queue by the run loop to be dispatched without
+// holding the lock. The job has already been rescheduled, or removed after its final run.
+type dueRun struct {
+	job     *Job
+	queue   *taskQueue
+	tasks   []jobTask
+	removed bool       // True if the job was removed from the queue, as this is its final run
+	spill   bool       // True if tasks not fitting in the task queue are spilled to the overflow queue
+	record  *JobRecord // The job's rescheduled record, to be persisted if there is a job store
+}
+
+// droppedJob is a due job removed from the queue without being executed.
+type droppedJob struct {
+	jobID     string
+	unpersist bool // True if the job's record is to be deleted from the job store
+}
+
+// takeDueRuns takes up to dispatchBatchSize jobs due at now from the queue, starting a run of each
+// job to be dispatched, unless the job is skipped, delayed or deferred by its policies. Jobs with
+// runs are rescheduled before their tasks are dispatched, so that the queue is consistent while
+// the lock is released. Returns the started runs, and the jobs removed without being executed.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) takeDueRuns(now time.Time) ([]dueRun, []droppedJob) {
+	var runs []dueRun
+	var dropped []droppedJob
+	tm.batchTasks = 0
+	for range dispatchBatchSize {
+		if tm.jobQueue.Len() == 0 {
+			break
+		}
+		nextJob := tm.jobQueue.jobs[0]
+		if nextJob.blocked() || nextJob.NextExec.After(now) || tm.drainedJob(nextJob) {
+			break
+		}
+
+		run, drop, ok := tm.takeDueRun(nextJob, now)
+		if !ok {
+			break
+		}
+		if drop != nil {
+			dropped = append(dropped, *drop)
+		}
+		if run != nil {
+			runs = append(runs, *run)
+		}
+	}
+
+	// Dispatch the runs of the jobs of the highest priority first
+	slices.SortStableFunc(runs, func(a, b dueRun) int {
+		return cmp.Compare(b.job.Priority, a.job.Priority)
+	})
+	return runs, dropped
+}
+
+// takeDueRun starts a run of a job due at now, unless the job is skipped, delayed or deferred by
+// its policies, in which case it is rescheduled. Returns the started run, if any, and the job if
+// it was removed without being executed. Returns false if the job could not be removed from the
+// queue, and is left due.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) takeDueRun(job *Job, now time.Time) (*dueRun, *droppedJob, bool) {
+	// Remove the job without executing it if its deadline has passed, e.g. while paused
+	if job.expired(now) {
+		tm.logger.Debug("Removing job, deadline passed", "jobID", job.ID, "until", job.Until)
+		if err := tm.removeJob(job); err != nil {
+			tm.logger.Warn("Failed to remove expired job", "jobID", job.ID, "error", err)
+			return nil, nil, false
+		}
+		tm.releaseRunWaiters(job.ID)
+		job.cancel()
+		return nil, &droppedJob{jobID: job.ID, unpersist: true}, true
+	}
+
+	// Skip the execution if another instance holds the distributed lock
+	if tm.lock != nil && !tm.leader.Load() {
+		tm.logger.Debug("Skipping run of job, distributed lock not held", "jobID", job.ID)
+		if job.once {
+			// The lock holder executes one-shot jobs
+			if err := tm.removeJob(job); err != nil {
+				tm.logger.Warn("Failed to remove one-shot job", "jobID", job.ID, "error", err)
+				return nil, nil, false
+			}
+			job.cancel()
+			return nil, &droppedJob{jobID: job.ID}, true
+		}
+		job.reschedule(now)
+		heap.Fix(&tm.jobQueue, job.index)
+		return nil, nil, true
+	}
+
+	// Defer or skip the run if it is due during a blackout window or in maintenance mode
+	if end, policy, ok := tm.blackout(job, now); ok {
+		if policy == BlackoutSkip {
+			tm.logger.Debug("Skipping run of job, blacked out", "jobID", job.ID, "maintenance", tm.maintenance)
+			return tm.skipRun(job, now)
+		}
+		tm.logger.Debug("Deferring run of job, blacked out", "jobID", job.ID, "maintenance", tm.maintenance, "until", end)
+		if end.IsZero() {
+			job.deferred = true
+		} else {
+			job.NextExec = end
+		}
+		heap.Fix(&tm.jobQueue, job.index)
+		return nil, nil, true
+	}
+
+	// Skip the run if it is due on a date excluded by the job's calendar, recording the skip
+	if job.Calendar != nil && job.Calendar.Excludes(job.NextExec) {
+		tm.logger.Debug("Skipping run of job, date excluded by calendar", "jobID", job.ID, "due", job.NextExec)
+		job.state.stats.recordHistory(RunRecord{Started: now, Finished: now, Skipped: true}, int(tm.historySize.Load()))
+		return tm.skipRun(job, now)
+	}
+
+	// Apply the job's overlap policy if it has reached its limit of concurrent runs
+	if limit := job.maxConcurrentRuns(); limit > 0 && job.state.running >= limit {
+		if job.OverlapPolicy == OverlapSkip {
+			tm.logger.Debug("Skipping run of job, previous runs still executing", "jobID", job.ID, "running", job.state.running)
+			job.reschedule(now)
+		} else {
+			tm.logger.Debug("Delaying run of job, previous runs still executing", "jobID", job.ID, "running", job.state.running)
+			job.delayed = true
+		}
+		heap.Fix(&tm.jobQueue, job.index)
+		return nil, nil, true
+	}
+
+	// Coalesce the run with a queued or executing run of the same logical work, e.g. of the job an
+	// identical job was scheduled in place of during a reconfiguration
+	if key := job.IdempotencyKey; key != "" && tm.inflightKeys[key] > 0 {
+		tm.logger.Debug("Coalescing run of job, run of the same idempotency key in flight", "jobID", job.ID, "key", key)
+		tm.runsCoalesced.Add(1)
+		return tm.skipRun(job, now)
+	}
+
+	// Skip the run if it is late, and the job's misfire policy is to skip to its next execution
+	if job.MisfirePolicy == MisfireSkipToNext && job.misfired(now) {
+		tm.logger.Debug("Skipping late run of job, following executions missed", "jobID", job.ID, "scheduled", job.scheduled)
+		job.reschedule(now)
+		heap.Fix(&tm.jobQueue, job.index)
+		return nil, nil, true
+	}
+
+	// Skip the run if it is stale, being due longer ago than the job's max delay
+	if job.MaxDelay > 0 && now.Sub(job.NextExec) > job.MaxDelay {
+		tm.logger.Debug("Skipping stale run of job, max delay exceeded", "jobID", job.ID,
+			"lateness", now.Sub(job.NextExec), "maxDelay", job.MaxDelay)
+		return tm.skipRun(job, now)
+	}
+
+	// Defer the run if it would exceed the dispatch rate limits
+	if wait := tm.dispatchDelay(job, now); wait > 0 {
+		tm.logger.Debug("Deferring run of job, dispatch rate limit reached", "jobID", job.ID, "wait", wait)
+		job.NextExec = now.Add(wait)
+		heap.Fix(&tm.jobQueue, job.index)
+		return nil, nil, true
+	}
+
+	// Apply the saturation policy if the run's tasks do not fit in the full task queue
+	width := len(job.Tasks)
+	if job.ExecutionMode != ExecutionParallel {
+		width = 1
+	}
+	saturated := job.pooled() && tm.saturated(width)
+	if saturated && tm.saturationPolicy == SaturationShed && job.Priority < tm.shedBelow {
+		tm.logger.Debug("Shedding run of job, task queue full", "jobID", job.ID, "priority", job.Priority)
+		tm.runsShed.Add(1)
+		return tm.skipRun(job, now)
+	}
+
+	// The job's final run removes the job when dispatched, as a one-shot job's only run does
+	job.runs++
+	final := job.once || job.finalRun(now)
+
+	// Record how late the run is dispatched
+	lateness := now.Sub(job.NextExec)
+	job.state.stats.recordDispatch(lateness)
+	tm.metrics.lateness.record(lateness, managerLatenessSamples)
+
+	tm.logger.Debug("Dispatching job", "jobID", job.ID)
+	run := dueRun{job: job, tasks: tm.startRun(job, now), queue: tm.taskQueueOf(job)}
+	run.spill = saturated && tm.saturationPolicy == SaturationSpill
+	if job.pooled() {
+		tm.batchTasks += len(run.tasks)
+	}
+	if final {
+		// Jobs are removed on their final run, their context is cancelled once the run has finished
+		if err := tm.removeJob(job); err != nil {
+			tm.logger.Warn("Failed to remove job on its final run", "jobID", job.ID, "error", err)
+		} else {
+			run.removed = true
+			run.tasks[0].run.final = true
+		}
+	} else {
+		job.reschedule(now)
+		heap.Fix(&tm.jobQueue, job.index)
+		if tm.store != nil {
+			record, err := job.record()
+			if err != nil {
+				tm.logger.Warn("Failed to serialize tasks of job", "jobID", job.ID, "error", err)
+			}
+			run.record = &record
+		}
+	}
+	return &run, nil, true
+}
+
+// skipRun skips a due run of the job, emitting an EventRunSkipped event, and reschedules the job,
+// or removes it if it is a one-shot job. Returns the job if it was removed, and false if it could
+// not be removed from the queue.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) skipRun(job *Job, now time.Time) (*dueRun, *droppedJob, bool) {
+	job.state.stats.recordSkip()
+	tm.emitEvent(Event{Type: EventRunSkipped, JobID: job.ID, Metadata: job.Metadata})
+	if job.once {
+		// One-shot jobs have no later run to skip to, and are removed
+		if err := tm.removeJob(job); err != nil {
+			tm.logger.Warn("Failed to remove one-shot job", "jobID", job.ID, "error", err)
+			return nil, nil, false
+		}
+		tm.releaseRunWaiters(job.ID)
+		job.cancel()
+		return nil, &droppedJob{jobID: job.ID, unpersist: true}, true
+	}
+	job.reschedule(now)
+	heap.Fix(&tm.jobQueue, job.index)
+	return nil, nil, true
+}
+
+// startRun starts a run of the job, returning its tasks ready to be dispatched to the worker pool.
+// For sequential runs, only the first task is returned.
+// Note: does not acquire a mutex lock for accessing the job, that is up to the caller.
+func (tm *TaskManager) startRun(job *Job, now time.Time) []jobTask {
+	retryPolicy := job.RetryPolicy
+	if retryPolicy == nil {
+		retryPolicy = tm.retryPolicy
+	}
+	run := newJobRun(tm, job, now)
+	job.state.running++
+	tm.activeRuns++
+	if job.IdempotencyKey != "" {
+		tm.inflightKeys[job.IdempotencyKey]++
+	}
+	ctx := job.ctx
+	if tm.tracer != nil {
+		ctx, run.span = tm.startRunSpan(job)
+	}
+	ctx = withJobMetadata(ctx, job.Metadata)
+	run.ctx = ctx
+
+	// Tasks parked on the quota of the job's concurrency group are sent to the job's queue again
+	quota := tm.quotas[job.ConcurrencyGroup]
+	if quota != nil {
+		run.queue = tm.taskQueueOf(job)
+	}
+
+	tasks := make([]jobTask, len(job.Tasks))
+	for i, task := range job.Tasks {
+		tasks[i] = jobTask{
+			task:         task,
+			index:        i,
+			ctx:          ctx,
+			retryPolicy:  retryPolicy,
+			run:          run,
+			executor:     tm.executor,
+			logger:       tm.logger,
+			panicHandler: tm.panicHandler,
+			discard:      tm.discardRemoved,
+			measureCPU:   tm.measureCPU,
+			chaos:        tm.chaos.Load(),
+			quota:        quota,
+		}
+		if job.pooled() {
+			tasks[i].priority = job.Priority
+			tasks[i].preemptions = &tm.preemptions
+		}
+	}
+
+	// Sequential runs start with their first task, the rest are dispatched as tasks finish. Remote
+	// runs are executed by the remote worker as a whole.
+	if job.ExecutionMode != ExecutionParallel && len(tasks) > 1 && !job.Remote {
+		run.pending = tasks[1:]
+		run.queue = tm.taskQueueOf(job)
+		return tasks[:1]
+	}
+	return tasks
+}
+
+// dispatchRun sends the tasks of a started run to the worker pool for execution. If spill is set,
+// tasks which do not fit in the task queue, or would be queued ahead of spilled tasks, are spilled
+// to the overflow queue instead. Returns false if the TaskManager was stopped before all tasks were
+// dispatched.
+// Note: must not be called while holding the mutex lock, as sending tasks may block.
+func (tm *TaskManager) dispatchRun(job *Job, queue *taskQueue, tasks []jobTask, spill bool) bool {
+	// Scale the worker pool back up if it was scaled down while idle
+	wasIdle := tm.idle()
+	tm.lastDispatch.Store(tm.now().UnixNano())
+	if wasIdle {
+		tm.RLock()
+		tm.scaleWorkerPool(0)
+		tm.RUnlock()
+	}
+
+	tm.hooks.jobStarted(job.ID)
+	tm.emitEvent(Event{Type: EventJobDispatched, JobID: job.ID, Metadata: job.Metadata})
+
+	if job.Remote {
+		tm.publishRun(job, tasks)
+		return true
+	}
+	for i, task := range tasks {
+		if spill && (tm.overflow.len() > 0 || !queue.trySend(task)) {
+			tm.overflow.push(job.Priority, tasks[i:])
+			return true
+		}
+		tm.chaos.Load().delay(tm.ctx.Done())
+		if reserved := tm.reservedQueue(job); reserved != nil && reserved.trySend(task.unpreemptible()) {
+			continue
+		}
+		tm.preemptFor(job, queue)
+		if !queue.send(tm.ctx.Done(), task) {
+			return false
+		}
+	}
+	return true
+}
+
 // periodicWorkerScaling scales the worker pool at regular intervals, based on the state of the
 // job queue. The worker pool is already scaled every time a job is added or removed, but this
 // function provides a way to scale the worker pool over time.
@@ -390,7 +1932,9 @@ func (tm *TaskManager) periodicWorkerScaling() {
 		select {
 		case <-ticker.C:
 			// Scale the worker pool based, setting 0 workers needed immediately
+			tm.RLock()
 			tm.scaleWorkerPool(0)
+			tm.RUnlock()
 		case <-tm.ctx.Done():
 			// TaskManager received stop signal, exiting periodic scaling
 			return
@@ -399,12 +1943,14 @@ func (tm *TaskManager) periodicWorkerScaling() {
 }
 
 // scaleWorkerPool scales the worker pool based on the current job queue.
-// The worker pool is scaled based on the highest of three metrics:
+// The worker pool is scaled based on the highest of four metrics:
 // - The widest job in the queue in terms of number of tasks
 // - The average execution time and concurrency of tasks
 // - The number of tasks in the latest job related to available workers at the moment
+// - The number of busy workers plus the headroom of spare idle workers, see SetWorkerHeadroom
+// Note: does not acquire a mutex lock, that is up to the caller.
 func (tm *TaskManager) scaleWorkerPool(workersNeededNow int) {
-	logger.Debug().Msgf("Scaling workers, available/running: %d/%d", tm.workerPool.availableWorkers(), tm.workerPool.runningWorkers())
+	tm.logger.Debug("Scaling workers", "available", tm.workerPool.availableWorkers(), "running", tm.workerPool.runningWorkers())
 	bufferFactor50 := 1.5
 	bufferFactor100 := 2.0
 
@@ -429,53 +1975,187 @@ func (tm *TaskManager) scaleWorkerPool(workersNeededNow int) {
 		workersNeededImmediately = int32(math.Ceil(float64(tm.workerPool.runningWorkers()+extraWorkersNeeded) * bufferFactor50))
 	}
 
-	// Use the highest of the three metrics
-	workersNeeded := max(workersNeededParallelTasks, workersNeededConcurrently, workersNeededImmediately)
+	// Keep the headroom of spare workers beyond the busy workers, if set
+	workersNeededHeadroom := tm.workerPool.activeWorkers() + tm.headroom.Load()
+
+	// Use the highest of the four metrics
+	workersNeeded := max(workersNeededParallelTasks, workersNeededConcurrently, workersNeededImmediately, workersNeededHeadroom)
+	// Scale down to the minimum number of workers while the pool is idle
+	if workersNeededNow == 0 && tm.idle() {
+		workersNeeded = 0
+	}
+	// Ensure the worker pool stays within the resource budget, if set
+	workersNeeded = tm.budgetWorkers(workersNeeded)
 	// Ensure the worker pool has at least the minimum number of workers
-	workersNeeded = max(workersNeeded, int32(tm.minWorkerCount))
+	workersNeeded = max(workersNeeded, tm.minWorkerCount.Load())
 	// Ensure the worker pool has at most the maximum number of workers
-	workersNeeded = min(workersNeeded, int32(maxWorkerCount))
+	workersNeeded = min(workersNeeded, tm.maxWorkers.Load())
 
 	// Adjust the worker pool size
+	if workersNeeded != tm.workerPool.targetWorkerCount() {
+		tm.emitEvent(Event{Type: EventWorkerScaled, Workers: int(workersNeeded)})
+	}
 	tm.workerPool.enqueueWorkerScaling(workersNeeded)
-	logger.Debug().Msgf("Scaling workers, request: %d", workersNeeded)
+	tm.logger.Debug("Scaling workers", "requested", workersNeeded)
 }
 
-// validateJob validates a Job.
+// validateJob validates a Job about to be scheduled.
 // Note: does not acquire a mutex lock for accessing the jobQueue, that is up to the caller.
 func (tm *TaskManager) validateJob(job Job) error {
-	// Jobs with cadence <= 0 are invalid, as such jobs would execute immediately and continuously
-	// and risk overwhelming the worker pool.
-	if job.Cadence <= 0 {
-		return errors.New("invalid cadence, must be greater than 0")
+	if err := tm.validateJobConfig(job); err != nil {
+		return err
+	}
+	// Job ID:s are unique, so duplicates are invalid.
+	if _, ok := tm.jobQueue.JobInQueue(job.ID); ok == nil {
+		return ErrDuplicateJobID
+	}
+	if _, ok := tm.deadLetters[job.ID]; ok {
+		return fmt.Errorf("%w, job is dead-lettered", ErrDuplicateJobID)
+	}
+	return nil
+}
+
+// validateJobConfig validates the configuration of a Job, regardless of the jobs in the queue
+// sharing its ID, as for a job replacing a scheduled one.
+// Note: does not acquire a mutex lock for accessing the jobQueue, that is up to the caller.
+func (tm *TaskManager) validateJobConfig(job Job) error {
+	// Jobs with invalid IDs are invalid, as they could not be identified reliably.
+	if err := validateJobID(job.ID); err != nil {
+		return err
 	}
 	// Jobs with no tasks are invalid, as they would not do anything.
 	if len(job.Tasks) == 0 {
 		return errors.New("job has no tasks")
 	}
-	// Jobs with a NextExec time more than one Cadence old are invalid, as they would re-execute continually.
-	if job.NextExec.Before(time.Now().Add(-job.Cadence)) {
-		return errors.New("job NextExec is too early")
+	// Jobs with duplicate task IDs are invalid, as their tasks could not be told apart.
+	if err := validateTaskIDs(job.Tasks); err != nil {
+		return err
 	}
-	// Job ID:s are unique, so duplicates are invalid.
-	if _, ok := tm.jobQueue.JobInQueue(job.ID); ok == nil {
-		return errors.New("duplicate job ID")
+	// One-shot jobs are never rescheduled and dependent jobs are executed by their dependencies, so
+	// the cadence and NextExec constraints below, which exist to prevent continuous re-execution, do
+	// not apply to them.
+	if job.once || len(job.DependsOn) > 0 {
+		if job.Cadence < 0 {
+			return fmt.Errorf("%w, must not be negative", ErrInvalidCadence)
+		}
+	} else {
+		// Jobs with cadence <= 0 are invalid, as such jobs would execute immediately and continuously
+		// and risk overwhelming the worker pool.
+		if job.Cadence <= 0 {
+			return fmt.Errorf("%w, must be greater than 0", ErrInvalidCadence)
+		}
+		// Jobs with a NextExec time more than one Cadence old are invalid, as they would re-execute continually.
+		if job.NextExec.Before(tm.now().Add(-job.Cadence)) {
+			return errors.New("job NextExec is too early")
+		}
 	}
-	return nil
+	// Jobs aligned to their cadence must be executed at their cadence.
+	if job.Align && (job.Schedule != nil || job.cron != nil || job.once || len(job.DependsOn) > 0) {
+		return errors.New("alignment requires a job executing at its cadence")
+	}
+	// Jobs with a schedule which never executes are invalid.
+	if job.Schedule != nil && job.NextExec.IsZero() {
+		return errors.New("schedule never matches")
+	}
+	// Jobs with a negative max runs, or a deadline before their first execution, are invalid.
+	if job.MaxRuns < 0 {
+		return errors.New("invalid max runs, must not be negative")
+	}
+	if !job.Until.IsZero() && len(job.DependsOn) == 0 && job.NextExec.After(job.Until) {
+		return errors.New("job NextExec is after its Until")
+	}
+	// Jobs with empty tags are invalid, as they could not be operated on by tag.
+	if slices.Contains(job.Tags, "") {
+		return errors.New("invalid tag, must not be empty")
+	}
+	if _, ok := job.Metadata[""]; ok {
+		return errors.New("invalid metadata key, must not be empty")
+	}
+	// Jobs with an unknown overlap policy or a negative concurrency limit are invalid.
+	if job.OverlapPolicy < OverlapAllow || job.OverlapPolicy > OverlapDelay {
+		return errors.New("invalid overlap policy")
+	}
+	if job.MaxConcurrent < 0 {
+		return errors.New("invalid max concurrent runs, must not be negative")
+	}
+	// Jobs with an unknown misfire policy are invalid.
+	if job.MisfirePolicy < MisfireRunOnceNow || job.MisfirePolicy > MisfireSkipToNext {
+		return errors.New("invalid misfire policy")
+	}
+	// Jobs with a negative max delay or failure backoff are invalid.
+	if job.MaxDelay < 0 {
+		return errors.New("invalid max delay, must not be negative")
+	}
+	if job.FailureBackoff < 0 {
+		return errors.New("invalid failure backoff, must not be negative")
+	}
+	if job.IdleTTL < 0 {
+		return errors.New("invalid idle TTL, must not be negative")
+	}
+	for _, blackout := range job.Blackouts {
+		if err := blackout.validate(); err != nil {
+			return err
+		}
+	}
+	// Jobs with an unknown panic policy or a negative panic threshold are invalid.
+	if job.PanicPolicy < PanicKeepRunning || job.PanicPolicy > PanicQuarantine {
+		return errors.New("invalid panic policy")
+	}
+	if job.PanicThreshold < 0 {
+		return errors.New("invalid panic threshold, must not be negative")
+	}
+	// Jobs with an unknown execution mode are invalid.
+	if job.ExecutionMode < ExecutionParallel || job.ExecutionMode > ExecutionStopOnError {
+		return errors.New("invalid execution mode")
+	}
+	// Jobs with a jitter above half the cadence are invalid, as consecutive executions could swap order.
+	if job.Jitter < 0 || job.Jitter > 0.5 {
+		return errors.New("invalid jitter, must be between 0 and 0.5")
+	}
+	// Jobs with a negative dispatch rate are invalid.
+	if job.MaxDispatchRate < 0 {
+		return errors.New("invalid dispatch rate, must not be negative")
+	}
+	// Jobs with a negative dead letter threshold are invalid.
+	if job.DeadLetterThreshold < 0 {
+		return errors.New("invalid dead letter threshold, must not be negative")
+	}
+	// Jobs with an invalid retry policy are invalid.
+	if job.RetryPolicy != nil {
+		if err := job.RetryPolicy.validate(); err != nil {
+			return err
+		}
+	}
+	// Jobs depending on themselves are invalid, as they would never execute.
+	if err := tm.validateDependencies(job); err != nil {
+		return err
+	}
+	// Jobs assigned to an unknown worker group are invalid.
+	if err := tm.validateGroup(job); err != nil {
+		return err
+	}
+	// Dedicated jobs assigned to a worker group are invalid, as they have workers of their own.
+	if job.Dedicated && job.Group != "" {
+		return errors.New("dedicated jobs cannot be assigned to a worker group")
+	}
+	// Remote jobs require a dispatcher, and tasks the remote workers can deserialize.
+	return tm.validateRemote(job)
 }
 
 // newTaskManager creates, initializes, and starts a new TaskManager.
 func newTaskManager(
-	taskChan chan Task,
+	taskQueue *taskQueue,
 	errorChan chan error,
 	execTimeChan chan time.Duration,
 	minWorkerCount int,
 	scaleInterval time.Duration,
 	workerPoolDone chan struct{},
+	logger Logger,
+	clock Clock,
 ) *TaskManager {
 	// Input validation
-	if taskChan == nil {
-		panic("taskChan cannot be nil")
+	if taskQueue == nil {
+		panic("taskQueue cannot be nil")
 	}
 	if errorChan == nil {
 		panic("errorChan cannot be nil")
@@ -489,58 +2169,238 @@ func newTaskManager(
 	if workerPoolDone == nil {
 		panic("workerPoolDone cannot be nil")
 	}
+	if logger == nil {
+		panic("logger cannot be nil")
+	}
+	if clock == nil {
+		panic("clock cannot be nil")
+	}
 
 	// Create and start the manager metrics
 	metrics := &managerMetrics{
 		done: workerPoolDone,
 	}
 
-	// Create the worker pool
-
 	ctx, cancel := context.WithCancel(context.Background())
 	tm := &TaskManager{
 		ctx:            ctx,
 		cancel:         cancel,
+		logger:         logger,
+		clock:          clock,
 		metrics:        metrics,
-		jobQueue:       make(priorityQueue, 0),
+		jobQueue:       priorityQueue{byID: make(map[string]*Job)},
 		newJobChan:     make(chan bool, 2),
 		errorChan:      errorChan,
+		errorFan:       newErrorFanOut(errorChan),
 		runDone:        make(chan struct{}),
-		taskChan:       taskChan,
+		hooks:          &hooks{},
+		events:         &stream[Event]{},
+		results:        &stream[Result]{done: ctx.Done()},
+		runWaiters:     make(map[string][]chan JobResult),
+		overflow:       newOverflowQueue(),
+		dedicated:      make(map[*jobState]*dedicatedRunner),
+		idGenerator:    defaultIDGenerator,
+		deadLetters:    make(map[string]*Job),
+		quotas:         make(map[string]*concurrencyQuota),
+		inflightKeys:   make(map[string]int),
+		taskTypes:      map[string]TaskFactory{HTTPTaskType: JSONTaskFactory[HTTPTask]()},
+		jobTemplates:   make(map[string]JobTemplate),
+		groups:         make(map[string]*workerGroup),
+		taskQueue:      taskQueue,
 		workerPoolDone: workerPoolDone,
-		minWorkerCount: minWorkerCount,
 		scaleInterval:  scaleInterval,
 	}
-	tm.workerPool = newWorkerPool(minWorkerCount, errorChan, execTimeChan, taskChan, workerPoolDone)
+	tm.queueSpace = sync.NewCond(tm)
+	tm.sampleResources = readResourceUsage
+	tm.expiryWake = make(chan struct{}, 1)
+	tm.minWorkerCount.Store(int32(minWorkerCount))
+	tm.maxWorkers.Store(maxWorkerCount)
+	tm.lastDispatch.Store(tm.now().UnixNano())
+	tm.workerPool = newWorkerPool(minWorkerCount, tm.errorFan.in, execTimeChan, taskQueue, workerPoolDone, logger)
 
 	heap.Init(&tm.jobQueue)
 
 	go metrics.consumeExecTime(execTimeChan)
 	go tm.run()
 	go tm.periodicWorkerScaling()
+	tm.dispatches.Add(1)
+	go tm.drainOverflow()
+	tm.dispatches.Add(1)
+	go tm.expireIdleJobs()
 
 	return tm
 }
 
-// New creates, starts and returns a new TaskManager with default values.
-func New() *TaskManager {
-	taskChan := make(chan Task, defaultBufferedSize)
-	errorChan := make(chan error, defaultBufferedSize)
-	execTimeChan := make(chan time.Duration, defaultBufferedSize)
-	initialWorkerCount := runtime.NumCPU()
-	autoScaleInterval := defaultScaleInterval
+// New creates, starts and returns a new TaskManager, configured by the options. Without options,
+// default values are used.
+// Note: panics if an option has an invalid value, e.g. a worker count <= 0.
+func New(opts ...Option) *TaskManager {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.taskBufferSize < 0 || o.errorBufferSize < 0 {
+		panic("buffer sizes cannot be negative")
+	}
+	if o.scaleInterval <= 0 {
+		panic("scaleInterval must be greater than 0")
+	}
+	if o.taskShards <= 0 {
+		panic("taskShards must be greater than 0")
+	}
+
+	taskQueue := newTaskQueue(o.taskShards, o.taskBufferSize)
+	errorChan := make(chan error, o.errorBufferSize)
+	execTimeChan := make(chan time.Duration, o.taskBufferSize)
 	workerPoolDone := make(chan struct{})
 
-	return newTaskManager(taskChan, errorChan, execTimeChan, initialWorkerCount, autoScaleInterval, workerPoolDone)
+	tm := newTaskManager(taskQueue, errorChan, execTimeChan, o.workerCount, o.scaleInterval, workerPoolDone, o.logger, o.clock)
+	if o.maxWorkers != maxWorkerCount {
+		if err := tm.SetWorkerBounds(o.workerCount, o.maxWorkers); err != nil {
+			tm.Stop()
+			panic(err.Error())
+		}
+	}
+	if err := tm.SetIdleScaleDown(o.idleScaleDown); err != nil {
+		tm.Stop()
+		panic(err.Error())
+	}
+	if o.workerHeadroom != 0 {
+		if err := tm.SetWorkerHeadroom(o.workerHeadroom); err != nil {
+			tm.Stop()
+			panic(err.Error())
+		}
+	}
+	if err := tm.SetScaleDownPolicy(o.downScaleThreshold, o.downScaleInterval); err != nil {
+		tm.Stop()
+		panic(err.Error())
+	}
+	if o.retryPolicy != nil {
+		if err := tm.SetRetryPolicy(o.retryPolicy); err != nil {
+			tm.Stop()
+			panic(err.Error())
+		}
+	}
+	for name, workers := range o.workerGroups {
+		if err := tm.SetWorkerGroup(name, workers); err != nil {
+			tm.Stop()
+			panic(err.Error())
+		}
+	}
+	if o.reservedWorkers != 0 {
+		if err := tm.SetWorkerReservation(o.reservedWorkers, o.reservedPriority); err != nil {
+			tm.Stop()
+			panic(err.Error())
+		}
+	}
+	if err := tm.SetMaxJobs(o.maxJobs, o.overflowPolicy); err != nil {
+		tm.Stop()
+		panic(err.Error())
+	}
+	if err := tm.SetSaturationPolicy(o.saturationPolicy, o.shedBelow); err != nil {
+		tm.Stop()
+		panic(err.Error())
+	}
+	if err := tm.SetMaxDispatchRate(o.dispatchRate, o.dispatchBurst); err != nil {
+		tm.Stop()
+		panic(err.Error())
+	}
+	if err := tm.SetDeadLetterThreshold(o.deadLetterThreshold); err != nil {
+		tm.Stop()
+		panic(err.Error())
+	}
+	if err := tm.SetOverrunDetection(o.overrunRuns); err != nil {
+		tm.Stop()
+		panic(err.Error())
+	}
+	if err := tm.SetJobHistory(o.historySize, o.persistHistory); err != nil {
+		tm.Stop()
+		panic(err.Error())
+	}
+	if err := tm.SetClockJumpPolicy(o.clockJumpPolicy); err != nil {
+		tm.Stop()
+		panic(err.Error())
+	}
+	if err := tm.SetResourceBudget(o.resourceBudget); err != nil {
+		tm.Stop()
+		panic(err.Error())
+	}
+	if err := tm.SetBlackouts(o.blackouts...); err != nil {
+		tm.Stop()
+		panic(err.Error())
+	}
+	if err := tm.SetJobTTL(o.jobTTL); err != nil {
+		tm.Stop()
+		panic(err.Error())
+	}
+	for group, limit := range o.concurrencyLimits {
+		if err := tm.SetConcurrencyLimit(group, limit); err != nil {
+			tm.Stop()
+			panic(err.Error())
+		}
+	}
+	if err := tm.SetResultOverflowPolicy(o.resultOverflow); err != nil {
+		tm.Stop()
+		panic(err.Error())
+	}
+	if err := tm.SetChaos(o.chaos); err != nil {
+		tm.Stop()
+		panic(err.Error())
+	}
+	if len(o.middleware) > 0 {
+		tm.UseTaskMiddleware(o.middleware...)
+	}
+	if o.tracerProvider != nil {
+		tm.SetTracerProvider(o.tracerProvider)
+	}
+	if o.panicHandler != nil {
+		tm.SetPanicHandler(o.panicHandler)
+	}
+	if o.recoverDisabled {
+		tm.SetRecoverDisabled(true)
+	}
+	if o.cpuAccounting {
+		tm.SetCPUAccounting(true)
+	}
+	if o.discardRemoved {
+		tm.SetDiscardRemovedTasks(true)
+	}
+	if o.workerLifecycle != nil {
+		tm.SetWorkerLifecycle(*o.workerLifecycle)
+	}
+	if o.idGenerator != nil {
+		tm.SetIDGenerator(o.idGenerator)
+	}
+	if o.store != nil {
+		tm.SetJobStore(o.store)
+	}
+	if o.eventLog != nil {
+		tm.SetEventLog(o.eventLog)
+	}
+	if o.lock != nil {
+		if err := tm.SetDistributedLock(o.lock, o.lockTTL); err != nil {
+			tm.Stop()
+			panic(err.Error())
+		}
+	}
+	if o.dispatcher != nil {
+		if err := tm.SetDispatcher(o.dispatcher, o.ackTimeout); err != nil {
+			tm.Stop()
+			panic(err.Error())
+		}
+	}
+
+	return tm
 }
 
 // NewCustom creates, starts and returns a new TaskManager using custom values for the task
-// manager parameters.
+// manager parameters. Equivalent to New with the WithWorkers, WithTaskBuffer, WithErrorBuffer
+// and WithScaleInterval options.
 func NewCustom(initialWorkerCount, channelBufferSize int, autoScaleInterval time.Duration) *TaskManager {
-	taskChan := make(chan Task, channelBufferSize)
-	errorChan := make(chan error, channelBufferSize)
-	execTimeChan := make(chan time.Duration, channelBufferSize)
-	workerPoolDone := make(chan struct{})
-
-	return newTaskManager(taskChan, errorChan, execTimeChan, initialWorkerCount, autoScaleInterval, workerPoolDone)
+	return New(
+		WithWorkers(initialWorkerCount),
+		WithTaskBuffer(channelBufferSize),
+		WithErrorBuffer(channelBufferSize),
+		WithScaleInterval(autoScaleInterval),
+	)
 }
@@ -14,17 +14,34 @@ type TaskManagerMetrics struct {
 	QueueMaxJobWidth int // Widest job in the queue in terms of number of tasks
 	QueuedJobs       int // Total number of jobs in the queue
 	QueuedTasks      int // Total number of tasks in the queue
+	JobsOverrunning  int // Number of jobs in the queue detected as overrunning, see SetOverrunDetection
+
+	// Job dispatch
+	DispatchLateness Lateness      // Lateness of the recent scheduled dispatches of all jobs
+	RunsShed         int           // Number of runs shed as the task queue was full, see SetSaturationPolicy
+	RunsCoalesced    int           // Number of runs skipped as a run of the same Job.IdempotencyKey was queued or executing
+	TasksSpilled     int           // Number of tasks spilled as the task queue was full, waiting for space
+	DispatchBlocked  time.Duration // Total time the dispatch of tasks waited for space in full task queues, as workers were busy
 
 	// Task execution
 	TaskAverageExecTime  time.Duration // Average execution time of tasks
 	TasksTotalExecutions int           // Total number of tasks executed
 	TasksPerSecond       float32       // Number of tasks executed per second
+	TaskTotalExecTime    time.Duration // Cumulative wall time of the executions of the tasks of jobs
+	TaskTotalCPUTime     time.Duration // Cumulative CPU time of the executions of the tasks of jobs, see SetCPUAccounting
+
+	// Errors
+	DroppedErrors    int           // Number of errors dropped for the error channel or a subscription, as its buffer was full
+	DroppedResults   int           // Number of results dropped for a subscription, as its buffer was full, see SetResultOverflowPolicy
+	DroppedExecTimes int           // Number of execution times of tasks dropped from the average, as its computation lagged behind
+	ResultsBlocked   time.Duration // Total time the delivery of results waited for full subscriptions, with ResultBlock
 
 	// Worker pool
 	WorkerCountTarget   int     // Target number of workers
 	WorkerScalingEvents int     // Number of worker scaling events since start
 	WorkerUtilization   float32 // Utilization of workers
 	WorkersActive       int     // Number of active workers
+	WorkersDraining     int     // Number of workers stopping, finishing their current task
 	WorkersRunning      int     // Number of running workers
 
 	// TODO: consider adding:
@@ -47,6 +64,9 @@ type managerMetrics struct {
 	tasksInQueue        atomic.Int64     // Total number of tasks in the queue
 	maxJobWidth         atomic.Int32     // Widest job in the queue in terms of number of tasks
 
+	// Job dispatch
+	lateness latenessWindow // Lateness of the recent dispatches, guarded by the TaskManager's lock
+
 	done <-chan struct{}
 }
 
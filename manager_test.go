@@ -8,6 +8,7 @@ import (
 	"os"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -90,10 +91,9 @@ func TestNewTaskManagerCustom(t *testing.T) {
 	// Verify jobQueue is initialized
 	assert.NotNil(t, manager.jobQueue, "Expected job queue to be non-nil")
 
-	// Verify taskChan is initialized and has the correct buffer size
-	assert.NotNil(t, manager.taskChan, "Expected task channel to be non-nil")
-	taskChanBuffer := getChannelBufferSize(manager.taskChan)
-	assert.Equal(t, 1, taskChanBuffer, "Expected task channel to have buffer size 1")
+	// Verify taskQueue is initialized and has the correct buffer size
+	assert.NotNil(t, manager.taskQueue, "Expected task queue to be non-nil")
+	assert.Equal(t, 1, manager.taskQueue.capacity(), "Expected task queue to have buffer size 1")
 
 	// Verify errorChan is initialized and has the correct buffer size
 	assert.NotNil(t, manager.errorChan, "Expected error channel to be non-nil")
@@ -114,7 +114,8 @@ func TestManagerStop(t *testing.T) {
 		testChan <- true
 		return nil
 	}}
-	manager.ScheduleTask(testTask, testTask.cadence)
+	_, err := manager.ScheduleTask(testTask, testTask.cadence)
+	assert.ErrorIs(t, err, ErrManagerStopped, "Expected an error for scheduling on a stopped manager")
 
 	// Since the manager is stopped, the task should not have been added to the job queue
 	if manager.jobsInQueue() != 0 {
@@ -146,7 +147,7 @@ func TestScheduleFunc(t *testing.T) {
 
 	assert.Equal(t, 1, manager.jobsInQueue(), "Expected job queue length to be 1, got %d", manager.jobsInQueue())
 
-	job := manager.jobQueue[0]
+	job := manager.jobQueue.jobs[0]
 	assert.Equal(t, 1, len(job.Tasks), "Expected job to have 1 task, got %d", len(job.Tasks))
 	assert.Equal(t, jobID, job.ID, "Expected job ID to be %s, got %s", jobID, job.ID)
 }
@@ -163,12 +164,171 @@ func TestScheduleTask(t *testing.T) {
 
 	assert.Equal(t, 1, manager.jobsInQueue(), "Expected job queue length to be 1, got %d", manager.jobsInQueue())
 
-	job := manager.jobQueue[0]
+	job := manager.jobQueue.jobs[0]
 	assert.Equal(t, 1, len(job.Tasks), "Expected job to have 1 task, got %d", len(job.Tasks))
 	assert.Equal(t, testTask, job.Tasks[0], "Expected the task in the job to be the test task")
 	assert.Equal(t, jobID, job.ID, "Expected job ID to be %s, got %s", jobID, job.ID)
 }
 
+func TestScheduleFuncContext(t *testing.T) {
+	t.Run("Context cancelled on removal", func(t *testing.T) {
+		manager := NewCustom(2, 2, 1*time.Minute)
+		defer manager.Stop()
+
+		started := make(chan struct{})
+		cancelled := make(chan struct{})
+		job := Job{
+			ID:       "context-removal-job",
+			Cadence:  1 * time.Second,
+			NextExec: time.Now(),
+			Tasks: []Task{SimpleContextTask{function: func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				close(cancelled)
+				return ctx.Err()
+			}}},
+		}
+		err := manager.ScheduleJob(job)
+		assert.NoError(t, err, "Expected no error scheduling job")
+
+		select {
+		case <-started:
+		case <-time.After(20 * time.Millisecond):
+			t.Fatal("Task did not start in expected time")
+		}
+
+		err = manager.RemoveJob(job.ID)
+		assert.NoError(t, err, "Expected no error removing job")
+
+		select {
+		case <-cancelled:
+			// Task context was cancelled as expected
+		case <-time.After(20 * time.Millisecond):
+			t.Fatal("Expected task context to be cancelled when the job is removed")
+		}
+	})
+
+	t.Run("Context cancelled on stop", func(t *testing.T) {
+		manager := NewCustom(2, 2, 1*time.Minute)
+
+		started := make(chan struct{})
+		_, err := manager.ScheduleFuncContext(func(ctx context.Context) error {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			// Block until cancelled, simulating a long-running task
+			<-ctx.Done()
+			return nil
+		}, 10*time.Millisecond)
+		assert.NoError(t, err, "Expected no error scheduling function")
+
+		select {
+		case <-started:
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("Task did not start in expected time")
+		}
+
+		// Stop should not hang on the long-running task
+		stopped := make(chan struct{})
+		go func() {
+			manager.Stop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+			// Manager stopped as expected
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("Expected Stop to return once the task context is cancelled")
+		}
+	})
+
+	t.Run("Context survives replacement", func(t *testing.T) {
+		manager := NewCustom(2, 2, 1*time.Minute)
+		defer manager.Stop()
+
+		job := getMockedJob(1, "context-replace-job", 1*time.Second, 1*time.Second)
+		err := manager.ScheduleJob(job)
+		assert.NoError(t, err)
+		jobCtx := manager.jobQueue.jobs[0].ctx
+
+		err = manager.ReplaceJob(getMockedJob(2, "context-replace-job", 1*time.Second, 1*time.Second))
+		assert.NoError(t, err)
+		assert.Equal(t, jobCtx, manager.jobQueue.jobs[0].ctx, "Expected replaced job to keep its context")
+		assert.NoError(t, jobCtx.Err(), "Expected context to not be cancelled by replacement")
+	})
+}
+
+func TestScheduleOnce(t *testing.T) {
+	manager := NewCustom(2, 2, 1*time.Minute)
+	defer manager.Stop()
+
+	t.Run("Executes once and is removed", func(t *testing.T) {
+		var mu sync.Mutex
+		executions := 0
+		testTask := MockTask{ID: "once-task", executeFunc: func() error {
+			mu.Lock()
+			executions++
+			mu.Unlock()
+			return nil
+		}}
+		start := time.Now()
+		jobID, err := manager.ScheduleOnce(testTask, 10*time.Millisecond)
+		assert.NoError(t, err, "Expected no error scheduling one-shot task")
+		assert.NotEmpty(t, jobID, "Expected a job ID to be returned")
+		assert.Equal(t, 1, manager.jobsInQueue(), "Expected job queue length to be 1")
+
+		// Wait for what would have been several executions of a recurring job
+		time.Sleep(50 * time.Millisecond)
+
+		mu.Lock()
+		assert.Equal(t, 1, executions, "Expected exactly one execution, %v", time.Since(start))
+		mu.Unlock()
+		assert.Equal(t, 0, manager.jobsInQueue(), "Expected one-shot job to be removed after execution")
+		assert.Equal(t, 0, manager.Metrics().QueuedTasks, "Expected no queued tasks after removal")
+	})
+
+	t.Run("Zero delay", func(t *testing.T) {
+		doneChan := make(chan struct{})
+		_, err := manager.ScheduleOnce(MockTask{ID: "instant-once-task", executeFunc: func() error {
+			close(doneChan)
+			return nil
+		}}, 0)
+		assert.NoError(t, err, "Expected no error scheduling one-shot task without delay")
+
+		select {
+		case <-doneChan:
+			// Task executed as expected
+		case <-time.After(10 * time.Millisecond):
+			t.Fatal("Task did not execute immediately")
+		}
+	})
+
+	t.Run("Removed before execution", func(t *testing.T) {
+		testChan := make(chan struct{}, 1)
+		jobID, err := manager.ScheduleOnce(MockTask{ID: "removed-once-task", executeFunc: func() error {
+			testChan <- struct{}{}
+			return nil
+		}}, 20*time.Millisecond)
+		assert.NoError(t, err)
+
+		err = manager.RemoveJob(jobID)
+		assert.NoError(t, err, "Expected no error removing one-shot job")
+
+		select {
+		case <-testChan:
+			t.Fatal("Did not expect removed one-shot task to execute")
+		case <-time.After(40 * time.Millisecond):
+			// No execution, as expected
+		}
+	})
+
+	t.Run("Negative delay", func(t *testing.T) {
+		_, err := manager.ScheduleOnce(MockTask{ID: "negative-once-task"}, -time.Second)
+		assert.Error(t, err, "Expected error scheduling one-shot task with negative delay")
+	})
+}
+
 func TestScheduleTasks(t *testing.T) {
 	manager := NewCustom(10, 2, 1*time.Minute)
 	defer manager.Stop()
@@ -189,7 +349,7 @@ func TestScheduleTasks(t *testing.T) {
 
 	// Assert that the job was added
 	assert.Equal(t, 1, manager.jobsInQueue(), "Expected job queue length to be 1, got %d", manager.jobsInQueue())
-	job := manager.jobQueue[0]
+	job := manager.jobQueue.jobs[0]
 	assert.Equal(t, 2, len(job.Tasks), "Expected job to have 2 tasks, got %d", len(job.Tasks))
 	assert.Equal(t, jobID, job.ID, "Expected job ID to be %s, got %s", jobID, job.ID)
 }
@@ -206,11 +366,100 @@ func TestScheduleJob(t *testing.T) {
 
 	// Assert that the job was added
 	assert.Equal(t, 1, manager.jobsInQueue(), "Expected job queue length to be 1, got %d", manager.jobsInQueue())
-	scheduledJob := manager.jobQueue[0]
+	scheduledJob := manager.jobQueue.jobs[0]
 	assert.Equal(t, len(job.Tasks), len(scheduledJob.Tasks), "Expected job to have 2 tasks, got %d", len(job.Tasks))
 	assert.Equal(t, job.ID, scheduledJob.ID, "Expected job ID to be %s, got %s", scheduledJob.ID, job.ID)
 }
 
+func TestValidateJobWithoutScheduling(t *testing.T) {
+	manager := NewCustom(1, 2, 1*time.Minute)
+	defer manager.Stop()
+
+	job := getMockedJob(1, "valid-job", time.Hour, time.Hour)
+	assert.NoError(t, manager.ValidateJob(job))
+	assert.Equal(t, 0, manager.jobsInQueue(), "Expected the job not to be scheduled")
+
+	invalid := getMockedJob(1, "invalid-job", time.Hour, time.Hour)
+	invalid.Tasks = nil
+	assert.Error(t, manager.ValidateJob(invalid), "Expected error for a job without tasks")
+	invalid = getMockedJob(1, "invalid-job", time.Hour, time.Hour)
+	invalid.Group = "unknown"
+	assert.Error(t, manager.ValidateJob(invalid), "Expected error for an unknown worker group")
+
+	// Defaults are applied as when scheduling
+	aligned := getMockedJob(1, "aligned-job", time.Minute, 0)
+	aligned.NextExec, aligned.Align = time.Time{}, true
+	assert.NoError(t, manager.ValidateJob(aligned))
+
+	assert.NoError(t, manager.ScheduleJob(job))
+	assert.ErrorIs(t, manager.ValidateJob(job), ErrDuplicateJobID)
+}
+
+func TestScheduleJobs(t *testing.T) {
+	t.Run("Schedules all jobs", func(t *testing.T) {
+		manager := NewCustom(1, 4, 1*time.Minute)
+		defer manager.Stop()
+		store := NewMemoryJobStore()
+		manager.SetJobStore(store)
+
+		// Jobs of the batch may depend on each other
+		dependent := getMockedJob(1, "dependent-job", 0, 0)
+		dependent.DependsOn = []string{"batch-job-1"}
+		jobs := []Job{
+			getMockedJob(1, "batch-job-1", time.Hour, time.Hour),
+			getMockedJob(3, "batch-job-2", time.Hour, time.Hour),
+			dependent,
+		}
+		assert.NoError(t, manager.ScheduleJobs(jobs))
+		assert.Equal(t, 3, manager.jobsInQueue())
+		assert.Equal(t, 5, manager.Metrics().QueuedTasks)
+		records, err := store.List()
+		assert.NoError(t, err)
+		assert.Len(t, records, 3, "Expected all jobs to be persisted")
+	})
+
+	t.Run("Schedules no jobs if any is invalid", func(t *testing.T) {
+		manager := NewCustom(1, 4, 1*time.Minute)
+		defer manager.Stop()
+		store := NewMemoryJobStore()
+		manager.SetJobStore(store)
+		assert.NoError(t, manager.ScheduleJob(getMockedJob(1, "existing-job", time.Hour, time.Hour)))
+
+		invalid := getMockedJob(1, "invalid-job", time.Hour, time.Hour)
+		invalid.Tasks = nil
+		err := manager.ScheduleJobs([]Job{getMockedJob(1, "valid-job", time.Hour, time.Hour), invalid})
+		assert.ErrorContains(t, err, "invalid-job", "Expected the error to identify the invalid job")
+
+		err = manager.ScheduleJobs([]Job{getMockedJob(1, "valid-job", time.Hour, time.Hour), getMockedJob(1, "existing-job", time.Hour, time.Hour)})
+		assert.ErrorIs(t, err, ErrDuplicateJobID)
+		err = manager.ScheduleJobs([]Job{getMockedJob(1, "valid-job", time.Hour, time.Hour), getMockedJob(1, "valid-job", time.Hour, time.Hour)})
+		assert.ErrorIs(t, err, ErrDuplicateJobID)
+		assert.ErrorContains(t, err, "duplicate job ID in batch")
+
+		// Jobs of the batch depending on each other in a cycle are invalid
+		first, second := getMockedJob(1, "first-job", 0, 0), getMockedJob(1, "second-job", 0, 0)
+		first.DependsOn, second.DependsOn = []string{"second-job"}, []string{"first-job"}
+		err = manager.ScheduleJobs([]Job{first, second})
+		assert.ErrorContains(t, err, "depends on itself")
+
+		assert.Equal(t, 1, manager.jobsInQueue(), "Expected no job of a failed batch to be scheduled")
+		records, err := store.List()
+		assert.NoError(t, err)
+		assert.Len(t, records, 1, "Expected no job of a failed batch to be persisted")
+	})
+
+	t.Run("Rejects batches not fitting in the queue", func(t *testing.T) {
+		manager := NewCustom(1, 4, 1*time.Minute)
+		defer manager.Stop()
+		assert.NoError(t, manager.SetMaxJobs(2, OverflowDropOldest))
+		assert.NoError(t, manager.ScheduleJob(getMockedJob(1, "existing-job", time.Hour, time.Hour)))
+
+		err := manager.ScheduleJobs([]Job{getMockedJob(1, "job-1", time.Hour, time.Hour), getMockedJob(1, "job-2", time.Hour, time.Hour)})
+		assert.ErrorIs(t, err, ErrQueueFull)
+		assert.Equal(t, 1, manager.jobsInQueue(), "Expected no job to be dropped or scheduled")
+	})
+}
+
 func TestRemoveJob(t *testing.T) {
 	manager := NewCustom(10, 2, 1*time.Minute)
 	defer manager.Stop()
@@ -221,7 +470,7 @@ func TestRemoveJob(t *testing.T) {
 
 	// Assert that the job was added
 	assert.Equal(t, 1, manager.jobsInQueue(), "Expected job queue length to be 1, got %d", manager.jobsInQueue())
-	qJob := manager.jobQueue[0]
+	qJob := manager.jobQueue.jobs[0]
 	assert.Equal(t, job.ID, qJob.ID, "Expected job ID to be %s, got %s", job.ID, qJob.ID)
 	assert.Equal(t, 2, len(qJob.Tasks), "Expected job to have 2 tasks, got %d", len(qJob.Tasks))
 
@@ -237,6 +486,256 @@ func TestRemoveJob(t *testing.T) {
 	assert.Error(t, err, "Expected removal of non-existent job to produce an error")
 }
 
+func TestDiscardRemovedTasks(t *testing.T) {
+	for _, discard := range []bool{false, true} {
+		t.Run(fmt.Sprintf("discard=%t", discard), func(t *testing.T) {
+			manager := New(WithWorkers(1), WithScaleInterval(time.Hour), WithDiscardRemovedTasks(discard))
+			defer manager.Stop()
+			results := make(chan JobResult, 1)
+			manager.OnJobResult(func(result JobResult) { results <- result })
+
+			// The second task is dispatched once the first finishes, after the job is removed
+			started := make(chan struct{})
+			release := make(chan struct{})
+			var executed atomic.Bool
+			job := Job{
+				ID:            "removed-job",
+				Cadence:       time.Hour,
+				NextExec:      time.Now(),
+				ExecutionMode: ExecutionSequential,
+				Tasks: []Task{
+					MockTask{executeFunc: func() error {
+						close(started)
+						<-release
+						return nil
+					}},
+					MockTask{executeFunc: func() error {
+						executed.Store(true)
+						return nil
+					}},
+				},
+			}
+			assert.NoError(t, manager.ScheduleJob(job))
+			select {
+			case <-started:
+			case <-time.After(50 * time.Millisecond):
+				t.Fatal("Expected the first task to start")
+			}
+			assert.NoError(t, manager.RemoveJob(job.ID))
+			close(release)
+
+			select {
+			case result := <-results:
+				assert.Equal(t, discard, result.TaskResults[1].Skipped, "Expected the second task to be skipped only when discarding")
+			case <-time.After(50 * time.Millisecond):
+				t.Fatal("Expected the run to finish")
+			}
+			assert.Equal(t, !discard, executed.Load(), "Expected the second task to execute only when not discarding")
+		})
+	}
+}
+
+func TestJobs(t *testing.T) {
+	manager := NewCustom(1, 1, 1*time.Minute)
+	defer manager.Stop()
+
+	assert.Empty(t, manager.Jobs(), "Expected no jobs in an empty manager")
+
+	// Schedule jobs in an order different from their execution order
+	for i, timeToNextExec := range []time.Duration{30 * time.Second, 10 * time.Second, 20 * time.Second} {
+		job := getMockedJob(i+1, fmt.Sprintf("job-%d", i), 1*time.Minute, timeToNextExec)
+		assert.NoError(t, manager.ScheduleJob(job), "Expected no error scheduling job")
+	}
+
+	t.Run("Jobs", func(t *testing.T) {
+		jobs := manager.Jobs()
+		assert.Len(t, jobs, 3)
+		assert.Equal(t, []string{"job-1", "job-2", "job-0"}, []string{jobs[0].ID, jobs[1].ID, jobs[2].ID}, "Expected jobs ordered by next execution")
+		assert.Equal(t, 2, jobs[0].TaskCount)
+		assert.Equal(t, 1*time.Minute, jobs[0].Cadence)
+		assert.Equal(t, 0, jobs[0].Running)
+	})
+
+	t.Run("Job", func(t *testing.T) {
+		info, err := manager.Job("job-2")
+		assert.NoError(t, err, "Expected no error getting job")
+		assert.Equal(t, "job-2", info.ID)
+		assert.Equal(t, 3, info.TaskCount)
+		assert.WithinDuration(t, time.Now().Add(20*time.Second), info.NextExec, time.Second)
+
+		_, err = manager.Job("unknown-job")
+		assert.Error(t, err, "Expected error getting unknown job")
+	})
+
+	t.Run("QueueSnapshot", func(t *testing.T) {
+		runs := manager.QueueSnapshot(2)
+		assert.Equal(t, []string{"job-1", "job-2"}, []string{runs[0].JobID, runs[1].JobID}, "Expected the next runs ordered by due time")
+		assert.WithinDuration(t, time.Now().Add(10*time.Second), runs[0].NextExec, time.Second)
+		assert.Len(t, manager.QueueSnapshot(0), 3, "Expected the runs of all jobs without a limit")
+
+		// Paused jobs are not dispatched when due, and are left out
+		assert.NoError(t, manager.PauseJob("job-1"))
+		defer func() { assert.NoError(t, manager.ResumeJob("job-1")) }()
+		runs = manager.QueueSnapshot(2)
+		assert.Equal(t, []string{"job-2", "job-0"}, []string{runs[0].JobID, runs[1].JobID}, "Expected the paused job to be left out")
+	})
+}
+
+func TestTriggerJob(t *testing.T) {
+	manager := NewCustom(2, 2, 1*time.Minute)
+	defer manager.Stop()
+
+	t.Run("Executes immediately", func(t *testing.T) {
+		executions := make(chan struct{}, 2)
+		job := getMockedJob(1, "trigger-job", 1*time.Minute, 1*time.Minute)
+		job.Tasks = []Task{MockTask{ID: "trigger-task", executeFunc: func() error {
+			executions <- struct{}{}
+			return nil
+		}}}
+		assert.NoError(t, manager.ScheduleJob(job), "Expected no error scheduling job")
+		before, err := manager.Job(job.ID)
+		assert.NoError(t, err)
+
+		assert.NoError(t, manager.TriggerJob(job.ID), "Expected no error triggering job")
+		select {
+		case <-executions:
+			// Task executed as expected
+		case <-time.After(10 * time.Millisecond):
+			t.Fatal("Task did not execute when triggered")
+		}
+
+		after, err := manager.Job(job.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, before.NextExec, after.NextExec, "Expected the schedule to be unchanged")
+		assert.NoError(t, manager.RemoveJob(job.ID))
+	})
+
+	t.Run("Overlap policy", func(t *testing.T) {
+		release := make(chan struct{})
+		job := getMockedJob(1, "trigger-overlap-job", 1*time.Minute, 1*time.Minute)
+		job.Tasks = []Task{MockTask{ID: "blocking-task", executeFunc: func() error {
+			<-release
+			return nil
+		}}}
+		job.OverlapPolicy = OverlapSkip
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		assert.NoError(t, manager.TriggerJob(job.ID), "Expected no error triggering job")
+		assert.Error(t, manager.TriggerJob(job.ID), "Expected error triggering job with a run executing")
+		close(release)
+		assert.NoError(t, manager.RemoveJob(job.ID))
+	})
+
+	t.Run("One-shot job", func(t *testing.T) {
+		executions := make(chan struct{}, 2)
+		jobID, err := manager.ScheduleOnce(MockTask{ID: "trigger-once-task", executeFunc: func() error {
+			executions <- struct{}{}
+			return nil
+		}}, 20*time.Millisecond)
+		assert.NoError(t, err)
+
+		assert.NoError(t, manager.TriggerJob(jobID), "Expected no error triggering one-shot job")
+		assert.Equal(t, 0, manager.jobsInQueue(), "Expected triggered one-shot job to be removed")
+
+		time.Sleep(40 * time.Millisecond)
+		assert.Len(t, executions, 1, "Expected exactly one execution")
+	})
+
+	t.Run("Unknown job", func(t *testing.T) {
+		assert.Error(t, manager.TriggerJob("unknown-job"), "Expected error triggering unknown job")
+	})
+}
+
+func TestRunJobNow(t *testing.T) {
+	manager := NewCustom(2, 2, 1*time.Minute)
+	defer manager.Stop()
+
+	t.Run("Returns the result of the run", func(t *testing.T) {
+		job := getMockedJob(1, "run-now-job", 1*time.Minute, 1*time.Minute)
+		job.Tasks = []Task{
+			NewResultFuncTask(func(ctx context.Context) (int, error) { return 42, nil }, nil),
+			MockTask{executeFunc: func() error { return errors.New("task failed") }},
+		}
+		assert.NoError(t, manager.ScheduleJob(job))
+		before, err := manager.Job(job.ID)
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		result, err := manager.RunJobNow(ctx, job.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, job.ID, result.JobID)
+		assert.Equal(t, map[string]any{resultValueKey: 42}, result.TaskResults[0].Data)
+		assert.ErrorContains(t, result.TaskResults[1].Err, "task failed")
+
+		after, err := manager.Job(job.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, before.NextExec, after.NextExec, "Expected the schedule to be unchanged")
+		assert.NoError(t, manager.RemoveJob(job.ID))
+	})
+
+	t.Run("Returns when the context is done", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		job := getMockedJob(1, "run-now-blocking-job", 1*time.Minute, 1*time.Minute)
+		job.Tasks = []Task{MockTask{executeFunc: func() error {
+			<-release
+			return nil
+		}}}
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := manager.RunJobNow(ctx, job.ID)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.NoError(t, manager.RemoveJob(job.ID))
+	})
+
+	t.Run("Unknown job", func(t *testing.T) {
+		_, err := manager.RunJobNow(context.Background(), "unknown-job")
+		assert.Error(t, err, "Expected error running unknown job")
+	})
+}
+
+func TestScheduleEarlierJob(t *testing.T) {
+	// The run loop waits up to clockCheckInterval for the head of the queue, so a job due earlier
+	// executing within a fraction of that shows that scheduling it woke the loop
+	earlierJob := func(executed chan struct{}) Job {
+		job := getMockedJob(1, "earlier-job", time.Hour, 10*time.Millisecond)
+		job.Tasks[0] = MockTask{executeFunc: func() error {
+			select {
+			case executed <- struct{}{}:
+			default:
+			}
+			return nil
+		}}
+		return job
+	}
+
+	for name, schedule := range map[string]func(*TaskManager, Job) error{
+		"ScheduleJob": (*TaskManager).ScheduleJob,
+		"ScheduleJobs": func(manager *TaskManager, job Job) error {
+			return manager.ScheduleJobs([]Job{job})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			manager := NewCustom(1, 1, 1*time.Minute)
+			defer manager.Stop()
+
+			assert.NoError(t, manager.ScheduleJob(getMockedJob(1, "later-job", time.Hour, time.Hour)))
+			time.Sleep(20 * time.Millisecond) // Let the run loop wait for the later job
+
+			executed := make(chan struct{}, 1)
+			assert.NoError(t, schedule(manager, earlierJob(executed)))
+			select {
+			case <-executed:
+			case <-time.After(time.Second):
+				t.Fatal("Expected the earlier job to execute when due")
+			}
+		})
+	}
+}
+
 func TestReplaceJob(t *testing.T) {
 	manager := NewCustom(4, 4, 1*time.Minute)
 	defer manager.Stop()
@@ -247,7 +746,7 @@ func TestReplaceJob(t *testing.T) {
 	assert.Nil(t, err, "Error adding job")
 	// Assert job added
 	assert.Equal(t, 1, manager.jobsInQueue(), "Expected job queue length to be 1, got %d", manager.jobsInQueue())
-	qJob := manager.jobQueue[0]
+	qJob := manager.jobQueue.jobs[0]
 	assert.Equal(t, firstJob.ID, qJob.ID, "Expected ID to be '%s', got '%s'", firstJob.ID, qJob.ID)
 
 	// Replace the first job
@@ -256,7 +755,7 @@ func TestReplaceJob(t *testing.T) {
 	assert.Nil(t, err, "Error replacing job")
 	// Assert that the job was replaced in the queue
 	assert.Equal(t, 1, manager.jobsInQueue(), "Expected job queue length to be 1, got %d", manager.jobsInQueue())
-	qJob = manager.jobQueue[0]
+	qJob = manager.jobQueue.jobs[0]
 	// The queue job should retain the index and NextExec time of the first job
 	assert.Equal(t, firstJob.index, qJob.index, "Expected index to be '%s', got '%s'", secondJob.index, qJob.index)
 	assert.Equal(t, firstJob.NextExec, qJob.NextExec, "Expected ID to be '%s', got '%s'", secondJob.NextExec, qJob.NextExec)
@@ -269,6 +768,72 @@ func TestReplaceJob(t *testing.T) {
 	thirdJob := getMockedJob(2, "anotherJobID", 10*time.Millisecond, 100*time.Millisecond)
 	err = manager.ReplaceJob(thirdJob)
 	assert.Error(t, err, "Expected replace attempt of non-existent job to produce an error")
+
+	// Try to replace the job with an invalid job
+	invalidJob := getMockedJob(2, "aJobID", 0, 100*time.Millisecond)
+	err = manager.ReplaceJob(invalidJob)
+	assert.ErrorIs(t, err, ErrInvalidCadence, "Expected replace attempt with an invalid job to produce an error")
+	assert.Equal(t, secondJob.Cadence, manager.jobQueue.jobs[0].Cadence, "Expected the job to be kept")
+
+	// Replace a one-shot job, which stays a one-shot job
+	var executions atomic.Int32
+	onceID, err := manager.ScheduleOnce(MockTask{executeFunc: func() error { return nil }}, 20*time.Millisecond)
+	assert.NoError(t, err)
+	err = manager.ReplaceJob(Job{ID: onceID, Tasks: []Task{MockTask{executeFunc: func() error {
+		executions.Add(1)
+		return nil
+	}}}})
+	assert.NoError(t, err, "Error replacing one-shot job")
+	assert.Eventually(t, func() bool {
+		_, err := manager.Job(onceID)
+		return err != nil
+	}, time.Second, time.Millisecond, "Expected the one-shot job to be removed after executing")
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(1), executions.Load(), "Expected the one-shot job to execute once")
+}
+
+func TestJobJitter(t *testing.T) {
+	manager := NewCustom(1, 1, 1*time.Minute)
+	defer manager.Stop()
+
+	cadence := 1 * time.Minute
+	maxOffset := 6 * time.Second
+	start := time.Now().Add(cadence)
+
+	t.Run("Randomized NextExec", func(t *testing.T) {
+		nextExecs := make(map[time.Time]bool)
+		for i := range 20 {
+			job := getMockedJob(1, fmt.Sprintf("jitter-job-%d", i), cadence, cadence)
+			job.NextExec = start
+			job.Jitter = 0.1
+			assert.NoError(t, manager.ScheduleJob(job), "Expected no error scheduling job")
+
+			info, err := manager.Job(job.ID)
+			assert.NoError(t, err)
+			nextExec := info.NextExec
+			assert.WithinDuration(t, start, nextExec, maxOffset, "Expected NextExec within ±10%% of the cadence")
+			nextExecs[nextExec] = true
+		}
+		assert.Greater(t, len(nextExecs), 1, "Expected jobs with the same schedule to be spread out")
+	})
+
+	t.Run("Rescheduling does not drift", func(t *testing.T) {
+		job := &Job{Cadence: cadence, Jitter: 0.1, NextExec: start, scheduled: start}
+		for i := 1; i <= 100; i++ {
+			job.reschedule(time.Now())
+			expected := start.Add(time.Duration(i) * cadence)
+			assert.WithinDuration(t, expected, job.NextExec, maxOffset, "Expected jitter relative to the unjittered schedule")
+		}
+	})
+
+	t.Run("Invalid jitter", func(t *testing.T) {
+		job := getMockedJob(1, "invalid-jitter-job", cadence, cadence)
+		job.Jitter = 0.6
+		assert.Error(t, manager.ScheduleJob(job), "Expected error for jitter above 0.5")
+
+		job.Jitter = -0.1
+		assert.Error(t, manager.ScheduleJob(job), "Expected error for negative jitter")
+	})
 }
 
 func TestTaskExecution(t *testing.T) {
@@ -544,7 +1109,18 @@ func TestValidateJob(t *testing.T) {
 		Tasks:    []Task{MockTask{ID: "task1"}},
 	}
 	err = manager.validateJob(invalidJobZeroCadence)
-	assert.Error(t, err, "Expected error for job with zero cadence")
+	assert.ErrorIs(t, err, ErrInvalidCadence, "Expected error for job with zero cadence")
+
+	// Test case: invalid one-shot job with negative cadence
+	invalidJobNegativeCadence := Job{
+		ID:       "invalid-job-negative-cadence",
+		Cadence:  -time.Second,
+		NextExec: time.Now().Add(100 * time.Millisecond),
+		Tasks:    []Task{MockTask{ID: "task1"}},
+		once:     true,
+	}
+	err = manager.validateJob(invalidJobNegativeCadence)
+	assert.ErrorIs(t, err, ErrInvalidCadence, "Expected error for one-shot job with negative cadence")
 
 	// Test case: invalid job with no tasks
 	invalidJobNoTasks := Job{
@@ -576,7 +1152,7 @@ func TestValidateJob(t *testing.T) {
 	manager.ScheduleJob(alreadyPresentJob)
 	duplicateJob := alreadyPresentJob
 	err = manager.validateJob(duplicateJob)
-	assert.Error(t, err, "Expected error for duplicate job ID")
+	assert.ErrorIs(t, err, ErrDuplicateJobID, "Expected error for duplicate job ID")
 }
 
 func TestErrorChannelConsumption(t *testing.T) {
@@ -670,6 +1246,55 @@ func TestManagerMetrics(t *testing.T) {
 	})
 }
 
+func TestDispatchBatch(t *testing.T) {
+	t.Run("Takes due jobs in batches", func(t *testing.T) {
+		manager := NewCustom(1, 4, 1*time.Minute)
+		defer manager.Stop()
+
+		for i := range dispatchBatchSize + 10 {
+			assert.NoError(t, manager.ScheduleJob(getMockedJob(1, fmt.Sprintf("job-%d", i), time.Hour, time.Hour)))
+		}
+
+		// All jobs are due an hour from now, the run loop is left waiting for them
+		manager.Lock()
+		now := time.Now().Add(time.Hour + time.Second)
+		runs, dropped := manager.takeDueRuns(now)
+		assert.Len(t, runs, dispatchBatchSize, "Expected a batch of due runs")
+		assert.Empty(t, dropped)
+		for _, run := range runs {
+			assert.True(t, manager.jobQueue.contains(run.job), "Expected job %s to be kept in the queue", run.job.ID)
+			assert.True(t, run.job.NextExec.After(now), "Expected job %s to be rescheduled before dispatch", run.job.ID)
+			assert.Len(t, run.tasks, 1)
+		}
+		runs, _ = manager.takeDueRuns(now)
+		assert.Len(t, runs, 10, "Expected the remaining due runs")
+		manager.Unlock()
+	})
+
+	t.Run("Does not block scheduling while dispatching", func(t *testing.T) {
+		manager := NewCustom(1, 1, 1*time.Minute)
+		defer manager.Stop()
+
+		// The single worker is kept busy, so dispatching the due jobs blocks on the task channel
+		release := make(chan struct{})
+		defer close(release)
+		for i := range 20 {
+			job := getMockedJob(1, fmt.Sprintf("busy-job-%d", i), time.Hour, 0)
+			job.Tasks[0] = MockTask{executeFunc: func() error {
+				<-release
+				return nil
+			}}
+			assert.NoError(t, manager.ScheduleJob(job))
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		start := time.Now()
+		assert.NoError(t, manager.ScheduleJob(getMockedJob(1, "new-job", time.Hour, time.Hour)))
+		assert.Len(t, manager.Jobs(), 21, "Expected the dispatched jobs to be kept in the queue")
+		assert.Less(t, time.Since(start), 20*time.Millisecond, "Expected scheduling to not wait for the dispatch")
+	})
+}
+
 func TestWorkerPoolScaling(t *testing.T) {
 	// Start a manager with 1 worker
 	manager := NewCustom(1, 4, 1*time.Minute)
@@ -859,10 +1484,175 @@ func TestWorkerPoolScaling(t *testing.T) {
 		time.Sleep(150 * time.Millisecond) // Allow time for job removal
 
 		// Check that the worker pool has scaled down
-		assert.Equal(t, int32(manager.minWorkerCount), manager.workerPool.targetWorkerCount(), "Expected target worker count to be %d after removing all jobs", manager.minWorkerCount)
+		assert.Equal(t, manager.minWorkerCount.Load(), manager.workerPool.targetWorkerCount(), "Expected target worker count to be %d after removing all jobs", manager.minWorkerCount.Load())
+	})
+}
+
+func TestSetWorkerCount(t *testing.T) {
+	manager := NewCustom(2, 4, 1*time.Minute)
+	defer manager.Stop()
+	assert.Equal(t, 2, manager.WorkerCount(), "Expected initial worker count")
+
+	t.Run("Scale up", func(t *testing.T) {
+		assert.NoError(t, manager.SetWorkerCount(8), "Expected no error setting worker count")
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, 8, manager.WorkerCount(), "Expected worker count to be scaled up")
+		assert.Equal(t, int32(8), manager.workerPool.runningWorkers(), "Expected workers to be started")
+	})
+
+	t.Run("Scale down", func(t *testing.T) {
+		assert.NoError(t, manager.SetWorkerCount(3), "Expected no error setting worker count")
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, 3, manager.WorkerCount(), "Expected worker count to be scaled down")
+		assert.Equal(t, int32(3), manager.workerPool.runningWorkers(), "Expected workers to be stopped")
+	})
+
+	t.Run("Invalid count", func(t *testing.T) {
+		assert.Error(t, manager.SetWorkerCount(0), "Expected error setting zero workers")
+		assert.Error(t, manager.SetWorkerCount(maxWorkerCount+1), "Expected error setting too many workers")
+		assert.Equal(t, 3, manager.WorkerCount(), "Expected worker count to be unchanged")
 	})
 }
 
+func TestWorkerBounds(t *testing.T) {
+	manager := New(WithWorkerBounds(2, 3), WithScaleInterval(time.Hour))
+	defer manager.Stop()
+	assert.Equal(t, 2, manager.WorkerCount(), "Expected the minimum as the initial worker count")
+
+	// A job requiring more workers scales the pool up to the maximum only
+	assert.NoError(t, manager.ScheduleJob(getMockedJob(10, "wide-job", time.Hour, time.Hour)))
+	assert.Eventually(t, func() bool { return manager.WorkerCount() == 3 }, 50*time.Millisecond, time.Millisecond,
+		"Expected the worker count to be capped at the maximum")
+
+	assert.Error(t, manager.SetWorkerCount(4), "Expected error setting a count above the maximum")
+	assert.Error(t, manager.SetWorkerBounds(0, 3), "Expected error for a minimum of zero")
+	assert.Error(t, manager.SetWorkerBounds(4, 3), "Expected error for a minimum above the maximum")
+	assert.Error(t, manager.SetWorkerBounds(1, maxWorkerCount+1), "Expected error for a maximum above the limit")
+	assert.Error(t, manager.SetScaleDownPolicy(0, time.Second), "Expected error for a zero threshold")
+	assert.Error(t, manager.SetScaleDownPolicy(0.5, -time.Second), "Expected error for a negative interval")
+	assert.Error(t, manager.SetIdleScaleDown(-time.Second), "Expected error for a negative idle duration")
+
+	assert.NoError(t, manager.SetWorkerBounds(1, 5))
+	assert.Eventually(t, func() bool { return manager.WorkerCount() == 5 }, 50*time.Millisecond, time.Millisecond,
+		"Expected the worker count to be scaled up to the new maximum")
+}
+
+func TestIdleScaleDown(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	manager := New(
+		WithWorkerBounds(1, 8),
+		WithScaleInterval(10*time.Millisecond),
+		WithIdleScaleDown(time.Minute),
+		WithScaleDownPolicy(defaultUtilizationThreshold, 0),
+		WithClock(clock),
+	)
+	defer manager.Stop()
+
+	job := getMockedJob(4, "idle-job", time.Hour, time.Hour)
+	job.NextExec = clock.Now().Add(time.Hour)
+	assert.NoError(t, manager.ScheduleJob(job))
+	assert.Eventually(t, func() bool { return manager.WorkerCount() == 8 }, 20*time.Millisecond, time.Millisecond,
+		"Expected the pool to be scaled up for the job")
+
+	// The pool is idle once the idle time has passed on the TaskManager's clock
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 8, manager.WorkerCount(), "Expected the pool to be kept until the idle time has passed")
+	clock.Advance(time.Minute)
+	assert.Eventually(t, func() bool {
+		return manager.WorkerCount() == 1
+	}, 300*time.Millisecond, 5*time.Millisecond, "Expected the idle pool to be scaled down to its minimum")
+
+	// A dispatch scales the pool back up immediately
+	assert.NoError(t, manager.TriggerJob("idle-job"))
+	assert.Eventually(t, func() bool { return manager.WorkerCount() == 8 }, 20*time.Millisecond, time.Millisecond,
+		"Expected the pool to be scaled up on dispatch")
+}
+
+func TestWorkerHeadroom(t *testing.T) {
+	manager := New(
+		WithWorkerBounds(1, 16),
+		WithScaleInterval(10*time.Millisecond),
+		WithScaleDownPolicy(defaultUtilizationThreshold, 0),
+		WithWorkerHeadroom(3),
+	)
+	defer manager.Stop()
+	assert.Error(t, manager.SetWorkerHeadroom(-1), "Expected error for a negative headroom")
+	assert.Eventually(t, func() bool { return manager.WorkerCount() == 3 }, 100*time.Millisecond, time.Millisecond,
+		"Expected the headroom to be kept while no worker is busy")
+
+	// The headroom follows the busy workers
+	release := make(chan struct{})
+	defer close(release)
+	for _, id := range []string{"busy-job-1", "busy-job-2"} {
+		job := getMockedJob(1, id, time.Hour, time.Hour)
+		job.Tasks[0] = MockTask{executeFunc: func() error {
+			<-release
+			return nil
+		}}
+		assert.NoError(t, manager.ScheduleJob(job))
+		assert.NoError(t, manager.TriggerJob(id))
+	}
+	assert.Eventually(t, func() bool { return manager.WorkerCount() == 5 }, 100*time.Millisecond, time.Millisecond,
+		"Expected the headroom beyond the two busy workers")
+
+	// Without headroom, the pool is scaled for the jobs alone
+	assert.NoError(t, manager.SetWorkerHeadroom(0))
+	assert.Eventually(t, func() bool { return manager.WorkerCount() == 2 }, 100*time.Millisecond, time.Millisecond,
+		"Expected the pool to be scaled down to the busy workers")
+}
+
+func TestDrainWorkers(t *testing.T) {
+	manager := NewCustom(4, 4, 1*time.Minute)
+	defer manager.Stop()
+
+	release := make(chan struct{})
+	finished := make(chan struct{}, 2)
+	job := getMockedJob(2, "busy-job", time.Hour, time.Hour)
+	for i := range job.Tasks {
+		job.Tasks[i] = MockTask{executeFunc: func() error {
+			<-release
+			finished <- struct{}{}
+			return nil
+		}}
+	}
+	assert.NoError(t, manager.ScheduleJob(job))
+	assert.NoError(t, manager.TriggerJob(job.ID))
+	assert.Eventually(t, func() bool {
+		return manager.workerPool.activeWorkers() == 2
+	}, 50*time.Millisecond, time.Millisecond, "Expected two busy workers")
+
+	// Draining more workers than are idle stops busy workers once their current task has finished
+	drained, err := manager.DrainWorkers(3)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, manager.WorkerCount(), "Expected the worker count to be lowered at once")
+	assert.Equal(t, int32(1), manager.minWorkerCount.Load(), "Expected the minimum worker count to be lowered")
+	assert.Eventually(t, func() bool {
+		return manager.Metrics().WorkersDraining == 1
+	}, 50*time.Millisecond, time.Millisecond, "Expected the busy worker to be draining")
+	select {
+	case <-drained:
+		t.Fatal("Expected the busy worker to finish its task before being drained")
+	default:
+	}
+
+	close(release)
+	for range 2 {
+		<-finished
+	}
+	select {
+	case <-drained:
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the workers to be drained")
+	}
+	assert.Equal(t, int32(1), manager.workerPool.runningWorkers(), "Expected one worker to remain")
+	assert.Zero(t, manager.Metrics().WorkersDraining)
+
+	_, err = manager.DrainWorkers(1)
+	assert.Error(t, err, "Expected an error draining the last worker")
+	_, err = manager.DrainWorkers(0)
+	assert.Error(t, err, "Expected an error draining zero workers")
+}
+
 func TestWorkerPoolPeriodicScaling(t *testing.T) {
 	// Start a manager with 1 worker, and a scaling interval of 40ms. The scaling interval is set
 	// to occur after the first job has executed at least once.
@@ -1027,3 +1817,391 @@ func TestGoroutineLeak(t *testing.T) {
 		"Expected goroutine count to return to initial level, got %d (initial: %d)",
 		finalGoroutines, initialGoroutines)
 }
+
+func TestPauseJob(t *testing.T) {
+	manager := NewCustom(2, 4, 1*time.Minute)
+	defer manager.Stop()
+
+	t.Run("Pause and resume", func(t *testing.T) {
+		var executions atomic.Int32
+		job := Job{ID: "pause-job", Cadence: 10 * time.Millisecond, NextExec: time.Now(), Tasks: []Task{MockTask{executeFunc: func() error {
+			executions.Add(1)
+			return nil
+		}}}}
+		assert.NoError(t, manager.ScheduleJob(job))
+		time.Sleep(5 * time.Millisecond)
+
+		assert.NoError(t, manager.PauseJob(job.ID))
+		info, err := manager.Job(job.ID)
+		assert.NoError(t, err)
+		assert.True(t, info.Paused)
+		paused := executions.Load()
+		time.Sleep(35 * time.Millisecond)
+		assert.Equal(t, paused, executions.Load(), "Expected no executions while paused")
+
+		assert.NoError(t, manager.ResumeJob(job.ID))
+		info, err = manager.Job(job.ID)
+		assert.NoError(t, err)
+		assert.False(t, info.Paused)
+		assert.True(t, info.NextExec.After(time.Now()), "Expected missed executions to be skipped")
+		time.Sleep(25 * time.Millisecond)
+		assert.Greater(t, executions.Load(), paused, "Expected executions to continue once resumed")
+		assert.NoError(t, manager.RemoveJob(job.ID))
+	})
+
+	t.Run("Other jobs keep executing", func(t *testing.T) {
+		var executions atomic.Int32
+		paused := getMockedJob(1, "paused-job", 5*time.Millisecond, 0)
+		assert.NoError(t, manager.ScheduleJob(paused))
+		assert.NoError(t, manager.PauseJob(paused.ID))
+		other := Job{ID: "other-job", Cadence: 5 * time.Millisecond, NextExec: time.Now().Add(5 * time.Millisecond),
+			Tasks: []Task{MockTask{executeFunc: func() error {
+				executions.Add(1)
+				return nil
+			}}}}
+		assert.NoError(t, manager.ScheduleJob(other))
+
+		time.Sleep(30 * time.Millisecond)
+		assert.GreaterOrEqual(t, executions.Load(), int32(3), "Expected the paused job to not block other jobs")
+		assert.NoError(t, manager.RemoveJob(paused.ID))
+		assert.NoError(t, manager.RemoveJob(other.ID))
+	})
+
+	t.Run("Missing job", func(t *testing.T) {
+		assert.Error(t, manager.PauseJob("missing-job"))
+		assert.Error(t, manager.ResumeJob("missing-job"))
+	})
+}
+
+func TestJobResume(t *testing.T) {
+	now := time.Now()
+	job := &Job{Cadence: 10 * time.Second, scheduled: now.Add(-25 * time.Second), paused: true}
+	job.resume(now)
+	assert.False(t, job.paused)
+	assert.Equal(t, now.Add(5*time.Second), job.NextExec, "Expected the next execution following the schedule")
+
+	once := &Job{once: true, scheduled: now.Add(-time.Second), paused: true}
+	once.resume(now)
+	assert.Equal(t, now, once.NextExec, "Expected a due one-shot job to execute immediately")
+
+	upcoming := &Job{Cadence: 10 * time.Second, scheduled: now.Add(time.Second), NextExec: now.Add(time.Second), paused: true}
+	upcoming.resume(now)
+	assert.Equal(t, now.Add(time.Second), upcoming.NextExec, "Expected an upcoming execution to be kept")
+}
+
+func TestJobLimits(t *testing.T) {
+	manager := NewCustom(2, 4, 1*time.Minute)
+	defer manager.Stop()
+
+	// removals returns a channel receiving the IDs of removed jobs.
+	removals := make(chan string, 4)
+	manager.OnJobRemoved(func(jobID string) { removals <- jobID })
+	awaitRemoval := func(t *testing.T, jobID string, timeout time.Duration) {
+		t.Helper()
+		select {
+		case id := <-removals:
+			assert.Equal(t, jobID, id)
+		case <-time.After(timeout):
+			t.Fatalf("Expected job %s to be removed", jobID)
+		}
+	}
+
+	t.Run("Max runs", func(t *testing.T) {
+		var executions atomic.Int32
+		job := Job{ID: "max-runs-job", Cadence: 5 * time.Millisecond, NextExec: time.Now(), MaxRuns: 3,
+			Tasks: []Task{MockTask{executeFunc: func() error {
+				executions.Add(1)
+				return nil
+			}}}}
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		awaitRemoval(t, job.ID, 50*time.Millisecond)
+		time.Sleep(15 * time.Millisecond)
+		assert.Equal(t, int32(3), executions.Load(), "Expected the job to execute MaxRuns times")
+		_, err := manager.Job(job.ID)
+		assert.Error(t, err, "Expected the job to be removed")
+	})
+
+	t.Run("Final run outlives earlier runs", func(t *testing.T) {
+		var runs atomic.Int32
+		finalErr := make(chan error, 1)
+		job := Job{ID: "overlapping-max-runs-job", Cadence: 10 * time.Millisecond, NextExec: time.Now(), MaxRuns: 2,
+			Tasks: []Task{SimpleContextTask{function: func(ctx context.Context) error {
+				if runs.Add(1) == 1 {
+					// The first run finishes while the final run executes
+					time.Sleep(30 * time.Millisecond)
+					return nil
+				}
+				select {
+				case <-ctx.Done():
+					finalErr <- ctx.Err()
+				case <-time.After(50 * time.Millisecond):
+					finalErr <- nil
+				}
+				return nil
+			}}}}
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		awaitRemoval(t, job.ID, 50*time.Millisecond)
+		select {
+		case err := <-finalErr:
+			assert.NoError(t, err, "Expected the final run to keep its context until it finished")
+		case <-time.After(200 * time.Millisecond):
+			t.Fatal("Expected the final run to finish")
+		}
+	})
+
+	t.Run("Triggered runs count", func(t *testing.T) {
+		job := getMockedJob(1, "triggered-max-runs-job", time.Minute, time.Minute)
+		job.MaxRuns = 2
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		assert.NoError(t, manager.TriggerJob(job.ID))
+		_, err := manager.Job(job.ID)
+		assert.NoError(t, err, "Expected the job to remain after its first run")
+		assert.NoError(t, manager.TriggerJob(job.ID))
+		awaitRemoval(t, job.ID, 10*time.Millisecond)
+	})
+
+	t.Run("Replaced runs count", func(t *testing.T) {
+		job := getMockedJob(1, "replaced-max-runs-job", time.Minute, time.Minute)
+		job.MaxRuns = 2
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		assert.NoError(t, manager.TriggerJob(job.ID))
+		replacement := getMockedJob(2, job.ID, time.Minute, time.Minute)
+		replacement.MaxRuns = 2
+		assert.NoError(t, manager.ReplaceJob(replacement))
+		assert.NoError(t, manager.TriggerJob(job.ID))
+		awaitRemoval(t, job.ID, 10*time.Millisecond)
+	})
+
+	t.Run("Until", func(t *testing.T) {
+		clock := newFakeClock(time.Now())
+		manager := New(WithWorkers(1), WithClock(clock))
+		defer manager.Stop()
+
+		var executions atomic.Int32
+		now := clock.Now()
+		job := Job{ID: "until-job", Cadence: 10 * time.Millisecond, NextExec: now, Until: now.Add(25 * time.Millisecond),
+			Tasks: []Task{MockTask{executeFunc: func() error {
+				executions.Add(1)
+				return nil
+			}}}}
+		assert.NoError(t, manager.ScheduleJob(job))
+		awaitExecutions := func(n int32) {
+			t.Helper()
+			assert.Eventually(t, func() bool {
+				clock.wake()
+				return executions.Load() == n
+			}, time.Second, time.Millisecond, "Expected %d executions", n)
+		}
+
+		// Runs at 0ms, 10ms and 20ms, the job is removed after the last run before Until, rather
+		// than when next due at 30ms
+		awaitExecutions(1)
+		clock.Advance(10 * time.Millisecond)
+		awaitExecutions(2)
+		_, err := manager.Job(job.ID)
+		assert.NoError(t, err, "Expected the job to remain until its final run")
+		clock.Advance(10 * time.Millisecond)
+		awaitExecutions(3)
+		assert.Eventually(t, func() bool {
+			_, err := manager.Job(job.ID)
+			return err != nil
+		}, time.Second, time.Millisecond, "Expected the job to be removed after its final run")
+
+		clock.Advance(20 * time.Millisecond)
+		clock.wake()
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, int32(3), executions.Load(), "Expected the job to execute until Until")
+	})
+
+	t.Run("Expired while paused", func(t *testing.T) {
+		var executions atomic.Int32
+		now := time.Now()
+		job := Job{ID: "expired-job", Cadence: time.Minute, NextExec: now.Add(5 * time.Millisecond), Until: now.Add(10 * time.Millisecond),
+			Tasks: []Task{MockTask{executeFunc: func() error {
+				executions.Add(1)
+				return nil
+			}}}}
+		assert.NoError(t, manager.ScheduleJob(job))
+		assert.NoError(t, manager.PauseJob(job.ID))
+		time.Sleep(15 * time.Millisecond)
+
+		assert.NoError(t, manager.ResumeJob(job.ID))
+		awaitRemoval(t, job.ID, 10*time.Millisecond)
+		assert.Equal(t, int32(0), executions.Load(), "Expected no execution after Until")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		job := getMockedJob(1, "invalid-limits-job", time.Second, 0)
+		job.MaxRuns = -1
+		assert.Error(t, manager.ScheduleJob(job), "Expected error for negative max runs")
+
+		job.MaxRuns = 0
+		job.Until = job.NextExec.Add(-time.Millisecond)
+		assert.Error(t, manager.ScheduleJob(job), "Expected error for Until before the first execution")
+	})
+}
+
+func TestJobAlign(t *testing.T) {
+	t.Run("Align to", func(t *testing.T) {
+		start := time.Date(2025, 1, 1, 12, 3, 20, 0, time.UTC)
+		assert.Equal(t, time.Date(2025, 1, 1, 12, 5, 0, 0, time.UTC), alignTo(start, 5*time.Minute))
+		assert.Equal(t, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), alignTo(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), 5*time.Minute),
+			"Expected an aligned time to be kept")
+		assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), alignTo(start, 24*time.Hour))
+	})
+
+	t.Run("Executions are aligned", func(t *testing.T) {
+		manager := NewCustom(2, 4, 1*time.Minute)
+		defer manager.Stop()
+
+		cadence := 20 * time.Millisecond
+		executed := make(chan time.Time, 4)
+		job := Job{ID: "aligned-job", Cadence: cadence, Align: true, Tasks: []Task{MockTask{executeFunc: func() error {
+			executed <- time.Now()
+			return nil
+		}}}}
+		before := time.Now()
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		info, err := manager.Job(job.ID)
+		assert.NoError(t, err)
+		assert.True(t, info.NextExec.Equal(info.NextExec.Truncate(cadence)), "Expected NextExec to be a multiple of the cadence")
+		assert.False(t, info.NextExec.Before(before), "Expected NextExec to not be before now")
+		assert.WithinDuration(t, before, info.NextExec, cadence, "Expected the first execution within one cadence")
+
+		for range 2 {
+			select {
+			case executedAt := <-executed:
+				offset := executedAt.Sub(executedAt.Truncate(cadence))
+				assert.Less(t, offset, 5*time.Millisecond, "Expected the execution to be aligned to the cadence")
+			case <-time.After(50 * time.Millisecond):
+				t.Fatal("Expected the job to execute")
+			}
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		manager := NewCustom(1, 1, 1*time.Minute)
+		defer manager.Stop()
+
+		job := getMockedJob(1, "invalid-align-job", time.Second, 0)
+		job.Align = true
+		job.Schedule = FixedCadence(time.Second)
+		assert.Error(t, manager.ScheduleJob(job), "Expected error aligning a job with a schedule")
+	})
+}
+
+func TestJobMaxDelay(t *testing.T) {
+	t.Run("Stale runs are skipped", func(t *testing.T) {
+		manager := NewCustom(1, 4, 1*time.Minute)
+		defer manager.Stop()
+		events, unsubscribe := manager.SubscribeEvents(16)
+		defer unsubscribe()
+
+		// Both jobs were due 50ms ago, exceeding the max delay of the first
+		executed := make(chan string, 4)
+		for _, maxDelay := range []time.Duration{10 * time.Millisecond, time.Second} {
+			job := getMockedJob(1, fmt.Sprintf("delay-%v", maxDelay), time.Hour, -50*time.Millisecond)
+			job.MaxDelay = maxDelay
+			job.Tasks[0] = MockTask{executeFunc: func() error {
+				executed <- job.ID
+				return nil
+			}}
+			assert.NoError(t, manager.ScheduleJob(job))
+		}
+
+		select {
+		case jobID := <-executed:
+			assert.Equal(t, "delay-1s", jobID, "Expected only the run within its max delay to execute")
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("Expected the job within its max delay to execute")
+		}
+		select {
+		case jobID := <-executed:
+			t.Fatalf("Expected the stale run of %s to be skipped", jobID)
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		skipped := false
+		for !skipped {
+			select {
+			case event := <-events:
+				if event.Type == EventRunSkipped {
+					assert.Equal(t, "delay-10ms", event.JobID)
+					skipped = true
+				}
+			case <-time.After(50 * time.Millisecond):
+				t.Fatal("Expected a skipped run event")
+			}
+		}
+		stats, err := manager.JobStats("delay-10ms")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, stats.SkippedRuns)
+		assert.Equal(t, 0, stats.TotalRuns)
+		info, err := manager.Job("delay-10ms")
+		assert.NoError(t, err)
+		assert.True(t, info.NextExec.After(time.Now()), "Expected the job to be rescheduled")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		manager := NewCustom(1, 1, 1*time.Minute)
+		defer manager.Stop()
+
+		job := getMockedJob(1, "invalid-delay-job", time.Second, 0)
+		job.MaxDelay = -time.Second
+		assert.Error(t, manager.ScheduleJob(job), "Expected error scheduling a job with a negative max delay")
+	})
+}
+
+func TestJobIdempotencyKey(t *testing.T) {
+	manager := NewCustom(2, 4, 1*time.Minute)
+	defer manager.Stop()
+
+	// A run of the first job is executing when the second job with the same key is due
+	release := make(chan struct{})
+	first := getMockedJob(1, "first-key-job", time.Hour, time.Hour)
+	first.IdempotencyKey = "sync"
+	first.Tasks[0] = MockTask{executeFunc: func() error {
+		<-release
+		return nil
+	}}
+	assert.NoError(t, manager.ScheduleJob(first))
+	assert.NoError(t, manager.TriggerJob(first.ID))
+	assert.Eventually(t, func() bool {
+		manager.RLock()
+		defer manager.RUnlock()
+		return manager.inflightKeys["sync"] == 1
+	}, time.Second, time.Millisecond, "Expected the run of the key to be in flight")
+
+	var executions atomic.Int32
+	second := getMockedJob(1, "second-key-job", time.Hour, -time.Millisecond)
+	second.IdempotencyKey = "sync"
+	second.Tasks[0] = MockTask{executeFunc: func() error {
+		executions.Add(1)
+		return nil
+	}}
+	assert.NoError(t, manager.ScheduleJob(second))
+	assert.Eventually(t, func() bool { return manager.Metrics().RunsCoalesced == 1 }, time.Second, time.Millisecond,
+		"Expected the duplicate run to be coalesced")
+	assert.Equal(t, int32(0), executions.Load(), "Expected the coalesced run not to execute")
+	stats, err := manager.JobStats(second.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.SkippedRuns)
+
+	// Once the run finishes, runs of the key are dispatched again
+	close(release)
+	assert.Eventually(t, func() bool {
+		manager.RLock()
+		defer manager.RUnlock()
+		return len(manager.inflightKeys) == 0
+	}, time.Second, time.Millisecond, "Expected no runs of the key in flight")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = manager.RunJobNow(ctx, second.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), executions.Load())
+}
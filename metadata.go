@@ -0,0 +1,26 @@
+package taskman
+
+import (
+	"context"
+	"maps"
+)
+
+// metadataKey is the context key of the metadata of a job, in the context passed to its tasks.
+type metadataKey struct{}
+
+// JobMetadata returns a copy of the metadata of the job, as passed in the context to the job's
+// context-aware tasks, e.g. for tasks to identify the tenant they execute for. Returns nil if the
+// context carries no metadata.
+func JobMetadata(ctx context.Context) map[string]string {
+	metadata, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return maps.Clone(metadata)
+}
+
+// withJobMetadata returns a context carrying the metadata of a job, or the context itself if the
+// job has no metadata.
+func withJobMetadata(ctx context.Context, metadata map[string]string) context.Context {
+	if len(metadata) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
@@ -0,0 +1,90 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobMetadata(t *testing.T) {
+	manager := NewCustom(1, 4, 1*time.Minute)
+	defer manager.Stop()
+	store := NewMemoryJobStore()
+	manager.SetJobStore(store)
+	events, unsubscribe := manager.SubscribeEvents(16)
+	defer unsubscribe()
+
+	type traceKey struct{}
+	received := make(chan map[string]string, 1)
+	metadata := map[string]string{"tenant": "acme"}
+	job := Job{
+		ID:          "tenant-job",
+		Cadence:     time.Hour,
+		NextExec:    time.Now().Add(time.Hour),
+		BaseContext: context.WithValue(context.Background(), traceKey{}, "trace-1"),
+		Metadata:    metadata,
+		Tasks: []Task{SimpleContextTask{func(ctx context.Context) error {
+			assert.Equal(t, "trace-1", ctx.Value(traceKey{}), "Expected the values of the base context")
+			received <- JobMetadata(ctx)
+			return errors.New("task failed")
+		}}},
+	}
+	assert.NoError(t, manager.ScheduleJob(job))
+	metadata["tenant"] = "modified"
+
+	info, err := manager.Job(job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"tenant": "acme"}, info.Metadata, "Expected the metadata to be copied when scheduled")
+	records, err := store.List()
+	assert.NoError(t, err)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "acme", records[0].Metadata["tenant"], "Expected the metadata to be persisted")
+	}
+
+	// The metadata is passed to the job's tasks, and included in its errors and events
+	assert.NoError(t, manager.TriggerJob(job.ID))
+	select {
+	case got := <-received:
+		assert.Equal(t, map[string]string{"tenant": "acme"}, got)
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the task to execute")
+	}
+	select {
+	case err := <-manager.ErrorChannel():
+		var taskErr *TaskError
+		if assert.ErrorAs(t, err, &taskErr) {
+			assert.Equal(t, "acme", taskErr.Metadata["tenant"], "Expected the error to include the metadata")
+		}
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected an error to be reported")
+	}
+	timeout := time.After(50 * time.Millisecond)
+	for seen := map[EventType]bool{}; len(seen) < 3; {
+		select {
+		case event := <-events:
+			if event.Type == EventJobDispatched || event.Type == EventTaskStarted || event.Type == EventTaskCompleted {
+				assert.Equal(t, "acme", event.Metadata["tenant"], "Expected %s to include the metadata", event.Type)
+				seen[event.Type] = true
+			}
+		case <-timeout:
+			t.Fatal("Expected the job's dispatch and task events")
+		}
+	}
+
+	// The metadata of a replacing job is copied as well
+	replacement := getMockedJob(1, job.ID, time.Hour, time.Hour)
+	replacement.Metadata = map[string]string{"tenant": "globex"}
+	assert.NoError(t, manager.ReplaceJob(replacement))
+	replacement.Metadata["tenant"] = "modified"
+	info, err = manager.Job(job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"tenant": "globex"}, info.Metadata, "Expected the metadata to be copied when replaced")
+
+	assert.Nil(t, JobMetadata(context.Background()), "Expected no metadata outside of jobs")
+	invalid := getMockedJob(1, "invalid-job", time.Hour, time.Hour)
+	invalid.Metadata = map[string]string{"": "value"}
+	assert.Error(t, manager.ScheduleJob(invalid), "Expected an error for an empty metadata key")
+}
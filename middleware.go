@@ -0,0 +1,71 @@
+package taskman
+
+import "context"
+
+// TaskExecution describes a single execution attempt of a task of a scheduled job, as passed
+// through the chain of TaskMiddleware.
+type TaskExecution struct {
+	JobID     string // ID of the job the task belongs to
+	TaskIndex int    // Index of the task within the job's tasks
+	TaskID    string // ID of the task, if it is a NamedTask
+	Attempt   int    // Attempt of the execution, starting at 1
+	Task      Task   // The task to execute
+
+	data *map[string]any // Destination of the output of a ResultTask, if any
+}
+
+// TaskExecutor executes a task, returning its error.
+type TaskExecutor func(ctx context.Context, exec TaskExecution) error
+
+// TaskMiddleware wraps a TaskExecutor, e.g. for tracing, logging or metrics around every task
+// execution. A middleware calls next to execute the task, and may inspect or replace its error.
+type TaskMiddleware func(next TaskExecutor) TaskExecutor
+
+// UseTaskMiddleware adds middleware wrapping every execution attempt of the tasks of scheduled
+// jobs, inside the TaskManager's retries and panic recovery. Middleware added first is outermost,
+// and middleware applies to runs dispatched after it was added.
+func (tm *TaskManager) UseTaskMiddleware(middleware ...TaskMiddleware) {
+	tm.Lock()
+	defer tm.Unlock()
+	tm.middleware = append(tm.middleware, middleware...)
+	tm.updateExecutor()
+}
+
+// updateExecutor rebuilds the TaskManager's middleware chain, with tracing outermost if enabled.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) updateExecutor() {
+	middleware := tm.middleware
+	if tm.tracer != nil {
+		middleware = append([]TaskMiddleware{tracingMiddleware(tm.tracer)}, middleware...)
+	}
+	tm.executor = nil
+	if len(middleware) > 0 {
+		tm.executor = chainMiddleware(middleware)
+	}
+}
+
+// chainMiddleware returns a TaskExecutor executing tasks through the middleware, in order.
+func chainMiddleware(middleware []TaskMiddleware) TaskExecutor {
+	executor := TaskExecutor(executeTask)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		executor = middleware[i](executor)
+	}
+	return executor
+}
+
+// executeTask executes the task of the execution, passing the context on to context-aware tasks,
+// and storing the output of result tasks. It is the innermost TaskExecutor of every middleware
+// chain.
+func executeTask(ctx context.Context, exec TaskExecution) error {
+	if rt, ok := exec.Task.(ResultTask); ok {
+		data, err := rt.ExecuteResult(ctx)
+		if exec.data != nil {
+			*exec.data = data
+		}
+		return err
+	}
+	if ct, ok := exec.Task.(ContextTask); ok {
+		return ct.ExecuteContext(ctx)
+	}
+	return exec.Task.Execute()
+}
@@ -0,0 +1,102 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskMiddleware(t *testing.T) {
+	t.Run("Chain order", func(t *testing.T) {
+		var calls []string
+		record := func(name string) TaskMiddleware {
+			return func(next TaskExecutor) TaskExecutor {
+				return func(ctx context.Context, exec TaskExecution) error {
+					calls = append(calls, name+" before")
+					err := next(ctx, exec)
+					calls = append(calls, name+" after")
+					return err
+				}
+			}
+		}
+		executor := chainMiddleware([]TaskMiddleware{record("outer"), record("inner")})
+
+		task := MockTask{executeFunc: func() error {
+			calls = append(calls, "task")
+			return nil
+		}}
+		assert.NoError(t, executor(context.Background(), TaskExecution{Task: task}))
+		assert.Equal(t, []string{"outer before", "inner before", "task", "inner after", "outer after"}, calls)
+	})
+
+	t.Run("Wraps executions", func(t *testing.T) {
+		var mu sync.Mutex
+		var executions []TaskExecution
+		middleware := func(next TaskExecutor) TaskExecutor {
+			return func(ctx context.Context, exec TaskExecution) error {
+				mu.Lock()
+				executions = append(executions, exec)
+				mu.Unlock()
+				return next(ctx, exec)
+			}
+		}
+		manager := New(WithWorkers(1), WithTaskMiddleware(middleware))
+		defer manager.Stop()
+
+		attempts := 0
+		job := Job{ID: "middleware-job", Cadence: time.Minute, NextExec: time.Now(), RetryPolicy: &RetryPolicy{MaxAttempts: 2},
+			Tasks: []Task{MockTask{executeFunc: func() error {
+				attempts++
+				if attempts == 1 {
+					return errors.New("first attempt failed")
+				}
+				return nil
+			}}}}
+		completed := make(chan error, 1)
+		manager.OnJobComplete(func(jobID string, duration time.Duration, err error) { completed <- err })
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		select {
+		case err := <-completed:
+			assert.NoError(t, err, "Expected the retry to succeed")
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("Expected the job to complete")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if assert.Len(t, executions, 2, "Expected the middleware to wrap every attempt") {
+			assert.Equal(t, "middleware-job", executions[0].JobID)
+			assert.Equal(t, 0, executions[0].TaskIndex)
+			assert.Equal(t, 1, executions[0].Attempt)
+			assert.Equal(t, 2, executions[1].Attempt)
+		}
+	})
+
+	t.Run("Replaces errors", func(t *testing.T) {
+		manager := NewCustom(1, 4, 1*time.Minute)
+		defer manager.Stop()
+
+		errReplaced := errors.New("replaced")
+		manager.UseTaskMiddleware(func(next TaskExecutor) TaskExecutor {
+			return func(ctx context.Context, exec TaskExecution) error {
+				if err := next(ctx, exec); err != nil {
+					return errReplaced
+				}
+				return nil
+			}
+		})
+		_, err := manager.ScheduleOnce(MockTask{executeFunc: func() error { return errors.New("task failed") }}, 0)
+		assert.NoError(t, err)
+
+		select {
+		case err := <-manager.ErrorChannel():
+			assert.ErrorIs(t, err, errReplaced, "Expected the error returned by the middleware")
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("Expected an error to be reported")
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package taskman
+
+import "fmt"
+
+// NamedTask is a Task with an ID, unique within its job, e.g. the endpoint a task of an
+// aggregation job fetches from. The ID is reported alongside the task's index in its TaskResult,
+// Result, TaskError and events, and its failures are counted in the job's TaskFailures stat,
+// identifying which of a job's tasks failed.
+type NamedTask interface {
+	Task
+	// TaskID returns the ID of the task.
+	TaskID() string
+}
+
+// taskIDOf returns the ID of the task, or an empty string if it is not a NamedTask.
+func taskIDOf(task Task) string {
+	if named, ok := task.(NamedTask); ok {
+		return named.TaskID()
+	}
+	return ""
+}
+
+// validateTaskIDs returns an error if any two of the tasks have the same ID.
+func validateTaskIDs(tasks []Task) error {
+	seen := make(map[string]bool)
+	for _, task := range tasks {
+		taskID := taskIDOf(task)
+		if taskID == "" {
+			continue
+		}
+		if seen[taskID] {
+			return fmt.Errorf("duplicate task ID %s", taskID)
+		}
+		seen[taskID] = true
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// endpointTask is a named task for testing, failing if err is set.
+type endpointTask struct {
+	endpoint string
+	err      error
+}
+
+func (et endpointTask) Execute() error { return et.err }
+
+func (et endpointTask) TaskID() string { return et.endpoint }
+
+func TestNamedTasks(t *testing.T) {
+	manager := New(WithWorkers(2))
+	defer manager.Stop()
+	events, unsubscribe := manager.SubscribeEvents(16)
+	defer unsubscribe()
+
+	job := Job{
+		ID:       "aggregate-job",
+		Cadence:  time.Hour,
+		NextExec: time.Now().Add(time.Hour),
+		Tasks: []Task{
+			endpointTask{endpoint: "endpoint-a"},
+			endpointTask{endpoint: "endpoint-b", err: errors.New("unreachable")},
+			MockTask{ID: "unnamed"},
+		},
+	}
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	result, err := manager.RunJobNow(ctx, job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "endpoint-a", result.TaskResults[0].TaskID)
+	assert.Equal(t, "endpoint-b", result.TaskResults[1].TaskID)
+	assert.Empty(t, result.TaskResults[2].TaskID, "Expected no ID for a task which is not named")
+
+	var taskErr *TaskError
+	assert.ErrorAs(t, result.Err(), &taskErr)
+	assert.Equal(t, "endpoint-b", taskErr.TaskID)
+	assert.Equal(t, "job aggregate-job, task 1 (endpoint-b), attempt 1: unreachable", taskErr.Error())
+
+	stats, err := manager.JobStats(job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"endpoint-b": 1}, stats.TaskFailures)
+
+	select {
+	case event := <-waitForTaskEvent(events, "endpoint-b"):
+		assert.Equal(t, 1, event.TaskIndex)
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected a task event with the task's ID")
+	}
+}
+
+func TestNamedTasksUnique(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+
+	job := Job{
+		ID:       "duplicate-job",
+		Cadence:  time.Hour,
+		NextExec: time.Now().Add(time.Hour),
+		Tasks:    []Task{endpointTask{endpoint: "endpoint-a"}, endpointTask{endpoint: "endpoint-a"}},
+	}
+	assert.ErrorContains(t, manager.ScheduleJob(job), "duplicate task ID")
+
+	job.Tasks = job.Tasks[:1]
+	assert.NoError(t, manager.ScheduleJob(job))
+	assert.ErrorContains(t, manager.AddTaskToJob(job.ID, endpointTask{endpoint: "endpoint-a"}), "duplicate task ID")
+	assert.NoError(t, manager.AddTaskToJob(job.ID, endpointTask{endpoint: "endpoint-b"}))
+}
+
+// waitForTaskEvent returns a channel receiving the first task event of the task with the ID.
+func waitForTaskEvent(events <-chan Event, taskID string) <-chan Event {
+	found := make(chan Event, 1)
+	go func() {
+		for event := range events {
+			if event.TaskID == taskID {
+				found <- event
+				return
+			}
+		}
+	}()
+	return found
+}
@@ -0,0 +1,408 @@
+package taskman
+
+import (
+	"io"
+	"runtime"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+)
+
+// options holds the configuration of a TaskManager created with New.
+type options struct {
+	workerCount         int
+	maxWorkers          int
+	idleScaleDown       time.Duration
+	workerHeadroom      int
+	downScaleThreshold  float64
+	downScaleInterval   time.Duration
+	taskBufferSize      int
+	taskShards          int
+	errorBufferSize     int
+	scaleInterval       time.Duration
+	retryPolicy         *RetryPolicy
+	workerGroups        map[string]int
+	reservedWorkers     int
+	reservedPriority    int
+	maxJobs             int
+	overflowPolicy      OverflowPolicy
+	saturationPolicy    SaturationPolicy
+	shedBelow           int
+	dispatchRate        rate.Limit
+	dispatchBurst       int
+	deadLetterThreshold int
+	overrunRuns         int
+	historySize         int
+	clockJumpPolicy     ClockJumpPolicy
+	chaos               Chaos
+	resultOverflow      ResultOverflowPolicy
+	concurrencyLimits   map[string]int
+	resourceBudget      ResourceBudget
+	blackouts           []Blackout
+	jobTTL              time.Duration
+	persistHistory      bool
+	middleware          []TaskMiddleware
+	tracerProvider      trace.TracerProvider
+	panicHandler        PanicHandler
+	recoverDisabled     bool
+	cpuAccounting       bool
+	workerLifecycle     *WorkerLifecycle
+	discardRemoved      bool
+	idGenerator         IDGenerator
+	store               JobStore
+	lock                DistributedLock
+	lockTTL             time.Duration
+	eventLog            io.Writer
+	dispatcher          Dispatcher
+	ackTimeout          time.Duration
+	logger              Logger
+	clock               Clock
+}
+
+// Option configures a TaskManager created with New.
+type Option func(*options)
+
+// WithWorkers sets the initial, and minimum, number of workers in the worker pool. Defaults to
+// the number of CPUs.
+func WithWorkers(n int) Option {
+	return func(o *options) {
+		o.workerCount = n
+	}
+}
+
+// WithWorkerBounds sets the minimum and maximum number of workers in the worker pool, as set by
+// SetWorkerBounds. The minimum is also the initial number of workers, as set by WithWorkers.
+func WithWorkerBounds(minWorkers, maxWorkers int) Option {
+	return func(o *options) {
+		o.workerCount = minWorkers
+		o.maxWorkers = maxWorkers
+	}
+}
+
+// WithIdleScaleDown sets the idle time after which the worker pool is scaled down to its minimum,
+// as set by SetIdleScaleDown.
+func WithIdleScaleDown(d time.Duration) Option {
+	return func(o *options) {
+		o.idleScaleDown = d
+	}
+}
+
+// WithWorkerHeadroom sets the number of spare idle workers the worker pool keeps beyond its busy
+// workers, as set by SetWorkerHeadroom.
+func WithWorkerHeadroom(workers int) Option {
+	return func(o *options) {
+		o.workerHeadroom = workers
+	}
+}
+
+// WithScaleDownPolicy sets the utilization threshold and minimum interval of scaling down the
+// worker pool, as set by SetScaleDownPolicy.
+func WithScaleDownPolicy(threshold float64, interval time.Duration) Option {
+	return func(o *options) {
+		o.downScaleThreshold = threshold
+		o.downScaleInterval = interval
+	}
+}
+
+// WithTaskBuffer sets the buffer size of the channel tasks are dispatched to the worker pool on.
+// Defaults to 64.
+func WithTaskBuffer(n int) Option {
+	return func(o *options) {
+		o.taskBufferSize = n
+	}
+}
+
+// WithTaskShards sets the number of shards the task buffer is split into, each received from by
+// its own share of the workers, which steal tasks from the other shards when their own is empty.
+// Sharding spreads the contention of dispatching tasks at high rates, at the cost of tasks not
+// being executed in strict dispatch order. Capped at the task buffer size, and defaults to a single
+// shard, keeping the dispatch order.
+func WithTaskShards(n int) Option {
+	return func(o *options) {
+		o.taskShards = n
+	}
+}
+
+// WithErrorBuffer sets the buffer size of the error channel returned by ErrorChannel. Errors are
+// dropped while the buffer is full, rather than blocking the workers, and counted in the
+// DroppedErrors metric. Defaults to 64.
+func WithErrorBuffer(n int) Option {
+	return func(o *options) {
+		o.errorBufferSize = n
+	}
+}
+
+// WithScaleInterval sets the interval of the periodic automatic scaling of the worker pool.
+// Defaults to 1 minute.
+func WithScaleInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.scaleInterval = d
+	}
+}
+
+// WithRetryPolicy sets the default retry policy, as set by SetRetryPolicy.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithWorkerGroup adds a named worker group of the given number of workers, as set by
+// SetWorkerGroup. May be passed once per group.
+func WithWorkerGroup(name string, workers int) Option {
+	return func(o *options) {
+		if o.workerGroups == nil {
+			o.workerGroups = make(map[string]int)
+		}
+		o.workerGroups[name] = workers
+	}
+}
+
+// WithWorkerReservation reserves a number of workers for the jobs of at least the given priority, as
+// set by SetWorkerReservation.
+func WithWorkerReservation(workers, minPriority int) Option {
+	return func(o *options) {
+		o.reservedWorkers = workers
+		o.reservedPriority = minPriority
+	}
+}
+
+// WithMaxJobs sets the maximum number of jobs in the queue and the policy applied when it is
+// full, as set by SetMaxJobs.
+func WithMaxJobs(maxJobs int, policy OverflowPolicy) Option {
+	return func(o *options) {
+		o.maxJobs = maxJobs
+		o.overflowPolicy = policy
+	}
+}
+
+// WithSaturationPolicy sets what happens to due runs whose tasks do not fit in the full task queue,
+// as set by SetSaturationPolicy.
+func WithSaturationPolicy(policy SaturationPolicy, shedBelow int) Option {
+	return func(o *options) {
+		o.saturationPolicy = policy
+		o.shedBelow = shedBelow
+	}
+}
+
+// WithMaxDispatchRate sets the maximum rate and burst at which job runs are dispatched, as set by
+// SetMaxDispatchRate.
+func WithMaxDispatchRate(r rate.Limit, burst int) Option {
+	return func(o *options) {
+		o.dispatchRate = r
+		o.dispatchBurst = burst
+	}
+}
+
+// WithDeadLetterThreshold sets the default dead letter threshold, as set by
+// SetDeadLetterThreshold.
+func WithDeadLetterThreshold(threshold int) Option {
+	return func(o *options) {
+		o.deadLetterThreshold = threshold
+	}
+}
+
+// WithOverrunDetection sets the number of runs after which jobs are checked for overrunning, as set
+// by SetOverrunDetection.
+func WithOverrunDetection(runs int) Option {
+	return func(o *options) {
+		o.overrunRuns = runs
+	}
+}
+
+// WithClockJumpPolicy sets how the schedules of jobs are adjusted when the wall clock jumps, as set
+// by SetClockJumpPolicy.
+func WithClockJumpPolicy(policy ClockJumpPolicy) Option {
+	return func(o *options) {
+		o.clockJumpPolicy = policy
+	}
+}
+
+// WithResourceBudget sets the resource budget bounding the automatic scaling of the worker pool, as
+// set by SetResourceBudget.
+func WithResourceBudget(budget ResourceBudget) Option {
+	return func(o *options) {
+		o.resourceBudget = budget
+	}
+}
+
+// WithBlackouts sets the blackout windows applying to the runs of all jobs, as set by SetBlackouts.
+func WithBlackouts(blackouts ...Blackout) Option {
+	return func(o *options) {
+		o.blackouts = blackouts
+	}
+}
+
+// WithJobTTL sets the default idle TTL of jobs, after which jobs not touched are removed, as set by
+// SetJobTTL.
+func WithJobTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.jobTTL = ttl
+	}
+}
+
+// WithConcurrencyLimit sets the max number of concurrently executing tasks of the jobs in a
+// concurrency group, as set by SetConcurrencyLimit. May be given once for each group.
+func WithConcurrencyLimit(group string, limit int) Option {
+	return func(o *options) {
+		if o.concurrencyLimits == nil {
+			o.concurrencyLimits = make(map[string]int)
+		}
+		o.concurrencyLimits[group] = limit
+	}
+}
+
+// WithResultOverflowPolicy sets what happens to results delivered to a full subscription, as set by
+// SetResultOverflowPolicy.
+func WithResultOverflowPolicy(policy ResultOverflowPolicy) Option {
+	return func(o *options) {
+		o.resultOverflow = policy
+	}
+}
+
+// WithChaos enables the chaos mode of the TaskManager, injecting random delays and panics for
+// stress testing, as set by SetChaos.
+func WithChaos(config Chaos) Option {
+	return func(o *options) {
+		o.chaos = config
+	}
+}
+
+// WithJobHistory sets the number of recent runs recorded in the history of each job, and whether
+// the history is persisted after every run, as set by SetJobHistory.
+func WithJobHistory(size int, persist bool) Option {
+	return func(o *options) {
+		o.historySize = size
+		o.persistHistory = persist
+	}
+}
+
+// WithTaskMiddleware adds middleware wrapping every task execution, as added by
+// UseTaskMiddleware.
+func WithTaskMiddleware(middleware ...TaskMiddleware) Option {
+	return func(o *options) {
+		o.middleware = append(o.middleware, middleware...)
+	}
+}
+
+// WithTracerProvider sets the OpenTelemetry tracer provider of the TaskManager, as set by
+// SetTracerProvider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = provider
+	}
+}
+
+// WithPanicHandler sets the handler called when a task panics, as set by SetPanicHandler.
+func WithPanicHandler(handler PanicHandler) Option {
+	return func(o *options) {
+		o.panicHandler = handler
+	}
+}
+
+// WithRecoverDisabled disables the recovery of panicking tasks, as set by SetRecoverDisabled.
+func WithRecoverDisabled() Option {
+	return func(o *options) {
+		o.recoverDisabled = true
+	}
+}
+
+// WithCPUAccounting enables measuring the CPU time of tasks, as set by SetCPUAccounting.
+func WithCPUAccounting() Option {
+	return func(o *options) {
+		o.cpuAccounting = true
+	}
+}
+
+// WithWorkerLifecycle sets the callbacks called as workers start and stop, as set by
+// SetWorkerLifecycle.
+func WithWorkerLifecycle(lifecycle WorkerLifecycle) Option {
+	return func(o *options) {
+		o.workerLifecycle = &lifecycle
+	}
+}
+
+// WithDiscardRemovedTasks sets whether dispatched tasks of removed jobs which have not started are
+// discarded, as set by SetDiscardRemovedTasks.
+func WithDiscardRemovedTasks(discard bool) Option {
+	return func(o *options) {
+		o.discardRemoved = discard
+	}
+}
+
+// WithIDGenerator sets the generator of the IDs of jobs scheduled without an ID of their own, as set
+// by SetIDGenerator.
+func WithIDGenerator(generator IDGenerator) Option {
+	return func(o *options) {
+		o.idGenerator = generator
+	}
+}
+
+// WithJobStore sets the store persisting the TaskManager's jobs, as set by SetJobStore.
+func WithJobStore(store JobStore) Option {
+	return func(o *options) {
+		o.store = store
+	}
+}
+
+// WithDistributedLock sets the lock to hold for dispatching jobs, as set by SetDistributedLock.
+func WithDistributedLock(lock DistributedLock, ttl time.Duration) Option {
+	return func(o *options) {
+		o.lock = lock
+		o.lockTTL = ttl
+	}
+}
+
+// WithEventLog sets the writer to which the events are written as JSON lines, as set by
+// SetEventLog.
+func WithEventLog(w io.Writer) Option {
+	return func(o *options) {
+		o.eventLog = w
+	}
+}
+
+// WithDispatcher sets the dispatcher publishing the runs of Remote jobs, as set by SetDispatcher.
+func WithDispatcher(dispatcher Dispatcher, ackTimeout time.Duration) Option {
+	return func(o *options) {
+		o.dispatcher = dispatcher
+		o.ackTimeout = ackTimeout
+	}
+}
+
+// WithLogger sets the logger of the TaskManager and its worker pool, e.g. a *slog.Logger or a
+// zerolog logger adapted with NewZerologLogger. Defaults to the package logger set by SetLogger.
+func WithLogger(l Logger) Option {
+	return func(o *options) {
+		if l != nil {
+			o.logger = l
+		}
+	}
+}
+
+// WithClock sets the source of time used for scheduling jobs. Defaults to the real time.
+func WithClock(c Clock) Option {
+	return func(o *options) {
+		if c != nil {
+			o.clock = c
+		}
+	}
+}
+
+// defaultOptions returns the options of a TaskManager created with New without options.
+func defaultOptions() options {
+	return options{
+		workerCount:        runtime.NumCPU(),
+		maxWorkers:         maxWorkerCount,
+		downScaleThreshold: defaultUtilizationThreshold,
+		downScaleInterval:  defaultDownScaleMinInterval,
+		taskBufferSize:     defaultBufferedSize,
+		taskShards:         1,
+		errorBufferSize:    defaultBufferedSize,
+		scaleInterval:      defaultScaleInterval,
+		overrunRuns:        defaultOverrunRuns,
+		historySize:        defaultJobHistory,
+		logger:             zerologLogger{},
+		clock:              realClock{},
+	}
+}
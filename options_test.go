@@ -0,0 +1,80 @@
+package taskman
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		manager := New()
+		defer manager.Stop()
+
+		assert.Equal(t, runtime.NumCPU(), manager.WorkerCount(), "Expected a worker per CPU")
+		assert.Equal(t, defaultBufferedSize, manager.taskQueue.capacity())
+		assert.Len(t, manager.taskQueue.shards, 1, "Expected a single shard")
+		assert.Equal(t, defaultBufferedSize, getChannelBufferSize(manager.errorChan))
+		assert.Equal(t, defaultScaleInterval, manager.scaleInterval)
+		assert.Equal(t, int32(maxWorkerCount), manager.maxWorkers.Load())
+		assert.Zero(t, manager.idleScaleDown.Load())
+		assert.Equal(t, defaultUtilizationThreshold, manager.workerPool.downScaleThreshold.Load())
+		assert.Equal(t, defaultDownScaleMinInterval, manager.workerPool.downScaleInterval.Load())
+		assert.Nil(t, manager.retryPolicy)
+		assert.Zero(t, manager.maxJobs)
+		assert.Nil(t, manager.dispatchLimiter)
+		assert.Zero(t, manager.deadLetterThreshold)
+		assert.True(t, manager.IsLeader())
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		policy := &RetryPolicy{MaxAttempts: 3}
+		store := NewMemoryJobStore()
+		lock := NewMemoryLock()
+		manager := New(
+			WithWorkers(3),
+			WithTaskBuffer(5),
+			WithTaskShards(2),
+			WithErrorBuffer(7),
+			WithScaleInterval(10*time.Second),
+			WithRetryPolicy(policy),
+			WithMaxJobs(100, OverflowBlock),
+			WithMaxDispatchRate(10, 2),
+			WithDeadLetterThreshold(4),
+			WithJobStore(store),
+			WithDistributedLock(lock.Holder(), time.Second),
+		)
+		defer manager.Stop()
+
+		assert.Equal(t, 3, manager.WorkerCount())
+		assert.Equal(t, 5, manager.taskQueue.capacity())
+		assert.Len(t, manager.taskQueue.shards, 2)
+		assert.Equal(t, 7, getChannelBufferSize(manager.errorChan))
+		assert.Equal(t, 10*time.Second, manager.scaleInterval)
+		assert.Equal(t, policy, manager.retryPolicy)
+		assert.Equal(t, 100, manager.maxJobs)
+		assert.Equal(t, OverflowBlock, manager.overflowPolicy)
+		if assert.NotNil(t, manager.dispatchLimiter) {
+			assert.Equal(t, rate.Limit(10), manager.dispatchLimiter.Limit())
+			assert.Equal(t, 2, manager.dispatchLimiter.Burst())
+		}
+		assert.Equal(t, 4, manager.deadLetterThreshold)
+		assert.Equal(t, store, manager.store)
+		assert.True(t, manager.IsLeader(), "Expected the lock to be acquired")
+	})
+
+	t.Run("Invalid options", func(t *testing.T) {
+		assert.Panics(t, func() { New(WithWorkers(0)) }, "Expected panic for zero workers")
+		assert.Panics(t, func() { New(WithTaskBuffer(-1)) }, "Expected panic for negative buffer size")
+		assert.Panics(t, func() { New(WithTaskShards(0)) }, "Expected panic for zero task shards")
+		assert.Panics(t, func() { New(WithScaleInterval(0)) }, "Expected panic for zero scale interval")
+		assert.Panics(t, func() { New(WithRetryPolicy(&RetryPolicy{Jitter: 2})) }, "Expected panic for invalid retry policy")
+		assert.Panics(t, func() { New(WithMaxJobs(-1, OverflowReject)) }, "Expected panic for negative max jobs")
+		assert.Panics(t, func() { New(WithMaxDispatchRate(10, 0)) }, "Expected panic for zero dispatch burst")
+		assert.Panics(t, func() { New(WithDeadLetterThreshold(-1)) }, "Expected panic for negative dead letter threshold")
+		assert.Panics(t, func() { New(WithDistributedLock(NewMemoryLock().Holder(), 0)) }, "Expected panic for invalid lock ttl")
+	})
+}
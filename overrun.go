@@ -0,0 +1,83 @@
+package taskman
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// defaultOverrunRuns is the default number of runs a job must have completed before it is
+// detected as overrunning.
+const defaultOverrunRuns = 3
+
+// OverrunError is the warning reported on the error channel when a job is detected as overrunning,
+// its average run duration exceeding its cadence, so that its runs can never keep up with its
+// schedule. It is reported once each time the job starts overrunning.
+type OverrunError struct {
+	JobID           string        // ID of the overrunning job
+	Cadence         time.Duration // Cadence of the job
+	AverageDuration time.Duration // Average duration of the job's runs
+	Runs            int           // Number of runs the average is taken over
+
+	Metadata map[string]string // Metadata of the job, must not be modified
+}
+
+// Error returns the warning message.
+func (e *OverrunError) Error() string {
+	return fmt.Sprintf("job %s overrunning, average run duration %v exceeds cadence %v over %d runs",
+		e.JobID, e.AverageDuration, e.Cadence, e.Runs)
+}
+
+// SetOverrunDetection sets the number of completed runs after which a job whose average run
+// duration exceeds its cadence is detected as overrunning. Overrunning jobs are reported with an
+// *OverrunError on the error channel and an EventJobOverrun, marked in their JobStats and counted
+// in the JobsOverrunning metric. Only jobs executing at a cadence are checked. A number of 0
+// disables the detection. Defaults to 3.
+func (tm *TaskManager) SetOverrunDetection(runs int) error {
+	if runs < 0 {
+		return errors.New("invalid overrun detection runs, must not be negative")
+	}
+	tm.overrunRuns.Store(int32(runs))
+	return nil
+}
+
+// checkOverrun checks whether the job of a finished run has started overrunning, reporting it if
+// so.
+func (tm *TaskManager) checkOverrun(job *Job) {
+	if job.state == nil || job.once {
+		return
+	}
+	stats, started := job.state.stats.checkOverrun(job.Cadence, int(tm.overrunRuns.Load()))
+	if !started {
+		return
+	}
+
+	tm.logger.Warn("Job overrunning, average run duration exceeds its cadence", "jobID", job.ID,
+		"averageDuration", stats.AverageDuration, "cadence", job.Cadence)
+	err := &OverrunError{
+		JobID:           job.ID,
+		Cadence:         job.Cadence,
+		AverageDuration: stats.AverageDuration,
+		Runs:            stats.TotalRuns,
+		Metadata:        job.Metadata,
+	}
+	select {
+	case tm.errorFan.in <- err:
+	default:
+		// Error channel full, drop the warning
+		tm.errorFan.dropped.Add(1)
+	}
+	tm.emitEvent(Event{Type: EventJobOverrun, JobID: job.ID, Err: err, Metadata: job.Metadata})
+}
+
+// overrunningJobs returns the number of jobs in the queue detected as overrunning.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) overrunningJobs() int {
+	var n int
+	for _, job := range tm.jobQueue.jobs {
+		if job.state != nil && job.state.stats.overrunning() {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,80 @@
+package taskman
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobStatsCheckOverrun(t *testing.T) {
+	js := &jobStats{}
+	js.recordRun(time.Now(), 20*time.Millisecond, nil)
+
+	_, started := js.checkOverrun(10*time.Millisecond, 2)
+	assert.False(t, started, "Expected no overrun before the minimum number of runs")
+
+	js.recordRun(time.Now(), 20*time.Millisecond, nil)
+	stats, started := js.checkOverrun(10*time.Millisecond, 2)
+	assert.True(t, started, "Expected an overrun once the average exceeds the cadence")
+	assert.True(t, stats.Overrunning)
+	_, started = js.checkOverrun(10*time.Millisecond, 2)
+	assert.False(t, started, "Expected the overrun to be reported once")
+
+	stats, _ = js.checkOverrun(time.Second, 2)
+	assert.False(t, stats.Overrunning, "Expected no overrun once the average is within the cadence")
+	stats, _ = js.checkOverrun(10*time.Millisecond, 0)
+	assert.False(t, stats.Overrunning, "Expected no overrun with the detection disabled")
+}
+
+func TestOverrunDetection(t *testing.T) {
+	manager := New(WithWorkers(4), WithOverrunDetection(2))
+	defer manager.Stop()
+	errs, unsubscribe := manager.SubscribeErrors(8)
+	defer unsubscribe()
+	events, unsubscribeEvents := manager.SubscribeEvents(64)
+	defer unsubscribeEvents()
+
+	job := Job{
+		ID:            "overrunning-job",
+		Cadence:       5 * time.Millisecond,
+		NextExec:      time.Now(),
+		OverlapPolicy: OverlapSkip,
+		Tasks: []Task{MockTask{executeFunc: func() error {
+			time.Sleep(15 * time.Millisecond)
+			return nil
+		}}},
+	}
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	select {
+	case err := <-errs:
+		var overrunErr *OverrunError
+		assert.True(t, errors.As(err, &overrunErr), "Expected an *OverrunError, got %v", err)
+		assert.Equal(t, job.ID, overrunErr.JobID)
+		assert.Equal(t, job.Cadence, overrunErr.Cadence)
+		assert.Greater(t, overrunErr.AverageDuration, job.Cadence)
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("Expected the overrunning job to be reported")
+	}
+	assert.Eventually(t, func() bool {
+		for {
+			select {
+			case event := <-events:
+				if event.Type == EventJobOverrun && event.JobID == job.ID {
+					return true
+				}
+			default:
+				return false
+			}
+		}
+	}, 50*time.Millisecond, time.Millisecond, "Expected an EventJobOverrun")
+
+	stats, err := manager.JobStats(job.ID)
+	assert.NoError(t, err)
+	assert.True(t, stats.Overrunning)
+	assert.Equal(t, 1, manager.Metrics().JobsOverrunning)
+
+	assert.Error(t, manager.SetOverrunDetection(-1), "Expected an error for a negative number of runs")
+}
@@ -0,0 +1,93 @@
+package taskman
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// preemptedKey is the context key of the preemption signal of a task, in the context passed to
+// the tasks of the default worker pool.
+type preemptedKey struct{}
+
+// Preempted returns a channel closed when the task should yield its worker to a task of a job of
+// higher Priority, which is waiting while no worker of the default worker pool is available.
+// Preemption is cooperative: long-running tasks of low priority may poll the channel and return
+// early, e.g. saving their progress to resume on their next run, while tasks ignoring it execute
+// as usual. Returns nil, a channel never closed, if the context carries no preemption signal, as
+// for the tasks of worker groups, dedicated and remote jobs.
+func Preempted(ctx context.Context) <-chan struct{} {
+	preempted, _ := ctx.Value(preemptedKey{}).(chan struct{})
+	return preempted
+}
+
+// preemption is the preemption signal of an executing task.
+type preemption struct {
+	priority  int           // Priority of the task's job
+	started   time.Time     // Time the task started executing
+	preempted chan struct{} // Channel closed to preempt the task
+}
+
+// preemptions holds the preemption signals of the executing tasks of the default worker pool.
+type preemptions struct {
+	mu    sync.Mutex
+	tasks map[*preemption]struct{}
+}
+
+// add registers an executing task of a job of the given priority, returning its signal.
+func (p *preemptions) add(priority int) *preemption {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.tasks == nil {
+		p.tasks = make(map[*preemption]struct{})
+	}
+	task := &preemption{priority: priority, started: time.Now(), preempted: make(chan struct{})}
+	p.tasks[task] = struct{}{}
+	return task
+}
+
+// remove unregisters a task as it finishes executing.
+func (p *preemptions) remove(task *preemption) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.tasks, task)
+}
+
+// preempt signals the executing task of the lowest priority below the given priority to yield,
+// preferring the task started last as it has the least work to lose. Signalled tasks are
+// unregistered, so that each task is preempted once. Returns false if no task is of lower
+// priority.
+func (p *preemptions) preempt(priority int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var target *preemption
+	for task := range p.tasks {
+		if task.priority >= priority {
+			continue
+		}
+		if target == nil || task.priority < target.priority ||
+			(task.priority == target.priority && task.started.After(target.started)) {
+			target = task
+		}
+	}
+	if target == nil {
+		return false
+	}
+	delete(p.tasks, target)
+	close(target.preempted)
+	return true
+}
+
+// preemptFor preempts an executing task of lower priority than the job if the job's task is about
+// to wait for a worker of the default worker pool, none being available.
+func (tm *TaskManager) preemptFor(job *Job, queue *taskQueue) {
+	if !job.pooled() || queue != tm.taskQueue {
+		return
+	}
+	if tm.workerPool.availableWorkers() > int32(queue.len()) {
+		return
+	}
+	if tm.preemptions.preempt(job.Priority) {
+		tm.logger.Debug("Preempted task for job of higher priority", "jobID", job.ID, "priority", job.Priority)
+	}
+}
@@ -0,0 +1,58 @@
+package taskman
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreempted(t *testing.T) {
+	assert.Nil(t, Preempted(context.Background()), "Expected no preemption signal outside of tasks")
+
+	manager := New(WithWorkerBounds(1, 1))
+	defer manager.Stop()
+
+	// A low-priority job saturates the default worker pool, yielding once preempted
+	preempted := make(chan struct{})
+	low := Job{
+		ID:       "low-job",
+		Cadence:  time.Hour,
+		NextExec: time.Now().Add(time.Hour),
+		Priority: -1,
+		Tasks: []Task{SimpleContextTask{function: func(ctx context.Context) error {
+			select {
+			case <-Preempted(ctx):
+				close(preempted)
+			case <-ctx.Done():
+			}
+			return nil
+		}}},
+	}
+	assert.NoError(t, manager.ScheduleJob(low))
+	assert.NoError(t, manager.TriggerJob(low.ID))
+	assert.Eventually(t, func() bool { return manager.workerPool.availableWorkers() == 0 }, time.Second, time.Millisecond)
+
+	// A job of the same priority waits without preempting the task
+	same := getMockedJob(1, "same-job", time.Hour, time.Hour)
+	same.Priority = -1
+	assert.NoError(t, manager.ScheduleJob(same))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := manager.RunJobNow(ctx, same.ID)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Expected the job to wait for the worker")
+
+	// A job of higher priority preempts the task, and executes on the worker it yields
+	high := getMockedJob(1, "high-job", time.Hour, time.Hour)
+	assert.NoError(t, manager.ScheduleJob(high))
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = manager.RunJobNow(ctx, high.ID)
+	assert.NoError(t, err, "Expected the job to execute once the task yielded")
+	select {
+	case <-preempted:
+	default:
+		t.Fatal("Expected the low-priority task to be preempted")
+	}
+}
@@ -7,78 +7,104 @@ import (
 )
 
 // priorityQueue implements heap.Interface and holds Jobs.
-// Priority is determined by the NextExec time of the Job.
-type priorityQueue []*Job
+// Priority is determined by the NextExec time of the Job. Jobs are indexed by ID, making lookups
+// by ID O(1) and removals by ID O(log n).
+type priorityQueue struct {
+	jobs []*Job          // The heap of jobs
+	byID map[string]*Job // The jobs in the heap by ID
+}
 
 // Interface implementation
 
 // Len returns the length of the heap.
-func (pq priorityQueue) Len() int { return len(pq) }
+func (pq *priorityQueue) Len() int { return len(pq.jobs) }
 
-// Less prioritizes jobs with earlier NextExec times.
-func (pq priorityQueue) Less(i, j int) bool {
-	return pq[i].NextExec.Before(pq[j].NextExec)
+// Less prioritizes jobs with earlier NextExec times. Jobs with a run delayed by their overlap
+// policy, or waiting for their dependencies, are placed after all other jobs, since they cannot be
+// dispatched until a run completes.
+func (pq *priorityQueue) Less(i, j int) bool {
+	if pq.jobs[i].blocked() != pq.jobs[j].blocked() {
+		return !pq.jobs[i].blocked()
+	}
+	// Compare by the wall clock alone, as NextExec may carry a monotonic reading
+	return pq.jobs[i].NextExec.Before(pq.jobs[j].NextExec.Round(0))
 }
 
 // Swap swaps two jobs in the heap.
-func (pq priorityQueue) Swap(i, j int) {
-	pq[i], pq[j] = pq[j], pq[i]
-	pq[i].index = i // Maintain index within the heap.
-	pq[j].index = j
+func (pq *priorityQueue) Swap(i, j int) {
+	pq.jobs[i], pq.jobs[j] = pq.jobs[j], pq.jobs[i]
+	pq.jobs[i].index = i // Maintain index within the heap.
+	pq.jobs[j].index = j
 }
 
 // Push adds a job to the heap.
 func (pq *priorityQueue) Push(x interface{}) {
-	n := len(*pq)
 	job := x.(*Job)
-	job.index = n
-	*pq = append(*pq, job)
+	job.index = len(pq.jobs)
+	pq.jobs = append(pq.jobs, job)
+	if pq.byID == nil {
+		pq.byID = make(map[string]*Job)
+	}
+	pq.byID[job.ID] = job
 }
 
 // Pop removes and returns the job with the earliest NextExec time.
 func (pq *priorityQueue) Pop() interface{} {
-	old := *pq
-	n := len(old)
-	job := old[n-1]
-	job.index = -1 // For safety.
-	*pq = old[0 : n-1]
+	n := len(pq.jobs)
+	job := pq.jobs[n-1]
+	pq.jobs[n-1] = nil // Avoid keeping a reference to the job.
+	job.index = -1     // For safety.
+	pq.jobs = pq.jobs[0 : n-1]
+	delete(pq.byID, job.ID)
 	return job
 }
 
 // Custom functionality
 
+// contains reports whether the exact job is currently in the queue, as opposed to another job with
+// the same ID, e.g. one which has replaced it.
+func (pq *priorityQueue) contains(job *Job) bool {
+	return job.index >= 0 && job.index < len(pq.jobs) && pq.jobs[job.index] == job
+}
+
 // JobInQueue finds whether a job with the jobID is currently in the queue, and returns the job's
 // index if found.
 func (pq *priorityQueue) JobInQueue(jobID string) (int, error) {
-	for _, job := range *pq {
-		if job.ID == jobID {
-			return job.index, nil
-		}
+	job, ok := pq.byID[jobID]
+	if !ok {
+		return 0, errors.New("job not found")
 	}
-	return 0, errors.New("job not found")
+	return job.index, nil
 }
 
 // Peek returns the job with the earliest NextExec time.
 func (pq *priorityQueue) Peek() *Job {
-	if len(*pq) == 0 {
+	if len(pq.jobs) == 0 {
 		return nil
 	}
-	return (*pq)[0]
+	return pq.jobs[0]
 }
 
 // RemoveByID finds a job in the priorityQueue by ID, and removes it if found.
 func (pq *priorityQueue) RemoveByID(jobID string) error {
-	for i, job := range *pq {
-		if job.ID == jobID {
-			heap.Remove(pq, i)
-			return nil
-		}
+	job, ok := pq.byID[jobID]
+	if !ok {
+		return errors.New("job not found")
 	}
-	return errors.New("job not found")
+	heap.Remove(pq, job.index)
+	return nil
+}
+
+// Replace replaces the job at index with a job of the same ID, which takes over its position.
+func (pq *priorityQueue) Replace(index int, job *Job) {
+	job.index = index
+	pq.jobs[index] = job
+	pq.byID[job.ID] = job
 }
 
 // Update modifies the NextExec time of a job in the heap.
 func (pq *priorityQueue) Update(job *Job, newNextExec time.Time) {
 	job.NextExec = newNextExec
+	job.scheduled = newNextExec
 	heap.Fix(pq, job.index)
 }
@@ -2,6 +2,7 @@ package taskman
 
 import (
 	"container/heap"
+	"fmt"
 	"testing"
 	"time"
 
@@ -25,6 +26,22 @@ func TestPushAndPop(t *testing.T) {
 	assert.Equal(t, "job3", heap.Pop(pq).(*Job).ID, "Expected job3 to be popped last")
 }
 
+func TestDelayedJobsOrderedLast(t *testing.T) {
+	pq := &priorityQueue{}
+	heap.Init(pq)
+
+	delayedJob := &Job{ID: "delayed", NextExec: time.Now(), delayed: true}
+	laterJob := &Job{ID: "later", NextExec: time.Now().Add(10 * time.Second)}
+	heap.Push(pq, delayedJob)
+	heap.Push(pq, laterJob)
+
+	assert.Equal(t, "later", pq.Peek().ID, "Expected delayed job to be ordered after later jobs")
+
+	delayedJob.delayed = false
+	heap.Fix(pq, delayedJob.index)
+	assert.Equal(t, "delayed", pq.Peek().ID, "Expected released job to be ordered by NextExec")
+}
+
 func TestJobInQueue(t *testing.T) {
 	pq := &priorityQueue{}
 	heap.Init(pq)
@@ -40,6 +57,22 @@ func TestJobInQueue(t *testing.T) {
 	assert.Error(t, err, "Expected job2 to not be found")
 }
 
+func TestContains(t *testing.T) {
+	pq := &priorityQueue{}
+	heap.Init(pq)
+
+	job := &Job{ID: "job1", NextExec: time.Now().Add(10 * time.Second)}
+	heap.Push(pq, job)
+	assert.True(t, pq.contains(job), "Expected job1 to be contained in the queue")
+
+	// A different job with the same ID is not the same job
+	replacement := &Job{ID: "job1", NextExec: job.NextExec, index: job.index}
+	assert.False(t, pq.contains(replacement), "Expected replacement job to not be contained in the queue")
+
+	heap.Remove(pq, job.index)
+	assert.False(t, pq.contains(job), "Expected removed job to not be contained in the queue")
+}
+
 func TestPeek(t *testing.T) {
 	pq := &priorityQueue{}
 	heap.Init(pq)
@@ -87,3 +120,45 @@ func TestUpdate(t *testing.T) {
 	assert.Equal(t, "job1", pq.Peek().ID, "Expected job1 to be peeked first after update")
 	assert.Equal(t, newNextExec, pq.Peek().NextExec, "Expected job1 to have updated NextExec time")
 }
+
+func TestReplace(t *testing.T) {
+	pq := &priorityQueue{}
+	heap.Init(pq)
+
+	job := &Job{ID: "job1", NextExec: time.Now()}
+	heap.Push(pq, job)
+	heap.Push(pq, &Job{ID: "job2", NextExec: time.Now().Add(5 * time.Second)})
+
+	replacement := &Job{ID: "job1", NextExec: job.NextExec}
+	pq.Replace(job.index, replacement)
+	assert.True(t, pq.contains(replacement), "Expected replacement job to be contained in the queue")
+	assert.False(t, pq.contains(job), "Expected replaced job to not be contained in the queue")
+
+	assert.NoError(t, pq.RemoveByID("job1"))
+	assert.False(t, pq.contains(replacement), "Expected replacement job to be removed by ID")
+	assert.Equal(t, "job2", pq.Peek().ID)
+}
+
+func TestIndexByID(t *testing.T) {
+	pq := &priorityQueue{}
+	heap.Init(pq)
+
+	now := time.Now()
+	for i := range 100 {
+		heap.Push(pq, &Job{ID: fmt.Sprintf("job%d", i), NextExec: now.Add(time.Duration(i%10) * time.Second)})
+	}
+	// Remove every other job, and pop a few, leaving the index consistent with the heap
+	for i := 0; i < 100; i += 2 {
+		assert.NoError(t, pq.RemoveByID(fmt.Sprintf("job%d", i)))
+	}
+	for range 5 {
+		heap.Pop(pq)
+	}
+
+	assert.Equal(t, len(pq.jobs), len(pq.byID), "Expected every job in the heap to be indexed")
+	for _, job := range pq.jobs {
+		index, err := pq.JobInQueue(job.ID)
+		assert.NoError(t, err)
+		assert.Same(t, job, pq.jobs[index], "Expected job %s to be found at its index", job.ID)
+	}
+}
@@ -0,0 +1,88 @@
+package taskman
+
+import (
+	"errors"
+)
+
+// ErrQueueFull is returned when scheduling a job in a TaskManager whose queue has reached its
+// maximum number of jobs, with the OverflowReject policy.
+var ErrQueueFull = errors.New("job queue is full")
+
+// OverflowPolicy determines what happens when a job is scheduled in a TaskManager whose queue has
+// reached its maximum number of jobs.
+type OverflowPolicy int
+
+const (
+	// OverflowReject rejects the new job, returning ErrQueueFull.
+	OverflowReject OverflowPolicy = iota
+	// OverflowDropOldest removes the job which has been scheduled the longest to make room for the
+	// new job, calling the job removal hooks for the dropped job.
+	OverflowDropOldest
+	// OverflowBlock blocks until a job is removed from the queue, or until the TaskManager is
+	// stopped.
+	OverflowBlock
+)
+
+// SetMaxJobs limits the number of jobs in the TaskManager's queue to maxJobs, applying the policy
+// when a job is scheduled in a full queue. Jobs already in the queue are kept, even if they exceed
+// the limit. A limit of 0 removes the limit.
+func (tm *TaskManager) SetMaxJobs(maxJobs int, policy OverflowPolicy) error {
+	if maxJobs < 0 {
+		return errors.New("invalid max jobs, must not be negative")
+	}
+	if policy < OverflowReject || policy > OverflowBlock {
+		return errors.New("invalid overflow policy")
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	tm.maxJobs = maxJobs
+	tm.overflowPolicy = policy
+	// Wake any blocked callers, the new limit may leave room for their jobs
+	tm.queueSpace.Broadcast()
+	return nil
+}
+
+// queueFull returns true if the queue has reached its maximum number of jobs.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) queueFull() bool {
+	return tm.maxJobs > 0 && tm.jobQueue.Len() >= tm.maxJobs
+}
+
+// makeRoom applies the overflow policy until there is room in the queue for the job, returning
+// the job dropped to make room for it, if any. Blocked callers revalidate the job once woken, as
+// the queue may have changed while waiting.
+// Note: must be called while holding the TaskManager's lock, which is released while waiting.
+func (tm *TaskManager) makeRoom(job Job) (*Job, error) {
+	for tm.queueFull() {
+		switch tm.overflowPolicy {
+		case OverflowReject:
+			return nil, ErrQueueFull
+		case OverflowDropOldest:
+			oldest := tm.jobQueue.jobs[0]
+			for _, j := range tm.jobQueue.jobs {
+				if j.seq < oldest.seq {
+					oldest = j
+				}
+			}
+			if err := tm.removeJob(oldest); err != nil {
+				return nil, err
+			}
+			if tm.store != nil {
+				tm.unpersistJob(tm.store, oldest.ID)
+			}
+			oldest.cancel()
+			tm.logger.Warn("Dropped oldest job, job queue is full", "jobID", oldest.ID)
+			return oldest, nil
+		case OverflowBlock:
+			tm.queueSpace.Wait()
+			if tm.ctx.Err() != nil {
+				return nil, ErrManagerStopped
+			}
+			if err := tm.validateJob(job); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,115 @@
+package taskman
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxJobs(t *testing.T) {
+	t.Run("Reject", func(t *testing.T) {
+		manager := New(WithWorkers(1), WithMaxJobs(2, OverflowReject))
+		defer manager.Stop()
+
+		for i := range 2 {
+			assert.NoError(t, manager.ScheduleJob(getMockedJob(1, fmt.Sprintf("job-%d", i), time.Minute, time.Minute)))
+		}
+		err := manager.ScheduleJob(getMockedJob(1, "rejected-job", time.Minute, time.Minute))
+		assert.ErrorIs(t, err, ErrQueueFull)
+		assert.Len(t, manager.Jobs(), 2)
+
+		assert.NoError(t, manager.RemoveJob("job-0"))
+		assert.NoError(t, manager.ScheduleJob(getMockedJob(1, "rejected-job", time.Minute, time.Minute)), "Expected room after removing a job")
+	})
+
+	t.Run("Drop oldest", func(t *testing.T) {
+		manager := New(WithWorkers(1), WithMaxJobs(2, OverflowDropOldest))
+		defer manager.Stop()
+
+		removed := make(chan string, 1)
+		manager.OnJobRemoved(func(jobID string) { removed <- jobID })
+
+		// The oldest job is the first scheduled, not the first to execute
+		assert.NoError(t, manager.ScheduleJob(getMockedJob(1, "oldest-job", time.Minute, 2*time.Minute)))
+		assert.NoError(t, manager.ScheduleJob(getMockedJob(1, "newer-job", time.Minute, time.Minute)))
+		assert.NoError(t, manager.ScheduleJob(getMockedJob(1, "newest-job", time.Minute, time.Minute)))
+
+		_, err := manager.Job("oldest-job")
+		assert.Error(t, err, "Expected the oldest job to be dropped")
+		assert.Len(t, manager.Jobs(), 2)
+		select {
+		case jobID := <-removed:
+			assert.Equal(t, "oldest-job", jobID)
+		default:
+			t.Fatal("Expected the removal hook to be called for the dropped job")
+		}
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		manager := New(WithWorkers(1), WithMaxJobs(1, OverflowBlock))
+		defer manager.Stop()
+
+		assert.NoError(t, manager.ScheduleJob(getMockedJob(1, "first-job", time.Minute, time.Minute)))
+
+		scheduled := make(chan error, 1)
+		go func() {
+			scheduled <- manager.ScheduleJob(getMockedJob(1, "blocked-job", time.Minute, time.Minute))
+		}()
+		select {
+		case <-scheduled:
+			t.Fatal("Expected scheduling to block while the queue is full")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		assert.NoError(t, manager.RemoveJob("first-job"))
+		select {
+		case err := <-scheduled:
+			assert.NoError(t, err, "Expected the blocked job to be scheduled once room was made")
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("Expected scheduling to unblock after a job was removed")
+		}
+		_, err := manager.Job("blocked-job")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Block until stopped", func(t *testing.T) {
+		manager := New(WithWorkers(1), WithMaxJobs(1, OverflowBlock))
+
+		assert.NoError(t, manager.ScheduleJob(getMockedJob(1, "first-job", time.Minute, time.Minute)))
+
+		scheduled := make(chan error, 1)
+		go func() {
+			scheduled <- manager.ScheduleJob(getMockedJob(1, "blocked-job", time.Minute, time.Minute))
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		manager.Stop()
+		select {
+		case err := <-scheduled:
+			assert.Error(t, err, "Expected an error scheduling in a stopped manager")
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("Expected scheduling to unblock when the manager is stopped")
+		}
+	})
+
+	t.Run("Once jobs make room", func(t *testing.T) {
+		manager := New(WithWorkers(1), WithMaxJobs(1, OverflowBlock))
+		defer manager.Stop()
+
+		_, err := manager.ScheduleOnce(MockTask{ID: "once-task"}, 10*time.Millisecond)
+		assert.NoError(t, err)
+		assert.NoError(t, manager.ScheduleJob(getMockedJob(1, "waiting-job", time.Minute, time.Minute)),
+			"Expected the job to be scheduled once the one-shot job executed")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		manager := NewCustom(1, 1, 1*time.Minute)
+		defer manager.Stop()
+
+		assert.Error(t, manager.SetMaxJobs(-1, OverflowReject), "Expected error for negative max jobs")
+		assert.Error(t, manager.SetMaxJobs(1, OverflowPolicy(42)), "Expected error for unknown overflow policy")
+		assert.NoError(t, manager.SetMaxJobs(0, OverflowReject), "Expected no error removing the limit")
+	})
+}
@@ -0,0 +1,76 @@
+package taskman
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// SetMaxDispatchRate limits the rate at which the TaskManager dispatches job runs, across all jobs,
+// to r runs per second with bursts of up to burst runs. Runs due while the limit is reached are
+// deferred until the limit allows them, bounding the load put on downstream systems when many jobs
+// are due at once. A rate of 0 removes the limit.
+// Note: runs dispatched with TriggerJob are not rate limited.
+func (tm *TaskManager) SetMaxDispatchRate(r rate.Limit, burst int) error {
+	if r < 0 {
+		return errors.New("invalid dispatch rate, must not be negative")
+	}
+	if r > 0 && burst <= 0 {
+		return errors.New("invalid dispatch burst, must be greater than 0")
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	if r == 0 || r == rate.Inf {
+		tm.dispatchLimiter = nil
+		return nil
+	}
+	tm.dispatchLimiter = rate.NewLimiter(r, burst)
+	return nil
+}
+
+// setDispatchRate sets the maximum dispatch rate of the job's runs, with bursts of a single run.
+// A rate of 0 removes the limit.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (s *jobState) setDispatchRate(r rate.Limit, now time.Time) {
+	switch {
+	case r <= 0 || r == rate.Inf:
+		s.limiter = nil
+	case s.limiter == nil:
+		s.limiter = rate.NewLimiter(r, 1)
+	default:
+		s.limiter.SetLimitAt(now, r)
+	}
+}
+
+// dispatchDelay returns how long a due run of the job must be deferred to stay within the global
+// and per-job dispatch rates. If the run may be dispatched, 0 is returned and the run is counted
+// against the rates.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) dispatchDelay(job *Job, now time.Time) time.Duration {
+	delay := max(limiterDelay(tm.dispatchLimiter, now), limiterDelay(job.state.limiter, now))
+	if delay > 0 {
+		return delay
+	}
+	if tm.dispatchLimiter != nil {
+		tm.dispatchLimiter.AllowN(now, 1)
+	}
+	if job.state.limiter != nil {
+		job.state.limiter.AllowN(now, 1)
+	}
+	return 0
+}
+
+// limiterDelay returns how long until the limiter has a token available, without consuming it.
+func limiterDelay(limiter *rate.Limiter, now time.Time) time.Duration {
+	if limiter == nil {
+		return 0
+	}
+	tokens := limiter.TokensAt(now)
+	if tokens >= 1 {
+		return 0
+	}
+	// Round up, so that the token is available once the delay has passed
+	return time.Duration((1-tokens)/float64(limiter.Limit())*float64(time.Second)) + time.Nanosecond
+}
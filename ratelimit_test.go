@@ -0,0 +1,86 @@
+package taskman
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestLimiterDelay(t *testing.T) {
+	assert.Zero(t, limiterDelay(nil, time.Now()), "Expected no delay without a limiter")
+
+	now := time.Now()
+	limiter := rate.NewLimiter(10, 1)
+	assert.Zero(t, limiterDelay(limiter, now), "Expected no delay with a token available")
+	assert.True(t, limiter.AllowN(now, 1))
+
+	delay := limiterDelay(limiter, now)
+	assert.InDelta(t, float64(100*time.Millisecond), float64(delay), float64(time.Microsecond))
+	assert.True(t, limiter.AllowN(now.Add(delay), 1), "Expected a token to be available after the delay")
+}
+
+func TestMaxDispatchRate(t *testing.T) {
+	t.Run("Global", func(t *testing.T) {
+		manager := New(WithWorkers(4), WithMaxDispatchRate(20, 2))
+		defer manager.Stop()
+
+		// All jobs are due at once, but only a burst of 2 is dispatched, then one run per 50ms
+		var executions atomic.Int32
+		for i := range 5 {
+			job := Job{ID: fmt.Sprintf("job-%d", i), Cadence: time.Minute, NextExec: time.Now(),
+				Tasks: []Task{MockTask{executeFunc: func() error {
+					executions.Add(1)
+					return nil
+				}}}}
+			assert.NoError(t, manager.ScheduleJob(job))
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, int32(2), executions.Load(), "Expected the burst to be dispatched")
+		time.Sleep(230 * time.Millisecond)
+		assert.Equal(t, int32(5), executions.Load(), "Expected deferred runs to be dispatched")
+	})
+
+	t.Run("Per job", func(t *testing.T) {
+		manager := NewCustom(4, 8, 1*time.Minute)
+		defer manager.Stop()
+
+		var limited, unlimited atomic.Int32
+		job := Job{ID: "limited-job", Cadence: time.Millisecond, NextExec: time.Now(), MaxDispatchRate: 50,
+			Tasks: []Task{MockTask{executeFunc: func() error {
+				limited.Add(1)
+				return nil
+			}}}}
+		assert.NoError(t, manager.ScheduleJob(job))
+		other := Job{ID: "unlimited-job", Cadence: 5 * time.Millisecond, NextExec: time.Now(),
+			Tasks: []Task{MockTask{executeFunc: func() error {
+				unlimited.Add(1)
+				return nil
+			}}}}
+		assert.NoError(t, manager.ScheduleJob(other))
+
+		// Runs of the limited job at 0ms, 20ms, 40ms, 60ms, 80ms and 100ms
+		time.Sleep(110 * time.Millisecond)
+		assert.GreaterOrEqual(t, limited.Load(), int32(4), "Expected the limited job to keep executing")
+		assert.LessOrEqual(t, limited.Load(), int32(7), "Expected the job's runs to be rate limited")
+		assert.GreaterOrEqual(t, unlimited.Load(), int32(15), "Expected other jobs to not be limited")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		manager := NewCustom(1, 1, 1*time.Minute)
+		defer manager.Stop()
+
+		assert.Error(t, manager.SetMaxDispatchRate(-1, 1), "Expected error for negative rate")
+		assert.Error(t, manager.SetMaxDispatchRate(10, 0), "Expected error for zero burst")
+		assert.NoError(t, manager.SetMaxDispatchRate(0, 0), "Expected no error removing the limit")
+		assert.Nil(t, manager.dispatchLimiter)
+
+		job := getMockedJob(1, "invalid-rate-job", time.Second, 0)
+		job.MaxDispatchRate = -1
+		assert.Error(t, manager.ScheduleJob(job), "Expected error for negative job dispatch rate")
+	})
+}
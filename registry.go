@@ -0,0 +1,96 @@
+package taskman
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SerializableTask is a Task which can be serialized, allowing jobs persisted in a JobStore to
+// be restored with their tasks. The task's type must be registered with RegisterTaskType in the
+// TaskManager restoring the task.
+type SerializableTask interface {
+	Task
+	// TaskType returns the name the task's type is registered with.
+	TaskType() string
+	// MarshalTask serializes the task, for the registered factory to deserialize.
+	MarshalTask() ([]byte, error)
+}
+
+// TaskFactory creates a task of a registered type from the data serialized by MarshalTask.
+type TaskFactory func(data []byte) (Task, error)
+
+// TaskRecord is the persisted state of a SerializableTask.
+type TaskRecord struct {
+	Type string // Registered name of the task's type
+	Data []byte // The serialized task
+}
+
+// JSONTaskFactory returns a TaskFactory deserializing tasks of type T from JSON, for tasks which
+// are serialized with json.Marshal in MarshalTask.
+func JSONTaskFactory[T Task]() TaskFactory {
+	return func(data []byte) (Task, error) {
+		var task T
+		if err := json.Unmarshal(data, &task); err != nil {
+			return nil, err
+		}
+		return task, nil
+	}
+}
+
+// RegisterTaskType registers a factory for deserializing tasks of the named type. Register all
+// task types before restoring jobs from a JobStore. HTTPTask is registered as HTTPTaskType in every
+// TaskManager.
+func (tm *TaskManager) RegisterTaskType(name string, factory TaskFactory) error {
+	if name == "" {
+		return errors.New("task type name cannot be empty")
+	}
+	if factory == nil {
+		return errors.New("task factory cannot be nil")
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	if _, ok := tm.taskTypes[name]; ok {
+		return fmt.Errorf("task type %s already registered", name)
+	}
+	tm.taskTypes[name] = factory
+	return nil
+}
+
+// marshalTasks serializes the tasks, or returns nil if any of the tasks is not serializable.
+func marshalTasks(tasks []Task) ([]TaskRecord, error) {
+	records := make([]TaskRecord, 0, len(tasks))
+	for _, task := range tasks {
+		serializable, ok := task.(SerializableTask)
+		if !ok {
+			return nil, nil
+		}
+		data, err := serializable.MarshalTask()
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize task of type %s: %w", serializable.TaskType(), err)
+		}
+		records = append(records, TaskRecord{Type: serializable.TaskType(), Data: data})
+	}
+	return records, nil
+}
+
+// unmarshalTasks deserializes tasks using the registered factories.
+func (tm *TaskManager) unmarshalTasks(records []TaskRecord) ([]Task, error) {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	tasks := make([]Task, 0, len(records))
+	for _, record := range records {
+		factory, ok := tm.taskTypes[record.Type]
+		if !ok {
+			return nil, fmt.Errorf("task type %s not registered", record.Type)
+		}
+		task, err := factory(record.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to deserialize task of type %s: %w", record.Type, err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
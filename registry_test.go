@@ -0,0 +1,88 @@
+package taskman
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// greetExecutions counts the executions of restored greetTasks.
+var greetExecutions atomic.Int32
+
+// greetTask is a serializable task for testing.
+type greetTask struct {
+	Message string `json:"message"`
+}
+
+func (gt greetTask) Execute() error {
+	if gt.Message == "restored" {
+		greetExecutions.Add(1)
+	}
+	return nil
+}
+
+func (gt greetTask) TaskType() string { return "greet" }
+
+func (gt greetTask) MarshalTask() ([]byte, error) { return json.Marshal(gt) }
+
+func TestRegisterTaskType(t *testing.T) {
+	manager := NewCustom(1, 1, 1*time.Minute)
+	defer manager.Stop()
+
+	assert.NoError(t, manager.RegisterTaskType("greet", JSONTaskFactory[greetTask]()), "Expected no error registering task type")
+	assert.Error(t, manager.RegisterTaskType("greet", JSONTaskFactory[greetTask]()), "Expected error registering duplicate task type")
+	assert.Error(t, manager.RegisterTaskType("", JSONTaskFactory[greetTask]()), "Expected error registering unnamed task type")
+	assert.Error(t, manager.RegisterTaskType("nil", nil), "Expected error registering nil factory")
+
+	t.Run("Round trip", func(t *testing.T) {
+		records, err := marshalTasks([]Task{greetTask{Message: "hello"}, greetTask{Message: "world"}})
+		assert.NoError(t, err)
+		assert.Equal(t, "greet", records[0].Type)
+
+		tasks, err := manager.unmarshalTasks(records)
+		assert.NoError(t, err, "Expected no error deserializing tasks")
+		assert.Equal(t, []Task{greetTask{Message: "hello"}, greetTask{Message: "world"}}, tasks)
+
+		_, err = manager.unmarshalTasks([]TaskRecord{{Type: "unknown"}})
+		assert.Error(t, err, "Expected error deserializing unregistered task type")
+		_, err = manager.unmarshalTasks([]TaskRecord{{Type: "greet", Data: []byte("not json")}})
+		assert.Error(t, err, "Expected error deserializing invalid data")
+	})
+
+	t.Run("Not serializable", func(t *testing.T) {
+		records, err := marshalTasks([]Task{greetTask{Message: "hello"}, MockTask{ID: "mock"}})
+		assert.NoError(t, err)
+		assert.Nil(t, records, "Expected no records if any task is not serializable")
+	})
+}
+
+func TestRestoreSerializedJobs(t *testing.T) {
+	store := NewMemoryJobStore()
+
+	// Persist a job with serializable tasks
+	manager := NewCustom(1, 1, 1*time.Minute)
+	manager.SetJobStore(store)
+	job := Job{ID: "greet-job", Cadence: 10 * time.Millisecond, NextExec: time.Now().Add(time.Hour), Tasks: []Task{greetTask{Message: "restored"}}}
+	assert.NoError(t, manager.ScheduleJob(job))
+	manager.Stop()
+
+	record, err := store.Load("greet-job")
+	assert.NoError(t, err)
+	assert.Len(t, record.Tasks, 1, "Expected serialized tasks in the record")
+
+	// Restore the job and its tasks without a resolve function
+	record.NextExec = time.Now()
+	assert.NoError(t, store.Save(record))
+	manager = NewCustom(1, 1, 1*time.Minute)
+	defer manager.Stop()
+	manager.SetJobStore(store)
+	assert.Error(t, manager.RestoreJobs(nil), "Expected error restoring unregistered task type")
+
+	assert.NoError(t, manager.RegisterTaskType("greet", JSONTaskFactory[greetTask]()))
+	assert.NoError(t, manager.RestoreJobs(nil), "Expected no error restoring serialized tasks")
+	time.Sleep(5 * time.Millisecond)
+	assert.Greater(t, greetExecutions.Load(), int32(0), "Expected the restored task to execute")
+}
@@ -0,0 +1,251 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/xid"
+)
+
+// ErrAckTimeout is the error of the tasks of a remote run which was not completed within the
+// Dispatcher's acknowledgement timeout.
+var ErrAckTimeout = errors.New("remote run not acknowledged in time")
+
+// RemoteRun is a run of a Remote job, published by a Dispatcher for a remote worker to execute,
+// e.g. with TaskManager.ExecuteRemoteRun.
+type RemoteRun struct {
+	ID            string            // Unique ID of the run, e.g. for deduplicating redelivered runs
+	JobID         string            // ID of the job
+	Tasks         []TaskRecord      // Serialized tasks of the run, in the order of the job's tasks
+	ExecutionMode ExecutionMode     // How the tasks of the run are executed
+	Metadata      map[string]string // Metadata of the job, if any
+	Dispatched    time.Time         // Time the run was dispatched, according to the TaskManager's clock
+}
+
+// Dispatcher publishes the runs of Remote jobs to a message broker, e.g. a NATS subject or a Kafka
+// topic, for a fleet of remote workers to execute, with the TaskManager keeping their schedule.
+// Implement the interface for the broker at hand. NewMemoryDispatcher provides a dispatcher
+// delivering runs within one process.
+type Dispatcher interface {
+	// Dispatch publishes the run, returning an error if it could not be published. Once a remote
+	// worker has executed the run, e.g. when its acknowledgement is received, complete is called
+	// with the errors of the run's tasks, in the order of the run's tasks and nil for tasks which
+	// succeeded. Calls of complete after the first, or after the acknowledgement timeout, are
+	// ignored.
+	Dispatch(ctx context.Context, run RemoteRun, complete func(taskErrs []error)) error
+}
+
+// SetDispatcher sets the dispatcher publishing the runs of Remote jobs, instead of executing them
+// in the worker pool. Runs not completed within ackTimeout fail with ErrAckTimeout, so that an
+// unacknowledged run does not block the following ones, 0 for no timeout. Set the dispatcher
+// before scheduling any Remote jobs.
+func (tm *TaskManager) SetDispatcher(dispatcher Dispatcher, ackTimeout time.Duration) error {
+	if ackTimeout < 0 {
+		return errors.New("acknowledgement timeout cannot be negative")
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	tm.dispatcher = dispatcher
+	tm.ackTimeout = ackTimeout
+	return nil
+}
+
+// validateRemote returns an error if the Remote job cannot be dispatched.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) validateRemote(job Job) error {
+	if !job.Remote {
+		return nil
+	}
+	if tm.dispatcher == nil {
+		return errors.New("remote jobs require a dispatcher, see SetDispatcher")
+	}
+	if job.Group != "" || job.Dedicated {
+		return errors.New("remote jobs cannot be assigned to a worker group or be dedicated")
+	}
+	if job.ConcurrencyGroup != "" {
+		return errors.New("remote jobs cannot be assigned to a concurrency group")
+	}
+	records, err := marshalTasks(job.Tasks)
+	if err != nil {
+		return err
+	}
+	if records == nil {
+		return errors.New("tasks of remote jobs must be serializable")
+	}
+	return nil
+}
+
+// publishRun publishes a started run of a Remote job with the dispatcher, completing the run with
+// the errors reported by the remote worker.
+func (tm *TaskManager) publishRun(job *Job, tasks []jobTask) {
+	run := tasks[0].run
+	tm.RLock()
+	dispatcher, ackTimeout := tm.dispatcher, tm.ackTimeout
+	tm.RUnlock()
+
+	var once sync.Once
+	completed := make(chan struct{}) // Closed once the run completes, ending the wait for its ack
+	complete := func(taskErrs []error) {
+		once.Do(func() {
+			close(completed)
+			tm.completeRemoteRun(run, tasks, taskErrs)
+		})
+	}
+
+	taskList := make([]Task, len(tasks))
+	for i, task := range tasks {
+		taskList[i] = task.task
+	}
+	records, err := marshalTasks(taskList)
+	if err == nil && records == nil {
+		err = errors.New("tasks of remote jobs must be serializable")
+	}
+	if err == nil {
+		remote := RemoteRun{
+			ID:            xid.New().String(),
+			JobID:         job.ID,
+			Tasks:         records,
+			ExecutionMode: job.ExecutionMode,
+			Metadata:      job.Metadata,
+			Dispatched:    run.start,
+		}
+		if ackTimeout > 0 {
+			// Time out waiting for the ack on the TaskManager's clock
+			timeout := tm.clock.After(ackTimeout)
+			go func() {
+				select {
+				case <-timeout:
+					complete(repeatErr(ErrAckTimeout, len(tasks)))
+				case <-completed:
+				}
+			}()
+		}
+		err = dispatcher.Dispatch(run.ctx, remote, complete)
+	}
+	if err != nil {
+		tm.logger.Warn("Failed to dispatch remote run", "jobID", job.ID, "error", err)
+		complete(repeatErr(fmt.Errorf("failed to dispatch remote run: %w", err), len(tasks)))
+	}
+}
+
+// completeRemoteRun records the errors of the tasks of a remote run, finishing the run.
+func (tm *TaskManager) completeRemoteRun(run *jobRun, tasks []jobTask, taskErrs []error) {
+	duration := time.Since(run.began)
+	for i, task := range tasks {
+		var err error
+		if i < len(taskErrs) && taskErrs[i] != nil {
+			err = &TaskError{
+				JobID:     task.jobID(),
+				TaskIndex: task.index,
+				TaskID:    task.taskID(),
+				Attempt:   1,
+				Time:      run.start,
+				Err:       taskErrs[i],
+				Metadata:  task.metadata(),
+			}
+		}
+		tm.emitEvent(Event{
+			Type:      EventTaskCompleted,
+			JobID:     task.jobID(),
+			TaskIndex: task.index,
+			TaskID:    task.taskID(),
+			Err:       err,
+			Metadata:  task.metadata(),
+		})
+		run.taskExecuted(task.index, duration, nil, err)
+		if err != nil {
+			run.taskFailed(task.taskID(), err)
+		}
+		run.taskFinished()
+	}
+}
+
+// repeatErr returns a slice of n copies of err.
+func repeatErr(err error, n int) []error {
+	errs := make([]error, n)
+	for i := range errs {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ExecuteRemoteRun executes a run published by a Dispatcher, e.g. in a remote worker, returning
+// the errors of its tasks for completing the run, nil for tasks which succeeded or were skipped
+// after an error of a run stopping on errors. The tasks are deserialized with the factories
+// registered with RegisterTaskType, and executed according to the run's execution mode, with the
+// job's metadata in their context.
+func (tm *TaskManager) ExecuteRemoteRun(ctx context.Context, run RemoteRun) []error {
+	errs := make([]error, len(run.Tasks))
+	tasks := make([]Task, len(run.Tasks))
+	for i, record := range run.Tasks {
+		deserialized, err := tm.unmarshalTasks([]TaskRecord{record})
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		tasks[i] = deserialized[0]
+	}
+	ctx = withJobMetadata(ctx, run.Metadata)
+
+	execute := func(i int) {
+		if tasks[i] != nil {
+			errs[i] = executeTask(ctx, TaskExecution{JobID: run.JobID, TaskIndex: i, TaskID: taskIDOf(tasks[i]), Attempt: 1, Task: tasks[i]})
+		}
+	}
+	if run.ExecutionMode == ExecutionParallel {
+		var wg sync.WaitGroup
+		for i := range tasks {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				execute(i)
+			}()
+		}
+		wg.Wait()
+		return errs
+	}
+	for i := range tasks {
+		execute(i)
+		if errs[i] != nil && run.ExecutionMode == ExecutionStopOnError {
+			break
+		}
+	}
+	return errs
+}
+
+// MemoryDispatcher is a Dispatcher delivering runs within one process, e.g. for tests or for
+// executing Remote jobs in worker processes sharing a TaskManager's process.
+type MemoryDispatcher struct {
+	deliveries chan Delivery
+}
+
+// Delivery is a run delivered by a MemoryDispatcher, to be completed once executed.
+type Delivery struct {
+	Run      RemoteRun              // The run to execute
+	Complete func(taskErrs []error) // Completes the run with the errors of its tasks
+}
+
+// NewMemoryDispatcher creates a MemoryDispatcher buffering up to bufferSize undelivered runs, past
+// which Dispatch blocks.
+func NewMemoryDispatcher(bufferSize int) *MemoryDispatcher {
+	return &MemoryDispatcher{deliveries: make(chan Delivery, bufferSize)}
+}
+
+// Dispatch delivers the run, blocking until it is buffered or received, or the context is done.
+func (d *MemoryDispatcher) Dispatch(ctx context.Context, run RemoteRun, complete func(taskErrs []error)) error {
+	select {
+	case d.deliveries <- Delivery{Run: run, Complete: complete}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Deliveries returns the channel receiving the dispatched runs.
+func (d *MemoryDispatcher) Deliveries() <-chan Delivery {
+	return d.deliveries
+}
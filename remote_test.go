@@ -0,0 +1,131 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteJob(t *testing.T) {
+	dispatcher := NewMemoryDispatcher(4)
+	manager := New(WithWorkers(1), WithDispatcher(dispatcher, time.Second))
+	defer manager.Stop()
+	assert.NoError(t, manager.RegisterTaskType("greet", JSONTaskFactory[greetTask]()))
+
+	job := Job{
+		ID:            "remote-job",
+		Cadence:       time.Hour,
+		NextExec:      time.Now().Add(time.Hour),
+		Tasks:         []Task{greetTask{Message: "hello"}, greetTask{Message: "world"}},
+		ExecutionMode: ExecutionSequential,
+		Metadata:      map[string]string{"tenant": "acme"},
+		Remote:        true,
+	}
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	// A worker executes the published run and acknowledges it with its tasks' errors
+	go func() {
+		delivery := <-dispatcher.Deliveries()
+		delivery.Complete(manager.ExecuteRemoteRun(context.Background(), delivery.Run))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	result, err := manager.RunJobNow(ctx, job.ID)
+	assert.NoError(t, err)
+	assert.NoError(t, result.Err())
+	assert.Len(t, result.TaskResults, 2)
+
+	t.Run("Failed tasks", func(t *testing.T) {
+		failing := errors.New("remote failure")
+		go func() {
+			delivery := <-dispatcher.Deliveries()
+			assert.Equal(t, "remote-job", delivery.Run.JobID)
+			assert.Equal(t, ExecutionSequential, delivery.Run.ExecutionMode)
+			assert.Equal(t, map[string]string{"tenant": "acme"}, delivery.Run.Metadata)
+			assert.Len(t, delivery.Run.Tasks, 2)
+			delivery.Complete([]error{nil, failing})
+			delivery.Complete(nil) // Ignored after the first call
+		}()
+
+		result, err := manager.RunJobNow(ctx, job.ID)
+		assert.NoError(t, err)
+		var taskErr *TaskError
+		if assert.ErrorAs(t, result.Err(), &taskErr) {
+			assert.Equal(t, 1, taskErr.TaskIndex)
+			assert.ErrorIs(t, taskErr, failing)
+		}
+		assert.NoError(t, result.TaskResults[0].Err)
+	})
+
+	t.Run("Acknowledgement timeout", func(t *testing.T) {
+		clock := newFakeClock(time.Now())
+		manager := New(WithWorkers(1), WithClock(clock), WithDispatcher(NewMemoryDispatcher(1), time.Minute))
+		defer manager.Stop()
+		assert.NoError(t, manager.ScheduleJob(Job{ID: "unacknowledged", Cadence: time.Hour, NextExec: clock.Now().Add(time.Hour), Tasks: []Task{greetTask{}}, Remote: true}))
+
+		results := make(chan JobResult, 1)
+		go func() {
+			result, err := manager.RunJobNow(ctx, "unacknowledged")
+			assert.NoError(t, err)
+			results <- result
+		}()
+
+		// The run waits for its ack until the timeout has passed on the TaskManager's clock
+		select {
+		case <-results:
+			t.Fatal("Expected the run to wait for its ack")
+		case <-time.After(20 * time.Millisecond):
+		}
+		var result JobResult
+		assert.Eventually(t, func() bool {
+			clock.Advance(time.Minute)
+			select {
+			case result = <-results:
+				return true
+			default:
+				return false
+			}
+		}, time.Second, time.Millisecond)
+		assert.ErrorIs(t, result.Err(), ErrAckTimeout)
+	})
+}
+
+func TestRemoteJobValidation(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+
+	job := Job{ID: "remote-job", Cadence: time.Hour, NextExec: time.Now().Add(time.Hour), Tasks: []Task{greetTask{}}, Remote: true}
+	assert.Error(t, manager.ScheduleJob(job), "Expected error scheduling a remote job without a dispatcher")
+
+	assert.Error(t, manager.SetDispatcher(NewMemoryDispatcher(1), -time.Second), "Expected error setting a negative timeout")
+	assert.NoError(t, manager.SetDispatcher(NewMemoryDispatcher(1), 0))
+
+	dedicated := job
+	dedicated.Dedicated = true
+	assert.Error(t, manager.ScheduleJob(dedicated), "Expected error scheduling a dedicated remote job")
+	unserializable := job
+	unserializable.Tasks = []Task{MockTask{ID: "mock"}}
+	assert.Error(t, manager.ScheduleJob(unserializable), "Expected error scheduling a remote job with unserializable tasks")
+
+	assert.NoError(t, manager.ScheduleJob(job))
+	assert.Error(t, manager.AddTaskToJob(job.ID, MockTask{ID: "mock"}), "Expected error adding an unserializable task")
+	assert.NoError(t, manager.AddTaskToJob(job.ID, greetTask{Message: "added"}))
+}
+
+func TestExecuteRemoteRun(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+	assert.NoError(t, manager.RegisterTaskType("greet", JSONTaskFactory[greetTask]()))
+
+	records, err := marshalTasks([]Task{greetTask{Message: "hello"}})
+	assert.NoError(t, err)
+	run := RemoteRun{JobID: "remote-job", Tasks: append(records, TaskRecord{Type: "unknown"})}
+	errs := manager.ExecuteRemoteRun(context.Background(), run)
+	assert.Len(t, errs, 2)
+	assert.NoError(t, errs[0])
+	assert.Error(t, errs[1], "Expected error executing an unregistered task type")
+}
@@ -0,0 +1,146 @@
+package taskman
+
+import (
+	"errors"
+	"math"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+)
+
+// cgroupMemoryFiles are the files holding the memory limit of the process' cgroup, for cgroup v2
+// and v1 respectively.
+var cgroupMemoryFiles = []string{
+	"/sys/fs/cgroup/memory.max",
+	"/sys/fs/cgroup/memory/memory.limit_in_bytes",
+}
+
+// ResourceBudget bounds the automatic scaling of the worker pool by the resources of the process,
+// in addition to the worker bounds, so that tasks heavy on CPU or memory are not given more
+// workers than the process can sustain. The budget never scales the pool below its minimum worker
+// count, and only blocks scaling up: workers already running are kept when the process exceeds
+// its goroutine or memory budget. Zero fields are not limited.
+//
+// The memory limit MemoryFraction applies to is the limit of the process' cgroup if it has one,
+// e.g. the memory limit of its container, otherwise the Go runtime's soft memory limit, if set
+// with GOMEMLIMIT or debug.SetMemoryLimit. With both MaxMemory and MemoryFraction set, the lower
+// of the two applies.
+type ResourceBudget struct {
+	WorkersPerCPU  float64 // Max workers per CPU usable by the process, as set by GOMAXPROCS
+	MaxGoroutines  int     // Goroutines of the process above which the pool is not scaled up
+	MaxMemory      uint64  // Bytes of memory used by the Go runtime above which the pool is not scaled up
+	MemoryFraction float64 // Fraction of the memory limit of the process above which the pool is not scaled up
+}
+
+// resourceUsage is a sample of the resources used by the process.
+type resourceUsage struct {
+	procs      int    // CPUs usable by the process
+	goroutines int    // Number of goroutines
+	memory     uint64 // Bytes of memory mapped by the Go runtime and not released to the OS
+}
+
+// SetResourceBudget sets the resource budget bounding the automatic scaling of the worker pool.
+// The zero ResourceBudget, the default, disables the budget. The memory limit of a MemoryFraction
+// is read when the budget is set, and the fraction is ignored if the process has no memory limit.
+func (tm *TaskManager) SetResourceBudget(budget ResourceBudget) error {
+	if budget.WorkersPerCPU < 0 || budget.MaxGoroutines < 0 {
+		return errors.New("invalid resource budget, limits must not be negative")
+	}
+	if budget.MemoryFraction < 0 || budget.MemoryFraction > 1 {
+		return errors.New("invalid resource budget, memory fraction must be between 0 and 1")
+	}
+
+	memoryLimit := budget.MaxMemory
+	if budget.MemoryFraction > 0 {
+		limit := processMemoryLimit()
+		if limit == 0 {
+			tm.logger.Warn("Ignoring memory fraction of resource budget, the process has no memory limit")
+		}
+		fractionLimit := uint64(budget.MemoryFraction * float64(limit))
+		if limit > 0 && (memoryLimit == 0 || fractionLimit < memoryLimit) {
+			memoryLimit = fractionLimit
+		}
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	tm.resourceBudget = budget
+	tm.memoryBudget = memoryLimit
+	return nil
+}
+
+// budgetWorkers returns the number of workers needed capped by the resource budget, if set. The
+// workers per CPU bound the count, while a budget of goroutines or memory exceeded keeps the pool
+// from scaling up beyond its current target.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) budgetWorkers(workersNeeded int32) int32 {
+	budget := tm.resourceBudget
+	if budget == (ResourceBudget{}) {
+		return workersNeeded
+	}
+	usage := tm.sampleResources()
+
+	budgeted := workersNeeded
+	if budget.WorkersPerCPU > 0 {
+		budgeted = min(budgeted, int32(math.Ceil(budget.WorkersPerCPU*float64(usage.procs))))
+	}
+	current := tm.workerPool.targetWorkerCount()
+	if budgeted > current {
+		overGoroutines := budget.MaxGoroutines > 0 && usage.goroutines >= budget.MaxGoroutines
+		overMemory := tm.memoryBudget > 0 && usage.memory >= tm.memoryBudget
+		if overGoroutines || overMemory {
+			budgeted = current
+		}
+	}
+	if budgeted < workersNeeded {
+		tm.logger.Debug("Capped worker scaling by resource budget", "needed", workersNeeded, "budgeted", budgeted,
+			"goroutines", usage.goroutines, "memory", usage.memory)
+	}
+	return budgeted
+}
+
+// readResourceUsage samples the resources used by the process from the Go runtime.
+func readResourceUsage() resourceUsage {
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(samples)
+	var memory uint64
+	if samples[0].Value.Kind() == metrics.KindUint64 && samples[1].Value.Kind() == metrics.KindUint64 {
+		memory = samples[0].Value.Uint64() - samples[1].Value.Uint64()
+	}
+	return resourceUsage{
+		procs:      runtime.GOMAXPROCS(0),
+		goroutines: runtime.NumGoroutine(),
+		memory:     memory,
+	}
+}
+
+// processMemoryLimit returns the memory limit of the process' cgroup, or the Go runtime's soft
+// memory limit if the process has no cgroup limit, or 0 if neither is set.
+func processMemoryLimit() uint64 {
+	for _, path := range cgroupMemoryFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		value := strings.TrimSpace(string(data))
+		if value == "max" {
+			break
+		}
+		// Unlimited cgroup v1 limits are reported as a page-aligned max int64
+		limit, err := strconv.ParseUint(value, 10, 64)
+		if err == nil && limit < math.MaxInt64/2 {
+			return limit
+		}
+		break
+	}
+	if limit := debug.SetMemoryLimit(-1); limit < math.MaxInt64 {
+		return uint64(limit)
+	}
+	return 0
+}
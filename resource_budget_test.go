@@ -0,0 +1,60 @@
+package taskman
+
+import (
+	"math"
+	"runtime/debug"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceBudget(t *testing.T) {
+	manager := New(WithWorkerBounds(1, 64))
+	defer manager.Stop()
+	assert.Error(t, manager.SetResourceBudget(ResourceBudget{WorkersPerCPU: -1}), "Expected error for a negative limit")
+	assert.Error(t, manager.SetResourceBudget(ResourceBudget{MemoryFraction: 1.5}), "Expected error for a fraction above 1")
+
+	var mu sync.Mutex
+	usage := resourceUsage{procs: 2, goroutines: 10}
+	manager.Lock()
+	manager.sampleResources = func() resourceUsage {
+		mu.Lock()
+		defer mu.Unlock()
+		return usage
+	}
+	manager.Unlock()
+	target := func() int32 {
+		return manager.workerPool.targetWorkerCount()
+	}
+
+	// Workers are capped by the CPUs of the process, rather than scaled for the widest job
+	assert.NoError(t, manager.SetResourceBudget(ResourceBudget{WorkersPerCPU: 2, MaxGoroutines: 100}))
+	assert.NoError(t, manager.ScheduleJob(getMockedJob(10, "wide-job", time.Hour, time.Hour)))
+	assert.Eventually(t, func() bool { return target() == 4 }, time.Second, time.Millisecond, "Expected 4 workers for 2 CPUs")
+
+	// Exceeding the goroutine budget keeps the pool from scaling up further
+	mu.Lock()
+	usage = resourceUsage{procs: 8, goroutines: 100}
+	mu.Unlock()
+	assert.NoError(t, manager.ScheduleJob(getMockedJob(12, "wider-job", time.Hour, time.Hour)))
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int32(4), target(), "Expected no scaling up beyond the goroutine budget")
+
+	// Without a budget, the pool is scaled for the widest job
+	assert.NoError(t, manager.SetResourceBudget(ResourceBudget{}))
+	assert.NoError(t, manager.SetWorkerBounds(1, 64))
+	assert.Eventually(t, func() bool { return target() == 24 }, time.Second, time.Millisecond, "Expected workers for the widest job")
+}
+
+func TestResourceBudgetMemoryFraction(t *testing.T) {
+	defer debug.SetMemoryLimit(debug.SetMemoryLimit(1 << 30))
+
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+	assert.NoError(t, manager.SetResourceBudget(ResourceBudget{MemoryFraction: 0.5, MaxMemory: math.MaxUint64}))
+	manager.RLock()
+	defer manager.RUnlock()
+	assert.Equal(t, uint64(0.5*float64(processMemoryLimit())), manager.memoryBudget, "Expected the budget to be a fraction of the limit")
+}
@@ -0,0 +1,160 @@
+package taskman
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ResultTask is a Task which returns structured output along with its error. The TaskManager
+// executes such tasks with ExecuteResult, passing the context of the job like for a ContextTask,
+// and delivers their output as a Result to the subscriptions of SubscribeResults.
+type ResultTask interface {
+	Task
+	ExecuteResult(ctx context.Context) (map[string]any, error)
+}
+
+// Result is the outcome of an execution of a ResultTask of a job, after any retries.
+type Result struct {
+	JobID     string         // ID of the job the task belongs to
+	TaskIndex int            // Index of the task within the job's tasks
+	TaskID    string         // ID of the task, if it is a NamedTask
+	Time      time.Time      // Time the task finished, according to the TaskManager's clock
+	Duration  time.Duration  // Time the task took to execute, including retries
+	Data      map[string]any // Output of the task's last attempt
+	Err       error          // Error of the task's last attempt, nil if it succeeded
+}
+
+// ResultOverflowPolicy determines what happens to a result delivered to a subscription whose
+// channel's buffer is full, as its consumer is slow.
+type ResultOverflowPolicy int
+
+const (
+	// ResultDropNewest drops the new result, keeping the buffered ones. This is the default policy.
+	ResultDropNewest ResultOverflowPolicy = iota
+	// ResultDropOldest drops the oldest buffered result to make room for the new one, so that the
+	// buffer holds the most recent results.
+	ResultDropOldest
+	// ResultBlock blocks the worker delivering the result until the subscriber receives it, it
+	// unsubscribes, or the TaskManager is stopped, so that no result is lost. A slow subscriber
+	// then stalls the execution of ResultTasks.
+	ResultBlock
+)
+
+// SetResultOverflowPolicy sets what happens to results delivered to a full subscription of
+// SubscribeResults or SubscribeJobResults. Results dropped are counted in the DroppedResults
+// metric. Defaults to ResultDropNewest.
+func (tm *TaskManager) SetResultOverflowPolicy(policy ResultOverflowPolicy) error {
+	if policy < ResultDropNewest || policy > ResultBlock {
+		return fmt.Errorf("invalid result overflow policy %d", policy)
+	}
+	tm.results.policy.Store(int32(policy))
+	return nil
+}
+
+// SubscribeResults returns a new channel receiving the results of all executions of ResultTasks,
+// alongside their errors being delivered to the error channel. Like SubscribeErrors, every
+// subscription receives all results. While its channel's buffer of the given size is full, the
+// results delivered to a subscription are handled according to the result overflow policy, see
+// SetResultOverflowPolicy. The returned function unsubscribes, closing the channel, which is also
+// closed when the TaskManager stops.
+func (tm *TaskManager) SubscribeResults(bufferSize int) (<-chan Result, func()) {
+	return tm.results.subscribe(max(bufferSize, 0))
+}
+
+// SubscribeJobResults returns a new channel receiving the results of the executions of the
+// ResultTasks of the job with the given ID, e.g. for a component watching a single job, without
+// filtering the results of all jobs. The subscription otherwise behaves like those of
+// SubscribeResults, and is kept when the job is removed, receiving the results of any job later
+// scheduled with the ID.
+func (tm *TaskManager) SubscribeJobResults(jobID string, bufferSize int) (<-chan Result, func()) {
+	return tm.results.subscribeFiltered(max(bufferSize, 0), func(result Result) bool {
+		return result.JobID == jobID
+	})
+}
+
+// emitResult delivers the result of the task to the subscriptions, at the current time of the
+// TaskManager's clock. The result is passed to the task as well, if it receives its own results.
+func (tm *TaskManager) emitResult(task Task, result Result) {
+	result.Time = tm.clock.Now()
+	tm.results.emit(result)
+	if receiver, ok := task.(resultReceiver); ok {
+		receiver.receiveResult(result)
+	}
+}
+
+// resultValueKey is the key of the value returned by the function of a resultFuncTask, in the
+// Data of its results.
+const resultValueKey = "value"
+
+// TypedResult is the outcome of an execution of a function returning a value of type T, as
+// scheduled with ScheduleFuncResult.
+type TypedResult[T any] struct {
+	JobID    string        // ID of the job the function belongs to
+	Time     time.Time     // Time the function finished, according to the TaskManager's clock
+	Duration time.Duration // Time the function took to execute, including retries
+	Value    T             // Value returned by the function's last attempt
+	Err      error         // Error of the function's last attempt, nil if it succeeded
+}
+
+// resultReceiver is implemented by tasks receiving their own results, once per execution after
+// any retries.
+type resultReceiver interface {
+	receiveResult(result Result)
+}
+
+// resultFuncTask is a ResultTask executing a function returning a value of type T, passing its
+// typed results to a handler.
+type resultFuncTask[T any] struct {
+	function func(ctx context.Context) (T, error)
+	handle   func(result TypedResult[T])
+}
+
+// NewResultFuncTask returns a ResultTask executing the function, calling handle with the typed
+// result of every execution of the task, after any retries. The value is also delivered to the
+// subscriptions of SubscribeResults, as the "value" entry of the result's Data.
+func NewResultFuncTask[T any](function func(ctx context.Context) (T, error), handle func(result TypedResult[T])) ResultTask {
+	return resultFuncTask[T]{function: function, handle: handle}
+}
+
+// Execute executes the function with a background context, discarding its value.
+func (t resultFuncTask[T]) Execute() error {
+	_, err := t.function(context.Background())
+	return err
+}
+
+// ExecuteResult executes the function with the context, returning its value in the output.
+func (t resultFuncTask[T]) ExecuteResult(ctx context.Context) (map[string]any, error) {
+	value, err := t.function(ctx)
+	return map[string]any{resultValueKey: value}, err
+}
+
+// receiveResult passes the result to the handler, with the value of the function typed.
+func (t resultFuncTask[T]) receiveResult(result Result) {
+	if t.handle == nil {
+		return
+	}
+	// The value is missing, and left zero, if the function panicked
+	value, _ := result.Data[resultValueKey].(T)
+	t.handle(TypedResult[T]{
+		JobID:    result.JobID,
+		Time:     result.Time,
+		Duration: result.Duration,
+		Value:    value,
+		Err:      result.Err,
+	})
+}
+
+// ScheduleFuncResult takes a context-aware function returning a value of type T and adds it to the
+// Scheduler in a Job, calling handle with the typed result of every execution, so that values need
+// not be asserted from the Data of a Result. The handler is called from the worker executing the
+// function, and should return quickly. Creates and returns a randomized ID, used to identify
+// the Job within the task manager.
+func ScheduleFuncResult[T any](
+	s Scheduler,
+	function func(ctx context.Context) (T, error),
+	cadence time.Duration,
+	handle func(result TypedResult[T]),
+) (string, error) {
+	return s.ScheduleTask(NewResultFuncTask(function, handle), cadence)
+}
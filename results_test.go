@@ -0,0 +1,195 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// resultTask is a ResultTask executing a function.
+type resultTask struct {
+	function func(ctx context.Context) (map[string]any, error)
+}
+
+func (rt resultTask) Execute() error {
+	_, err := rt.function(context.Background())
+	return err
+}
+
+func (rt resultTask) ExecuteResult(ctx context.Context) (map[string]any, error) {
+	return rt.function(ctx)
+}
+
+func TestSubscribeResults(t *testing.T) {
+	manager := NewCustom(1, 4, 1*time.Minute)
+	results, unsubscribe := manager.SubscribeResults(4)
+	defer unsubscribe()
+
+	job := Job{
+		ID:       "result-job",
+		Cadence:  time.Hour,
+		NextExec: time.Now().Add(time.Hour),
+		Tasks: []Task{
+			resultTask{function: func(ctx context.Context) (map[string]any, error) {
+				assert.NotNil(t, ctx.Done(), "Expected the task to receive the job's context")
+				return map[string]any{"rows": 42}, nil
+			}},
+			MockTask{ID: "plain-task"},
+			resultTask{function: func(ctx context.Context) (map[string]any, error) {
+				return map[string]any{"rows": 0}, errors.New("query failed")
+			}},
+		},
+		ExecutionMode: ExecutionSequential,
+	}
+	assert.NoError(t, manager.ScheduleJob(job))
+	assert.NoError(t, manager.TriggerJob(job.ID))
+
+	// Results are delivered for result tasks only, in the order of execution of the sequential job
+	for _, expected := range []struct {
+		index int
+		rows  int
+		err   bool
+	}{{0, 42, false}, {2, 0, true}} {
+		select {
+		case result := <-results:
+			assert.Equal(t, job.ID, result.JobID)
+			assert.Equal(t, expected.index, result.TaskIndex)
+			assert.Equal(t, expected.rows, result.Data["rows"])
+			assert.Equal(t, expected.err, result.Err != nil, "Unexpected error of task %d", expected.index)
+			assert.False(t, result.Time.IsZero(), "Expected the result to be timestamped")
+		case <-time.After(50 * time.Millisecond):
+			t.Fatalf("Expected a result of task %d", expected.index)
+		}
+	}
+	select {
+	case err := <-manager.ErrorChannel():
+		assert.ErrorContains(t, err, "query failed", "Expected the failed result task's error to be reported as well")
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected an error to be reported")
+	}
+
+	manager.Stop()
+	_, ok := <-results
+	assert.False(t, ok, "Expected the subscription to be closed when the manager stops")
+}
+
+func TestSubscribeJobResults(t *testing.T) {
+	manager := NewCustom(1, 4, 1*time.Minute)
+	results, unsubscribe := manager.SubscribeJobResults("watched-job", 4)
+	defer unsubscribe()
+
+	rowsJob := func(id string, rows int) Job {
+		return Job{
+			ID:       id,
+			Cadence:  time.Hour,
+			NextExec: time.Now().Add(time.Hour),
+			Tasks: []Task{resultTask{function: func(ctx context.Context) (map[string]any, error) {
+				return map[string]any{"rows": rows}, nil
+			}}},
+		}
+	}
+	assert.NoError(t, manager.ScheduleJob(rowsJob("other-job", 1)))
+	assert.NoError(t, manager.ScheduleJob(rowsJob("watched-job", 2)))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := manager.RunJobNow(ctx, "other-job")
+	assert.NoError(t, err)
+	_, err = manager.RunJobNow(ctx, "watched-job")
+	assert.NoError(t, err)
+
+	// Only the results of the watched job are received
+	select {
+	case result := <-results:
+		assert.Equal(t, "watched-job", result.JobID)
+		assert.Equal(t, 2, result.Data["rows"])
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected a result of the watched job")
+	}
+	assert.Empty(t, results, "Expected no results of other jobs")
+
+	manager.Stop()
+	_, ok := <-results
+	assert.False(t, ok, "Expected the subscription to be closed when the manager stops")
+}
+
+func TestResultOverflowPolicy(t *testing.T) {
+	manager := New(WithWorkers(1), WithResultOverflowPolicy(ResultDropOldest))
+	defer manager.Stop()
+	assert.Error(t, manager.SetResultOverflowPolicy(ResultOverflowPolicy(42)), "Expected error for an unknown policy")
+
+	results, unsubscribe := manager.SubscribeJobResults("result-job", 1)
+	defer unsubscribe()
+	rows := 0
+	job := Job{
+		ID:       "result-job",
+		Cadence:  time.Hour,
+		NextExec: time.Now().Add(time.Hour),
+		Tasks: []Task{resultTask{function: func(ctx context.Context) (map[string]any, error) {
+			rows++
+			return map[string]any{"rows": rows}, nil
+		}}},
+	}
+	assert.NoError(t, manager.ScheduleJob(job))
+	for range 3 {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_, err := manager.RunJobNow(ctx, job.ID)
+		cancel()
+		assert.NoError(t, err)
+	}
+
+	// The subscription holds the latest result, the older ones were dropped
+	result := <-results
+	assert.Equal(t, 3, result.Data["rows"])
+	assert.Equal(t, 2, manager.Metrics().DroppedResults)
+}
+
+func TestScheduleFuncResult(t *testing.T) {
+	manager := NewCustom(1, 4, 1*time.Minute)
+	defer manager.Stop()
+	results, unsubscribe := manager.SubscribeResults(4)
+	defer unsubscribe()
+
+	type report struct {
+		Rows int
+	}
+	typed := make(chan TypedResult[report], 2)
+	fail := false
+	jobID, err := ScheduleFuncResult(manager, func(ctx context.Context) (report, error) {
+		if fail {
+			return report{}, errors.New("query failed")
+		}
+		return report{Rows: 42}, nil
+	}, time.Hour, func(result TypedResult[report]) {
+		typed <- result
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, manager.TriggerJob(jobID))
+	select {
+	case result := <-typed:
+		assert.Equal(t, jobID, result.JobID)
+		assert.Equal(t, report{Rows: 42}, result.Value, "Expected the typed value of the function")
+		assert.NoError(t, result.Err)
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected a typed result")
+	}
+	select {
+	case result := <-results:
+		assert.Equal(t, report{Rows: 42}, result.Data[resultValueKey], "Expected the value in the result's data")
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected a result")
+	}
+
+	fail = true
+	assert.NoError(t, manager.TriggerJob(jobID))
+	select {
+	case result := <-typed:
+		assert.Zero(t, result.Value)
+		assert.ErrorContains(t, result.Err, "query failed")
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected a typed result")
+	}
+}
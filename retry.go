@@ -0,0 +1,103 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// BackoffStrategy determines how the delay between retry attempts grows.
+type BackoffStrategy int
+
+const (
+	// BackoffConstant waits the initial delay between every attempt.
+	BackoffConstant BackoffStrategy = iota
+	// BackoffLinear increases the delay by the initial delay for every attempt.
+	BackoffLinear
+	// BackoffExponential doubles the delay for every attempt.
+	BackoffExponential
+)
+
+// RetryPolicy controls how failed task executions are retried before the error is reported. A
+// policy can be set per Job, or as a default for all jobs in the TaskManager.
+type RetryPolicy struct {
+	MaxAttempts  int             // Total number of attempts including the first, values <= 1 disable retries
+	Backoff      BackoffStrategy // Strategy for increasing the delay between attempts
+	InitialDelay time.Duration   // Delay before the first retry
+	MaxDelay     time.Duration   // Upper bound of the delay between attempts, 0 means no bound
+	Jitter       float64         // Fraction between 0 and 1 by which each delay is randomized, e.g. 0.1 for ±10%
+}
+
+// delay returns the time to wait before the next attempt, after the given number of failed
+// attempts.
+func (rp *RetryPolicy) delay(failedAttempts int) time.Duration {
+	delay := rp.InitialDelay
+	switch rp.Backoff {
+	case BackoffLinear:
+		delay = rp.InitialDelay * time.Duration(failedAttempts)
+	case BackoffExponential:
+		for i := 1; i < failedAttempts; i++ {
+			// Saturate rather than overflow the duration
+			if delay > math.MaxInt64/2 {
+				delay = math.MaxInt64
+				break
+			}
+			delay *= 2
+		}
+	}
+
+	if rp.Jitter > 0 {
+		// Randomize the delay within ±Jitter of its value
+		jittered := float64(delay) * (1 + rp.Jitter*(rand.Float64()*2-1))
+		delay = time.Duration(min(jittered, float64(math.MaxInt64/2)))
+	}
+	if rp.MaxDelay > 0 && delay > rp.MaxDelay {
+		delay = rp.MaxDelay
+	}
+	return delay
+}
+
+// validate validates the retry policy.
+func (rp *RetryPolicy) validate() error {
+	if rp.MaxAttempts < 0 {
+		return errors.New("invalid retry policy, max attempts must not be negative")
+	}
+	if rp.InitialDelay < 0 || rp.MaxDelay < 0 {
+		return errors.New("invalid retry policy, delays must not be negative")
+	}
+	if rp.Jitter < 0 || rp.Jitter > 1 {
+		return errors.New("invalid retry policy, jitter must be between 0 and 1")
+	}
+	if rp.Backoff < BackoffConstant || rp.Backoff > BackoffExponential {
+		return errors.New("invalid retry policy, unknown backoff strategy")
+	}
+	return nil
+}
+
+// executeWithRetry executes the function, retrying failed attempts according to the policy. The
+// function is passed the number of the attempt, starting at 1. The error of the last attempt is
+// returned if all attempts fail. Retries are abandoned if the context is cancelled while waiting
+// for the next attempt.
+func executeWithRetry(ctx context.Context, policy *RetryPolicy, logger Logger, execute func(attempt int) error) error {
+	err := execute(1)
+	if err == nil || policy == nil {
+		return err
+	}
+
+	for attempt := 1; attempt < policy.MaxAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			// The job was removed or the manager stopped, report the last error
+			return err
+		case <-time.After(policy.delay(attempt)):
+		}
+
+		logger.Debug("Retrying task", "attempt", attempt+1, "maxAttempts", policy.MaxAttempts, "error", err)
+		if err = execute(attempt + 1); err == nil {
+			return nil
+		}
+	}
+	return err
+}
@@ -0,0 +1,165 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	t.Run("Constant", func(t *testing.T) {
+		policy := RetryPolicy{Backoff: BackoffConstant, InitialDelay: 10 * time.Millisecond}
+		assert.Equal(t, 10*time.Millisecond, policy.delay(1))
+		assert.Equal(t, 10*time.Millisecond, policy.delay(5))
+	})
+
+	t.Run("Linear", func(t *testing.T) {
+		policy := RetryPolicy{Backoff: BackoffLinear, InitialDelay: 10 * time.Millisecond}
+		assert.Equal(t, 10*time.Millisecond, policy.delay(1))
+		assert.Equal(t, 30*time.Millisecond, policy.delay(3))
+	})
+
+	t.Run("Exponential", func(t *testing.T) {
+		policy := RetryPolicy{Backoff: BackoffExponential, InitialDelay: 10 * time.Millisecond}
+		assert.Equal(t, 10*time.Millisecond, policy.delay(1))
+		assert.Equal(t, 20*time.Millisecond, policy.delay(2))
+		assert.Equal(t, 80*time.Millisecond, policy.delay(4))
+		assert.Greater(t, policy.delay(1000), time.Duration(0), "Expected large attempt counts to not overflow")
+	})
+
+	t.Run("MaxDelay", func(t *testing.T) {
+		policy := RetryPolicy{Backoff: BackoffExponential, InitialDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+		assert.Equal(t, 50*time.Millisecond, policy.delay(10))
+	})
+
+	t.Run("Jitter", func(t *testing.T) {
+		policy := RetryPolicy{Backoff: BackoffConstant, InitialDelay: 100 * time.Millisecond, Jitter: 0.1}
+		for range 100 {
+			delay := policy.delay(1)
+			assert.GreaterOrEqual(t, delay, 90*time.Millisecond)
+			assert.LessOrEqual(t, delay, 110*time.Millisecond)
+		}
+	})
+}
+
+func TestRetryPolicyValidate(t *testing.T) {
+	assert.NoError(t, (&RetryPolicy{MaxAttempts: 3, InitialDelay: time.Second, Jitter: 0.5}).validate())
+	assert.Error(t, (&RetryPolicy{MaxAttempts: -1}).validate(), "Expected error for negative max attempts")
+	assert.Error(t, (&RetryPolicy{InitialDelay: -time.Second}).validate(), "Expected error for negative delay")
+	assert.Error(t, (&RetryPolicy{Jitter: 1.5}).validate(), "Expected error for jitter above 1")
+	assert.Error(t, (&RetryPolicy{Backoff: BackoffStrategy(42)}).validate(), "Expected error for unknown backoff")
+}
+
+func TestExecuteWithRetry(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond}
+
+	t.Run("Succeeds after retries", func(t *testing.T) {
+		var attempts int
+		err := executeWithRetry(context.Background(), policy, zerologLogger{}, func(attempt int) error {
+			attempts++
+			assert.Equal(t, attempts, attempt, "Expected attempt numbers to start at 1 and increase")
+			if attempts < 3 {
+				return errors.New("transient error")
+			}
+			return nil
+		})
+		assert.NoError(t, err, "Expected no error after successful retry")
+		assert.Equal(t, 3, attempts, "Expected 3 attempts")
+	})
+
+	t.Run("Fails after max attempts", func(t *testing.T) {
+		var attempts int
+		err := executeWithRetry(context.Background(), policy, zerologLogger{}, func(int) error {
+			attempts++
+			return errors.New("permanent error")
+		})
+		assert.EqualError(t, err, "permanent error")
+		assert.Equal(t, 3, attempts, "Expected 3 attempts")
+	})
+
+	t.Run("No policy", func(t *testing.T) {
+		var attempts int
+		err := executeWithRetry(context.Background(), nil, zerologLogger{}, func(int) error {
+			attempts++
+			return errors.New("error")
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts, "Expected a single attempt without a policy")
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var attempts int
+		err := executeWithRetry(ctx, &RetryPolicy{MaxAttempts: 5, InitialDelay: time.Hour}, zerologLogger{}, func(int) error {
+			attempts++
+			cancel()
+			return errors.New("error")
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts, "Expected retries to be abandoned when the context is cancelled")
+	})
+}
+
+func TestManagerRetryPolicy(t *testing.T) {
+	manager := NewCustom(2, 4, 1*time.Minute)
+	defer manager.Stop()
+
+	t.Run("Job policy", func(t *testing.T) {
+		var attempts atomic.Int32
+		job := getMockedJob(1, "retry-job", 1*time.Second, 0)
+		job.Tasks = []Task{MockTask{ID: "flaky-task", executeFunc: func() error {
+			if attempts.Add(1) < 3 {
+				return errors.New("transient error")
+			}
+			return nil
+		}}}
+		job.RetryPolicy = &RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond}
+		err := manager.ScheduleJob(job)
+		assert.NoError(t, err, "Expected no error scheduling job")
+
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, int32(3), attempts.Load(), "Expected the task to be attempted 3 times")
+		select {
+		case err := <-manager.ErrorChannel():
+			t.Fatalf("Expected no error to be reported after a successful retry, got %v", err)
+		default:
+		}
+	})
+
+	t.Run("Manager policy", func(t *testing.T) {
+		err := manager.SetRetryPolicy(&RetryPolicy{MaxAttempts: 2, InitialDelay: time.Millisecond})
+		assert.NoError(t, err, "Expected no error setting retry policy")
+
+		var attempts atomic.Int32
+		_, err = manager.ScheduleOnce(MockTask{ID: "failing-task", executeFunc: func() error {
+			attempts.Add(1)
+			return errors.New("permanent error")
+		}}, 0)
+		assert.NoError(t, err)
+
+		select {
+		case err := <-manager.ErrorChannel():
+			var taskErr *TaskError
+			assert.ErrorAs(t, err, &taskErr, "Expected a task error")
+			assert.Equal(t, 2, taskErr.Attempt, "Expected the error of the last attempt")
+			assert.EqualError(t, taskErr.Err, "permanent error")
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("Expected an error to be reported after all attempts failed")
+		}
+		assert.Equal(t, int32(2), attempts.Load(), "Expected the task to be attempted 2 times")
+	})
+
+	t.Run("Invalid policy", func(t *testing.T) {
+		err := manager.SetRetryPolicy(&RetryPolicy{Jitter: 2})
+		assert.Error(t, err, "Expected error setting invalid retry policy")
+
+		job := getMockedJob(1, "invalid-retry-job", 1*time.Second, 0)
+		job.RetryPolicy = &RetryPolicy{MaxAttempts: -1}
+		err = manager.ScheduleJob(job)
+		assert.Error(t, err, "Expected error scheduling job with invalid retry policy")
+	})
+}
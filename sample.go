@@ -0,0 +1,65 @@
+package taskman
+
+import (
+	"slices"
+	"time"
+)
+
+// ExecutingTask is a task being executed by a worker, as returned by Sample.
+type ExecutingTask struct {
+	JobID     string    // ID of the task's job
+	TaskIndex int       // Index of the task within the job's tasks
+	TaskID    string    // ID of the task, if it is a NamedTask
+	Started   time.Time // Time the worker started executing the task
+}
+
+// Sample returns the tasks currently being executed by the workers of the TaskManager, including
+// its worker groups, reserved workers and the workers of Dedicated jobs, ordered by when they were
+// started, e.g. for sampling at intervals to build a view of what runs over time. Tasks waiting for
+// a worker, parked at the limit of their concurrency group or executed by remote workers are not
+// included.
+func (tm *TaskManager) Sample() []ExecutingTask {
+	tm.RLock()
+	pools := []*workerPool{tm.workerPool}
+	for _, group := range tm.groups {
+		pools = append(pools, group.pool)
+	}
+	if reservation := tm.reservation.Load(); reservation != nil {
+		pools = append(pools, reservation.group.pool)
+	}
+	for _, runner := range tm.dedicated {
+		pools = append(pools, runner.pool)
+	}
+	tm.RUnlock()
+
+	var tasks []ExecutingTask
+	for _, pool := range pools {
+		tasks = pool.appendExecuting(tasks)
+	}
+	slices.SortFunc(tasks, func(a, b ExecutingTask) int {
+		return a.Started.Compare(b.Started)
+	})
+	return tasks
+}
+
+// executingTask returns the sample of a task started by a worker at the time started.
+func executingTask(task Task, started time.Time) *ExecutingTask {
+	executing := &ExecutingTask{Started: started}
+	if jt, ok := task.(jobTask); ok {
+		executing.JobID = jt.jobID()
+		executing.TaskIndex = jt.index
+		executing.TaskID = jt.taskID()
+	}
+	return executing
+}
+
+// appendExecuting appends the tasks being executed by the pool's workers to tasks.
+func (wp *workerPool) appendExecuting(tasks []ExecutingTask) []ExecutingTask {
+	wp.workers.Range(func(_, value any) bool {
+		if executing := value.(*workerInfo).executing.Load(); executing != nil {
+			tasks = append(tasks, *executing)
+		}
+		return true
+	})
+	return tasks
+}
@@ -0,0 +1,44 @@
+package taskman
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSample(t *testing.T) {
+	manager := New(WithWorkers(4), WithWorkerGroup("bulk", 1))
+	defer manager.Stop()
+	assert.Empty(t, manager.Sample(), "Expected no executing tasks in an idle manager")
+
+	release := make(chan struct{})
+	blocking := MockTask{executeFunc: func() error {
+		<-release
+		return nil
+	}}
+	job := getMockedJob(2, "sampled-job", time.Hour, time.Hour)
+	job.Tasks = []Task{blocking, blocking}
+	assert.NoError(t, manager.ScheduleJob(job))
+	grouped := getMockedJob(1, "grouped-job", time.Hour, time.Hour)
+	grouped.Tasks = []Task{blocking}
+	grouped.Group = "bulk"
+	assert.NoError(t, manager.ScheduleJob(grouped))
+
+	before := time.Now()
+	assert.NoError(t, manager.TriggerJob(job.ID))
+	time.Sleep(time.Millisecond)
+	assert.NoError(t, manager.TriggerJob(grouped.ID))
+	assert.Eventually(t, func() bool { return len(manager.Sample()) == 3 }, time.Second, time.Millisecond,
+		"Expected the tasks of both jobs to be sampled")
+
+	sample := manager.Sample()
+	assert.Equal(t, job.ID, sample[0].JobID, "Expected the tasks ordered by when they were started")
+	assert.ElementsMatch(t, []int{0, 1}, []int{sample[0].TaskIndex, sample[1].TaskIndex})
+	assert.Equal(t, grouped.ID, sample[2].JobID, "Expected the tasks of worker groups to be sampled")
+	assert.False(t, sample[0].Started.Before(before))
+
+	close(release)
+	assert.Eventually(t, func() bool { return len(manager.Sample()) == 0 }, time.Second, time.Millisecond,
+		"Expected no executing tasks once finished")
+}
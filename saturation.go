@@ -0,0 +1,133 @@
+package taskman
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// SaturationPolicy determines what happens to a due run of a job executed by the default worker
+// pool, when the run's tasks do not fit in the pool's full task queue.
+type SaturationPolicy int
+
+const (
+	// SaturationBlock waits for space in the task queue, delaying the dispatch of the runs due
+	// after the run.
+	SaturationBlock SaturationPolicy = iota
+	// SaturationShed skips the run if its job's Priority is below the shedding priority, emitting an
+	// EventRunSkipped event. Runs of jobs of a higher priority wait for space, as with
+	// SaturationBlock.
+	SaturationShed
+	// SaturationSpill queues the run's tasks in an unbounded overflow queue, from which they are
+	// sent to the task queue as it frees up, highest Priority first, without delaying the dispatch
+	// of the runs due after the run.
+	SaturationSpill
+)
+
+// SetSaturationPolicy sets what happens to due runs of jobs executed by the default worker pool
+// whose tasks do not fit in the pool's full task queue, e.g. during a load spike. With
+// SaturationShed, runs of jobs with a Priority below shedBelow are shed. Regardless of the policy,
+// runs due at the same time are dispatched highest Priority first. Shed runs are counted in the
+// RunsShed metric, and spilled tasks waiting for space in TasksSpilled. Defaults to
+// SaturationBlock.
+func (tm *TaskManager) SetSaturationPolicy(policy SaturationPolicy, shedBelow int) error {
+	if policy < SaturationBlock || policy > SaturationSpill {
+		return errors.New("invalid saturation policy")
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	tm.saturationPolicy = policy
+	tm.shedBelow = shedBelow
+	return nil
+}
+
+// saturated returns true if n tasks would not fit in the default worker pool's task queue,
+// alongside the tasks of the runs already taken in the run loop's current batch. Waiting workers
+// receive tasks without them taking up space in the queue.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) saturated(n int) bool {
+	q := tm.taskQueue
+	room := q.capacity() - q.len() + int(q.parked.Load()) - tm.batchTasks
+	return n > room
+}
+
+// overflowQueue holds the tasks spilled under SaturationSpill, ordered by the priority of their
+// jobs, and by when they were spilled for jobs of the same priority.
+type overflowQueue struct {
+	mu    sync.Mutex
+	tasks []spilledTask
+	ready chan struct{} // Channel to signal that tasks have been spilled
+}
+
+// spilledTask is a task waiting in the overflow queue.
+type spilledTask struct {
+	task     Task
+	priority int // Priority of the task's job
+}
+
+// newOverflowQueue creates an empty overflow queue.
+func newOverflowQueue() *overflowQueue {
+	return &overflowQueue{ready: make(chan struct{}, 1)}
+}
+
+// push spills tasks of a job of the given priority, after the spilled tasks of jobs of the same
+// or a higher priority.
+func (q *overflowQueue) push(priority int, tasks []jobTask) {
+	q.mu.Lock()
+	i := sort.Search(len(q.tasks), func(i int) bool { return q.tasks[i].priority < priority })
+	spilled := make([]spilledTask, len(tasks))
+	for j, task := range tasks {
+		spilled[j] = spilledTask{task: task, priority: priority}
+	}
+	q.tasks = slices.Insert(q.tasks, i, spilled...)
+	q.mu.Unlock()
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+		// A signal is already pending
+	}
+}
+
+// pop removes and returns the spilled task of the highest priority. Returns false if no task is
+// spilled.
+func (q *overflowQueue) pop() (Task, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.tasks) == 0 {
+		return nil, false
+	}
+	task := q.tasks[0].task
+	q.tasks[0] = spilledTask{}
+	q.tasks = q.tasks[1:]
+	return task, true
+}
+
+// len returns the number of spilled tasks.
+func (q *overflowQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.tasks)
+}
+
+// drainOverflow sends the spilled tasks to the default worker pool's task queue as it frees up,
+// until the TaskManager is stopped.
+func (tm *TaskManager) drainOverflow() {
+	defer tm.dispatches.Done()
+	for {
+		task, ok := tm.overflow.pop()
+		if !ok {
+			select {
+			case <-tm.overflow.ready:
+				continue
+			case <-tm.ctx.Done():
+				return
+			}
+		}
+		if !tm.taskQueue.send(tm.ctx.Done(), task) {
+			return
+		}
+	}
+}
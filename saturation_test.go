@@ -0,0 +1,155 @@
+package taskman
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// saturate occupies the only worker of the manager and fills its task queue of one task, returning
+// a function releasing them.
+func saturate(t *testing.T, manager *TaskManager) func() {
+	release := make(chan struct{})
+	blocked := make(chan struct{})
+	_, err := manager.ScheduleOnce(SimpleContextTask{func(ctx context.Context) error {
+		close(blocked)
+		<-release
+		return nil
+	}}, 0)
+	assert.NoError(t, err)
+	<-blocked
+
+	_, err = manager.ScheduleOnce(SimpleContextTask{func(ctx context.Context) error {
+		<-release
+		return nil
+	}}, 0)
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool { return manager.taskQueue.len() == 1 }, 100*time.Millisecond,
+		time.Millisecond, "Expected the task queue to fill up")
+	return func() { close(release) }
+}
+
+func TestSaturationShed(t *testing.T) {
+	manager := New(WithWorkerBounds(1, 1), WithTaskBuffer(1), WithTaskShards(1),
+		WithSaturationPolicy(SaturationShed, 1))
+	defer manager.Stop()
+	release := saturate(t, manager)
+	events, unsubscribe := manager.SubscribeEvents(16)
+	defer unsubscribe()
+
+	executed := make(chan string, 4)
+	low := getMockedJob(1, "low-priority-job", time.Hour, 0)
+	low.Tasks[0] = MockTask{executeFunc: func() error {
+		executed <- low.ID
+		return nil
+	}}
+	high := getMockedJob(1, "high-priority-job", time.Hour, 0)
+	high.Priority = 1
+	high.Tasks[0] = MockTask{executeFunc: func() error {
+		executed <- high.ID
+		return nil
+	}}
+	assert.NoError(t, manager.ScheduleJobs([]Job{low, high}))
+
+	// The low-priority run is shed, and the high-priority run waits for space
+	select {
+	case event := <-waitForEvent(events, EventRunSkipped):
+		assert.Equal(t, low.ID, event.JobID)
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the low-priority run to be shed")
+	}
+	assert.Equal(t, 1, manager.Metrics().RunsShed)
+	stats, err := manager.JobStats(low.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.SkippedRuns)
+
+	release()
+	select {
+	case jobID := <-executed:
+		assert.Equal(t, high.ID, jobID)
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the high-priority run to execute")
+	}
+	select {
+	case jobID := <-executed:
+		t.Fatalf("Expected only the high-priority run to execute, %s executed", jobID)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestSaturationSpill(t *testing.T) {
+	manager := New(WithWorkerBounds(1, 1), WithTaskBuffer(1), WithTaskShards(1),
+		WithSaturationPolicy(SaturationSpill, 0))
+	defer manager.Stop()
+	release := saturate(t, manager)
+
+	executed := make(chan string, 4)
+	var jobs []Job
+	for jobID, priority := range map[string]int{"low": 0, "high": 2, "medium": 1} {
+		job := getMockedJob(1, jobID, time.Hour, 0)
+		job.Priority = priority
+		job.Tasks[0] = MockTask{executeFunc: func() error {
+			executed <- job.ID
+			return nil
+		}}
+		jobs = append(jobs, job)
+	}
+	assert.NoError(t, manager.ScheduleJobs(jobs))
+
+	// The runs are spilled rather than blocking the run loop, and executed highest priority first
+	assert.Eventually(t, func() bool { return manager.Metrics().TasksSpilled >= 2 }, 50*time.Millisecond,
+		time.Millisecond, "Expected the tasks to be spilled")
+	release()
+	for _, expected := range []string{"high", "medium", "low"} {
+		select {
+		case jobID := <-executed:
+			assert.Equal(t, expected, jobID)
+		case <-time.After(50 * time.Millisecond):
+			t.Fatalf("Expected the %s-priority run to execute", expected)
+		}
+	}
+	assert.Equal(t, 0, manager.Metrics().TasksSpilled)
+}
+
+func TestOverflowQueue(t *testing.T) {
+	q := newOverflowQueue()
+	q.push(0, []jobTask{{index: 0}, {index: 1}})
+	q.push(2, []jobTask{{index: 2}})
+	q.push(0, []jobTask{{index: 3}})
+	q.push(1, []jobTask{{index: 4}})
+	assert.Equal(t, 5, q.len())
+
+	var order []int
+	for {
+		task, ok := q.pop()
+		if !ok {
+			break
+		}
+		order = append(order, task.(jobTask).index)
+	}
+	assert.Equal(t, []int{2, 4, 0, 1, 3}, order, "Expected tasks by priority, then in order spilled")
+}
+
+func TestSetSaturationPolicy(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+
+	assert.NoError(t, manager.SetSaturationPolicy(SaturationSpill, 0))
+	assert.Error(t, manager.SetSaturationPolicy(SaturationPolicy(42), 0))
+}
+
+// waitForEvent returns a channel receiving the first event of the given type.
+func waitForEvent(events <-chan Event, eventType EventType) <-chan Event {
+	found := make(chan Event, 1)
+	go func() {
+		for event := range events {
+			if event.Type == eventType {
+				found <- event
+				return
+			}
+		}
+	}()
+	return found
+}
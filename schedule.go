@@ -0,0 +1,146 @@
+package taskman
+
+import (
+	"slices"
+	"time"
+)
+
+// Schedule determines the execution times of a job on a calendar, e.g. at specific times of day,
+// as an alternative to executing the job at a fixed cadence. Set a job's Schedule to have the
+// TaskManager call Next to find each following execution. A job whose schedule has no further
+// executions is removed after its last execution.
+// Note: schedules are not part of a JobRecord, so stored jobs with a schedule are restored at their
+// Cadence, an estimate of the schedule's interval, unless scheduled again with their schedule.
+type Schedule interface {
+	// Next returns the first execution time strictly after the given time, or the zero time if
+	// there is none.
+	Next(after time.Time) time.Time
+}
+
+// TimeOfDay is a wall clock time within a day.
+type TimeOfDay struct {
+	Hour   int // Hour of the day, 0-23
+	Minute int // Minute of the hour, 0-59
+}
+
+// At returns the time of day with the given hour and minute.
+func At(hour, minute int) TimeOfDay {
+	return TimeOfDay{Hour: hour, Minute: minute}
+}
+
+// on returns the time of day on the given date, in the location loc. Dates are normalized as by
+// time.Date, so that e.g. a time skipped by a daylight saving transition is moved past it.
+func (tod TimeOfDay) on(year int, month time.Month, day int, loc *time.Location) time.Time {
+	return time.Date(year, month, day, tod.Hour, tod.Minute, 0, 0, loc)
+}
+
+// FixedCadence returns a schedule executing at a fixed cadence, equivalent to setting a job's
+// Cadence.
+func FixedCadence(cadence time.Duration) Schedule {
+	return fixedCadence(cadence)
+}
+
+type fixedCadence time.Duration
+
+// Next returns the time one cadence after the given time.
+func (c fixedCadence) Next(after time.Time) time.Time {
+	if c <= 0 {
+		return time.Time{}
+	}
+	return after.Add(time.Duration(c))
+}
+
+// DailyAt returns a schedule executing every day at each of the given times of day. Times are in
+// the time zone of the time passed to Next, which for the TaskManager's default clock is the local
+// time zone, and follow the wall clock across daylight saving transitions.
+func DailyAt(times ...TimeOfDay) Schedule {
+	sorted := slices.Clone(times)
+	slices.SortFunc(sorted, func(a, b TimeOfDay) int {
+		return (a.Hour*60 + a.Minute) - (b.Hour*60 + b.Minute)
+	})
+	return dailySchedule{times: sorted}
+}
+
+type dailySchedule struct {
+	times []TimeOfDay // Times of day in ascending order
+}
+
+// Next returns the first of the schedule's times of day after the given time.
+func (s dailySchedule) Next(after time.Time) time.Time {
+	year, month, day := after.Date()
+	// Two days always contain the next time, the third covers days shortened by a transition
+	for offset := range 3 {
+		for _, tod := range s.times {
+			if t := tod.on(year, month, day+offset, after.Location()); t.After(after) {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// Weekly returns a schedule executing every week on the weekday, at the time of day. Times are in
+// the time zone of the time passed to Next, as for DailyAt.
+func Weekly(weekday time.Weekday, at TimeOfDay) Schedule {
+	return weeklySchedule{weekday: weekday, at: at}
+}
+
+type weeklySchedule struct {
+	weekday time.Weekday
+	at      TimeOfDay
+}
+
+// Next returns the first occurrence of the schedule's weekday and time of day after the given time.
+func (s weeklySchedule) Next(after time.Time) time.Time {
+	year, month, day := after.Date()
+	for offset := range 8 {
+		t := s.at.on(year, month, day+offset, after.Location())
+		if t.Weekday() == s.weekday && t.After(after) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Monthly returns a schedule executing every month on the day of the month, at the time of day.
+// Months without the day, e.g. February for day 30, are skipped. Times are in the time zone of the
+// time passed to Next, as for DailyAt.
+func Monthly(day int, at TimeOfDay) Schedule {
+	return monthlySchedule{day: day, at: at}
+}
+
+type monthlySchedule struct {
+	day int
+	at  TimeOfDay
+}
+
+// Next returns the first occurrence of the schedule's day of the month and time of day after the
+// given time.
+func (s monthlySchedule) Next(after time.Time) time.Time {
+	if s.day < 1 || s.day > 31 {
+		return time.Time{}
+	}
+	year, month, _ := after.Date()
+	// Any day of the month occurs at least once within a year and a month
+	for offset := range 13 {
+		t := s.at.on(year, month+time.Month(offset), s.day, after.Location())
+		if t.Day() == s.day && t.After(after) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// scheduleInterval returns an estimate of the time between executions of the schedule, following
+// the given time, or 0 if the schedule does not execute at least twice.
+func scheduleInterval(schedule Schedule, t time.Time) time.Duration {
+	first := schedule.Next(t)
+	if first.IsZero() {
+		return 0
+	}
+	second := schedule.Next(first)
+	if second.IsZero() {
+		return 0
+	}
+	return second.Sub(first)
+}
@@ -0,0 +1,208 @@
+package taskman
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ScheduleFile is a declarative schedule of jobs, e.g. read from a YAML or JSON configuration file
+// with ReadScheduleFile, allowing cadences to be changed without a redeploy. The tasks of its jobs
+// are created by the factories registered with RegisterTaskType.
+type ScheduleFile struct {
+	Jobs []ScheduleEntry `yaml:"jobs" json:"jobs"`
+}
+
+// ScheduleEntry is a job of a ScheduleFile, executing a single task of a registered type.
+type ScheduleEntry struct {
+	Name     string            `yaml:"name" json:"name,omitempty"`         // ID of the job
+	Task     string            `yaml:"task" json:"task,omitempty"`         // Registered type of the job's task
+	Params   map[string]any    `yaml:"params" json:"params,omitempty"`     // Parameters of the task, passed to its factory as JSON
+	Cadence  string            `yaml:"cadence" json:"cadence,omitempty"`   // Time between executions, e.g. "5m", unless Cron is set
+	Cron     string            `yaml:"cron" json:"cron,omitempty"`         // Cron expression determining the executions, see ScheduleCron
+	Tags     []string          `yaml:"tags" json:"tags,omitempty"`         // Tags of the job, if any
+	Metadata map[string]string `yaml:"metadata" json:"metadata,omitempty"` // Metadata of the job, if any
+	Retry    *ScheduleRetry    `yaml:"retry" json:"retry,omitempty"`       // Retry policy of the job, the TaskManager default if unset
+}
+
+// ScheduleRetry is the retry policy of a ScheduleEntry, see RetryPolicy.
+type ScheduleRetry struct {
+	MaxAttempts  int     `yaml:"max_attempts" json:"max_attempts,omitempty"`   // Total number of attempts including the first
+	Backoff      string  `yaml:"backoff" json:"backoff,omitempty"`             // "constant", "linear" or "exponential", constant if empty
+	InitialDelay string  `yaml:"initial_delay" json:"initial_delay,omitempty"` // Delay before the first retry, e.g. "1s"
+	MaxDelay     string  `yaml:"max_delay" json:"max_delay,omitempty"`         // Upper bound of the delay between attempts, if any
+	Jitter       float64 `yaml:"jitter" json:"jitter,omitempty"`               // Fraction by which each delay is randomized
+}
+
+// ScheduleEntryError is the error of an invalid entry of a ScheduleFile.
+type ScheduleEntryError struct {
+	Index int    // Index of the entry within the file's jobs
+	Name  string // Name of the entry, if any
+	Err   error  // The validation error
+}
+
+// Error returns the error message, prefixed with the entry.
+func (e *ScheduleEntryError) Error() string {
+	return fmt.Sprintf("schedule entry %d (%s): %v", e.Index, e.Name, e.Err)
+}
+
+// Unwrap returns the validation error.
+func (e *ScheduleEntryError) Unwrap() error {
+	return e.Err
+}
+
+// backoffStrategies maps the backoff names of a ScheduleRetry to their strategies.
+var backoffStrategies = map[string]BackoffStrategy{
+	"":            BackoffConstant,
+	"constant":    BackoffConstant,
+	"linear":      BackoffLinear,
+	"exponential": BackoffExponential,
+}
+
+// ParseScheduleFile parses a schedule in YAML, or in JSON as a subset of YAML. Unknown fields are
+// rejected, catching misspelled settings.
+func ParseScheduleFile(data []byte) (ScheduleFile, error) {
+	var file ScheduleFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return ScheduleFile{}, fmt.Errorf("failed to parse schedule file: %w", err)
+	}
+	return file, nil
+}
+
+// ReadScheduleFile reads and parses the schedule file at path, see ParseScheduleFile.
+func ReadScheduleFile(path string) (ScheduleFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ScheduleFile{}, fmt.Errorf("failed to read schedule file: %w", err)
+	}
+	return ParseScheduleFile(data)
+}
+
+// LoadSchedule schedules the jobs of a schedule file, creating their tasks with the registered task
+// types. All entries are validated before any job is scheduled, and if any entry is invalid no job
+// is scheduled, and a *ScheduleEntryError for each invalid entry is returned, joined.
+func (tm *TaskManager) LoadSchedule(file ScheduleFile) error {
+	jobs, err := tm.scheduleFileJobs(file)
+	if err != nil {
+		return err
+	}
+	return tm.ScheduleJobs(jobs)
+}
+
+// ValidateSchedule validates the entries of a schedule file as LoadSchedule does, as a dry run
+// which schedules no job, e.g. for checking a schedule file in a CI pipeline before deploying it.
+// Cron expressions, cadences, retry settings and task types and params are all validated, and a
+// *ScheduleEntryError for each invalid entry is returned, joined.
+func (tm *TaskManager) ValidateSchedule(file ScheduleFile) error {
+	_, err := tm.scheduleFileJobs(file)
+	return err
+}
+
+// scheduleFileJobs returns the validated jobs of the entries of a schedule file, or the errors of
+// its invalid entries.
+func (tm *TaskManager) scheduleFileJobs(file ScheduleFile) ([]Job, error) {
+	now := tm.now()
+	jobs := make([]Job, 0, len(file.Jobs))
+	names := make(map[string]bool, len(file.Jobs))
+	var errs []error
+	for i, entry := range file.Jobs {
+		job, err := tm.entryJob(entry, now)
+		if err == nil && names[entry.Name] {
+			err = fmt.Errorf("%w in schedule file", ErrDuplicateJobID)
+		}
+		if err == nil {
+			err = tm.ValidateJob(job)
+		}
+		if err != nil {
+			errs = append(errs, &ScheduleEntryError{Index: i, Name: entry.Name, Err: err})
+			continue
+		}
+		names[entry.Name] = true
+		jobs = append(jobs, job)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return jobs, nil
+}
+
+// entryJob returns the job of a schedule entry, scheduled at the time now.
+func (tm *TaskManager) entryJob(entry ScheduleEntry, now time.Time) (Job, error) {
+	if entry.Task == "" {
+		return Job{}, errors.New("task type cannot be empty")
+	}
+	data, err := json.Marshal(entry.Params)
+	if err != nil {
+		return Job{}, fmt.Errorf("invalid task params: %w", err)
+	}
+	tasks, err := tm.unmarshalTasks([]TaskRecord{{Type: entry.Task, Data: data}})
+	if err != nil {
+		return Job{}, err
+	}
+
+	var job Job
+	switch {
+	case entry.Cron != "" && entry.Cadence != "":
+		return Job{}, errors.New("cadence and cron cannot both be set")
+	case entry.Cron != "":
+		job, err = cronJob(entry.Name, tasks[0], entry.Cron, now)
+		if err != nil {
+			return Job{}, err
+		}
+	default:
+		cadence, err := time.ParseDuration(entry.Cadence)
+		if err != nil {
+			return Job{}, fmt.Errorf("invalid cadence: %w", err)
+		}
+		job = Job{ID: entry.Name, Tasks: tasks, Cadence: cadence, NextExec: now.Add(cadence)}
+	}
+	job.Tags = entry.Tags
+	job.Metadata = entry.Metadata
+	if entry.Retry != nil {
+		job.RetryPolicy, err = entry.Retry.policy()
+		if err != nil {
+			return Job{}, err
+		}
+	}
+	return job, nil
+}
+
+// policy returns the RetryPolicy of the retry settings.
+func (sr *ScheduleRetry) policy() (*RetryPolicy, error) {
+	backoff, ok := backoffStrategies[sr.Backoff]
+	if !ok {
+		return nil, fmt.Errorf("invalid retry backoff %q", sr.Backoff)
+	}
+	initialDelay, err := parseOptionalDuration(sr.InitialDelay)
+	if err != nil {
+		return nil, fmt.Errorf("invalid retry initial delay: %w", err)
+	}
+	maxDelay, err := parseOptionalDuration(sr.MaxDelay)
+	if err != nil {
+		return nil, fmt.Errorf("invalid retry max delay: %w", err)
+	}
+	policy := &RetryPolicy{
+		MaxAttempts:  sr.MaxAttempts,
+		Backoff:      backoff,
+		InitialDelay: initialDelay,
+		MaxDelay:     maxDelay,
+		Jitter:       sr.Jitter,
+	}
+	return policy, nil
+}
+
+// parseOptionalDuration parses a duration, or returns 0 if the value is empty.
+func parseOptionalDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(value)
+}
@@ -0,0 +1,131 @@
+package taskman
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testScheduleFile = `
+jobs:
+  - name: greet-hourly
+    task: greet
+    params:
+      message: hello
+    cadence: 1h
+    tags: [greetings]
+    retry:
+      max_attempts: 3
+      backoff: exponential
+      initial_delay: 1s
+  - name: greet-daily
+    task: greet
+    params: {message: good morning}
+    cron: "0 8 * * *"
+    metadata:
+      tenant: acme
+`
+
+func TestParseScheduleFile(t *testing.T) {
+	file, err := ParseScheduleFile([]byte(testScheduleFile))
+	assert.NoError(t, err)
+	assert.Len(t, file.Jobs, 2)
+	assert.Equal(t, "greet-hourly", file.Jobs[0].Name)
+	assert.Equal(t, map[string]any{"message": "hello"}, file.Jobs[0].Params)
+	assert.Equal(t, 3, file.Jobs[0].Retry.MaxAttempts)
+
+	// JSON is parsed as a subset of YAML
+	file, err = ParseScheduleFile([]byte(`{"jobs": [{"name": "greet-json", "task": "greet", "cadence": "5m"}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "5m", file.Jobs[0].Cadence)
+
+	_, err = ParseScheduleFile([]byte("jobs:\n  - name: typo\n    cadense: 1m\n"))
+	assert.Error(t, err, "Expected error parsing an unknown field")
+
+	path := filepath.Join(t.TempDir(), "schedule.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(testScheduleFile), 0o600))
+	file, err = ReadScheduleFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, file.Jobs, 2)
+	_, err = ReadScheduleFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestLoadSchedule(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+	assert.NoError(t, manager.RegisterTaskType("greet", JSONTaskFactory[greetTask]()))
+
+	file, err := ParseScheduleFile([]byte(testScheduleFile))
+	assert.NoError(t, err)
+	assert.NoError(t, manager.LoadSchedule(file))
+
+	hourly, err := manager.Job("greet-hourly")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, hourly.Cadence)
+	assert.Equal(t, []string{"greetings"}, hourly.Tags)
+	daily, err := manager.Job("greet-daily")
+	assert.NoError(t, err)
+	assert.Equal(t, 8, daily.NextExec.Hour())
+	assert.Equal(t, map[string]string{"tenant": "acme"}, daily.Metadata)
+
+	manager.RLock()
+	index, err := manager.jobQueue.JobInQueue("greet-hourly")
+	assert.NoError(t, err)
+	job := manager.jobQueue.jobs[index]
+	assert.Equal(t, greetTask{Message: "hello"}, job.Tasks[0])
+	assert.Equal(t, &RetryPolicy{MaxAttempts: 3, Backoff: BackoffExponential, InitialDelay: time.Second}, job.RetryPolicy)
+	manager.RUnlock()
+}
+
+func TestLoadScheduleInvalid(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+	assert.NoError(t, manager.RegisterTaskType("greet", JSONTaskFactory[greetTask]()))
+
+	file := ScheduleFile{Jobs: []ScheduleEntry{
+		{Name: "valid", Task: "greet", Cadence: "1m"},
+		{Name: "unknown-task", Task: "unknown", Cadence: "1m"},
+		{Name: "no-cadence", Task: "greet"},
+		{Name: "both", Task: "greet", Cadence: "1m", Cron: "@daily"},
+		{Name: "bad-backoff", Task: "greet", Cadence: "1m", Retry: &ScheduleRetry{Backoff: "quadratic"}},
+		{Name: "valid", Task: "greet", Cadence: "1m"},
+		{Name: "", Task: "greet", Cadence: "1m"},
+	}}
+	err := manager.LoadSchedule(file)
+	assert.Error(t, err)
+
+	// Every invalid entry is reported, and no job is scheduled
+	var invalid []int
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var entryErr *ScheduleEntryError
+		if assert.True(t, errors.As(err, &entryErr)) {
+			invalid = append(invalid, entryErr.Index)
+		}
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, invalid)
+	assert.ErrorIs(t, err, ErrDuplicateJobID)
+	assert.Empty(t, manager.Jobs())
+
+	// Validating the schedule reports the same errors
+	assert.Equal(t, err.Error(), manager.ValidateSchedule(file).Error())
+}
+
+func TestValidateSchedule(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+	assert.NoError(t, manager.RegisterTaskType("greet", JSONTaskFactory[greetTask]()))
+
+	file, err := ParseScheduleFile([]byte(testScheduleFile))
+	assert.NoError(t, err)
+	assert.NoError(t, manager.ValidateSchedule(file))
+	assert.Empty(t, manager.Jobs(), "Expected no job to be scheduled by a dry run")
+
+	// Once loaded, the entries conflict with the scheduled jobs
+	assert.NoError(t, manager.LoadSchedule(file))
+	assert.ErrorIs(t, manager.ValidateSchedule(file), ErrDuplicateJobID)
+}
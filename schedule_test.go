@@ -0,0 +1,135 @@
+package taskman
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixedCadence(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, start.Add(time.Hour), FixedCadence(time.Hour).Next(start))
+	assert.True(t, FixedCadence(0).Next(start).IsZero(), "Expected no executions without a cadence")
+}
+
+func TestDailyAt(t *testing.T) {
+	schedule := DailyAt(At(17, 30), At(9, 0))
+
+	morning := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), schedule.Next(morning))
+	noon := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 1, 1, 17, 30, 0, 0, time.UTC), schedule.Next(noon))
+	evening := time.Date(2025, 1, 1, 17, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), schedule.Next(evening), "Expected the next time to be strictly after")
+	endOfYear := time.Date(2025, 12, 31, 18, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC), schedule.Next(endOfYear))
+
+	assert.True(t, DailyAt().Next(noon).IsZero(), "Expected no executions without times")
+}
+
+func TestDailyAtDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	// Clocks move forward on 2025-03-09, the time of day is kept in wall clock time
+	schedule := DailyAt(At(9, 0))
+	before := time.Date(2025, 3, 8, 10, 0, 0, 0, loc)
+	next := schedule.Next(before)
+	assert.Equal(t, time.Date(2025, 3, 9, 9, 0, 0, 0, loc), next)
+	assert.Equal(t, 23*time.Hour, next.Sub(before.Add(-time.Hour)), "Expected the day to be an hour shorter")
+}
+
+func TestWeekly(t *testing.T) {
+	schedule := Weekly(time.Monday, At(8, 0))
+
+	// 2025-01-01 is a Wednesday
+	wednesday := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC), schedule.Next(wednesday))
+	monday := time.Date(2025, 1, 6, 7, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC), schedule.Next(monday))
+	mondayLater := time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 1, 13, 8, 0, 0, 0, time.UTC), schedule.Next(mondayLater))
+}
+
+func TestMonthly(t *testing.T) {
+	schedule := Monthly(15, At(6, 30))
+	start := time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 2, 15, 6, 30, 0, 0, time.UTC), schedule.Next(start))
+
+	// Months without the day are skipped
+	schedule = Monthly(31, At(0, 0))
+	start = time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), schedule.Next(start))
+
+	assert.True(t, Monthly(0, At(0, 0)).Next(start).IsZero(), "Expected no executions for an invalid day")
+	assert.True(t, Monthly(32, At(0, 0)).Next(start).IsZero(), "Expected no executions for an invalid day")
+}
+
+// listSchedule is a schedule executing at a fixed list of times.
+type listSchedule []time.Time
+
+// Next returns the first time of the list after the given time.
+func (s listSchedule) Next(after time.Time) time.Time {
+	for _, t := range s {
+		if t.After(after) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func TestJobSchedule(t *testing.T) {
+	t.Run("Executes on schedule", func(t *testing.T) {
+		manager := NewCustom(2, 4, 1*time.Minute)
+		defer manager.Stop()
+
+		now := time.Now()
+		schedule := listSchedule{now.Add(5 * time.Millisecond), now.Add(15 * time.Millisecond), now.Add(25 * time.Millisecond)}
+		var executions atomic.Int32
+		removed := make(chan string, 1)
+		manager.OnJobRemoved(func(jobID string) { removed <- jobID })
+
+		jobID, err := manager.ScheduleCalendar(MockTask{executeFunc: func() error {
+			executions.Add(1)
+			return nil
+		}}, schedule)
+		assert.NoError(t, err)
+		info, err := manager.Job(jobID)
+		assert.NoError(t, err)
+		assert.Equal(t, schedule[0], info.NextExec, "Expected the first execution of the schedule")
+		assert.Equal(t, 10*time.Millisecond, info.Cadence, "Expected the cadence to be estimated from the schedule")
+
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, int32(1), executions.Load())
+		select {
+		case id := <-removed:
+			assert.Equal(t, jobID, id)
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("Expected the job to be removed after the schedule's last execution")
+		}
+		assert.Eventually(t, func() bool { return executions.Load() == 3 }, 20*time.Millisecond, time.Millisecond,
+			"Expected the last execution of the schedule")
+	})
+
+	t.Run("Skips missed executions", func(t *testing.T) {
+		now := time.Now()
+		job := &Job{Schedule: FixedCadence(10 * time.Millisecond), scheduled: now.Add(-35 * time.Millisecond)}
+		assert.Equal(t, now.Add(10*time.Millisecond), job.nextExecAfter(now))
+		job.scheduled = now.Add(-5 * time.Millisecond)
+		assert.Equal(t, now.Add(5*time.Millisecond), job.nextExecAfter(now), "Expected the schedule to follow on from the previous execution")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		manager := NewCustom(1, 1, 1*time.Minute)
+		defer manager.Stop()
+
+		_, err := manager.ScheduleCalendar(MockTask{}, nil)
+		assert.Error(t, err, "Expected error for a nil schedule")
+		_, err = manager.ScheduleCalendar(MockTask{}, DailyAt())
+		assert.Error(t, err, "Expected error for a schedule which never executes")
+	})
+}
@@ -0,0 +1,51 @@
+package taskman
+
+import (
+	"context"
+	"time"
+)
+
+// Scheduler is the scheduling and job API shared by TaskManager and ShardedTaskManager, so that
+// code scheduling and operating on jobs works with either, e.g. when a queue outgrows a single
+// TaskManager. Features beyond it, e.g. worker pool settings, are configured on a TaskManager or
+// on the shards of a ShardedTaskManager.
+type Scheduler interface {
+	ScheduleJob(job Job) error
+	ScheduleJobs(jobs []Job) error
+	ValidateJob(job Job) error
+	ScheduleFunc(function func() error, cadence time.Duration) (string, error)
+	ScheduleTask(task Task, cadence time.Duration) (string, error)
+	ScheduleCron(task Task, cronExpr string) (string, error)
+	ScheduleOnce(task Task, delay time.Duration) (string, error)
+
+	RemoveJob(jobID string) error
+	ReplaceJob(newJob Job) error
+	AddTaskToJob(jobID string, task Task) error
+	RemoveTaskFromJob(jobID string, index int) error
+	PauseJob(jobID string) error
+	ResumeJob(jobID string) error
+	TriggerJob(jobID string) error
+	RunJobNow(ctx context.Context, jobID string) (JobResult, error)
+	AwaitRun(ctx context.Context, jobID string) (JobResult, error)
+
+	Job(jobID string) (JobInfo, error)
+	JobStats(jobID string) (JobStats, error)
+	JobHistory(jobID string) ([]RunRecord, error)
+	Jobs() []JobInfo
+	QueueSnapshot(limit int) []QueuedRun
+
+	JobsByTag(tag string) []JobInfo
+	PauseJobsByTag(tag string) int
+	ResumeJobsByTag(tag string) int
+	RemoveJobsByTag(tag string) []string
+
+	RestoreJobs(resolve func(record JobRecord) ([]Task, error)) error
+	Metrics() TaskManagerMetrics
+	SubscribeErrors(bufferSize int) (<-chan error, func())
+	Stop()
+}
+
+var (
+	_ Scheduler = (*TaskManager)(nil)
+	_ Scheduler = (*ShardedTaskManager)(nil)
+)
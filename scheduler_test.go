@@ -0,0 +1,51 @@
+package taskman
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler(t *testing.T) {
+	schedulers := map[string]Scheduler{
+		"manager": New(WithWorkers(1)),
+		"sharded": NewSharded(3, WithWorkers(1)),
+	}
+	for name, scheduler := range schedulers {
+		t.Run(name, func(t *testing.T) {
+			defer scheduler.Stop()
+
+			executed := make(chan struct{}, 1)
+			jobID, err := scheduler.ScheduleFunc(func() error {
+				executed <- struct{}{}
+				return nil
+			}, time.Hour)
+			assert.NoError(t, err)
+			assert.NoError(t, scheduler.TriggerJob(jobID))
+			select {
+			case <-executed:
+			case <-time.After(50 * time.Millisecond):
+				t.Fatal("Expected the triggered job to execute")
+			}
+
+			for i := range 5 {
+				job := getMockedJob(1, fmt.Sprintf("tagged-job-%d", i), time.Hour, time.Hour)
+				job.Tags = []string{"tag"}
+				assert.NoError(t, scheduler.ValidateJob(job))
+				assert.NoError(t, scheduler.ScheduleJob(job))
+				assert.ErrorIs(t, scheduler.ValidateJob(job), ErrDuplicateJobID)
+			}
+			assert.Len(t, scheduler.JobsByTag("tag"), 5)
+			assert.Len(t, scheduler.QueueSnapshot(3), 3)
+			assert.Equal(t, 6, scheduler.Metrics().QueuedJobs)
+
+			assert.NoError(t, scheduler.RemoveJob(jobID))
+			_, err = scheduler.Job(jobID)
+			assert.Error(t, err, "Expected the removed job to be gone")
+			assert.Len(t, scheduler.RemoveJobsByTag("tag"), 5)
+			assert.Empty(t, scheduler.Jobs())
+		})
+	}
+}
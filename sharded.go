@@ -0,0 +1,420 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ShardedTaskManager spreads jobs across a number of TaskManagers, its shards, each with its own
+// queue, run loop and worker pool. Jobs are assigned to shards by a hash of their ID, so that
+// scheduling, removing and dispatching jobs of different shards do not contend for the same lock.
+// It is meant for very large queues, e.g. 100k+ jobs, where a single TaskManager's lock becomes a
+// bottleneck.
+//
+// Jobs only depend on jobs of their own shard, so dependent jobs and their dependencies should be
+// scheduled on a plain TaskManager instead, or given IDs hashing to the same shard.
+type ShardedTaskManager struct {
+	shards []*TaskManager
+}
+
+// NewSharded creates, starts and returns a ShardedTaskManager with the given number of shards,
+// each created with New and the given options. The options apply to each shard, e.g. WithWorkers
+// sets the number of workers of each shard's worker pool, and WithMaxJobs the maximum number of
+// jobs of each shard. The shards share the event log set by WithEventLog, so that their events are
+// written as whole lines. Distributed locks are not supported, as each shard would compete for the
+// lock, and NewSharded panics if one is set.
+func NewSharded(shards int, opts ...Option) *ShardedTaskManager {
+	if shards < 1 {
+		panic("shards must be greater than 0")
+	}
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.lock != nil {
+		panic("distributed locks are not supported by a ShardedTaskManager")
+	}
+
+	// Write the events of all shards through one event log, serializing their writes
+	var log *eventLog
+	if o.eventLog != nil {
+		log = newEventLog(o.eventLog)
+		opts = append(slices.Clone(opts), WithEventLog(nil))
+	}
+
+	sm := &ShardedTaskManager{shards: make([]*TaskManager, shards)}
+	for i := range sm.shards {
+		sm.shards[i] = New(opts...)
+		if log != nil {
+			sm.shards[i].eventLog.Store(log)
+		}
+	}
+	return sm
+}
+
+// Shard returns the shard of the job with the given ID, e.g. for scheduling a job with a given ID
+// using one of the TaskManager's methods which the ShardedTaskManager does not provide.
+func (sm *ShardedTaskManager) Shard(jobID string) *TaskManager {
+	hash := fnv.New32a()
+	hash.Write([]byte(jobID))
+	return sm.shards[hash.Sum32()%uint32(len(sm.shards))]
+}
+
+// Shards returns all shards, e.g. for registering hooks or middleware on each of them. Jobs must
+// not be scheduled directly on a shard, other than the one returned by Shard for their ID.
+func (sm *ShardedTaskManager) Shards() []*TaskManager {
+	return slices.Clone(sm.shards)
+}
+
+// ScheduleJob adds a job to its shard, see TaskManager.ScheduleJob.
+func (sm *ShardedTaskManager) ScheduleJob(job Job) error {
+	return sm.Shard(job.ID).ScheduleJob(job)
+}
+
+// ValidateJob validates a job against its shard without scheduling it, see TaskManager.ValidateJob.
+func (sm *ShardedTaskManager) ValidateJob(job Job) error {
+	return sm.Shard(job.ID).ValidateJob(job)
+}
+
+// ScheduleJobs schedules a batch of jobs, see TaskManager.ScheduleJobs. The jobs of each shard are
+// scheduled atomically, and if a shard fails to schedule its jobs, the jobs already scheduled on
+// other shards are removed, so that either all or none of the jobs are scheduled.
+func (sm *ShardedTaskManager) ScheduleJobs(jobs []Job) error {
+	batches := make(map[*TaskManager][]Job)
+	for _, job := range jobs {
+		shard := sm.Shard(job.ID)
+		batches[shard] = append(batches[shard], job)
+	}
+
+	var scheduled []*TaskManager
+	for _, shard := range sm.shards {
+		batch, ok := batches[shard]
+		if !ok {
+			continue
+		}
+		if err := shard.ScheduleJobs(batch); err != nil {
+			for _, scheduledShard := range scheduled {
+				scheduledShard.unscheduleJobs(batches[scheduledShard])
+			}
+			return err
+		}
+		scheduled = append(scheduled, shard)
+	}
+	return nil
+}
+
+// unscheduleJobs removes the jobs of a batch scheduled with ScheduleJobs, rolling back the batch.
+// Unlike RemoveJob, no removal hooks are called, and the records of jobs resumed from the job
+// store are kept, as they were stored before the batch was scheduled.
+func (tm *TaskManager) unscheduleJobs(jobs []Job) {
+	tm.Lock()
+	defer tm.Unlock()
+
+	for _, scheduled := range jobs {
+		jobIndex, err := tm.jobQueue.JobInQueue(scheduled.ID)
+		if err != nil {
+			tm.logger.Warn("Failed to remove job of batch", "jobID", scheduled.ID, "error", err)
+			continue
+		}
+		job := tm.jobQueue.jobs[jobIndex]
+		if err := tm.removeJob(job); err != nil {
+			tm.logger.Warn("Failed to remove job of batch", "jobID", job.ID, "error", err)
+			continue
+		}
+		if tm.store != nil && !job.restored {
+			tm.unpersistJob(tm.store, job.ID)
+		}
+		tm.releaseRunWaiters(job.ID)
+		job.cancel()
+	}
+}
+
+// ScheduleFunc adds a function executed at the cadence to a shard, see TaskManager.ScheduleFunc.
+func (sm *ShardedTaskManager) ScheduleFunc(function func() error, cadence time.Duration) (string, error) {
+	return sm.ScheduleTask(SimpleTask{function}, cadence)
+}
+
+// ScheduleTask adds a task executed at the cadence to a shard, see TaskManager.ScheduleTask.
+func (sm *ShardedTaskManager) ScheduleTask(task Task, cadence time.Duration) (string, error) {
+	jobID := sm.shards[0].newJobID()
+	shard := sm.Shard(jobID)
+	job := Job{
+		Tasks:    []Task{task},
+		Cadence:  cadence,
+		ID:       jobID,
+		NextExec: shard.clock.Now().Add(cadence),
+	}
+	return jobID, shard.ScheduleJob(job)
+}
+
+// ScheduleCron adds a task executed according to the cron expression to a shard, see
+// TaskManager.ScheduleCron.
+func (sm *ShardedTaskManager) ScheduleCron(task Task, cronExpr string) (string, error) {
+	job, err := cronJob(sm.shards[0].newJobID(), task, cronExpr, sm.shards[0].clock.Now())
+	if err != nil {
+		return "", err
+	}
+	return job.ID, sm.ScheduleJob(job)
+}
+
+// ScheduleOnce adds a task executed once after the delay to a shard, see
+// TaskManager.ScheduleOnce.
+func (sm *ShardedTaskManager) ScheduleOnce(task Task, delay time.Duration) (string, error) {
+	job, err := onceJob(sm.shards[0].newJobID(), task, delay, sm.shards[0].clock.Now())
+	if err != nil {
+		return "", err
+	}
+	return job.ID, sm.ScheduleJob(job)
+}
+
+// RemoveJob removes a job from its shard, see TaskManager.RemoveJob.
+func (sm *ShardedTaskManager) RemoveJob(jobID string) error {
+	return sm.Shard(jobID).RemoveJob(jobID)
+}
+
+// ReplaceJob replaces a job in its shard, see TaskManager.ReplaceJob.
+func (sm *ShardedTaskManager) ReplaceJob(newJob Job) error {
+	return sm.Shard(newJob.ID).ReplaceJob(newJob)
+}
+
+// AddTaskToJob adds a task to a job in its shard, see TaskManager.AddTaskToJob.
+func (sm *ShardedTaskManager) AddTaskToJob(jobID string, task Task) error {
+	return sm.Shard(jobID).AddTaskToJob(jobID, task)
+}
+
+// RemoveTaskFromJob removes a task from a job in its shard, see TaskManager.RemoveTaskFromJob.
+func (sm *ShardedTaskManager) RemoveTaskFromJob(jobID string, index int) error {
+	return sm.Shard(jobID).RemoveTaskFromJob(jobID, index)
+}
+
+// PauseJob pauses a job, see TaskManager.PauseJob.
+func (sm *ShardedTaskManager) PauseJob(jobID string) error {
+	return sm.Shard(jobID).PauseJob(jobID)
+}
+
+// ResumeJob resumes a paused job, see TaskManager.ResumeJob.
+func (sm *ShardedTaskManager) ResumeJob(jobID string) error {
+	return sm.Shard(jobID).ResumeJob(jobID)
+}
+
+// TriggerJob executes a job immediately, see TaskManager.TriggerJob.
+func (sm *ShardedTaskManager) TriggerJob(jobID string) error {
+	return sm.Shard(jobID).TriggerJob(jobID)
+}
+
+// RunJobNow executes a job immediately and waits for its result, see TaskManager.RunJobNow.
+func (sm *ShardedTaskManager) RunJobNow(ctx context.Context, jobID string) (JobResult, error) {
+	return sm.Shard(jobID).RunJobNow(ctx, jobID)
+}
+
+// AwaitRun blocks until the next run of a job finishes, see TaskManager.AwaitRun.
+func (sm *ShardedTaskManager) AwaitRun(ctx context.Context, jobID string) (JobResult, error) {
+	return sm.Shard(jobID).AwaitRun(ctx, jobID)
+}
+
+// Job returns a snapshot of the job with the given ID.
+func (sm *ShardedTaskManager) Job(jobID string) (JobInfo, error) {
+	return sm.Shard(jobID).Job(jobID)
+}
+
+// JobStats returns the execution statistics of the job with the given ID.
+func (sm *ShardedTaskManager) JobStats(jobID string) (JobStats, error) {
+	return sm.Shard(jobID).JobStats(jobID)
+}
+
+// JobHistory returns the records of the recent completed runs of the job with the given ID.
+func (sm *ShardedTaskManager) JobHistory(jobID string) ([]RunRecord, error) {
+	return sm.Shard(jobID).JobHistory(jobID)
+}
+
+// Jobs returns a snapshot of the scheduled jobs of all shards, ordered by their next execution.
+// Each shard is read separately, so the snapshot is not atomic across shards.
+func (sm *ShardedTaskManager) Jobs() []JobInfo {
+	var jobs []JobInfo
+	for _, shard := range sm.shards {
+		jobs = append(jobs, shard.Jobs()...)
+	}
+	slices.SortFunc(jobs, func(a, b JobInfo) int {
+		return a.NextExec.Compare(b.NextExec)
+	})
+	return jobs
+}
+
+// QueueSnapshot returns the next runs of up to limit scheduled jobs of all shards, ordered by when
+// they are due. A limit of 0 or less returns the next runs of all jobs which can be dispatched.
+func (sm *ShardedTaskManager) QueueSnapshot(limit int) []QueuedRun {
+	var runs []QueuedRun
+	for _, shard := range sm.shards {
+		runs = append(runs, shard.QueueSnapshot(limit)...)
+	}
+	return sortQueuedRuns(runs, limit)
+}
+
+// JobsByTag returns a snapshot of the scheduled jobs of all shards with the given tag, ordered by
+// their next execution.
+func (sm *ShardedTaskManager) JobsByTag(tag string) []JobInfo {
+	var jobs []JobInfo
+	for _, shard := range sm.shards {
+		jobs = append(jobs, shard.JobsByTag(tag)...)
+	}
+	slices.SortFunc(jobs, func(a, b JobInfo) int {
+		return a.NextExec.Compare(b.NextExec)
+	})
+	return jobs
+}
+
+// PauseJobsByTag pauses the jobs of all shards with the given tag, returning the number of jobs
+// paused. The jobs of each shard are paused atomically, but not across shards.
+func (sm *ShardedTaskManager) PauseJobsByTag(tag string) int {
+	var paused int
+	for _, shard := range sm.shards {
+		paused += shard.PauseJobsByTag(tag)
+	}
+	return paused
+}
+
+// ResumeJobsByTag resumes the paused jobs of all shards with the given tag, returning the number
+// of jobs resumed.
+func (sm *ShardedTaskManager) ResumeJobsByTag(tag string) int {
+	var resumed int
+	for _, shard := range sm.shards {
+		resumed += shard.ResumeJobsByTag(tag)
+	}
+	return resumed
+}
+
+// RemoveJobsByTag removes the jobs of all shards with the given tag, returning the IDs of the
+// removed jobs. The jobs of each shard are removed atomically, but not across shards.
+func (sm *ShardedTaskManager) RemoveJobsByTag(tag string) []string {
+	var removed []string
+	for _, shard := range sm.shards {
+		removed = append(removed, shard.RemoveJobsByTag(tag)...)
+	}
+	slices.Sort(removed)
+	return removed
+}
+
+// RestoreJobs schedules the jobs in the job store which are not already scheduled, each on its
+// shard, see TaskManager.RestoreJobs. The shards must share the store, set with WithJobStore.
+func (sm *ShardedTaskManager) RestoreJobs(resolve func(record JobRecord) ([]Task, error)) error {
+	first := sm.shards[0]
+	first.RLock()
+	store := first.store
+	first.RUnlock()
+	if store == nil {
+		return errors.New("no job store set")
+	}
+
+	records, err := store.List()
+	if err != nil {
+		return fmt.Errorf("failed to list stored jobs: %w", err)
+	}
+
+	var errs []error
+	for _, record := range records {
+		if err := sm.Shard(record.ID).restoreRecord(store, record, resolve); err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", record.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Metrics returns the metrics of all shards combined. Counts are summed, the widest job is the
+// widest of all shards, averages are weighted by the shards' executions and workers, and the
+// dispatch lateness is the highest of all shards.
+func (sm *ShardedTaskManager) Metrics() TaskManagerMetrics {
+	var combined TaskManagerMetrics
+	var execTime time.Duration
+	var activeWorkers float32
+	for _, shard := range sm.shards {
+		metrics := shard.Metrics()
+		combined.QueueMaxJobWidth = max(combined.QueueMaxJobWidth, metrics.QueueMaxJobWidth)
+		combined.QueuedJobs += metrics.QueuedJobs
+		combined.QueuedTasks += metrics.QueuedTasks
+		combined.JobsOverrunning += metrics.JobsOverrunning
+		combined.DispatchLateness = maxLateness(combined.DispatchLateness, metrics.DispatchLateness)
+		combined.RunsShed += metrics.RunsShed
+		combined.TasksSpilled += metrics.TasksSpilled
+		combined.DispatchBlocked += metrics.DispatchBlocked
+		execTime += metrics.TaskAverageExecTime * time.Duration(metrics.TasksTotalExecutions)
+		combined.TasksTotalExecutions += metrics.TasksTotalExecutions
+		combined.TasksPerSecond += metrics.TasksPerSecond
+		combined.TaskTotalExecTime += metrics.TaskTotalExecTime
+		combined.TaskTotalCPUTime += metrics.TaskTotalCPUTime
+		combined.DroppedErrors += metrics.DroppedErrors
+		combined.DroppedResults += metrics.DroppedResults
+		combined.DroppedExecTimes += metrics.DroppedExecTimes
+		combined.ResultsBlocked += metrics.ResultsBlocked
+		combined.RunsCoalesced += metrics.RunsCoalesced
+		combined.WorkerCountTarget += metrics.WorkerCountTarget
+		combined.WorkerScalingEvents += metrics.WorkerScalingEvents
+		activeWorkers += metrics.WorkerUtilization * float32(metrics.WorkersRunning)
+		combined.WorkersActive += metrics.WorkersActive
+		combined.WorkersDraining += metrics.WorkersDraining
+		combined.WorkersRunning += metrics.WorkersRunning
+	}
+	if combined.TasksTotalExecutions > 0 {
+		combined.TaskAverageExecTime = execTime / time.Duration(combined.TasksTotalExecutions)
+	}
+	if combined.WorkersRunning > 0 {
+		combined.WorkerUtilization = activeWorkers / float32(combined.WorkersRunning)
+	}
+	return combined
+}
+
+// SubscribeErrors returns a channel receiving the errors of all shards, see
+// TaskManager.SubscribeErrors, along with a function cancelling the subscription. The bufferSize
+// applies to each shard's subscription.
+func (sm *ShardedTaskManager) SubscribeErrors(bufferSize int) (<-chan error, func()) {
+	merged := make(chan error, bufferSize)
+	unsubscribes := make([]func(), len(sm.shards))
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, shard := range sm.shards {
+		errs, unsubscribe := shard.SubscribeErrors(bufferSize)
+		unsubscribes[i] = unsubscribe
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for err := range errs {
+				select {
+				case merged <- err:
+				case <-done:
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	var once sync.Once
+	return merged, func() {
+		once.Do(func() {
+			close(done)
+			for _, unsubscribe := range unsubscribes {
+				unsubscribe()
+			}
+		})
+	}
+}
+
+// Stop stops all shards, see TaskManager.Stop.
+// Note: blocks until all shards have completely stopped.
+func (sm *ShardedTaskManager) Stop() {
+	var wg sync.WaitGroup
+	for _, shard := range sm.shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shard.Stop()
+		}()
+	}
+	wg.Wait()
+}
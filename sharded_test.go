@@ -0,0 +1,210 @@
+package taskman
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSharded(t *testing.T) {
+	manager := NewSharded(4, WithWorkers(1))
+	defer manager.Stop()
+	assert.Len(t, manager.Shards(), 4)
+
+	// Jobs are assigned to the same shard by ID
+	for i := range 20 {
+		jobID := fmt.Sprintf("job-%d", i)
+		assert.Same(t, manager.Shard(jobID), manager.Shard(jobID), "Expected job %s to map to one shard", jobID)
+	}
+
+	// The shards share one event log
+	logged := NewSharded(2, WithWorkers(1), WithEventLog(io.Discard))
+	defer logged.Stop()
+	shards := logged.Shards()
+	assert.NotNil(t, shards[0].eventLog.Load())
+	assert.Same(t, shards[0].eventLog.Load(), shards[1].eventLog.Load(), "Expected the shards to share the event log")
+
+	assert.Panics(t, func() { NewSharded(0) }, "Expected a panic for no shards")
+	assert.Panics(t, func() { NewSharded(2, WithDistributedLock(NewMemoryLock().Holder(), time.Second)) },
+		"Expected a panic for a distributed lock")
+}
+
+func TestShardedScheduling(t *testing.T) {
+	manager := NewSharded(4, WithWorkers(1))
+	defer manager.Stop()
+
+	var jobs []Job
+	for i := range 40 {
+		jobs = append(jobs, getMockedJob(2, fmt.Sprintf("job-%d", i), time.Hour, time.Hour))
+	}
+	assert.NoError(t, manager.ScheduleJobs(jobs))
+
+	// The jobs are spread across the shards, each scheduled on the shard of its ID
+	used := 0
+	for _, shard := range manager.Shards() {
+		if shard.jobsInQueue() > 0 {
+			used++
+		}
+	}
+	assert.Greater(t, used, 1, "Expected jobs to be spread over several shards")
+	for _, job := range jobs {
+		_, err := manager.Shard(job.ID).Job(job.ID)
+		assert.NoError(t, err, "Expected job %s on its shard", job.ID)
+	}
+	assert.Len(t, manager.Jobs(), 40)
+	metrics := manager.Metrics()
+	assert.Equal(t, 40, metrics.QueuedJobs)
+	assert.Equal(t, 80, metrics.QueuedTasks)
+	assert.Equal(t, 2, metrics.QueueMaxJobWidth)
+
+	// A failed batch leaves none of its jobs scheduled on any shard
+	invalid := getMockedJob(1, "invalid-job", time.Hour, time.Hour)
+	invalid.Tasks = nil
+	batch := []Job{invalid}
+	for i := range 10 {
+		batch = append(batch, getMockedJob(1, fmt.Sprintf("batch-%d", i), time.Hour, time.Hour))
+	}
+	assert.Error(t, manager.ScheduleJobs(batch))
+	assert.Len(t, manager.Jobs(), 40, "Expected no job of the failed batch to be scheduled")
+
+	assert.NoError(t, manager.RemoveJob("job-0"))
+	assert.Len(t, manager.Jobs(), 39)
+}
+
+func TestShardedJobs(t *testing.T) {
+	manager := NewSharded(3, WithWorkers(1))
+	defer manager.Stop()
+
+	executed := make(chan struct{}, 1)
+	jobID, err := manager.ScheduleFunc(func() error {
+		executed <- struct{}{}
+		return errors.New("task failed")
+	}, time.Hour)
+	assert.NoError(t, err)
+	errs, unsubscribe := manager.SubscribeErrors(4)
+	defer unsubscribe()
+
+	assert.NoError(t, manager.TriggerJob(jobID))
+	select {
+	case <-executed:
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the triggered job to execute")
+	}
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "task failed")
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the error to be delivered to the merged subscription")
+	}
+	assert.Eventually(t, func() bool {
+		stats, err := manager.JobStats(jobID)
+		return err == nil && stats.TotalRuns == 1
+	}, 50*time.Millisecond, time.Millisecond)
+
+	assert.NoError(t, manager.PauseJob(jobID))
+	info, err := manager.Job(jobID)
+	assert.NoError(t, err)
+	assert.True(t, info.Paused)
+	assert.NoError(t, manager.ResumeJob(jobID))
+
+	cronID, err := manager.ScheduleCron(MockTask{}, "@hourly")
+	assert.NoError(t, err)
+	_, err = manager.Shard(cronID).Job(cronID)
+	assert.NoError(t, err, "Expected the cron job on the shard of its ID")
+	onceID, err := manager.ScheduleOnce(MockTask{}, time.Hour)
+	assert.NoError(t, err)
+	_, err = manager.Shard(onceID).Job(onceID)
+	assert.NoError(t, err, "Expected the one-shot job on the shard of its ID")
+
+	// Tagged jobs are operated on across all shards
+	for i := range 9 {
+		job := getMockedJob(1, fmt.Sprintf("tenant-job-%d", i), time.Hour, time.Hour)
+		job.Tags = []string{"tenant"}
+		assert.NoError(t, manager.ScheduleJob(job))
+	}
+	assert.Len(t, manager.JobsByTag("tenant"), 9)
+	assert.Equal(t, 9, manager.PauseJobsByTag("tenant"))
+	assert.Equal(t, 9, manager.ResumeJobsByTag("tenant"))
+	assert.Len(t, manager.RemoveJobsByTag("tenant"), 9)
+	assert.Len(t, manager.Jobs(), 3)
+
+	manager.Stop()
+	_, ok := <-errs
+	assert.False(t, ok, "Expected the merged subscription to be closed when the shards stop")
+}
+
+func TestShardedRestoreJobs(t *testing.T) {
+	store := NewMemoryJobStore()
+	for i := range 10 {
+		record := JobRecord{ID: fmt.Sprintf("stored-%d", i), Cadence: time.Hour, NextExec: time.Now().Add(time.Hour)}
+		assert.NoError(t, store.Save(record))
+	}
+
+	manager := NewSharded(3, WithWorkers(1), WithJobStore(store))
+	defer manager.Stop()
+	err := manager.RestoreJobs(func(record JobRecord) ([]Task, error) {
+		return []Task{MockTask{}}, nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, manager.Jobs(), 10, "Expected each job to be restored once")
+	for i := range 10 {
+		jobID := fmt.Sprintf("stored-%d", i)
+		_, err := manager.Shard(jobID).Job(jobID)
+		assert.NoError(t, err, "Expected job %s restored on its shard", jobID)
+	}
+}
+
+func TestShardedScheduleJobsRollback(t *testing.T) {
+	store := NewMemoryJobStore()
+	manager := NewSharded(3, WithWorkers(1), WithJobStore(store))
+	defer manager.Stop()
+	shards := manager.Shards()
+	var removed atomic.Int32
+	for _, shard := range shards {
+		shard.OnJobRemoved(func(string) { removed.Add(1) })
+	}
+
+	// The batch fails on the last shard, after the jobs of the other shards have been scheduled
+	var batch []Job
+	stored := make(map[string]bool)
+	for i := 0; len(batch) < 10; i++ {
+		jobID := fmt.Sprintf("rollback-%d", i)
+		if manager.Shard(jobID) == shards[len(shards)-1] {
+			continue
+		}
+		if i%2 == 0 {
+			// Jobs with a record stored before the batch resume their stored schedule
+			record := JobRecord{ID: jobID, Cadence: time.Hour, NextExec: time.Now().Add(time.Hour)}
+			assert.NoError(t, store.Save(record))
+			stored[jobID] = true
+		}
+		batch = append(batch, getMockedJob(1, jobID, time.Hour, time.Hour))
+	}
+	for i := 0; ; i++ {
+		jobID := fmt.Sprintf("invalid-%d", i)
+		if manager.Shard(jobID) == shards[len(shards)-1] {
+			invalid := getMockedJob(1, jobID, time.Hour, time.Hour)
+			invalid.Tasks = nil
+			batch = append(batch, invalid)
+			break
+		}
+	}
+	assert.Error(t, manager.ScheduleJobs(batch))
+
+	// The scheduled jobs are removed, while the records stored before the batch are kept
+	assert.Empty(t, manager.Jobs(), "Expected no job of the failed batch to be scheduled")
+	assert.Zero(t, removed.Load(), "Expected no removal hooks for a rolled back batch")
+	for _, job := range batch[:len(batch)-1] {
+		_, err := store.Load(job.ID)
+		if stored[job.ID] {
+			assert.NoError(t, err, "Expected the record of job %s to be kept", job.ID)
+		} else {
+			assert.ErrorIs(t, err, ErrJobNotFound, "Expected the record of job %s to be deleted", job.ID)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Package starlarktask provides a taskman task type executing Starlark scripts, a Python dialect
+// designed for embedding, so that the logic of scheduled jobs, e.g. small maintenance scripts, can
+// be changed without recompiling the application. Scripts read from a file are read again at every
+// execution, picking up changes to the file.
+//
+// A script is executed with the following predeclared names:
+//
+//	args      dict of the task's Args
+//	metadata  dict of the metadata of the task's job
+//	json      the Starlark json module, with encode and decode
+//	time      the Starlark time module
+//
+// If the script defines a function named run, it is called without arguments once the script has
+// been executed, and a dict it returns is the output of the task. Lines printed by the script are
+// part of the output under "output". The task fails if the script fails, e.g. by calling fail.
+//
+//	def run():
+//	    if args["mode"] != "dry-run":
+//	        fail("unsupported mode", args["mode"])
+//	    return {"checked": 3}
+package starlarktask
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	taskman "github.com/jkbrsn/go-taskman"
+	starlarkjson "go.starlark.net/lib/json"
+	starlarktime "go.starlark.net/lib/time"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// TaskType is the name Task is registered with by Register.
+const TaskType = "starlark"
+
+// Task is a taskman task executing a Starlark script, given as source or as the path of a file.
+// Tasks are serialized as JSON, e.g. in a schedule file:
+//
+//	task: starlark
+//	params: {file: /etc/app/cleanup.star, args: {mode: dry-run}}
+type Task struct {
+	Script string            `json:"script,omitempty"` // Source of the script, if File is empty
+	File   string            `json:"file,omitempty"`   // Path of the script, read at every execution
+	Args   map[string]string `json:"args,omitempty"`   // Arguments of the script, as the args dict
+}
+
+// Register registers the task type with the TaskManager, validating tasks when they are
+// deserialized, e.g. from a schedule file or a JobStore.
+func Register(manager *taskman.TaskManager) error {
+	return manager.RegisterTaskType(TaskType, func(data []byte) (taskman.Task, error) {
+		var task Task
+		if err := json.Unmarshal(data, &task); err != nil {
+			return nil, err
+		}
+		if err := task.validate(); err != nil {
+			return nil, err
+		}
+		return task, nil
+	})
+}
+
+// validate returns an error unless the task has exactly one of Script and File.
+func (t Task) validate() error {
+	if (t.Script == "") == (t.File == "") {
+		return errors.New("starlark task must have either a script or a file")
+	}
+	return nil
+}
+
+// Execute executes the script with a background context.
+func (t Task) Execute() error {
+	_, err := t.ExecuteResult(context.Background())
+	return err
+}
+
+// ExecuteResult executes the script, returning its output. The script is cancelled when the
+// context is done.
+func (t Task) ExecuteResult(ctx context.Context) (map[string]any, error) {
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	filename, src := "script.star", t.Script
+	if t.File != "" {
+		data, err := os.ReadFile(t.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read script: %w", err)
+		}
+		filename, src = t.File, string(data)
+	}
+
+	var mu sync.Mutex
+	var output []any
+	thread := &starlark.Thread{
+		Name: filename,
+		Print: func(_ *starlark.Thread, msg string) {
+			mu.Lock()
+			defer mu.Unlock()
+			output = append(output, msg)
+		},
+	}
+	stop := context.AfterFunc(ctx, func() { thread.Cancel(ctx.Err().Error()) })
+	defer stop()
+
+	predeclared := starlark.StringDict{
+		"args":     stringDict(t.Args),
+		"metadata": stringDict(taskman.JobMetadata(ctx)),
+		"json":     starlarkjson.Module,
+		"time":     starlarktime.Module,
+	}
+	opts := &syntax.FileOptions{While: true, TopLevelControl: true, GlobalReassign: true}
+	globals, err := starlark.ExecFileOptions(opts, thread, filename, src, predeclared)
+	if err != nil {
+		return nil, scriptError(err)
+	}
+
+	data := map[string]any{}
+	if run, ok := globals["run"]; ok {
+		value, err := starlark.Call(thread, run, nil, nil)
+		if err != nil {
+			return nil, scriptError(err)
+		}
+		if dict, ok := value.(*starlark.Dict); ok {
+			data = toGo(dict).(map[string]any)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(output) > 0 {
+		data["output"] = output
+	}
+	return data, nil
+}
+
+// TaskType returns the name Task is registered with.
+func (t Task) TaskType() string { return TaskType }
+
+// MarshalTask serializes the task as JSON.
+func (t Task) MarshalTask() ([]byte, error) { return json.Marshal(t) }
+
+// scriptError returns the error of a failed script, with the Starlark backtrace of evaluation
+// errors.
+func scriptError(err error) error {
+	var evalErr *starlark.EvalError
+	if errors.As(err, &evalErr) {
+		return fmt.Errorf("script failed: %s", evalErr.Backtrace())
+	}
+	return fmt.Errorf("script failed: %w", err)
+}
+
+// stringDict returns a Starlark dict of the map's entries.
+func stringDict(m map[string]string) *starlark.Dict {
+	dict := starlark.NewDict(len(m))
+	for key, value := range m {
+		_ = dict.SetKey(starlark.String(key), starlark.String(value))
+	}
+	return dict
+}
+
+// toGo converts a Starlark value to its Go equivalent, and values without one to their string
+// representation. Dict keys are converted to strings.
+func toGo(value starlark.Value) any {
+	switch v := value.(type) {
+	case starlark.NoneType:
+		return nil
+	case starlark.Bool:
+		return bool(v)
+	case starlark.Int:
+		if i, ok := v.Int64(); ok {
+			return i
+		}
+		return v.String()
+	case starlark.Float:
+		return float64(v)
+	case starlark.String:
+		return string(v)
+	case *starlark.List:
+		list := make([]any, v.Len())
+		for i := range list {
+			list[i] = toGo(v.Index(i))
+		}
+		return list
+	case starlark.Tuple:
+		list := make([]any, len(v))
+		for i, elem := range v {
+			list[i] = toGo(elem)
+		}
+		return list
+	case *starlark.Dict:
+		m := make(map[string]any, v.Len())
+		for _, item := range v.Items() {
+			key, ok := starlark.AsString(item[0])
+			if !ok {
+				key = item[0].String()
+			}
+			m[key] = toGo(item[1])
+		}
+		return m
+	default:
+		return v.String()
+	}
+}
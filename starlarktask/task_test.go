@@ -0,0 +1,88 @@
+package starlarktask
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	taskman "github.com/jkbrsn/go-taskman"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTask(t *testing.T) {
+	task := Task{
+		Script: `
+print("checking", args["target"])
+def run():
+    return {"target": args["target"], "count": 3, "items": [1, "two", None], "ok": True}
+`,
+		Args: map[string]string{"target": "cache"},
+	}
+	data, err := task.ExecuteResult(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"target": "cache",
+		"count":  int64(3),
+		"items":  []any{int64(1), "two", nil},
+		"ok":     true,
+		"output": []any{"checking cache"},
+	}, data)
+
+	err = Task{Script: `fail("disk full")`}.Execute()
+	assert.ErrorContains(t, err, "disk full")
+	assert.Error(t, Task{Script: "def run(:"}.Execute(), "Expected error executing an invalid script")
+	assert.Error(t, Task{}.Execute(), "Expected error executing a task without a script")
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := Task{Script: "while True:\n    pass\n"}.ExecuteResult(ctx)
+		assert.ErrorContains(t, err, context.DeadlineExceeded.Error())
+	})
+
+	t.Run("File", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "script.star")
+		assert.NoError(t, os.WriteFile(path, []byte(`def run():
+    return {"version": 1}
+`), 0o600))
+		task := Task{File: path}
+		data, err := task.ExecuteResult(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), data["version"])
+
+		// The file is read again at every execution
+		assert.NoError(t, os.WriteFile(path, []byte(`def run():
+    return {"version": 2}
+`), 0o600))
+		data, err = task.ExecuteResult(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), data["version"])
+	})
+}
+
+func TestRegister(t *testing.T) {
+	manager := taskman.New(taskman.WithWorkers(1))
+	defer manager.Stop()
+	assert.NoError(t, Register(manager))
+
+	file, err := taskman.ParseScheduleFile([]byte(`
+jobs:
+  - name: cleanup
+    task: starlark
+    params: {script: "print(args['mode'])", args: {mode: dry-run}}
+    cadence: 1h
+  - name: invalid
+    task: starlark
+    params: {}
+    cadence: 1h
+`))
+	assert.NoError(t, err)
+	assert.Error(t, manager.LoadSchedule(file), "Expected error loading a starlark task without a script")
+
+	file.Jobs = file.Jobs[:1]
+	assert.NoError(t, manager.LoadSchedule(file))
+	_, err = manager.Job("cleanup")
+	assert.NoError(t, err)
+}
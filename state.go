@@ -0,0 +1,71 @@
+package taskman
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// State is a serializable snapshot of the jobs scheduled in a TaskManager, as exported by
+// ExportState, e.g. to be encoded as JSON and imported into another TaskManager.
+type State struct {
+	Jobs []JobRecord // Records of the scheduled jobs, in the order they were scheduled
+}
+
+// ExportState returns a snapshot of the scheduled jobs, with their next executions, run counts
+// and histories, e.g. for a zero-downtime restart or for migrating the schedule to another
+// instance with ImportState. The tasks of each job are serialized if they are all a
+// SerializableTask, otherwise they are resolved when imported. Dead-lettered jobs are not
+// exported. Returns an error if a serializable task fails to serialize.
+func (tm *TaskManager) ExportState() (State, error) {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	jobs := slices.Clone(tm.jobQueue.jobs)
+	slices.SortFunc(jobs, func(a, b *Job) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+	state := State{Jobs: make([]JobRecord, 0, len(jobs))}
+	for _, job := range jobs {
+		record, err := job.record()
+		if err != nil {
+			return State{}, fmt.Errorf("job %s: %w", job.ID, err)
+		}
+		state.Jobs = append(state.Jobs, record)
+	}
+	return state, nil
+}
+
+// ImportState schedules the jobs of a state exported with ExportState, resuming their schedules,
+// run counts and histories. Tasks are deserialized or resolved as by RestoreJobs. Jobs past their
+// Until are not imported. The jobs are scheduled atomically as by ScheduleJobs, so if any of them
+// fails to be restored or scheduled, e.g. as a job with its ID is already scheduled, none of them
+// are.
+func (tm *TaskManager) ImportState(state State, resolve func(record JobRecord) ([]Task, error)) error {
+	now := tm.now()
+	jobs := make([]Job, 0, len(state.Jobs))
+	var errs []error
+	for _, record := range state.Jobs {
+		if !record.Until.IsZero() && now.After(record.Until) {
+			// The job's deadline passed since the state was exported
+			continue
+		}
+		tasks, err := tm.recordTasks(record, resolve)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", record.ID, err))
+			continue
+		}
+		job, err := record.job(tasks, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", record.ID, err))
+			continue
+		}
+		job.history = record.History
+		jobs = append(jobs, job)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return tm.ScheduleJobs(jobs)
+}
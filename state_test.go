@@ -0,0 +1,65 @@
+package taskman
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportImportState(t *testing.T) {
+	source := New(WithWorkers(1))
+	defer source.Stop()
+
+	greet := getMockedJob(1, "greet-job", time.Hour, time.Hour)
+	greet.Tasks = []Task{greetTask{Message: "hello"}}
+	greet.Tags = []string{"tenant"}
+	assert.NoError(t, source.ScheduleJob(greet))
+	opaque := getMockedJob(2, "opaque-job", time.Minute, 30*time.Minute)
+	assert.NoError(t, source.ScheduleJob(opaque))
+	assert.NoError(t, source.PauseJob(opaque.ID))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := source.RunJobNow(ctx, greet.ID)
+	assert.NoError(t, err)
+
+	state, err := source.ExportState()
+	assert.NoError(t, err)
+	assert.Len(t, state.Jobs, 2)
+	assert.Equal(t, greet.ID, state.Jobs[0].ID, "Expected the jobs in the order they were scheduled")
+
+	// The state survives serialization, and is imported into a fresh manager
+	data, err := json.Marshal(state)
+	assert.NoError(t, err)
+	var decoded State
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+
+	target := New(WithWorkers(1))
+	defer target.Stop()
+	assert.Error(t, target.ImportState(decoded, nil), "Expected error importing tasks of an unregistered type")
+	assert.Empty(t, target.Jobs(), "Expected no jobs imported when any job fails")
+
+	assert.NoError(t, target.RegisterTaskType("greet", JSONTaskFactory[greetTask]()))
+	resolve := func(record JobRecord) ([]Task, error) {
+		return []Task{MockTask{ID: "resolved-1"}, MockTask{ID: "resolved-2"}}, nil
+	}
+	assert.NoError(t, target.ImportState(decoded, resolve))
+
+	for _, jobID := range []string{greet.ID, opaque.ID} {
+		want, err := source.Job(jobID)
+		assert.NoError(t, err)
+		got, err := target.Job(jobID)
+		assert.NoError(t, err)
+		assert.True(t, want.NextExec.Equal(got.NextExec), "Expected the next execution of %s to be kept", jobID)
+		assert.Equal(t, want.Paused, got.Paused)
+		assert.Equal(t, want.Tags, got.Tags)
+		assert.Equal(t, want.TaskCount, got.TaskCount)
+	}
+	history, err := target.JobHistory(greet.ID)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1, "Expected the history of the job to be imported")
+
+	assert.Error(t, target.ImportState(decoded, resolve), "Expected error importing jobs already scheduled")
+}
@@ -0,0 +1,162 @@
+package taskman
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+)
+
+// JobStats holds execution statistics for a single job. A run of a job is considered failed if
+// any of its tasks failed.
+type JobStats struct {
+	TotalRuns           int           // Number of completed runs of the job
+	FailedRuns          int           // Number of runs in which at least one task failed
+	ConsecutiveFailures int           // Number of failed runs since the last successful run
+	SkippedRuns         int           // Number of runs skipped without being dispatched, e.g. as they were due longer ago than the job's MaxDelay
+	LastRun             time.Time     // Dispatch time of the last completed run
+	LastDuration        time.Duration // Duration of the last completed run
+	LastError           error         // Error of the last failed run, nil if no run has failed
+	AverageDuration     time.Duration // Average duration of the completed runs
+	Overrunning         bool          // True if the job is detected as overrunning, see SetOverrunDetection
+	Lateness            Lateness      // Lateness of the job's recent scheduled dispatches
+	TotalTaskTime       time.Duration // Cumulative wall time of the executions of the job's tasks
+	TotalCPUTime        time.Duration // Cumulative CPU time of the executions of the job's tasks, with SetCPUAccounting
+
+	TaskFailures map[string]int // Number of failed executions of each of the job's tasks with an ID, see NamedTask
+}
+
+// jobStats collects the execution statistics of a job, safe for concurrent use.
+type jobStats struct {
+	mu       sync.Mutex
+	stats    JobStats
+	lateness latenessWindow // Lateness of the job's recent dispatches
+	history  runHistory     // Records of the job's recent runs
+}
+
+// recordRun records the outcome of a completed run.
+func (js *jobStats) recordRun(start time.Time, duration time.Duration, err error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	js.stats.TotalRuns++
+	js.stats.LastRun = start
+	js.stats.LastDuration = duration
+	js.stats.AverageDuration += (duration - js.stats.AverageDuration) / time.Duration(js.stats.TotalRuns)
+	if err != nil {
+		js.stats.FailedRuns++
+		js.stats.ConsecutiveFailures++
+		js.stats.LastError = err
+	} else {
+		js.stats.ConsecutiveFailures = 0
+	}
+}
+
+// recordTaskTime records the wall time and CPU time of a task execution.
+func (js *jobStats) recordTaskTime(wall, cpu time.Duration) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.stats.TotalTaskTime += wall
+	js.stats.TotalCPUTime += cpu
+}
+
+// checkOverrun updates whether the job is overrunning, its average run duration exceeding the given
+// cadence after at least minRuns runs. Returns the updated statistics, and true if the job started
+// overrunning.
+func (js *jobStats) checkOverrun(cadence time.Duration, minRuns int) (JobStats, bool) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	overrunning := minRuns > 0 && cadence > 0 && js.stats.TotalRuns >= minRuns &&
+		js.stats.AverageDuration > cadence
+	started := overrunning && !js.stats.Overrunning
+	js.stats.Overrunning = overrunning
+	return js.stats, started
+}
+
+// recordDispatch records the lateness of a scheduled dispatch of the job.
+func (js *jobStats) recordDispatch(lateness time.Duration) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.lateness.record(lateness, jobLatenessSamples)
+}
+
+// recordSkip records a run of the job skipped without being dispatched.
+func (js *jobStats) recordSkip() {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.stats.SkippedRuns++
+}
+
+// recordTaskFailure records a failed execution of the job's task with the ID.
+func (js *jobStats) recordTaskFailure(taskID string) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	if js.stats.TaskFailures == nil {
+		js.stats.TaskFailures = make(map[string]int)
+	}
+	js.stats.TaskFailures[taskID]++
+}
+
+// recordHistory records a completed run in the job's history of the given size.
+func (js *jobStats) recordHistory(record RunRecord, size int) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.history.record(record, size)
+}
+
+// restoreHistory restores the job's history from stored records, keeping the most recent runs up
+// to the history's size.
+func (js *jobStats) restoreHistory(records []RunRecord, size int) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	for _, record := range records[max(len(records)-size, 0):] {
+		js.history.record(record, size)
+	}
+}
+
+// historyList returns the records of the job's recent runs, oldest first.
+func (js *jobStats) historyList() []RunRecord {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	return js.history.list()
+}
+
+// overrunning returns true if the job is detected as overrunning.
+func (js *jobStats) overrunning() bool {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	return js.stats.Overrunning
+}
+
+// resetFailures resets the number of consecutive failed runs.
+func (js *jobStats) resetFailures() {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.stats.ConsecutiveFailures = 0
+}
+
+// snapshot returns a copy of the current statistics.
+func (js *jobStats) snapshot() JobStats {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	stats := js.stats
+	stats.Lateness = js.lateness.summary()
+	stats.TaskFailures = maps.Clone(js.stats.TaskFailures)
+	return stats
+}
+
+// JobStats returns a snapshot of the execution statistics of the job with the given ID.
+func (tm *TaskManager) JobStats(jobID string) (JobStats, error) {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	if job, ok := tm.deadLetters[jobID]; ok {
+		return job.state.stats.snapshot(), nil
+	}
+	jobIndex, err := tm.jobQueue.JobInQueue(jobID)
+	if err != nil {
+		return JobStats{}, fmt.Errorf("job with ID %s not found", jobID)
+	}
+	return tm.jobQueue.jobs[jobIndex].state.stats.snapshot(), nil
+}
@@ -0,0 +1,74 @@
+package taskman
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobStatsRecordRun(t *testing.T) {
+	js := &jobStats{}
+	start := time.Now()
+
+	js.recordRun(start, 10*time.Millisecond, errors.New("failure"))
+	js.recordRun(start.Add(time.Second), 20*time.Millisecond, errors.New("another failure"))
+	stats := js.snapshot()
+	assert.Equal(t, 2, stats.TotalRuns)
+	assert.Equal(t, 2, stats.FailedRuns)
+	assert.Equal(t, 2, stats.ConsecutiveFailures)
+	assert.Equal(t, start.Add(time.Second), stats.LastRun)
+	assert.Equal(t, 20*time.Millisecond, stats.LastDuration)
+	assert.Equal(t, 15*time.Millisecond, stats.AverageDuration)
+	assert.EqualError(t, stats.LastError, "another failure")
+
+	js.recordRun(start.Add(2*time.Second), 5*time.Millisecond, nil)
+	stats = js.snapshot()
+	assert.Equal(t, 3, stats.TotalRuns)
+	assert.Equal(t, 2, stats.FailedRuns, "Expected failed runs to be kept after a success")
+	assert.Equal(t, 0, stats.ConsecutiveFailures, "Expected consecutive failures to reset after a success")
+	assert.InDelta(t, float64(35*time.Millisecond/3), float64(stats.AverageDuration), float64(time.Microsecond))
+}
+
+func TestManagerJobStats(t *testing.T) {
+	manager := NewCustom(2, 4, 1*time.Minute)
+	defer manager.Stop()
+
+	var executions atomic.Int32
+	job := Job{
+		ID:       "stats-job",
+		Cadence:  10 * time.Millisecond,
+		NextExec: time.Now(),
+		Tasks: []Task{MockTask{ID: "stats-task", executeFunc: func() error {
+			// Fail every other execution
+			if executions.Add(1)%2 == 0 {
+				return errors.New("even execution")
+			}
+			return nil
+		}}},
+	}
+	err := manager.ScheduleJob(job)
+	assert.NoError(t, err, "Expected no error scheduling job")
+
+	// Allow time for a few executions
+	time.Sleep(35 * time.Millisecond)
+
+	stats, err := manager.JobStats(job.ID)
+	assert.NoError(t, err, "Expected no error getting job stats")
+	assert.GreaterOrEqual(t, stats.TotalRuns, 3, "Expected at least 3 runs")
+	assert.GreaterOrEqual(t, stats.FailedRuns, 1, "Expected at least 1 failed run")
+	assert.LessOrEqual(t, stats.ConsecutiveFailures, 1, "Expected failures to never be consecutive")
+	assert.False(t, stats.LastRun.IsZero(), "Expected last run to be set")
+
+	// Stats are kept when the job is replaced
+	err = manager.ReplaceJob(getMockedJob(1, job.ID, 1*time.Second, 0))
+	assert.NoError(t, err)
+	replacedStats, err := manager.JobStats(job.ID)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, replacedStats.TotalRuns, stats.TotalRuns, "Expected stats to be kept after replacement")
+
+	_, err = manager.JobStats("non-existent-job")
+	assert.Error(t, err, "Expected error getting stats of non-existent job")
+}
@@ -0,0 +1,359 @@
+package taskman
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrJobNotFound is returned by a JobStore when no record exists for a job ID, and wrapped by the
+// errors of RemoveJob, PauseJob, ResumeJob and TriggerJob for a job which is not scheduled.
+var ErrJobNotFound = errors.New("job not found")
+
+// JobRecord is the persisted state of a scheduled job. Tasks are only part of the record if all of
+// the job's tasks implement SerializableTask, otherwise they are resolved when jobs are restored.
+type JobRecord struct {
+	ID                  string            // Unique ID of the job
+	Cadence             time.Duration     // Time between executions
+	Group               string            // Worker group of the job, if any
+	ConcurrencyGroup    string            // Concurrency group of the job, if any
+	Dedicated           bool              // True for jobs executed by workers of their own
+	Remote              bool              // True for jobs executed by remote workers
+	DependsOn           []string          // IDs of the jobs the job depends on, if any
+	NextExec            time.Time         // The next time the job should be executed, before jitter
+	CronExpr            string            // Cron expression of jobs scheduled with ScheduleCron
+	Once                bool              // True for jobs scheduled with ScheduleOnce
+	Paused              bool              // True for paused jobs
+	Jitter              float64           // Jitter of the job's executions
+	Align               bool              // True for jobs aligned to their cadence
+	OverlapPolicy       OverlapPolicy     // Overlap policy of the job
+	MaxConcurrent       int               // Max concurrently executing runs
+	MisfirePolicy       MisfirePolicy     // Misfire policy of the job
+	MaxDelay            time.Duration     // Max delay of the job's dispatches, if any
+	IdempotencyKey      string            // Idempotency key of the job, if any
+	Blackouts           []Blackout        // Blackout windows of the job, if any
+	Priority            int               // Priority of the job's runs under saturation
+	PanicPolicy         PanicPolicy       // Panic policy of the job
+	PanicThreshold      int               // Panic threshold of the job, if any
+	ExecutionMode       ExecutionMode     // Execution mode of the job's tasks
+	RetryPolicy         *RetryPolicy      // Retry policy of the job, if any
+	MaxDispatchRate     rate.Limit        // Max dispatch rate of the job, 0 for no limit
+	DeadLetterThreshold int               // Dead letter threshold of the job, if any
+	FailureBackoff      time.Duration     // Cap of the job's failure backoff, if any
+	MaxRuns             int               // Number of runs after which the job is removed, if any
+	Until               time.Time         // Time after which the job is removed, if any
+	IdleTTL             time.Duration     // Idle TTL of the job, if any
+	Runs                int               // Number of runs dispatched
+	Tags                []string          // Tags of the job, if any
+	Metadata            map[string]string // Metadata of the job, if any
+	Tasks               []TaskRecord      // Serialized tasks of the job, nil if not serializable
+	Template            string            // Name of the template the job was scheduled from, if any
+	TemplateParams      map[string]string // Parameters the job was created from by its template
+	History             []RunRecord       // Recent runs of the job, as of the record's save, see JobHistory
+}
+
+// JobStore persists the jobs of a TaskManager, allowing them to survive process restarts. Records
+// are saved when jobs are scheduled, replaced and executed, and deleted when jobs are removed.
+// Implementations must be safe for concurrent use.
+type JobStore interface {
+	// Save creates or overwrites the record with the record's ID.
+	Save(record JobRecord) error
+	// Load returns the record of the job with the given ID, or ErrJobNotFound.
+	Load(jobID string) (JobRecord, error)
+	// Delete deletes the record of the job with the given ID. Deleting a missing record is a no-op.
+	Delete(jobID string) error
+	// List returns all records in the store.
+	List() ([]JobRecord, error)
+}
+
+// MemoryJobStore is a JobStore keeping records in memory, e.g. for tests or for sharing state
+// between TaskManagers within one process.
+type MemoryJobStore struct {
+	mu      sync.RWMutex
+	records map[string]JobRecord
+}
+
+// NewMemoryJobStore creates an empty MemoryJobStore.
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{records: make(map[string]JobRecord)}
+}
+
+// Save creates or overwrites the record with the record's ID.
+func (s *MemoryJobStore) Save(record JobRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.ID] = record
+	return nil
+}
+
+// Load returns the record of the job with the given ID, or ErrJobNotFound.
+func (s *MemoryJobStore) Load(jobID string) (JobRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	record, ok := s.records[jobID]
+	if !ok {
+		return JobRecord{}, ErrJobNotFound
+	}
+	return record, nil
+}
+
+// Delete deletes the record of the job with the given ID.
+func (s *MemoryJobStore) Delete(jobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, jobID)
+	return nil
+}
+
+// List returns all records in the store.
+func (s *MemoryJobStore) List() ([]JobRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	records := make([]JobRecord, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// record returns the persisted state of the job. An error is returned if a serializable task
+// fails to serialize, in which case the record is returned without tasks.
+func (j *Job) record() (JobRecord, error) {
+	record := JobRecord{
+		ID:                  j.ID,
+		Cadence:             j.Cadence,
+		Group:               j.Group,
+		ConcurrencyGroup:    j.ConcurrencyGroup,
+		Dedicated:           j.Dedicated,
+		Remote:              j.Remote,
+		DependsOn:           j.DependsOn,
+		NextExec:            j.scheduled,
+		Once:                j.once,
+		Paused:              j.paused,
+		Jitter:              j.Jitter,
+		Align:               j.Align,
+		OverlapPolicy:       j.OverlapPolicy,
+		MaxConcurrent:       j.MaxConcurrent,
+		MisfirePolicy:       j.MisfirePolicy,
+		MaxDelay:            j.MaxDelay,
+		IdempotencyKey:      j.IdempotencyKey,
+		Blackouts:           j.Blackouts,
+		Priority:            j.Priority,
+		PanicPolicy:         j.PanicPolicy,
+		PanicThreshold:      j.PanicThreshold,
+		ExecutionMode:       j.ExecutionMode,
+		RetryPolicy:         j.RetryPolicy,
+		MaxDispatchRate:     j.MaxDispatchRate,
+		DeadLetterThreshold: j.DeadLetterThreshold,
+		FailureBackoff:      j.FailureBackoff,
+		MaxRuns:             j.MaxRuns,
+		Until:               j.Until,
+		IdleTTL:             j.IdleTTL,
+		Runs:                j.runs,
+		Tags:                j.Tags,
+		Metadata:            j.Metadata,
+		History:             j.runHistory(),
+	}
+	if j.cron != nil {
+		record.CronExpr = j.cron.expr
+	}
+	if j.template != nil {
+		record.Template = j.template.name
+		record.TemplateParams = j.template.params
+	}
+	tasks, err := marshalTasks(j.Tasks)
+	record.Tasks = tasks
+	return record, err
+}
+
+// SetJobStore sets the store in which the TaskManager persists its jobs. Jobs scheduled with the
+// ID of a stored record resume the record's schedule, rather than the schedule they were given.
+// Set the store before scheduling any jobs, and use RestoreJobs to reschedule all stored jobs.
+func (tm *TaskManager) SetJobStore(store JobStore) {
+	tm.Lock()
+	defer tm.Unlock()
+	tm.store = store
+}
+
+// RestoreJobs schedules all jobs in the job store which are not already scheduled. Serialized
+// tasks are deserialized with the factories registered with RegisterTaskType. For records without
+// serialized tasks, resolve is called to provide the tasks of the job, and may be nil if all tasks
+// are serializable. Records of jobs past their Until are deleted rather than restored. Records
+// failing to restore or schedule are skipped, and their errors are joined in the returned error.
+func (tm *TaskManager) RestoreJobs(resolve func(record JobRecord) ([]Task, error)) error {
+	tm.RLock()
+	store := tm.store
+	tm.RUnlock()
+	if store == nil {
+		return errors.New("no job store set")
+	}
+
+	records, err := store.List()
+	if err != nil {
+		return fmt.Errorf("failed to list stored jobs: %w", err)
+	}
+
+	var errs []error
+	for _, record := range records {
+		if err := tm.restoreRecord(store, record, resolve); err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", record.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// restoreRecord schedules the job of a stored record, unless it is already scheduled or past its
+// Until, in which case the record is deleted.
+func (tm *TaskManager) restoreRecord(store JobStore, record JobRecord, resolve func(record JobRecord) ([]Task, error)) error {
+	if _, err := tm.Job(record.ID); err == nil {
+		// Already scheduled
+		return nil
+	}
+	if !record.Until.IsZero() && tm.now().After(record.Until) {
+		// The job's deadline passed while it was not scheduled
+		tm.unpersistJob(store, record.ID)
+		return nil
+	}
+	tasks, err := tm.recordTasks(record, resolve)
+	if err != nil {
+		return err
+	}
+	job, err := record.job(tasks, tm.now())
+	if err != nil {
+		return err
+	}
+	return tm.ScheduleJob(job)
+}
+
+// recordTasks returns the tasks of a stored job, deserialized, created by the job's template, or
+// provided by resolve.
+func (tm *TaskManager) recordTasks(record JobRecord, resolve func(record JobRecord) ([]Task, error)) ([]Task, error) {
+	if record.Tasks != nil {
+		return tm.unmarshalTasks(record.Tasks)
+	}
+	if record.Template != "" {
+		job, err := tm.templateJob(record.Template, record.TemplateParams)
+		if err != nil {
+			return nil, err
+		}
+		return job.Tasks, nil
+	}
+	if resolve == nil {
+		return nil, errors.New("tasks not serialized, and no resolve function provided")
+	}
+	return resolve(record)
+}
+
+// job returns a job with the record's state and the given tasks, at the time now.
+func (r JobRecord) job(tasks []Task, now time.Time) (Job, error) {
+	job := Job{
+		Cadence:             r.Cadence,
+		Group:               r.Group,
+		ConcurrencyGroup:    r.ConcurrencyGroup,
+		Dedicated:           r.Dedicated,
+		Remote:              r.Remote,
+		DependsOn:           r.DependsOn,
+		Tasks:               tasks,
+		RetryPolicy:         r.RetryPolicy,
+		MaxDispatchRate:     r.MaxDispatchRate,
+		DeadLetterThreshold: r.DeadLetterThreshold,
+		FailureBackoff:      r.FailureBackoff,
+		MaxRuns:             r.MaxRuns,
+		Until:               r.Until,
+		IdleTTL:             r.IdleTTL,
+		Tags:                r.Tags,
+		Metadata:            r.Metadata,
+		OverlapPolicy:       r.OverlapPolicy,
+		MaxConcurrent:       r.MaxConcurrent,
+		MisfirePolicy:       r.MisfirePolicy,
+		MaxDelay:            r.MaxDelay,
+		IdempotencyKey:      r.IdempotencyKey,
+		Blackouts:           r.Blackouts,
+		Priority:            r.Priority,
+		PanicPolicy:         r.PanicPolicy,
+		PanicThreshold:      r.PanicThreshold,
+		ExecutionMode:       r.ExecutionMode,
+		Jitter:              r.Jitter,
+		Align:               r.Align,
+		ID:                  r.ID,
+		NextExec:            r.NextExec,
+		once:                r.Once,
+		paused:              r.Paused,
+		runs:                r.Runs,
+	}
+	if r.CronExpr != "" {
+		schedule, err := parseCron(r.CronExpr)
+		if err != nil {
+			return Job{}, err
+		}
+		job.cron = schedule
+		job.Cadence = schedule.interval(now)
+	}
+	if r.Template != "" {
+		job.template = &templateRef{name: r.Template, params: r.TemplateParams}
+	}
+	return job, nil
+}
+
+// restoreJob resumes the schedule and run count of a stored record with the job's ID, if one
+// exists. A stored NextExec followed by further missed executions, e.g. after a long downtime, is
+// handled according to the job's misfire policy, either executing once now or skipping to the
+// job's next execution.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) restoreJob(job *Job) error {
+	record, err := tm.store.Load(job.ID)
+	if errors.Is(err, ErrJobNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load stored job %s: %w", job.ID, err)
+	}
+
+	now := tm.now()
+	job.scheduled = record.NextExec
+	switch {
+	case !job.misfired(now):
+		job.NextExec = record.NextExec
+	case job.MisfirePolicy == MisfireSkipToNext:
+		job.NextExec = job.firstAfter(now)
+	default:
+		job.NextExec = now
+	}
+	job.runs = record.Runs
+	job.history = record.History
+	job.restored = true
+	tm.logger.Debug("Restored schedule of job", "jobID", job.ID, "nextExec", job.NextExec)
+	return nil
+}
+
+// saveJob persists the current state of the job, if the TaskManager has a job store.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) saveJob(job *Job) {
+	if tm.store == nil {
+		return
+	}
+	record, err := job.record()
+	if err != nil {
+		tm.logger.Warn("Failed to serialize tasks of job", "jobID", job.ID, "error", err)
+	}
+	tm.persistJob(tm.store, record)
+}
+
+// persistJob saves a record in the job store. Errors are logged rather than returned, as it is
+// called after the job has been rescheduled.
+func (tm *TaskManager) persistJob(store JobStore, record JobRecord) {
+	if err := store.Save(record); err != nil {
+		tm.logger.Warn("Failed to save job to the job store", "jobID", record.ID, "error", err)
+	}
+}
+
+// unpersistJob deletes a record from the job store. Errors are logged rather than returned, as it
+// is called after the job has been removed.
+func (tm *TaskManager) unpersistJob(store JobStore, jobID string) {
+	if err := store.Delete(jobID); err != nil {
+		tm.logger.Warn("Failed to delete job from the job store", "jobID", jobID, "error", err)
+	}
+}
@@ -0,0 +1,124 @@
+package taskman
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryJobStore(t *testing.T) {
+	store := NewMemoryJobStore()
+
+	assert.NoError(t, store.Save(JobRecord{ID: "job-1", Cadence: time.Second}))
+	assert.NoError(t, store.Save(JobRecord{ID: "job-1", Cadence: time.Minute}), "Expected saving to overwrite")
+
+	record, err := store.Load("job-1")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, record.Cadence)
+
+	_, err = store.Load("unknown-job")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+
+	records, err := store.List()
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	assert.NoError(t, store.Delete("job-1"))
+	records, err = store.List()
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestJobStorePersistence(t *testing.T) {
+	store := NewMemoryJobStore()
+	manager := NewCustom(1, 1, 1*time.Minute)
+	defer manager.Stop()
+	manager.SetJobStore(store)
+
+	t.Run("Saved when scheduled", func(t *testing.T) {
+		job := getMockedJob(1, "stored-job", 1*time.Minute, 1*time.Minute)
+		job.RetryPolicy = &RetryPolicy{MaxAttempts: 2}
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		record, err := store.Load("stored-job")
+		assert.NoError(t, err, "Expected job to be saved")
+		assert.Equal(t, 1*time.Minute, record.Cadence)
+		assert.True(t, job.NextExec.Equal(record.NextExec))
+		assert.Equal(t, 2, record.RetryPolicy.MaxAttempts)
+
+		_, err = manager.ScheduleCron(MockTask{ID: "cron-task"}, "@daily")
+		assert.NoError(t, err)
+		records, err := store.List()
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+	})
+
+	t.Run("Saved when rescheduled", func(t *testing.T) {
+		job := getMockedJob(1, "rescheduled-job", 20*time.Millisecond, 0)
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		time.Sleep(10 * time.Millisecond)
+		record, err := store.Load("rescheduled-job")
+		assert.NoError(t, err)
+		assert.True(t, job.NextExec.Add(20*time.Millisecond).Equal(record.NextExec), "Expected the next execution to be saved")
+	})
+
+	t.Run("Deleted when removed", func(t *testing.T) {
+		assert.NoError(t, manager.RemoveJob("stored-job"))
+		_, err := store.Load("stored-job")
+		assert.ErrorIs(t, err, ErrJobNotFound, "Expected removed job to be deleted")
+
+		jobID, err := manager.ScheduleOnce(MockTask{ID: "once-task"}, 0)
+		assert.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+		_, err = store.Load(jobID)
+		assert.ErrorIs(t, err, ErrJobNotFound, "Expected executed one-shot job to be deleted")
+	})
+}
+
+func TestRestoreJobs(t *testing.T) {
+	store := NewMemoryJobStore()
+	nextExec := time.Now().Add(1 * time.Hour)
+	assert.NoError(t, store.Save(JobRecord{ID: "future-job", Cadence: 2 * time.Hour, NextExec: nextExec}))
+	assert.NoError(t, store.Save(JobRecord{ID: "overdue-job", Cadence: 1 * time.Minute, NextExec: time.Now().Add(-1 * time.Hour)}))
+	assert.NoError(t, store.Save(JobRecord{ID: "cron-job", CronExpr: "@hourly", NextExec: time.Now().Add(-1 * time.Hour), MisfirePolicy: MisfireSkipToNext}))
+	assert.NoError(t, store.Save(JobRecord{ID: "unresolved-job", Cadence: 1 * time.Minute, NextExec: nextExec}))
+	assert.NoError(t, store.Save(JobRecord{ID: "expired-job", Cadence: 1 * time.Minute, NextExec: nextExec, Until: time.Now().Add(-1 * time.Minute)}))
+
+	manager := NewCustom(1, 1, 1*time.Minute)
+	defer manager.Stop()
+
+	t.Run("No store", func(t *testing.T) {
+		assert.Error(t, manager.RestoreJobs(nil), "Expected error restoring without a store")
+	})
+
+	manager.SetJobStore(store)
+	// Jobs scheduled before restoring are kept, with the stored schedule resumed
+	assert.NoError(t, manager.ScheduleJob(getMockedJob(1, "future-job", 2*time.Hour, 2*time.Hour)))
+
+	err := manager.RestoreJobs(func(record JobRecord) ([]Task, error) {
+		if record.ID == "unresolved-job" {
+			return nil, errors.New("unknown task")
+		}
+		return []Task{MockTask{ID: record.ID}}, nil
+	})
+	assert.ErrorContains(t, err, "unresolved-job", "Expected the error of the unresolved job")
+	assert.Len(t, manager.Jobs(), 3, "Expected resolved jobs to be restored")
+
+	info, err := manager.Job("future-job")
+	assert.NoError(t, err)
+	assert.True(t, nextExec.Equal(info.NextExec), "Expected the stored NextExec to be resumed")
+
+	info, err = manager.Job("overdue-job")
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), info.NextExec, 1*time.Minute, "Expected overdue job to execute now")
+
+	info, err = manager.Job("cron-job")
+	assert.NoError(t, err)
+	assert.True(t, info.NextExec.After(time.Now()), "Expected overdue cron job to skip to its next match")
+
+	_, err = store.Load("expired-job")
+	assert.ErrorIs(t, err, ErrJobNotFound, "Expected the record of an expired job to be deleted")
+}
@@ -0,0 +1,101 @@
+package taskman
+
+import "slices"
+
+// JobsByTag returns a snapshot of all scheduled jobs with the given tag, ordered by their next
+// execution.
+func (tm *TaskManager) JobsByTag(tag string) []JobInfo {
+	tm.RLock()
+	var jobs []JobInfo
+	for _, job := range tm.taggedJobs(tag) {
+		jobs = append(jobs, job.info())
+	}
+	tm.RUnlock()
+
+	slices.SortFunc(jobs, func(a, b JobInfo) int {
+		return a.NextExec.Compare(b.NextExec)
+	})
+	return jobs
+}
+
+// PauseJobsByTag pauses all scheduled jobs with the given tag, as with PauseJob, returning the
+// number of jobs paused. The jobs are paused atomically, so no job with the tag is dispatched
+// after the call has begun pausing them, apart from runs already executing.
+func (tm *TaskManager) PauseJobsByTag(tag string) int {
+	tm.Lock()
+	defer tm.Unlock()
+
+	var paused int
+	for _, job := range tm.taggedJobs(tag) {
+		if job.paused {
+			continue
+		}
+		tm.pauseJob(job)
+		paused++
+	}
+	return paused
+}
+
+// ResumeJobsByTag resumes all paused jobs with the given tag, as with ResumeJob, returning the
+// number of jobs resumed.
+func (tm *TaskManager) ResumeJobsByTag(tag string) int {
+	tm.Lock()
+	defer tm.Unlock()
+
+	var resumed int
+	for _, job := range tm.taggedJobs(tag) {
+		if tm.resumeJob(job) {
+			resumed++
+		}
+	}
+	if resumed > 0 {
+		// Signal the run loop that the jobs may be due
+		tm.wakeRunLoop()
+	}
+	return resumed
+}
+
+// RemoveJobsByTag removes all scheduled and dead-lettered jobs with the given tag, as with
+// RemoveJob, returning the IDs of the removed jobs. The jobs are removed atomically, so no job
+// with the tag is dispatched after the call has begun removing them.
+func (tm *TaskManager) RemoveJobsByTag(tag string) []string {
+	var removed []string
+	func() {
+		tm.Lock()
+		defer tm.Unlock()
+
+		jobs := tm.taggedJobs(tag)
+		for _, job := range tm.deadLetters {
+			if slices.Contains(job.Tags, tag) {
+				jobs = append(jobs, job)
+			}
+		}
+		for _, job := range jobs {
+			if err := tm.discardJob(job); err != nil {
+				tm.logger.Warn("Failed to remove tagged job", "jobID", job.ID, "tag", tag, "error", err)
+				continue
+			}
+			removed = append(removed, job.ID)
+		}
+	}()
+
+	// Call the removal hooks without holding the lock, allowing them to use the TaskManager
+	for _, jobID := range removed {
+		tm.hooks.jobWasRemoved(jobID)
+	}
+	slices.Sort(removed)
+	return removed
+}
+
+// taggedJobs returns the scheduled jobs with the given tag. The returned slice is a copy, so the
+// queue may be modified while iterating over it.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) taggedJobs(tag string) []*Job {
+	var jobs []*Job
+	for _, job := range tm.jobQueue.jobs {
+		if slices.Contains(job.Tags, tag) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
@@ -0,0 +1,102 @@
+package taskman
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// scheduleTaggedJobs schedules a job for each ID, with the given tags.
+func scheduleTaggedJobs(t *testing.T, manager *TaskManager, tags []string, jobIDs ...string) {
+	t.Helper()
+	for _, jobID := range jobIDs {
+		job := getMockedJob(1, jobID, time.Hour, time.Hour)
+		job.Tags = tags
+		assert.NoError(t, manager.ScheduleJob(job), "Expected no error scheduling job %s", jobID)
+	}
+}
+
+func TestJobsByTag(t *testing.T) {
+	manager := NewCustom(1, 4, 1*time.Minute)
+	defer manager.Stop()
+
+	scheduleTaggedJobs(t, manager, []string{"tenant-a", "reports"}, "a-1", "a-2")
+	scheduleTaggedJobs(t, manager, []string{"tenant-b"}, "b-1")
+
+	jobs := manager.JobsByTag("tenant-a")
+	if assert.Len(t, jobs, 2) {
+		assert.ElementsMatch(t, []string{"a-1", "a-2"}, []string{jobs[0].ID, jobs[1].ID})
+		assert.Equal(t, []string{"tenant-a", "reports"}, jobs[0].Tags)
+	}
+	assert.Len(t, manager.JobsByTag("reports"), 2)
+	assert.Len(t, manager.JobsByTag("tenant-b"), 1)
+	assert.Empty(t, manager.JobsByTag("tenant-c"))
+
+	job := getMockedJob(1, "empty-tag", time.Hour, time.Hour)
+	job.Tags = []string{""}
+	assert.Error(t, manager.ScheduleJob(job), "Expected an error for an empty tag")
+}
+
+func TestPauseJobsByTag(t *testing.T) {
+	manager := NewCustom(1, 4, 1*time.Minute)
+	defer manager.Stop()
+
+	scheduleTaggedJobs(t, manager, []string{"tenant-a"}, "a-1", "a-2")
+	scheduleTaggedJobs(t, manager, []string{"tenant-b"}, "b-1")
+	assert.NoError(t, manager.PauseJob("a-1"))
+
+	assert.Equal(t, 1, manager.PauseJobsByTag("tenant-a"), "Expected only the unpaused job to be paused")
+	for _, job := range manager.Jobs() {
+		assert.Equal(t, job.ID != "b-1", job.Paused, "Unexpected paused state of job %s", job.ID)
+	}
+
+	assert.Equal(t, 2, manager.ResumeJobsByTag("tenant-a"))
+	for _, job := range manager.Jobs() {
+		assert.False(t, job.Paused, "Expected job %s to be resumed", job.ID)
+	}
+	assert.Zero(t, manager.ResumeJobsByTag("tenant-a"), "Expected no jobs to resume")
+}
+
+func TestRemoveJobsByTag(t *testing.T) {
+	manager := NewCustom(1, 4, 1*time.Minute)
+	defer manager.Stop()
+
+	var mu sync.Mutex
+	var hooked []string
+	manager.OnJobRemoved(func(jobID string) {
+		mu.Lock()
+		defer mu.Unlock()
+		hooked = append(hooked, jobID)
+	})
+
+	store := NewMemoryJobStore()
+	manager.SetJobStore(store)
+	scheduleTaggedJobs(t, manager, []string{"tenant-a"}, "a-1", "a-2", "a-3")
+	scheduleTaggedJobs(t, manager, []string{"tenant-b"}, "b-1")
+
+	// Dead-lettered jobs with the tag are removed as well
+	manager.Lock()
+	jobIndex, err := manager.jobQueue.JobInQueue("a-3")
+	assert.NoError(t, err)
+	assert.NoError(t, manager.deadLetterJob(manager.jobQueue.jobs[jobIndex]))
+	manager.Unlock()
+
+	assert.Equal(t, []string{"a-1", "a-2", "a-3"}, manager.RemoveJobsByTag("tenant-a"))
+	assert.Empty(t, manager.JobsByTag("tenant-a"))
+	assert.Empty(t, manager.DeadLetteredJobs(), "Expected the dead-lettered job to be removed")
+	assert.Len(t, manager.Jobs(), 1, "Expected jobs without the tag to be kept")
+
+	records, err := store.List()
+	assert.NoError(t, err)
+	if assert.Len(t, records, 1, "Expected the removed jobs to be deleted from the store") {
+		assert.Equal(t, "b-1", records[0].ID)
+		assert.Equal(t, []string{"tenant-b"}, records[0].Tags)
+	}
+
+	mu.Lock()
+	assert.ElementsMatch(t, []string{"a-1", "a-2", "a-3"}, hooked, "Expected the removal hooks to be called")
+	mu.Unlock()
+	assert.Empty(t, manager.RemoveJobsByTag("tenant-a"), "Expected no jobs to remove")
+}
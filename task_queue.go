@@ -0,0 +1,168 @@
+package taskman
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// taskQueue is the queue through which tasks are dispatched to the workers of a worker pool. The
+// queue is split into shards, each a buffered channel, so that dispatching at high rates does not
+// make every sender and worker contend on a single channel. Tasks are sent to the shards in turn,
+// and each worker receives from its own shard, stealing tasks from the other shards when its own
+// is empty.
+type taskQueue struct {
+	shards []chan Task
+	next   atomic.Uint32 // Counter selecting the shard of the next sent task
+
+	parked atomic.Int32  // Number of workers waiting for tasks
+	wake   chan struct{} // Channel to wake a waiting worker, to steal a task sent to another shard
+
+	blocked atomic.Int64 // Nanoseconds senders spent waiting for space in the full queue
+}
+
+// newTaskQueue creates a task queue of the given number of shards, holding up to bufferSize tasks
+// in total. The number of shards is capped at the buffer size, and an unbuffered queue has a
+// single shard, since tasks on an unbuffered shard cannot be stolen while waiting to be received.
+func newTaskQueue(shards, bufferSize int) *taskQueue {
+	shards = max(min(shards, bufferSize), 1)
+	q := &taskQueue{
+		shards: make([]chan Task, shards),
+		wake:   make(chan struct{}, 1),
+	}
+	for i := range q.shards {
+		// Spread the buffer over the shards, with the remainder on the first shards
+		size := bufferSize / shards
+		if i < bufferSize%shards {
+			size++
+		}
+		q.shards[i] = make(chan Task, size)
+	}
+	return q
+}
+
+// capacity returns the number of tasks the queue can hold.
+func (q *taskQueue) capacity() int {
+	var n int
+	for _, shard := range q.shards {
+		n += cap(shard)
+	}
+	return n
+}
+
+// close closes all shards of the queue.
+// Note: must only be called once no more tasks are sent.
+func (q *taskQueue) close() {
+	for _, shard := range q.shards {
+		close(shard)
+	}
+}
+
+// len returns the number of tasks in the queue.
+func (q *taskQueue) len() int {
+	var n int
+	for _, shard := range q.shards {
+		n += len(shard)
+	}
+	return n
+}
+
+// receive returns a task from the given shard, or one stolen from the other shards if the shard is
+// empty, without blocking. Returns false as the second value if no task is queued, and false as
+// the third value if the queue is closed.
+func (q *taskQueue) receive(shard int) (Task, bool, bool) {
+	for i := range q.shards {
+		select {
+		case task, ok := <-q.shards[(shard+i)%len(q.shards)]:
+			if !ok {
+				return nil, false, false
+			}
+			return task, true, true
+		default:
+		}
+	}
+	return nil, false, true
+}
+
+// send sends a task to the first shard with buffer space, starting from the next shard in turn,
+// or blocks until the next shard has space if all are full. Returns false if done is closed before
+// the task is sent.
+func (q *taskQueue) send(done <-chan struct{}, task Task) bool {
+	start := int(q.next.Add(1) % uint32(len(q.shards)))
+	if q.sendFrom(start, task) {
+		return true
+	}
+
+	// All shards are full, the workers are not keeping up with the dispatch of tasks
+	blockedSince := time.Now()
+	defer func() {
+		q.blocked.Add(int64(time.Since(blockedSince)))
+	}()
+	select {
+	case <-done:
+		return false
+	case q.shards[start] <- task:
+		q.wakeParked()
+		return true
+	}
+}
+
+// trySend sends a task to the first shard with buffer space, starting from the next shard in
+// turn, without blocking. Returns false if all shards are full.
+func (q *taskQueue) trySend(task Task) bool {
+	return q.sendFrom(int(q.next.Add(1)%uint32(len(q.shards))), task)
+}
+
+// sendFrom sends a task to the first shard with buffer space, starting from the given shard,
+// without blocking. Returns false if all shards are full.
+func (q *taskQueue) sendFrom(start int, task Task) bool {
+	for i := range q.shards {
+		select {
+		case q.shards[(start+i)%len(q.shards)] <- task:
+			q.wakeParked()
+			return true
+		default:
+		}
+	}
+	return false
+}
+
+// blockedTime returns the total time senders spent waiting for space in the full queue.
+func (q *taskQueue) blockedTime() time.Duration {
+	return time.Duration(q.blocked.Load())
+}
+
+// shardOf returns the shard of the nth worker of a pool.
+func (q *taskQueue) shardOf(n int) int {
+	return n % len(q.shards)
+}
+
+// wakeParked wakes a waiting worker, if there is one, so that a task sent to a shard other than
+// the worker's own is not left waiting for the workers of that shard.
+func (q *taskQueue) wakeParked() {
+	if len(q.shards) == 1 || q.parked.Load() == 0 {
+		// All workers receive from the only shard, or none are waiting
+		return
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+		// A wake-up is already pending
+	}
+}
+
+// dispatchBlocked returns the total time the dispatch of tasks waited for space in the task queues
+// of the default worker pool, the worker groups, the worker reservation and the dedicated jobs.
+// Note: does not acquire a mutex lock for accessing the worker groups, that is up to the caller.
+func (tm *TaskManager) dispatchBlocked() time.Duration {
+	blocked := tm.taskQueue.blockedTime()
+	for _, group := range tm.groups {
+		blocked += group.queue.blockedTime()
+	}
+	if reservation := tm.reservation.Load(); reservation != nil {
+		blocked += reservation.group.queue.blockedTime()
+	}
+	for _, runner := range tm.dedicated {
+		blocked += runner.queue.blockedTime()
+	}
+	return blocked
+}
@@ -0,0 +1,100 @@
+package taskman
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTaskQueue(t *testing.T) {
+	queue := newTaskQueue(4, 10)
+	assert.Len(t, queue.shards, 4)
+	assert.Equal(t, 10, queue.capacity(), "Expected the buffer to be spread over the shards")
+	for i, size := range []int{3, 3, 2, 2} {
+		assert.Equal(t, size, cap(queue.shards[i]), "Unexpected buffer size of shard %d", i)
+	}
+
+	// The shards are capped at the buffer size, with an unbuffered queue having a single shard
+	assert.Len(t, newTaskQueue(8, 3).shards, 3)
+	unbuffered := newTaskQueue(8, 0)
+	assert.Len(t, unbuffered.shards, 1)
+	assert.Zero(t, unbuffered.capacity())
+}
+
+func TestTaskQueueSendReceive(t *testing.T) {
+	queue := newTaskQueue(2, 4)
+
+	// Tasks are spread over the shards, and fill the others before blocking
+	for range 4 {
+		assert.True(t, queue.send(nil, MockTask{}))
+	}
+	assert.Equal(t, 4, queue.len())
+	for i, shard := range queue.shards {
+		assert.Equal(t, 2, len(shard), "Expected shard %d to be full", i)
+	}
+
+	done := make(chan struct{})
+	close(done)
+	assert.False(t, queue.send(done, MockTask{}), "Expected no send to a full queue once done")
+
+	// Sending to the full queue blocks until a worker receives a task
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		queue.receive(0)
+	}()
+	assert.True(t, queue.send(nil, MockTask{}))
+	assert.GreaterOrEqual(t, queue.blockedTime(), 10*time.Millisecond, "Expected the time blocked to be counted")
+
+	// Tasks are stolen from the other shard once the own shard is empty
+	for range 4 {
+		_, ok, open := queue.receive(0)
+		assert.True(t, ok, "Expected a task to be received")
+		assert.True(t, open)
+	}
+	_, ok, open := queue.receive(0)
+	assert.False(t, ok, "Expected no task in the empty queue")
+	assert.True(t, open)
+
+	queue.close()
+	_, _, open = queue.receive(1)
+	assert.False(t, open, "Expected the queue to be closed")
+}
+
+func TestTaskQueueWorkStealing(t *testing.T) {
+	// Four shards but a single worker, so that all tasks but those of the worker's shard are stolen
+	tasks := newTaskQueue(4, 8)
+	pool := newWorkerPool(1, make(chan error, 1), make(chan time.Duration, 8), tasks, make(chan struct{}),
+		zerologLogger{})
+	defer pool.stop()
+
+	var wg sync.WaitGroup
+	var executed atomic.Int32
+	task := MockTask{executeFunc: func() error {
+		executed.Add(1)
+		wg.Done()
+		return nil
+	}}
+
+	// Send tasks both while the worker is waiting and while it is busy
+	for range 3 {
+		wg.Add(4)
+		for range 4 {
+			assert.True(t, tasks.send(nil, task))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	waited := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatalf("Expected all tasks to be executed, %d of 12 were", executed.Load())
+	}
+}
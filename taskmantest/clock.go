@@ -0,0 +1,83 @@
+package taskmantest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a fake taskman.Clock, with time only passing when advanced.
+type Clock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []waiter
+}
+
+// waiter is a pending channel returned by After.
+type waiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewClock creates a Clock set to the start time.
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now returns the clock's current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel receiving the clock's time once it has been advanced by the duration.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, waiter{deadline: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward by the duration, firing channels returned by After which have
+// reached their deadline.
+func (c *Clock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to the time t, firing channels returned by After which have reached their
+// deadline. Setting a time before the clock's current time has no effect.
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t.After(c.now) {
+		c.now = t
+	}
+	c.fire(func(w waiter) bool { return !w.deadline.After(c.now) })
+}
+
+// wake fires all pending channels returned by After, regardless of their deadline.
+func (c *Clock) wake() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fire(func(waiter) bool { return true })
+}
+
+// fire sends the current time on the channels of the waiters matching the filter, and removes them.
+func (c *Clock) fire(filter func(w waiter) bool) {
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if filter(w) {
+			w.ch <- c.now
+		} else {
+			pending = append(pending, w)
+		}
+	}
+	c.waiters = pending
+}
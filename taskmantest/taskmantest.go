@@ -0,0 +1,146 @@
+// Package taskmantest provides a TaskManager controlled by a fake clock, for deterministic tests
+// of scheduled tasks. Time only passes when advanced, and advancing blocks until all executions
+// that became due have finished, so that tests need no sleeps or polling of their own.
+package taskmantest
+
+import (
+	"sync"
+	"time"
+
+	taskman "github.com/jkbrsn/go-taskman"
+)
+
+// DefaultStart is the time the fake clock of a Manager created with New starts at.
+var DefaultStart = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// pollInterval is the interval at which Manager checks whether dispatched executions are done.
+const pollInterval = 100 * time.Microsecond
+
+// Run is a captured execution of a job.
+type Run struct {
+	JobID    string        // ID of the executed job
+	Time     time.Time     // Time of the execution, according to the fake clock
+	Duration time.Duration // Real duration of the execution
+	Err      error         // Joined errors of the job's failed tasks, nil if all succeeded
+}
+
+// Manager is a TaskManager controlled by a fake clock, capturing the runs of its jobs. All
+// methods of the TaskManager are available, e.g. for scheduling jobs.
+type Manager struct {
+	*taskman.TaskManager
+	clock *Clock
+
+	mu      sync.Mutex
+	pending int   // Number of dispatched runs which have not completed
+	runs    []Run // Captured runs, in order of completion
+}
+
+// New creates a Manager with its clock set to DefaultStart, configured by the options.
+func New(opts ...taskman.Option) *Manager {
+	return NewWithClock(NewClock(DefaultStart), opts...)
+}
+
+// NewWithClock creates a Manager using the clock, configured by the options.
+func NewWithClock(clock *Clock, opts ...taskman.Option) *Manager {
+	m := &Manager{
+		TaskManager: taskman.New(append(opts, taskman.WithClock(clock))...),
+		clock:       clock,
+	}
+	m.OnJobStart(func(string) {
+		m.mu.Lock()
+		m.pending++
+		m.mu.Unlock()
+	})
+	m.OnJobComplete(func(jobID string, duration time.Duration, err error) {
+		m.mu.Lock()
+		m.runs = append(m.runs, Run{JobID: jobID, Time: clock.Now(), Duration: duration, Err: err})
+		m.pending--
+		m.mu.Unlock()
+	})
+	return m
+}
+
+// Clock returns the fake clock of the Manager.
+func (m *Manager) Clock() *Clock {
+	return m.clock
+}
+
+// Now returns the current time of the fake clock.
+func (m *Manager) Now() time.Time {
+	return m.clock.Now()
+}
+
+// Advance moves the fake clock forward by the duration. Jobs are executed at every time they
+// become due along the way, each step blocking until the executions have finished, as if time
+// had passed normally.
+// Note: a task which never returns blocks Advance indefinitely.
+func (m *Manager) Advance(d time.Duration) {
+	target := m.clock.Now().Add(d)
+	for {
+		m.settle()
+		next, ok := m.nextExec()
+		if !ok || next.After(target) {
+			break
+		}
+		m.clock.Set(next)
+	}
+	m.clock.Set(target)
+	m.settle()
+}
+
+// Runs returns the captured runs of all jobs, in order of completion.
+func (m *Manager) Runs() []Run {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Run(nil), m.runs...)
+}
+
+// RunsOf returns the captured runs of the job, in order of completion.
+func (m *Manager) RunsOf(jobID string) []Run {
+	var runs []Run
+	for _, run := range m.Runs() {
+		if run.JobID == jobID {
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
+
+// nextExec returns the earliest next execution of the scheduled jobs, leaving out jobs which cannot
+// be dispatched when due, e.g. paused jobs and jobs waiting for their dependencies.
+func (m *Manager) nextExec() (time.Time, bool) {
+	runs := m.QueueSnapshot(1)
+	if len(runs) == 0 {
+		return time.Time{}, false
+	}
+	return runs[0].NextExec, true
+}
+
+// settle blocks until no jobs are due at the current time, and all dispatched runs have finished.
+func (m *Manager) settle() {
+	for !m.idle() {
+		// The manager may be waiting on a deadline set just before the clock was advanced, wake it
+		// to check for due jobs again
+		m.clock.wake()
+		time.Sleep(pollInterval)
+	}
+}
+
+// idle reports whether no jobs are due or executing. Jobs which cannot be dispatched when due, e.g.
+// paused jobs and jobs waiting for their dependencies, are not due, whatever their NextExec.
+func (m *Manager) idle() bool {
+	m.mu.Lock()
+	pending := m.pending
+	m.mu.Unlock()
+	if pending > 0 {
+		return false
+	}
+
+	for _, job := range m.Jobs() {
+		if job.Running > 0 {
+			return false
+		}
+	}
+	next, ok := m.nextExec()
+	return !ok || next.After(m.clock.Now())
+}
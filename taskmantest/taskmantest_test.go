@@ -0,0 +1,139 @@
+package taskmantest
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	taskman "github.com/jkbrsn/go-taskman"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	clock := NewClock(DefaultStart)
+	assert.Equal(t, DefaultStart, clock.Now())
+
+	ch := clock.After(10 * time.Second)
+	clock.Advance(5 * time.Second)
+	select {
+	case <-ch:
+		t.Fatal("Expected channel to not fire before its deadline")
+	default:
+	}
+
+	clock.Advance(5 * time.Second)
+	select {
+	case fired := <-ch:
+		assert.Equal(t, DefaultStart.Add(10*time.Second), fired)
+	default:
+		t.Fatal("Expected channel to fire at its deadline")
+	}
+
+	clock.Set(DefaultStart)
+	assert.Equal(t, DefaultStart.Add(10*time.Second), clock.Now(), "Expected the clock to not move backwards")
+}
+
+func TestManagerAdvance(t *testing.T) {
+	manager := New(taskman.WithWorkers(2))
+	defer manager.Stop()
+
+	var executions atomic.Int32
+	jobID, err := manager.ScheduleFunc(func() error {
+		executions.Add(1)
+		return nil
+	}, 10*time.Minute)
+	assert.NoError(t, err)
+
+	manager.Advance(9 * time.Minute)
+	assert.Equal(t, int32(0), executions.Load(), "Expected no execution before the job is due")
+
+	manager.Advance(1 * time.Minute)
+	assert.Equal(t, int32(1), executions.Load(), "Expected the job to execute when due")
+
+	// Every due execution along the way is run
+	manager.Advance(1 * time.Hour)
+	assert.Equal(t, int32(7), executions.Load(), "Expected an execution every 10 minutes")
+
+	runs := manager.RunsOf(jobID)
+	assert.Len(t, runs, 7)
+	for i, run := range runs {
+		assert.Equal(t, DefaultStart.Add(time.Duration(i+1)*10*time.Minute), run.Time, "Expected runs at their scheduled time")
+		assert.NoError(t, run.Err)
+	}
+}
+
+func TestManagerAdvancePausedJob(t *testing.T) {
+	manager := New()
+	defer manager.Stop()
+
+	var executions atomic.Int32
+	jobID, err := manager.ScheduleFunc(func() error {
+		executions.Add(1)
+		return nil
+	}, time.Minute)
+	assert.NoError(t, err)
+
+	// Advancing past the due time of a paused job neither executes it nor waits for it
+	manager.Advance(30 * time.Second)
+	assert.NoError(t, manager.PauseJob(jobID))
+	manager.Advance(time.Minute)
+	assert.Equal(t, int32(0), executions.Load(), "Expected no execution of the paused job")
+
+	assert.NoError(t, manager.ResumeJob(jobID))
+	manager.Advance(time.Minute)
+	assert.Equal(t, int32(1), executions.Load(), "Expected the job to execute once resumed")
+}
+
+func TestManagerAdvanceChain(t *testing.T) {
+	manager := New()
+	defer manager.Stop()
+
+	first := taskman.Job{ID: "first", Cadence: time.Minute, NextExec: DefaultStart.Add(time.Minute), Tasks: []taskman.Task{noopTask{}}}
+	second := taskman.Job{ID: "second", Tasks: []taskman.Task{noopTask{}}}
+	assert.NoError(t, manager.ScheduleChain(first, second))
+
+	// The dependent job waits for its dependency rather than being due, executing after each of its runs
+	manager.Advance(3 * time.Minute)
+	assert.Len(t, manager.RunsOf("first"), 3)
+	assert.Len(t, manager.RunsOf("second"), 3, "Expected the dependent job to execute after each run of its dependency")
+}
+
+func TestManagerCapturesErrors(t *testing.T) {
+	manager := New()
+	defer manager.Stop()
+
+	failingID, err := manager.ScheduleFunc(func() error {
+		return errors.New("task failed")
+	}, 1*time.Minute)
+	assert.NoError(t, err)
+	_, err = manager.ScheduleOnce(noopTask{}, 30*time.Second)
+	assert.NoError(t, err)
+
+	manager.Advance(1 * time.Minute)
+	assert.Len(t, manager.Runs(), 2, "Expected the runs of both jobs to be captured")
+
+	runs := manager.RunsOf(failingID)
+	assert.Len(t, runs, 1)
+	assert.ErrorContains(t, runs[0].Err, "task failed", "Expected the error of the run to be captured")
+	assert.Len(t, manager.Jobs(), 1, "Expected the one-shot job to be removed")
+}
+
+func TestManagerCron(t *testing.T) {
+	manager := New()
+	defer manager.Stop()
+
+	jobID, err := manager.ScheduleCron(noopTask{}, "0 3 * * *")
+	assert.NoError(t, err)
+
+	manager.Advance(72 * time.Hour)
+	runs := manager.RunsOf(jobID)
+	assert.Len(t, runs, 3, "Expected a run every day")
+	for _, run := range runs {
+		assert.Equal(t, 3, run.Time.In(time.Local).Hour(), "Expected runs at 03:00")
+	}
+}
+
+type noopTask struct{}
+
+func (noopTask) Execute() error { return nil }
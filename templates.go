@@ -0,0 +1,71 @@
+package taskman
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// JobTemplate creates a job from parameters, e.g. a tenant or device ID, allowing many similar
+// jobs to be scheduled with the same cadence, tags, retry policy and other settings.
+type JobTemplate func(params map[string]string) Job
+
+// templateRef identifies the template and parameters a job was scheduled from.
+type templateRef struct {
+	name   string
+	params map[string]string
+}
+
+// RegisterJobTemplate registers a template for scheduling jobs with ScheduleFromTemplate. Jobs
+// scheduled from a template are persisted with the template's name and their parameters, so that
+// RestoreJobs recreates their tasks from the template if they are not serializable. Register all
+// templates before restoring jobs from a JobStore.
+func (tm *TaskManager) RegisterJobTemplate(name string, template JobTemplate) error {
+	if name == "" {
+		return errors.New("job template name cannot be empty")
+	}
+	if template == nil {
+		return errors.New("job template cannot be nil")
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	if _, ok := tm.jobTemplates[name]; ok {
+		return fmt.Errorf("job template %s already registered", name)
+	}
+	tm.jobTemplates[name] = template
+	return nil
+}
+
+// ScheduleFromTemplate schedules the job created by the named template from the parameters, and
+// returns the job's ID. Jobs created without an ID are given a generated one, and jobs created
+// without a NextExec or Schedule are first executed after their cadence, as with ScheduleTask.
+func (tm *TaskManager) ScheduleFromTemplate(name string, params map[string]string) (string, error) {
+	job, err := tm.templateJob(name, params)
+	if err != nil {
+		return "", err
+	}
+	if job.ID == "" {
+		job.ID = tm.newJobID()
+	}
+	if job.NextExec.IsZero() && job.Schedule == nil && !job.Align {
+		job.NextExec = tm.now().Add(job.Cadence)
+	}
+	return job.ID, tm.ScheduleJob(job)
+}
+
+// templateJob creates a job from the named template and parameters, referencing the template.
+func (tm *TaskManager) templateJob(name string, params map[string]string) (Job, error) {
+	tm.RLock()
+	template, ok := tm.jobTemplates[name]
+	tm.RUnlock()
+	if !ok {
+		return Job{}, fmt.Errorf("job template %s not registered", name)
+	}
+
+	// Call the template without holding the lock, allowing it to use the TaskManager
+	params = maps.Clone(params)
+	job := template(maps.Clone(params))
+	job.template = &templateRef{name: name, params: params}
+	return job, nil
+}
@@ -0,0 +1,103 @@
+package taskman
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tenantTemplate creates a job syncing a tenant, counting the executions of its tasks.
+func tenantTemplate(executions *atomic.Int32) JobTemplate {
+	return func(params map[string]string) Job {
+		return Job{
+			ID:          "sync-" + params["tenant"],
+			Cadence:     time.Hour,
+			Tags:        []string{"sync"},
+			Metadata:    map[string]string{"tenant": params["tenant"]},
+			RetryPolicy: &RetryPolicy{MaxAttempts: 3},
+			Tasks: []Task{MockTask{executeFunc: func() error {
+				executions.Add(1)
+				return nil
+			}}},
+		}
+	}
+}
+
+func TestRegisterJobTemplate(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+
+	var executions atomic.Int32
+	assert.NoError(t, manager.RegisterJobTemplate("tenant-sync", tenantTemplate(&executions)))
+	assert.Error(t, manager.RegisterJobTemplate("tenant-sync", tenantTemplate(&executions)), "Expected error registering duplicate template")
+	assert.Error(t, manager.RegisterJobTemplate("", tenantTemplate(&executions)), "Expected error registering unnamed template")
+	assert.Error(t, manager.RegisterJobTemplate("nil", nil), "Expected error registering nil template")
+}
+
+func TestScheduleFromTemplate(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+
+	var executions atomic.Int32
+	assert.NoError(t, manager.RegisterJobTemplate("tenant-sync", tenantTemplate(&executions)))
+
+	for _, tenant := range []string{"acme", "globex"} {
+		jobID, err := manager.ScheduleFromTemplate("tenant-sync", map[string]string{"tenant": tenant})
+		assert.NoError(t, err)
+		assert.Equal(t, "sync-"+tenant, jobID)
+	}
+	jobs := manager.JobsByTag("sync")
+	assert.Len(t, jobs, 2)
+	for _, job := range jobs {
+		assert.Equal(t, time.Hour, job.Cadence)
+		assert.True(t, job.NextExec.After(time.Now()), "Expected the job to be executed after its cadence")
+	}
+
+	_, err := manager.ScheduleFromTemplate("unknown", nil)
+	assert.Error(t, err, "Expected error scheduling from unregistered template")
+	_, err = manager.ScheduleFromTemplate("tenant-sync", map[string]string{"tenant": "acme"})
+	assert.ErrorIs(t, err, ErrDuplicateJobID)
+
+	t.Run("Generated ID", func(t *testing.T) {
+		assert.NoError(t, manager.RegisterJobTemplate("anonymous", func(params map[string]string) Job {
+			return getMockedJob(1, "", time.Hour, time.Hour)
+		}))
+		jobID, err := manager.ScheduleFromTemplate("anonymous", nil)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, jobID)
+	})
+}
+
+func TestRestoreTemplateJobs(t *testing.T) {
+	store := NewMemoryJobStore()
+
+	// Persist a job scheduled from a template, with tasks that are not serializable
+	var executions atomic.Int32
+	manager := New(WithWorkers(1))
+	manager.SetJobStore(store)
+	assert.NoError(t, manager.RegisterJobTemplate("tenant-sync", tenantTemplate(&executions)))
+	_, err := manager.ScheduleFromTemplate("tenant-sync", map[string]string{"tenant": "acme"})
+	assert.NoError(t, err)
+	manager.Stop()
+
+	record, err := store.Load("sync-acme")
+	assert.NoError(t, err)
+	assert.Nil(t, record.Tasks)
+	assert.Equal(t, "tenant-sync", record.Template)
+	assert.Equal(t, map[string]string{"tenant": "acme"}, record.TemplateParams)
+
+	// Restore the job, recreating its tasks from the template
+	record.NextExec = time.Now()
+	assert.NoError(t, store.Save(record))
+	manager = New(WithWorkers(1))
+	defer manager.Stop()
+	manager.SetJobStore(store)
+	assert.Error(t, manager.RestoreJobs(nil), "Expected error restoring without the template registered")
+	assert.NoError(t, manager.RegisterJobTemplate("tenant-sync", tenantTemplate(&executions)))
+	assert.NoError(t, manager.RestoreJobs(nil))
+
+	assert.Eventually(t, func() bool { return executions.Load() == 1 }, 100*time.Millisecond,
+		time.Millisecond, "Expected the restored job to execute")
+}
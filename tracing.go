@@ -0,0 +1,72 @@
+package taskman
+
+import (
+	"context"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer spans of the TaskManager are created with.
+const tracerName = "github.com/jkbrsn/go-taskman"
+
+// SetTracerProvider sets the OpenTelemetry tracer provider of the TaskManager, which then emits a
+// span for every job run, from dispatch until its last task has finished, with a child span for
+// every task execution attempt. Spans are started from the context of the job, so that runs of a
+// job with a BaseContext carrying a span are part of its trace. A nil provider disables tracing.
+func (tm *TaskManager) SetTracerProvider(provider trace.TracerProvider) {
+	tm.Lock()
+	defer tm.Unlock()
+	tm.tracer = nil
+	if provider != nil {
+		tm.tracer = provider.Tracer(tracerName)
+	}
+	tm.updateExecutor()
+}
+
+// startRunSpan starts the span of a job run, returning the context of the run's tasks.
+// Note: should be called while holding the TaskManager's lock, as it reads the job.
+func (tm *TaskManager) startRunSpan(job *Job) (context.Context, trace.Span) {
+	return tm.tracer.Start(job.ctx, "taskman.run "+job.ID,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(
+			attribute.String("taskman.job.id", job.ID),
+			attribute.String("taskman.job.cadence", job.Cadence.String()),
+			attribute.Int("taskman.job.tasks", len(job.Tasks)),
+		),
+	)
+}
+
+// endSpan ends the span, recording the error, if any.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracingMiddleware returns middleware emitting a span for every task execution attempt.
+func tracingMiddleware(tracer trace.Tracer) TaskMiddleware {
+	return func(next TaskExecutor) TaskExecutor {
+		return func(ctx context.Context, exec TaskExecution) error {
+			attributes := []attribute.KeyValue{
+				attribute.String("taskman.job.id", exec.JobID),
+				attribute.Int("taskman.task.index", exec.TaskIndex),
+				attribute.Int("taskman.task.attempt", exec.Attempt),
+			}
+			if exec.TaskID != "" {
+				attributes = append(attributes, attribute.String("taskman.task.id", exec.TaskID))
+			}
+			ctx, span := tracer.Start(ctx, "taskman.task "+exec.JobID+"/"+strconv.Itoa(exec.TaskIndex),
+				trace.WithSpanKind(trace.SpanKindInternal),
+				trace.WithAttributes(attributes...),
+			)
+			err := next(ctx, exec)
+			endSpan(span, err)
+			return err
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package taskman
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// spanAttribute returns the value of the span's attribute with the given key.
+func spanAttribute(span sdktrace.ReadOnlySpan, key string) attribute.Value {
+	for _, attr := range span.Attributes() {
+		if string(attr.Key) == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	manager := New(WithWorkers(2), WithTracerProvider(provider))
+	defer manager.Stop()
+
+	// The job's runs are part of the trace of its base context
+	baseCtx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	parent.End()
+	completed := make(chan struct{}, 1)
+	manager.OnJobComplete(func(jobID string, duration time.Duration, err error) { completed <- struct{}{} })
+	job := Job{ID: "traced-job", Cadence: time.Minute, NextExec: time.Now(), BaseContext: baseCtx,
+		Tasks: []Task{MockTask{}, MockTask{executeFunc: func() error { return errors.New("task failed") }}}}
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	select {
+	case <-completed:
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the job to complete")
+	}
+
+	spans := recorder.Ended()
+	var run sdktrace.ReadOnlySpan
+	var tasks []sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		switch span.Name() {
+		case "taskman.run traced-job":
+			run = span
+		case "taskman.task traced-job/0", "taskman.task traced-job/1":
+			tasks = append(tasks, span)
+		}
+	}
+	if !assert.NotNil(t, run, "Expected a span of the run") {
+		return
+	}
+	assert.Equal(t, parent.SpanContext().TraceID(), run.SpanContext().TraceID(), "Expected the run to be part of the base context's trace")
+	assert.Equal(t, parent.SpanContext().SpanID(), run.Parent().SpanID())
+	assert.Equal(t, "traced-job", spanAttribute(run, "taskman.job.id").AsString())
+	assert.Equal(t, time.Minute.String(), spanAttribute(run, "taskman.job.cadence").AsString())
+	assert.Equal(t, codes.Error, run.Status().Code, "Expected the run's span to record the failed task")
+
+	assert.Len(t, tasks, 2, "Expected a span of each task")
+	for _, task := range tasks {
+		assert.Equal(t, run.SpanContext().SpanID(), task.Parent().SpanID(), "Expected the task spans to be children of the run's span")
+		assert.Equal(t, int64(1), spanAttribute(task, "taskman.task.attempt").AsInt64())
+		if spanAttribute(task, "taskman.task.index").AsInt64() == 1 {
+			assert.Equal(t, codes.Error, task.Status().Code, "Expected the failed task's span to record its error")
+		}
+	}
+}
+
+func TestJobBaseContext(t *testing.T) {
+	manager := NewCustom(1, 4, 1*time.Minute)
+
+	type key struct{}
+	base, cancelBase := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	received := make(chan context.Context, 1)
+	job := Job{ID: "base-context-job", Cadence: time.Minute, NextExec: time.Now(), BaseContext: base,
+		Tasks: []Task{SimpleContextTask{function: func(ctx context.Context) error {
+			received <- ctx
+			return nil
+		}}}}
+	cancelBase()
+	assert.NoError(t, manager.ScheduleJob(job))
+
+	var ctx context.Context
+	select {
+	case ctx = <-received:
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the task to be executed")
+	}
+	assert.Equal(t, "value", ctx.Value(key{}), "Expected the values of the base context")
+	assert.NoError(t, ctx.Err(), "Expected the cancellation of the base context to not be passed on")
+
+	// The job's context is cancelled on a goroutine of its own once the manager's context is
+	manager.Stop()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected stopping the manager to cancel the job's context")
+	}
+}
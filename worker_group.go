@@ -0,0 +1,110 @@
+package taskman
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// workerGroup is a named worker pool with a fixed number of workers, executing the tasks of the
+// jobs assigned to the group separately from the TaskManager's default worker pool.
+type workerGroup struct {
+	pool  *workerPool
+	queue *taskQueue    // Queue to send tasks to the group's workers
+	done  chan struct{} // Channel to receive signal that the group's pool has stopped
+}
+
+// SetWorkerGroup creates a named worker group of the given number of workers, or resizes it if it
+// already exists. Jobs are assigned to a group with their Group field, and their tasks are executed
+// only by the group's workers, so that e.g. CPU-heavy jobs cannot starve lightweight IO jobs of
+// workers. Unlike the default worker pool, groups are not scaled automatically.
+func (tm *TaskManager) SetWorkerGroup(name string, workers int) error {
+	if name == "" {
+		return errors.New("invalid worker group name, must not be empty")
+	}
+	if workers <= 0 {
+		return errors.New("invalid worker count, must be greater than 0")
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	select {
+	case <-tm.ctx.Done():
+		return ErrManagerStopped
+	default:
+	}
+
+	if group, ok := tm.groups[name]; ok {
+		group.pool.enqueueWorkerScaling(int32(workers))
+		tm.logger.Debug("Resized worker group", "group", name, "workers", workers)
+		return nil
+	}
+
+	// Execution times only inform the scaling of the default pool, so those of the group's workers
+	// are sent to a channel without receivers, and discarded
+	queue := newTaskQueue(len(tm.taskQueue.shards), tm.taskQueue.capacity())
+	done := make(chan struct{})
+	tm.groups[name] = &workerGroup{
+		pool:  newWorkerPool(workers, tm.errorFan.in, make(chan time.Duration), queue, done, tm.logger),
+		queue: queue,
+		done:  done,
+	}
+	if tm.workerLifecycle != nil {
+		tm.groups[name].pool.lifecycle.Store(tm.workerLifecycle)
+	}
+	tm.logger.Debug("Created worker group", "group", name, "workers", workers)
+	return nil
+}
+
+// WorkerGroups returns the number of workers each worker group is scaled to, keyed by group name.
+func (tm *TaskManager) WorkerGroups() map[string]int {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	groups := make(map[string]int, len(tm.groups))
+	for name, group := range tm.groups {
+		groups[name] = int(group.pool.targetWorkerCount())
+	}
+	return groups
+}
+
+// taskQueueOf returns the queue through which the job's tasks are sent to its workers.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) taskQueueOf(job *Job) *taskQueue {
+	if runner, ok := tm.dedicated[job.state]; ok {
+		return runner.queue
+	}
+	if group, ok := tm.groups[job.Group]; ok {
+		return group.queue
+	}
+	return tm.taskQueue
+}
+
+// validateGroup returns an error if the job is assigned to a worker group which does not exist.
+// Note: does not acquire a mutex lock, that is up to the caller.
+func (tm *TaskManager) validateGroup(job Job) error {
+	if job.Group == "" {
+		return nil
+	}
+	if _, ok := tm.groups[job.Group]; !ok {
+		return fmt.Errorf("worker group %s not found", job.Group)
+	}
+	return nil
+}
+
+// stopWorkerGroups stops the worker pools of all worker groups, waiting for them to finish.
+func (tm *TaskManager) stopWorkerGroups() {
+	tm.RLock()
+	groups := make([]*workerGroup, 0, len(tm.groups))
+	for _, group := range tm.groups {
+		groups = append(groups, group)
+	}
+	tm.RUnlock()
+
+	for _, group := range groups {
+		group.pool.stop()
+		<-group.done
+		group.queue.close()
+	}
+}
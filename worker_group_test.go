@@ -0,0 +1,83 @@
+package taskman
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerGroup(t *testing.T) {
+	t.Run("Isolated execution", func(t *testing.T) {
+		manager := New(WithWorkers(4), WithWorkerGroup("cpu", 1))
+		defer manager.Stop()
+
+		// The group's single worker executes the heavy job's tasks one at a time
+		tracker := &concurrencyTracker{}
+		heavy := Job{ID: "heavy-job", Group: "cpu", Cadence: time.Minute, NextExec: time.Now(),
+			Tasks: []Task{tracker.task(20 * time.Millisecond), tracker.task(20 * time.Millisecond), tracker.task(20 * time.Millisecond)}}
+		assert.NoError(t, manager.ScheduleJob(heavy))
+
+		var light atomic.Int32
+		lightJob := Job{ID: "light-job", Cadence: 5 * time.Millisecond, NextExec: time.Now(),
+			Tasks: []Task{MockTask{executeFunc: func() error {
+				light.Add(1)
+				return nil
+			}}}}
+		assert.NoError(t, manager.ScheduleJob(lightJob))
+
+		time.Sleep(30 * time.Millisecond)
+		assert.GreaterOrEqual(t, light.Load(), int32(3), "Expected the default pool to not be starved by the group")
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(3), tracker.executions.Load(), "Expected all of the group's tasks to execute")
+		assert.Equal(t, int32(1), tracker.max.Load(), "Expected the group's tasks to be limited to its worker count")
+	})
+
+	t.Run("Errors are reported", func(t *testing.T) {
+		manager := New(WithWorkers(1), WithWorkerGroup("io", 1))
+		defer manager.Stop()
+
+		job := Job{ID: "failing-group-job", Group: "io", Cadence: time.Minute, NextExec: time.Now(),
+			Tasks: []Task{MockTask{executeFunc: func() error { return assert.AnError }}}}
+		assert.NoError(t, manager.ScheduleJob(job))
+
+		select {
+		case err := <-manager.ErrorChannel():
+			assert.ErrorIs(t, err, assert.AnError)
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("Expected the group's errors on the error channel")
+		}
+	})
+
+	t.Run("Resize", func(t *testing.T) {
+		manager := New(WithWorkers(1), WithWorkerGroup("io", 2))
+		defer manager.Stop()
+
+		assert.Equal(t, map[string]int{"io": 2}, manager.WorkerGroups())
+		assert.NoError(t, manager.SetWorkerGroup("io", 5))
+		assert.Eventually(t, func() bool {
+			return manager.WorkerGroups()["io"] == 5
+		}, 50*time.Millisecond, time.Millisecond, "Expected the group to be resized")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		manager := NewCustom(1, 1, 1*time.Minute)
+		defer manager.Stop()
+
+		assert.Error(t, manager.SetWorkerGroup("", 1), "Expected error for an empty group name")
+		assert.Error(t, manager.SetWorkerGroup("io", 0), "Expected error for zero workers")
+
+		job := getMockedJob(1, "unknown-group-job", time.Second, 0)
+		job.Group = "unknown"
+		assert.Error(t, manager.ScheduleJob(job), "Expected error for an unknown worker group")
+		assert.Panics(t, func() { New(WithWorkerGroup("io", -1)) }, "Expected panic for an invalid worker group")
+	})
+
+	t.Run("Stopped", func(t *testing.T) {
+		manager := New(WithWorkers(1), WithWorkerGroup("io", 1))
+		manager.Stop()
+
+		assert.ErrorIs(t, manager.SetWorkerGroup("cpu", 1), ErrManagerStopped, "Expected error adding a group to a stopped manager")
+	})
+}
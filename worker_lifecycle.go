@@ -0,0 +1,105 @@
+package taskman
+
+import (
+	"context"
+	"runtime/debug"
+)
+
+// WorkerLifecycle holds the callbacks called as workers of a TaskManager start and stop, e.g. to
+// allocate a database connection or buffer per worker rather than per task execution.
+type WorkerLifecycle struct {
+	// OnWorkerStart is called once on a worker before it executes its first task, and returns the
+	// worker's resource, passed to the tasks the worker executes. May be nil.
+	OnWorkerStart func(workerID string) any
+	// OnWorkerStop is called once on a worker which called OnWorkerStart as it exits, with the
+	// resource returned by OnWorkerStart, to clean it up. May be nil.
+	OnWorkerStop func(workerID string, resource any)
+}
+
+// workerResourceKey is the context key of the resource of a worker, in the context passed to the
+// tasks it executes.
+type workerResourceKey struct{}
+
+// WorkerResource returns the resource of the worker executing the task, as returned by the
+// OnWorkerStart callback of the TaskManager's WorkerLifecycle and passed in the context to
+// context-aware tasks. Returns nil if the context carries no resource.
+func WorkerResource(ctx context.Context) any {
+	return ctx.Value(workerResourceKey{})
+}
+
+// withWorkerResource returns a context carrying the resource of a worker, or the context itself if
+// the worker has no resource.
+func withWorkerResource(ctx context.Context, resource any) context.Context {
+	if resource == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, workerResourceKey{}, resource)
+}
+
+// SetWorkerLifecycle sets the callbacks called as the workers of the default worker pool, of the
+// worker groups and of the worker reservation start and stop. Workers already running call
+// OnWorkerStart before their next task. The callbacks are called from the worker they are called
+// on, and a worker's tasks wait for its OnWorkerStart to return. A panic in a callback is recovered and logged.
+func (tm *TaskManager) SetWorkerLifecycle(lifecycle WorkerLifecycle) {
+	tm.Lock()
+	defer tm.Unlock()
+	tm.workerLifecycle = &lifecycle
+	tm.workerPool.lifecycle.Store(&lifecycle)
+	for _, group := range tm.groups {
+		group.pool.lifecycle.Store(&lifecycle)
+	}
+	if reservation := tm.reservation.Load(); reservation != nil {
+		reservation.group.pool.lifecycle.Store(&lifecycle)
+	}
+}
+
+// warmUpWorker calls the OnWorkerStart callback on the worker, unless it has already been called.
+// Note: must only be called from the worker's goroutine.
+func (wp *workerPool) warmUpWorker(worker *workerInfo) {
+	if worker.lifecycle != nil {
+		return
+	}
+	lifecycle := wp.lifecycle.Load()
+	if lifecycle == nil {
+		return
+	}
+	worker.lifecycle = lifecycle
+	if lifecycle.OnWorkerStart == nil {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			wp.logger.Error("Worker start callback panicked", "workerID", worker.id, "panic", r, "stack", string(debug.Stack()))
+		}
+	}()
+	worker.resource = lifecycle.OnWorkerStart(worker.id.String())
+}
+
+// coolDownWorker calls the OnWorkerStop callback on an exiting worker, if it called OnWorkerStart.
+// Note: must only be called from the worker's goroutine.
+func (wp *workerPool) coolDownWorker(worker *workerInfo) {
+	if worker.lifecycle == nil || worker.lifecycle.OnWorkerStop == nil {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			wp.logger.Error("Worker stop callback panicked", "workerID", worker.id, "panic", r, "stack", string(debug.Stack()))
+		}
+	}()
+	worker.lifecycle.OnWorkerStop(worker.id.String(), worker.resource)
+}
+
+// workerTask is a task which is passed the resource of the worker executing it.
+type workerTask interface {
+	executeWithResource(resource any) error
+}
+
+// executeOn executes the task on the worker, passing on the worker's resource to a workerTask.
+func executeOn(task Task, worker *workerInfo) error {
+	if wt, ok := task.(workerTask); ok && worker.resource != nil {
+		return wt.executeWithResource(worker.resource)
+	}
+	return task.Execute()
+}
@@ -0,0 +1,86 @@
+package taskman
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerLifecycle(t *testing.T) {
+	var mu sync.Mutex
+	started := make(map[string]bool)
+	stopped := make(map[string]any)
+	lifecycle := WorkerLifecycle{
+		OnWorkerStart: func(workerID string) any {
+			mu.Lock()
+			defer mu.Unlock()
+			started[workerID] = true
+			return "conn-" + workerID
+		},
+		OnWorkerStop: func(workerID string, resource any) {
+			mu.Lock()
+			defer mu.Unlock()
+			stopped[workerID] = resource
+		},
+	}
+	manager := New(WithWorkers(2), WithWorkerLifecycle(lifecycle))
+
+	received := make(chan any, 1)
+	job := Job{
+		ID:       "resource-job",
+		Cadence:  time.Hour,
+		NextExec: time.Now().Add(time.Hour),
+		Tasks: []Task{SimpleContextTask{func(ctx context.Context) error {
+			received <- WorkerResource(ctx)
+			return nil
+		}}},
+	}
+	assert.NoError(t, manager.ScheduleJob(job))
+	assert.NoError(t, manager.TriggerJob(job.ID))
+
+	select {
+	case resource := <-received:
+		mu.Lock()
+		assert.Contains(t, started, resource.(string)[len("conn-"):], "Expected the resource of a started worker")
+		mu.Unlock()
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the task to execute")
+	}
+
+	// Every started worker is stopped with its resource
+	manager.Stop()
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, started, 2)
+	assert.Len(t, stopped, 2)
+	for workerID, resource := range stopped {
+		assert.Equal(t, "conn-"+workerID, resource)
+	}
+}
+
+func TestWorkerLifecycleSetLater(t *testing.T) {
+	manager := New(WithWorkers(1))
+	defer manager.Stop()
+
+	// Running workers warm up before their next task
+	manager.SetWorkerLifecycle(WorkerLifecycle{
+		OnWorkerStart: func(workerID string) any { return "buffer" },
+	})
+	received := make(chan any, 1)
+	_, err := manager.ScheduleOnce(SimpleContextTask{func(ctx context.Context) error {
+		received <- WorkerResource(ctx)
+		return nil
+	}}, 0)
+	assert.NoError(t, err)
+
+	select {
+	case resource := <-received:
+		assert.Equal(t, "buffer", resource)
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the task to execute")
+	}
+	assert.Nil(t, WorkerResource(context.Background()), "Expected no resource without a worker")
+}
@@ -9,13 +9,14 @@ import (
 	"time"
 
 	"github.com/rs/xid"
+	uatomic "go.uber.org/atomic"
 )
 
 const (
 	// If the worker pool utilization is above this threshold, we will not scale down
-	utilizationThreshold = 0.4
+	defaultUtilizationThreshold = 0.4
 	// Minimum interval between downscaling events
-	downScaleMinInterval = time.Second * 30
+	defaultDownScaleMinInterval = time.Second * 30
 )
 
 // workerPool manages a pool of workers that execute tasks.
@@ -23,17 +24,27 @@ type workerPool struct {
 	workers           sync.Map     // Map worker ID (xid.ID) to worker (workerInfo)
 	workersActive     atomic.Int32 // Number of active workers
 	workersRunning    atomic.Int32 // Number of running workers
+	workersDraining   atomic.Int32 // Number of running workers signaled to stop
 	workerCountTarget atomic.Int32 // Target number of workers
 
 	errorChan       chan<- error       // Send-only channel for errors
 	execTimeChan    chan time.Duration // Channel to send execution times
-	taskChan        <-chan Task        // Receive-only channel for tasks
+	tasks           *taskQueue         // Queue of tasks to execute
 	workerCountChan chan int32         // Channel to receive worker count changes
 	stopPoolChan    chan struct{}      // Channel to signal stopping the worker pool
 	workerPoolDone  chan struct{}      // Channel to signal worker pool is done
+	logger          Logger             // Logger of the owning TaskManager
 
 	workerScalingEvents atomic.Int64 // Number of worker scaling events since start
+	errorsDropped       atomic.Int64 // Number of errors dropped as the error channel was full
+	execTimesDropped    atomic.Int64 // Number of execution times dropped as the execution time channel was full
 	lastDownScale       time.Time    // Last time a downscaling event occurred
+	workersAdded        int          // Number of workers added since start, to assign their shards
+
+	downScaleThreshold uatomic.Float64  // Utilization above which the pool is not scaled down
+	downScaleInterval  uatomic.Duration // Minimum interval between downscaling events
+
+	lifecycle atomic.Pointer[WorkerLifecycle] // Callbacks called as workers start and stop, if set
 
 	mu sync.Mutex
 	wg sync.WaitGroup
@@ -41,11 +52,19 @@ type workerPool struct {
 
 // worker represents a worker that executes tasks.
 type workerInfo struct {
-	id   xid.ID      // The worker ID
-	busy atomic.Bool // True if worker is busy
+	id    xid.ID      // The worker ID
+	busy  atomic.Bool // True if worker is busy
+	shard int         // The task queue shard the worker receives from
 
 	stopChan chan struct{} // Channel to signal stopping the worker
 	stopOnce sync.Once     // Once to ensure stop signal is sent only once
+	stopped  atomic.Bool   // True if the worker has been signaled to stop
+	done     chan struct{} // Channel closed when the worker has exited
+
+	executing atomic.Pointer[ExecutingTask] // Task being executed by the worker, nil while idle, see TaskManager.Sample
+
+	lifecycle *WorkerLifecycle // Lifecycle the worker was started with, nil until it is warmed up
+	resource  any              // Resource returned by the lifecycle's OnWorkerStart, if any
 }
 
 // activeWorkers returns the number of active workers.
@@ -53,6 +72,12 @@ func (wp *workerPool) activeWorkers() int32 {
 	return wp.workersActive.Load()
 }
 
+// drainingWorkers returns the number of running workers signaled to stop, finishing their
+// current task.
+func (wp *workerPool) drainingWorkers() int32 {
+	return wp.workersDraining.Load()
+}
+
 // runningWorkers returns the number of running workers.
 func (wp *workerPool) runningWorkers() int32 {
 	return wp.workersRunning.Load()
@@ -67,18 +92,37 @@ func (wp *workerPool) availableWorkers() int32 {
 	return wp.runningWorkers() - wp.activeWorkers()
 }
 
-// addWorkers adds to the worker pool by starting new workers.
+// addWorkers adds to the worker pool by starting new workers, spread over the task queue shards.
+// Note: this function is not thread-safe, it should be called from within a mutex lock.
 func (wp *workerPool) addWorkers(nWorkers int) {
-	logger.Debug().Msgf("Adding %d new workers to the pool", nWorkers)
+	wp.logger.Debug("Adding workers to the pool", "count", nWorkers)
 	wp.wg.Add(nWorkers)
 	for range nWorkers {
-		workerID := xid.New()
-		go wp.startWorker(workerID)
+		// Register the worker before starting it, so that it is counted as soon as it is added
+		worker := &workerInfo{
+			id:       xid.New(),
+			busy:     atomic.Bool{},
+			shard:    wp.tasks.shardOf(wp.workersAdded),
+			stopChan: make(chan struct{}),
+			done:     make(chan struct{}),
+		}
+		wp.workersAdded++
+		wp.workersRunning.Add(1)
+		wp.workers.Store(worker.id, worker)
+		go wp.startWorker(worker)
 	}
 }
 
 // adjustWorkerCount adjusts the number of workers in the pool to match the target worker count.
+// Note: this function is not thread-safe, it should be called from within a mutex lock.
 func (pool *workerPool) adjustWorkerCount(newTargetCount int32) {
+	// Workers are not added once the pool is stopping, as stop waits for the workers to exit
+	select {
+	case <-pool.stopPoolChan:
+		return
+	default:
+	}
+
 	pool.workerScalingEvents.Add(1)
 	currentTarget := pool.targetWorkerCount()
 
@@ -88,26 +132,26 @@ func (pool *workerPool) adjustWorkerCount(newTargetCount int32) {
 	switch {
 	case newTargetCount > currentTarget:
 		// Scale up
-		logger.Debug().Msgf("Scaling worker count UP from %d to %d", currentTarget, newTargetCount)
+		pool.logger.Debug("Scaling worker count up", "from", currentTarget, "to", newTargetCount)
 		pool.addWorkers(int(newTargetCount - currentTarget))
 
 	case newTargetCount < currentTarget:
 		// Scale down based on utilization and debounce
-		if pool.utilization() < utilizationThreshold && time.Since(pool.lastDownScale) >= downScaleMinInterval {
-			logger.Debug().Msgf("Scaling worker count DOWN from %d to %d", currentTarget, newTargetCount)
-			if err := pool.stopWorkers(int(currentTarget - newTargetCount)); err != nil {
-				logger.Warn().Err(err).Msg("stopWorkers failed")
+		if pool.utilization() < pool.downScaleThreshold.Load() &&
+			time.Since(pool.lastDownScale) >= pool.downScaleInterval.Load() {
+			pool.logger.Debug("Scaling worker count down", "from", currentTarget, "to", newTargetCount)
+			if _, err := pool.stopWorkers(int(currentTarget - newTargetCount)); err != nil {
+				pool.logger.Warn("Failed to stop workers", "error", err)
 			} else {
 				pool.lastDownScale = time.Now()
 			}
 		} else {
-			logger.Debug().
-				Msgf("Skipping down-scale: util=%.2f, sinceLast=%s",
-					pool.utilization(), time.Since(pool.lastDownScale))
+			pool.logger.Debug("Skipping down-scale",
+				"utilization", pool.utilization(), "sinceLast", time.Since(pool.lastDownScale))
 		}
 
 	default:
-		logger.Debug().Msgf("Pool already at target worker count %d", newTargetCount)
+		pool.logger.Debug("Pool already at target worker count", "count", newTargetCount)
 	}
 }
 
@@ -179,92 +223,169 @@ func (wp *workerPool) processWorkerCountScaling() {
 	}
 }
 
-// startWorker executes tasks from the task channel.
-func (wp *workerPool) startWorker(id xid.ID) {
-	logger.Debug().Msgf("Starting worker %s", id)
+// nextTask returns the next task for the worker to execute, from its own shard of the task queue or
+// stolen from another shard, blocking until a task is queued. Returns false if the worker is
+// stopped or the task queue is closed.
+func (wp *workerPool) nextTask(worker *workerInfo) (Task, bool) {
+	q := wp.tasks
+	for {
+		select {
+		case <-worker.stopChan:
+			wp.logger.Debug("Worker received targeted stop signal, exiting", "workerID", worker.id)
+			return nil, false
+		case <-wp.stopPoolChan:
+			wp.logger.Debug("Worker received global stop signal, exiting", "workerID", worker.id)
+			return nil, false
+		default:
+		}
+
+		task, ok, open := q.receive(worker.shard)
+		if !ok && open {
+			// Count the worker as waiting before checking the queue once more, so that a task sent
+			// meanwhile is either received here or followed by a wake-up
+			q.parked.Add(1)
+			task, ok, open = q.receive(worker.shard)
+			if !ok && open {
+				select {
+				case task, open = <-q.shards[worker.shard]:
+					ok = open
+				case <-q.wake:
+				case <-worker.stopChan:
+				case <-wp.stopPoolChan:
+				}
+			}
+			q.parked.Add(-1)
+		}
 
-	wp.workersRunning.Add(1)
-	worker := &workerInfo{
-		id:       id,
-		busy:     atomic.Bool{},
-		stopChan: make(chan struct{}),
+		if !open {
+			wp.logger.Debug("Task channel closed, worker exiting", "workerID", worker.id)
+			return nil, false
+		}
+		if ok {
+			// Pass the wake-up on if more tasks are queued, for them not to wait for this task
+			if q.parked.Load() > 0 && q.len() > 0 {
+				q.wakeParked()
+			}
+			return task, true
+		}
 	}
-	wp.workers.Store(id, worker)
+}
+
+// startWorker executes tasks from the task queue, until the worker is stopped.
+func (wp *workerPool) startWorker(worker *workerInfo) {
+	id := worker.id
+	wp.logger.Debug("Starting worker", "workerID", id)
 
 	defer func() {
+		wp.coolDownWorker(worker)
+		if worker.stopped.Load() {
+			wp.workersDraining.Add(-1)
+		}
 		wp.workersRunning.Add(-1)
 		wp.workers.Delete(id)
+		close(worker.done)
 		wp.wg.Done()
 	}()
 
+	wp.warmUpWorker(worker)
 	for {
-		select {
-		case task, ok := <-wp.taskChan:
-			if !ok {
-				logger.Debug().Msgf("Worker %s: task channel closed, exiting", id)
-				return
-			}
-			logger.Trace().Msgf("Worker %s executing task", id)
-
-			func() {
-				// Update worker state: busy
-				worker.busy.Store(true)
-				wp.workersActive.Add(1)
-
-				defer func() {
-					if r := recover(); r != nil {
-						logger.Error().Msgf("Worker %s: panic: %v\n%s", id, r, string(debug.Stack()))
-						err := fmt.Errorf("worker %s: panic: %v", id, r)
-						select {
-						case wp.errorChan <- err:
-							// Error sent
-						default:
-							// Error channel not ready to receive, do nothing
-						}
+		task, ok := wp.nextTask(worker)
+		if !ok {
+			return
+		}
+		wp.warmUpWorker(worker)
+		wp.logger.Debug("Worker executing task", "workerID", id)
+
+		func() {
+			// Update worker state: busy
+			worker.busy.Store(true)
+			wp.workersActive.Add(1)
+
+			defer func() {
+				if r := recover(); r != nil {
+					if unrecovered, ok := r.(unrecoveredPanic); ok {
+						panic(unrecovered)
 					}
-
-					// Update worker state: dormant
-					worker.busy.Store(false)
-					wp.workersActive.Add(-1)
-					logger.Trace().Msgf("Worker %s: finished task", id)
-				}()
-
-				// Execute the task
-				start := time.Now()
-				err := task.Execute()
-				if err != nil {
-					// No retry policy is implemented, we just log and send the error for now
+					wp.logger.Error("Worker recovered from panic", "workerID", id, "panic", r, "stack", string(debug.Stack()))
+					err := &PanicError{Value: r, Stack: debug.Stack()}
 					select {
 					case wp.errorChan <- err:
 						// Error sent
 					default:
-						// Error channel not ready to receive, do nothing
+						// Error channel not ready to receive, drop the error
+						wp.errorsDropped.Add(1)
 					}
 				}
-				execTime := time.Since(start)
+
+				// Update worker state: dormant
+				worker.executing.Store(nil)
+				worker.busy.Store(false)
+				wp.workersActive.Add(-1)
+				wp.logger.Debug("Worker finished task", "workerID", id)
+			}()
+
+			// Execute the task
+			start := time.Now()
+			worker.executing.Store(executingTask(task, start))
+			err := executeOn(task, worker)
+			if err != nil {
+				// The task has exhausted its job's retry policy, if any, so the error is sent as final
 				select {
-				case wp.execTimeChan <- execTime:
-					// Execution time sent
+				case wp.errorChan <- err:
+					// Error sent
 				default:
-					// Execution time channel not ready to receive, do nothing
+					// Error channel not ready to receive, drop the error
+					wp.errorsDropped.Add(1)
 				}
-			}()
+			}
+			execTime := time.Since(start)
+			select {
+			case wp.execTimeChan <- execTime:
+				// Execution time sent
+			default:
+				// Execution time channel not ready to receive, drop the execution time
+				wp.execTimesDropped.Add(1)
+			}
+		}()
+	}
+}
 
-		case <-worker.stopChan:
-			logger.Debug().Msgf("Worker %s: received targeted stop signal, exiting", id)
-			return
+// drainWorkers stops n workers and lowers the target worker count accordingly, regardless of the
+// utilization of the pool and the time since the last downscaling. Idle workers are stopped first,
+// and busy workers finish their current task without receiving another. Returns a channel which
+// is closed once all of the stopped workers have exited.
+func (wp *workerPool) drainWorkers(n int) (<-chan struct{}, error) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	target := wp.targetWorkerCount()
+	if n <= 0 || n >= int(target) {
+		return nil, fmt.Errorf("cannot drain %d of %d workers, at least one worker must remain", n, target)
+	}
+	stopped, err := wp.stopWorkers(n)
+	wp.workerScalingEvents.Add(1)
+	wp.workerCountTarget.Store(target - int32(len(stopped)))
+	if err != nil {
+		return nil, err
+	}
+	wp.logger.Debug("Draining workers", "count", len(stopped), "target", target-int32(len(stopped)))
 
-		case <-wp.stopPoolChan:
-			logger.Debug().Msgf("Worker %s: received global stop signal, exiting", id)
-			return
+	drained := make(chan struct{})
+	go func() {
+		for _, worker := range stopped {
+			<-worker.done
 		}
-	}
+		close(drained)
+	}()
+	return drained, nil
 }
 
 // stop signals the worker pool to stop processing tasks and exit.
 func (wp *workerPool) stop() {
-	// Signal workers to stop
+	// Signal workers to stop, holding the lock so that no workers are being added
+	wp.mu.Lock()
 	close(wp.stopPoolChan)
+	wp.mu.Unlock()
 
 	// Wait for all workers to finish
 	wp.wg.Wait()
@@ -273,79 +394,70 @@ func (wp *workerPool) stop() {
 	close(wp.workerPoolDone)
 }
 
-// stopWorker signals a specific worker to stop processing tasks and exit. This will also remove
-// the worker from the worker pool.
-func (wp *workerPool) stopWorker(id xid.ID) error {
+// stopWorker signals a specific worker to stop processing tasks and exit, returning the worker.
+// A busy worker finishes its current task without receiving another. This will also remove the
+// worker from the worker pool.
+func (wp *workerPool) stopWorker(id xid.ID) (*workerInfo, error) {
 	value, ok := wp.workers.Load(id)
 	if !ok {
-		return fmt.Errorf("worker %s not found", id)
+		return nil, fmt.Errorf("worker %s not found", id)
 	}
 
 	workerInfo, ok := value.(*workerInfo)
 	if !ok {
-		return fmt.Errorf("worker %s has invalid type", id)
+		return nil, fmt.Errorf("worker %s has invalid type", id)
 	}
 
 	workerInfo.stopOnce.Do(func() {
+		wp.workersDraining.Add(1)
+		workerInfo.stopped.Store(true)
 		close(workerInfo.stopChan)
 	})
-	return nil
+	return workerInfo, nil
 }
 
-// stopWorkers stops workers, which removes them from the pool.
+// stopWorkers stops workers, which removes them from the pool, and returns the stopped workers.
+// Workers already signaled to stop are not stopped again, nor counted.
 // Note 1: if the number of workers to stop exceeds the number of idle workers the function will
 // send stop signals to busy workers, which will stop after they finish their current task.
 // Note 2: due to the timing of the stop signal, there is a chance that a worker marked as idle
 // will pick up a task before the stop signal is received, in which case the worker will not stop
 // until it finishes the task. This will not block this function.
 // Note 3: this function is not thread-safe, it should be called from within a mutex lock.
-func (wp *workerPool) stopWorkers(workersToStop int) error {
-	// Validate number of workers to remove
+func (wp *workerPool) stopWorkers(workersToStop int) ([]*workerInfo, error) {
+	// Validate number of workers to remove, of those not already stopping
 	if workersToStop <= 0 {
-		return fmt.Errorf("invalid number of workers to remove: %d", workersToStop)
+		return nil, fmt.Errorf("invalid number of workers to remove: %d", workersToStop)
 	}
-	if workersToStop > int(wp.runningWorkers()) {
-		return fmt.Errorf("cannot remove %d out of %d running workers", workersToStop, wp.runningWorkers())
+	stoppable := wp.runningWorkers() - wp.drainingWorkers()
+	if workersToStop > int(stoppable) {
+		return nil, fmt.Errorf("cannot remove %d out of %d running workers", workersToStop, stoppable)
 	}
-	logger.Debug().Msgf("Removing %d workers from the pool", workersToStop)
+	wp.logger.Debug("Removing workers from the pool", "count", workersToStop)
 
 	busyWorkers, idleWorkers := wp.busyAndIdleWorkers()
 
-	// Stop a subset of the idle workers if there is an abundance
+	// Stop idle workers first, then busy workers as well, up to the number of workers to remove
+	var stopped []*workerInfo
 	var errs error
-	if len(idleWorkers) >= workersToStop {
-		workersToRemove := idleWorkers[:workersToStop]
-		for _, workerID := range workersToRemove {
-			err := wp.stopWorker(workerID)
-			if err != nil {
-				errs = errors.Join(errs, err)
-				logger.Debug().Err(err).Msgf("Failed to stop worker %s", workerID)
-			}
+	for _, workerID := range append(idleWorkers, busyWorkers...) {
+		if len(stopped) == workersToStop {
+			break
 		}
-		return errs
-	}
-
-	// If there aren't enough idle workers, first stop all idle workers
-	for _, workerID := range idleWorkers {
-		err := wp.stopWorker(workerID)
-		if err != nil {
-			errs = errors.Join(errs, err)
-			logger.Debug().Err(err).Msgf("Failed to stop worker %s", workerID)
+		if value, ok := wp.workers.Load(workerID); ok && value.(*workerInfo).stopped.Load() {
+			// Already stopping, finishing its current task
+			continue
 		}
-	}
-
-	// Then stop busy workers as well, up to the number of workers to remove
-	remainingToStop := min(workersToStop-len(idleWorkers), len(busyWorkers))
-	workersToRemove := busyWorkers[:remainingToStop]
-	for _, workerID := range workersToRemove {
-		err := wp.stopWorker(workerID)
+		worker, err := wp.stopWorker(workerID)
 		if err != nil {
 			errs = errors.Join(errs, err)
-			logger.Debug().Err(err).Msgf("Failed to stop worker %s", workerID)
+			wp.logger.Debug("Failed to stop worker", "workerID", workerID, "error", err)
+			continue
 		}
+		stopped = append(stopped, worker)
 	}
 
-	return errs
+	return stopped, errs
 }
 
 // utilization returns the utilization of the worker pool as a float between 0.0 and 1.0.
@@ -366,17 +478,21 @@ func newWorkerPool(
 	initialWorkerCount int,
 	errorChan chan error,
 	execTimeChan chan time.Duration,
-	taskChan chan Task,
+	tasks *taskQueue,
 	workerPoolDone chan struct{},
+	logger Logger,
 ) *workerPool {
 	pool := &workerPool{
+		logger:          logger,
 		errorChan:       errorChan,
 		execTimeChan:    execTimeChan,
 		stopPoolChan:    make(chan struct{}),
-		taskChan:        taskChan,
+		tasks:           tasks,
 		workerCountChan: make(chan int32, 1), // Buffered channel to prevent blocking
 		workerPoolDone:  workerPoolDone,
 	}
+	pool.downScaleThreshold.Store(defaultUtilizationThreshold)
+	pool.downScaleInterval.Store(defaultDownScaleMinInterval)
 	pool.addWorkers(initialWorkerCount)
 	pool.workerCountTarget.Store(int32(initialWorkerCount))
 
@@ -12,9 +12,9 @@ import (
 func getWorkerPool(nWorkers int) *workerPool {
 	errorChan := make(chan error, 1)
 	execTimeChan := make(chan time.Duration, 1)
-	taskChan := make(chan Task, 1)
+	tasks := newTaskQueue(1, 1)
 	workerPoolDone := make(chan struct{})
-	return newWorkerPool(nWorkers, errorChan, execTimeChan, taskChan, workerPoolDone)
+	return newWorkerPool(nWorkers, errorChan, execTimeChan, tasks, workerPoolDone, zerologLogger{})
 }
 
 func TestNewWorkerPool(t *testing.T) {
@@ -45,9 +45,9 @@ func TestWorkerPoolStartStop(t *testing.T) {
 func TestWorkerPoolTaskExecution(t *testing.T) {
 	errorChan := make(chan error, 1)
 	execTimeChan := make(chan time.Duration, 1)
-	taskChan := make(chan Task, 1)
+	tasks := newTaskQueue(1, 1)
 	workerPoolDone := make(chan struct{})
-	pool := newWorkerPool(1, errorChan, execTimeChan, taskChan, workerPoolDone)
+	pool := newWorkerPool(1, errorChan, execTimeChan, tasks, workerPoolDone, zerologLogger{})
 	defer pool.stop()
 
 	time.Sleep(10 * time.Millisecond) // Wait for worker to start
@@ -74,7 +74,7 @@ func TestWorkerPoolTaskExecution(t *testing.T) {
 	}()
 
 	// Send the task to the worker and verify active workers during task execution
-	taskChan <- task
+	tasks.send(nil, task)
 	time.Sleep(5 * time.Millisecond) // Wait for worker to pick up task
 	assert.Equal(t, int32(1), pool.activeWorkers(), "Expected 1 active worker")
 
@@ -86,9 +86,9 @@ func TestWorkerPoolTaskExecution(t *testing.T) {
 func TestWorkerPoolExecutionError(t *testing.T) {
 	errorChan := make(chan error, 1)
 	execTimeChan := make(chan time.Duration, 1)
-	taskChan := make(chan Task, 1)
+	tasks := newTaskQueue(1, 1)
 	workerPoolDone := make(chan struct{})
-	pool := newWorkerPool(1, errorChan, execTimeChan, taskChan, workerPoolDone)
+	pool := newWorkerPool(1, errorChan, execTimeChan, tasks, workerPoolDone, zerologLogger{})
 	defer pool.stop()
 
 	time.Sleep(10 * time.Millisecond) // Wait for worker to start
@@ -118,16 +118,16 @@ func TestWorkerPoolExecutionError(t *testing.T) {
 	}()
 
 	// Send the error-returning task to the worker
-	taskChan <- errorTask
+	tasks.send(nil, errorTask)
 	wg.Wait() // Don't exit the test until the error has been received
 }
 
 func TestWorkerPoolExecutionPanic(t *testing.T) {
 	errorChan := make(chan error, 1)
 	execTimeChan := make(chan time.Duration, 1)
-	taskChan := make(chan Task, 1)
+	tasks := newTaskQueue(1, 1)
 	workerPoolDone := make(chan struct{})
-	pool := newWorkerPool(1, errorChan, execTimeChan, taskChan, workerPoolDone)
+	pool := newWorkerPool(1, errorChan, execTimeChan, tasks, workerPoolDone, zerologLogger{})
 	defer pool.stop()
 
 	time.Sleep(5 * time.Millisecond) // Wait for worker to start
@@ -151,6 +151,8 @@ func TestWorkerPoolExecutionPanic(t *testing.T) {
 		case err := <-errorChan:
 			assert.Contains(t, err.Error(), "panic:")
 			assert.Contains(t, err.Error(), "test panic")
+			var panicErr *PanicError
+			assert.ErrorAs(t, err, &panicErr, "Expected a panic error")
 		case <-timeout:
 			assert.Fail(t, "Test timed out waiting on error")
 		}
@@ -158,16 +160,16 @@ func TestWorkerPoolExecutionPanic(t *testing.T) {
 	}()
 
 	// Send the panic-returning task to the worker
-	taskChan <- panicTask
+	tasks.send(nil, panicTask)
 	wg.Wait() // Don't exit the test until the error has been received
 }
 
 func TestWorkerPoolBusyWorkers(t *testing.T) {
 	errorChan := make(chan error, 1)
 	execTimeChan := make(chan time.Duration, 1)
-	taskChan := make(chan Task, 1)
+	tasks := newTaskQueue(1, 1)
 	workerPoolDone := make(chan struct{})
-	pool := newWorkerPool(2, errorChan, execTimeChan, taskChan, workerPoolDone)
+	pool := newWorkerPool(2, errorChan, execTimeChan, tasks, workerPoolDone, zerologLogger{})
 	defer pool.stop()
 
 	time.Sleep(10 * time.Millisecond) // Wait for workers to start
@@ -189,8 +191,8 @@ func TestWorkerPoolBusyWorkers(t *testing.T) {
 	}
 
 	// Send tasks to the workers
-	taskChan <- task1
-	taskChan <- task2
+	tasks.send(nil, task1)
+	tasks.send(nil, task2)
 	time.Sleep(5 * time.Millisecond) // Wait for workers to pick up tasks
 
 	// Verify active workers during task execution
@@ -206,27 +208,27 @@ func TestWorkerPoolBusyWorkers(t *testing.T) {
 	}
 
 	// Send the third task while workers are busy
-	taskChan <- task3
+	tasks.send(nil, task3)
 	time.Sleep(5 * time.Millisecond) // Allow some time for task to be queued
 
 	// Verify that the third task is queued and not yet executed
 	assert.Equal(t, int32(2), pool.activeWorkers(), "Expected 2 active workers")
-	assert.Equal(t, 1, len(taskChan), "Expected 1 task in the queue")
+	assert.Equal(t, 1, tasks.len(), "Expected 1 task in the queue")
 
 	// Wait for the first two tasks to complete
 	time.Sleep(50 * time.Millisecond)
 
 	// Verify that the third task is now being executed
 	assert.Equal(t, int32(1), pool.activeWorkers(), "Expected 1 active worker")
-	assert.Equal(t, 0, len(taskChan), "Expected no tasks in the queue")
+	assert.Equal(t, 0, tasks.len(), "Expected no tasks in the queue")
 }
 
 func TestStopWorker(t *testing.T) {
 	errorChan := make(chan error, 1)
 	execTimeChan := make(chan time.Duration, 1)
-	taskChan := make(chan Task, 1)
+	tasks := newTaskQueue(1, 1)
 	workerPoolDone := make(chan struct{})
-	pool := newWorkerPool(2, errorChan, execTimeChan, taskChan, workerPoolDone)
+	pool := newWorkerPool(2, errorChan, execTimeChan, tasks, workerPoolDone, zerologLogger{})
 	defer pool.stop()
 
 	time.Sleep(10 * time.Millisecond) // Wait for workers to start
@@ -254,7 +256,7 @@ func TestStopWorker(t *testing.T) {
 		},
 		ID: "task-1",
 	}
-	taskChan <- task
+	tasks.send(nil, task)
 	time.Sleep(5 * time.Millisecond) // Wait for worker to pick up task
 
 	// Verify active/busy workers during task execution
@@ -289,9 +291,9 @@ func TestStopWorker(t *testing.T) {
 func TestStopWorkers(t *testing.T) {
 	errorChan := make(chan error, 1)
 	execTimeChan := make(chan time.Duration, 1)
-	taskChan := make(chan Task, 1)
+	tasks := newTaskQueue(1, 1)
 	workerPoolDone := make(chan struct{})
-	pool := newWorkerPool(6, errorChan, execTimeChan, taskChan, workerPoolDone)
+	pool := newWorkerPool(6, errorChan, execTimeChan, tasks, workerPoolDone, zerologLogger{})
 	defer pool.stop()
 
 	time.Sleep(10 * time.Millisecond) // Wait for workers to start
@@ -320,7 +322,7 @@ func TestStopWorkers(t *testing.T) {
 		ID: "task-1",
 	}
 	for i := 0; i < 3; i++ {
-		taskChan <- task
+		tasks.send(nil, task)
 	}
 
 	time.Sleep(5 * time.Millisecond) // Wait for workers to pick up tasks
@@ -358,12 +360,59 @@ func TestStopWorkers(t *testing.T) {
 	assert.Equal(t, int32(2), pool.runningWorkers(), "Expected 2 running workers")
 }
 
+func TestWorkerPoolDrainWorkers(t *testing.T) {
+	errorChan := make(chan error, 1)
+	execTimeChan := make(chan time.Duration, 4)
+	tasks := newTaskQueue(1, 4)
+	workerPoolDone := make(chan struct{})
+	pool := newWorkerPool(2, errorChan, execTimeChan, tasks, workerPoolDone, zerologLogger{})
+	defer pool.stop()
+
+	// Keep both workers busy
+	release := make(chan struct{})
+	var executed sync.WaitGroup
+	executed.Add(3)
+	busyTask := &MockTask{executeFunc: func() error {
+		<-release
+		executed.Done()
+		return nil
+	}}
+	tasks.send(nil, busyTask)
+	tasks.send(nil, busyTask)
+	time.Sleep(5 * time.Millisecond) // Wait for workers to pick up tasks
+	assert.Equal(t, int32(2), pool.activeWorkers(), "Expected 2 active workers")
+
+	// Drain a busy worker, with a task left queued for the remaining worker
+	drained, err := pool.drainWorkers(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), pool.targetWorkerCount(), "Expected the target to be lowered")
+	assert.Equal(t, int32(1), pool.drainingWorkers(), "Expected 1 draining worker")
+	tasks.send(nil, &MockTask{executeFunc: func() error {
+		executed.Done()
+		return nil
+	}})
+
+	close(release)
+	select {
+	case <-drained:
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Expected the worker to be drained")
+	}
+	executed.Wait()
+	assert.Equal(t, int32(1), pool.runningWorkers(), "Expected 1 running worker")
+	assert.Zero(t, pool.drainingWorkers(), "Expected no draining workers")
+
+	// The last worker cannot be drained
+	_, err = pool.drainWorkers(1)
+	assert.Error(t, err)
+}
+
 func TestWorkerPoolUtilization(t *testing.T) {
 	errorChan := make(chan error, 1)
 	execTimeChan := make(chan time.Duration, 1)
-	taskChan := make(chan Task, 1)
+	tasks := newTaskQueue(1, 1)
 	workerPoolDone := make(chan struct{})
-	pool := newWorkerPool(4, errorChan, execTimeChan, taskChan, workerPoolDone)
+	pool := newWorkerPool(4, errorChan, execTimeChan, tasks, workerPoolDone, zerologLogger{})
 	defer pool.stop()
 
 	time.Sleep(5 * time.Millisecond) // Wait for workers to start
@@ -383,8 +432,8 @@ func TestWorkerPoolUtilization(t *testing.T) {
 	task2.ID = "task-2"
 
 	// Send tasks to the workers
-	taskChan <- task1
-	taskChan <- task2
+	tasks.send(nil, task1)
+	tasks.send(nil, task2)
 	time.Sleep(5 * time.Millisecond) // Wait for workers to pick up tasks
 
 	// Verify utilization during task execution
@@ -400,7 +449,7 @@ func TestWorkerPoolUtilization(t *testing.T) {
 	}
 
 	// Send the third task while workers are busy
-	taskChan <- task3
+	tasks.send(nil, task3)
 	time.Sleep(5 * time.Millisecond) // Allow some time for task to be queued
 
 	// Verify utilization remains the same as no new worker has picked up the task yet
@@ -0,0 +1,79 @@
+package taskman
+
+import (
+	"errors"
+	"time"
+)
+
+// workerReservation is a pool of workers reserved for the tasks of jobs of a minimum priority.
+type workerReservation struct {
+	group       *workerGroup // Reserved workers and the queue to send tasks to them
+	minPriority int          // Min Priority of the jobs whose tasks may be executed by the reserved workers
+}
+
+// SetWorkerReservation reserves a number of workers, in addition to the default worker pool, for
+// the jobs of the default worker pool with a Priority of at least minPriority, so that critical
+// jobs find a free worker even while bulk jobs saturate the pool. The tasks of such jobs are sent
+// to a reserved worker while one is available, and to the default worker pool otherwise. Calling
+// it again resizes the reservation and changes its priority. Like worker groups, the reserved
+// workers are not scaled automatically.
+func (tm *TaskManager) SetWorkerReservation(workers, minPriority int) error {
+	if workers <= 0 {
+		return errors.New("invalid worker count, must be greater than 0")
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	select {
+	case <-tm.ctx.Done():
+		return ErrManagerStopped
+	default:
+	}
+
+	if reservation := tm.reservation.Load(); reservation != nil {
+		reservation.group.pool.enqueueWorkerScaling(int32(workers))
+		tm.reservation.Store(&workerReservation{group: reservation.group, minPriority: minPriority})
+		tm.logger.Debug("Resized worker reservation", "workers", workers, "minPriority", minPriority)
+		return nil
+	}
+
+	// Execution times only inform the scaling of the default pool, and are discarded
+	queue := newTaskQueue(1, workers)
+	done := make(chan struct{})
+	group := &workerGroup{
+		pool:  newWorkerPool(workers, tm.errorFan.in, make(chan time.Duration), queue, done, tm.logger),
+		queue: queue,
+		done:  done,
+	}
+	if tm.workerLifecycle != nil {
+		group.pool.lifecycle.Store(tm.workerLifecycle)
+	}
+	tm.reservation.Store(&workerReservation{group: group, minPriority: minPriority})
+	tm.logger.Debug("Reserved workers", "workers", workers, "minPriority", minPriority)
+	return nil
+}
+
+// reservedQueue returns the queue of the reserved workers if the job may use them and one of them
+// is available, or nil otherwise.
+func (tm *TaskManager) reservedQueue(job *Job) *taskQueue {
+	reservation := tm.reservation.Load()
+	if reservation == nil || !job.pooled() || job.Priority < reservation.minPriority {
+		return nil
+	}
+	if reservation.group.pool.availableWorkers() <= int32(reservation.group.queue.len()) {
+		return nil
+	}
+	return reservation.group.queue
+}
+
+// stopWorkerReservation stops the reserved workers, if any, waiting for them to finish.
+func (tm *TaskManager) stopWorkerReservation() {
+	reservation := tm.reservation.Load()
+	if reservation == nil {
+		return
+	}
+	reservation.group.pool.stop()
+	<-reservation.group.done
+	reservation.group.queue.close()
+}
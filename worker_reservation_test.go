@@ -0,0 +1,50 @@
+package taskman
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerReservation(t *testing.T) {
+	manager := New(WithWorkerBounds(1, 1), WithWorkerReservation(1, 10))
+	defer manager.Stop()
+	assert.Error(t, manager.SetWorkerReservation(0, 10), "Expected error for no reserved workers")
+
+	// A bulk job saturates the default worker pool
+	release := make(chan struct{})
+	defer close(release)
+	bulk := getMockedJob(1, "bulk-job", time.Hour, time.Hour)
+	bulk.Tasks[0] = MockTask{executeFunc: func() error {
+		<-release
+		return nil
+	}}
+	assert.NoError(t, manager.ScheduleJob(bulk))
+	assert.NoError(t, manager.TriggerJob(bulk.ID))
+	assert.Eventually(t, func() bool { return manager.workerPool.availableWorkers() == 0 }, time.Second, time.Millisecond)
+
+	critical := getMockedJob(1, "critical-job", time.Hour, time.Hour)
+	critical.Priority = 10
+	assert.NoError(t, manager.ScheduleJob(critical))
+	normal := getMockedJob(1, "normal-job", time.Hour, time.Hour)
+	assert.NoError(t, manager.ScheduleJob(normal))
+
+	// Critical jobs execute on the reserved workers, other jobs wait for the default pool
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := manager.RunJobNow(ctx, critical.ID)
+	assert.NoError(t, err, "Expected the critical job to execute on a reserved worker")
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = manager.RunJobNow(ctx, normal.ID)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Expected the normal job to wait for the default pool")
+
+	// Resizing the reservation changes its priority
+	assert.NoError(t, manager.SetWorkerReservation(2, 20))
+	manager.RLock()
+	defer manager.RUnlock()
+	assert.Nil(t, manager.reservedQueue(manager.jobQueue.byID[critical.ID]), "Expected the job to no longer use reserved workers")
+}